@@ -11,6 +11,8 @@ import (
 	internalflags "github.com/solo-io/gloo/internal/control-plane/bootstrap/flags"
 	"github.com/solo-io/gloo/internal/control-plane/eventloop"
 	"github.com/solo-io/gloo/pkg/bootstrap/flags"
+	"github.com/solo-io/gloo/pkg/log"
+	"github.com/solo-io/gloo/pkg/metrics"
 	"github.com/solo-io/gloo/pkg/signals"
 
 	//register plugins
@@ -33,7 +35,11 @@ var rootCmd = &cobra.Command{
 	Use:   "gloo",
 	Short: "runs the gloo control plane to manage Envoy as a Function Gateway",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := log.Configure(opts.LogOptions.Format, opts.LogOptions.Level); err != nil {
+			return err
+		}
 		stop := signals.SetupSignalHandler()
+		metrics.Start(opts.MetricsOptions, stop)
 		eventLoop, err := eventloop.Setup(opts, xdsPort, stop)
 		if err != nil {
 			return errors.Wrap(err, "setting up event loop")
@@ -54,6 +60,7 @@ func init() {
 	flags.AddFileFlags(rootCmd, baseOpts)
 	flags.AddKubernetesFlags(rootCmd, baseOpts)
 	flags.AddConsulFlags(rootCmd, baseOpts)
+	flags.AddEtcdFlags(rootCmd, baseOpts)
 	flags.AddCoPilotFlags(rootCmd, baseOpts)
 	flags.AddVaultFlags(rootCmd, baseOpts)
 
@@ -62,4 +69,19 @@ func init() {
 
 	// Ingress flags
 	internalflags.AddIngressFlags(rootCmd, &opts)
+
+	// xds role assignment flags
+	internalflags.AddXdsFlags(rootCmd, &opts)
+
+	// admin api flags
+	internalflags.AddAdminFlags(rootCmd, &opts)
+
+	// acme certificate issuance flags
+	internalflags.AddAcmeFlags(rootCmd, &opts)
+
+	// logging
+	flags.AddLogFlags(rootCmd, baseOpts)
+
+	// prometheus metrics
+	flags.AddMetricsFlags(rootCmd, baseOpts)
 }
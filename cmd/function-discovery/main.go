@@ -11,6 +11,7 @@ import (
 	"github.com/solo-io/gloo/pkg/bootstrap"
 	"github.com/solo-io/gloo/pkg/bootstrap/flags"
 	"github.com/solo-io/gloo/pkg/log"
+	"github.com/solo-io/gloo/pkg/metrics"
 	"github.com/solo-io/gloo/pkg/signals"
 )
 
@@ -31,7 +32,11 @@ var rootCmd = &cobra.Command{
 	Short: "discovers functions for swagger, google functions, and lambda upstreams",
 
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := log.Configure(opts.LogOptions.Format, opts.LogOptions.Level); err != nil {
+			return err
+		}
 		stop := signals.SetupSignalHandler()
+		metrics.Start(opts.MetricsOptions, stop)
 		errs := make(chan error)
 
 		finished := make(chan error)
@@ -58,6 +63,7 @@ func init() {
 	flags.AddFileFlags(rootCmd, &opts)
 	flags.AddKubernetesFlags(rootCmd, &opts)
 	flags.AddConsulFlags(rootCmd, &opts)
+	flags.AddEtcdFlags(rootCmd, &opts)
 	flags.AddVaultFlags(rootCmd, &opts)
 
 	// function discovery: upstream service type detection
@@ -69,4 +75,10 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&discoveryOpts.AutoDiscoverProjectFn, "detect-project-fn", true, "enable automatic discovery project fn upstreams.")
 	rootCmd.PersistentFlags().StringSliceVar(&discoveryOpts.SwaggerUrisToTry, "swagger-uris", []string{}, "paths function discovery should try to use to discover swagger services. function discovery will query http://<upstream>/<uri> for the swagger.json document. "+
 		"if found, REST functions will be discovered for this upstream.")
+
+	// logging
+	flags.AddLogFlags(rootCmd, &opts)
+
+	// prometheus metrics
+	flags.AddMetricsFlags(rootCmd, &opts)
 }
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/solo-io/gloo/internal/control-plane/offline"
+	"github.com/solo-io/gloo/internal/control-plane/snapshot"
+	"github.com/solo-io/gloo/internal/control-plane/translator"
+	"github.com/solo-io/gloo/pkg/bootstrap"
+	"github.com/solo-io/gloo/pkg/bootstrap/flags"
+	"github.com/solo-io/gloo/pkg/plugins"
+)
+
+const outputText = "text"
+
+func diffCmd() *cobra.Command {
+	var (
+		output   string
+		exitCode bool
+	)
+	cmd := &cobra.Command{
+		Use:   "diff [OLD_DIR] NEW_DIR",
+		Short: "show how the gloo config, and the envoy resources of every role, differ between two sets of config",
+		Long: `compares two config directories, each containing upstreams/, virtualservices/ and roles/ directories,
+with secrets in secrets/ and files in files/. encrypted secrets are decrypted with the keys of --file.secret.keyfile.
+with a single directory, the config is compared against the storage backend selected with the storage flags.
+
+both sets of config are translated the same way as by validate, and the changes to the gloo objects and to the
+clusters, routes, listeners and tls certificate chains of each role are printed. secret values and private keys
+are never printed, only a hash of them.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != outputText && output != outputJson {
+				return errors.Errorf("invalid output format %q, must be %v or %v", output, outputText, outputJson)
+			}
+			var (
+				oldSnap *snapshot.Cache
+				err     error
+			)
+			if len(args) == 2 {
+				oldSnap, err = offline.LoadDir(dirOptions(args[0], opts.FileOptions.SecretKeyFile))
+			} else {
+				oldSnap, err = offline.LoadStorage(opts.Options)
+			}
+			if err != nil {
+				return errors.Wrap(err, "loading old config")
+			}
+			newSnap, err := offline.LoadDir(dirOptions(args[len(args)-1], opts.FileOptions.SecretKeyFile))
+			if err != nil {
+				return errors.Wrap(err, "loading new config")
+			}
+
+			trans := translator.NewTranslator(opts.IngressOptions, plugins.RegisteredPlugins())
+			oldResult, err := offline.Translate(trans, oldSnap)
+			if err != nil {
+				return err
+			}
+			newResult, err := offline.Translate(trans, newSnap)
+			if err != nil {
+				return err
+			}
+
+			changes := append(offline.DiffConfig(oldSnap, newSnap), offline.DiffEnvoy(oldResult, newResult)...)
+			if output == outputJson {
+				err = printChangesJson(os.Stdout, changes)
+			} else {
+				printChanges(os.Stdout, changes)
+			}
+			if err != nil {
+				return err
+			}
+
+			if rejected := newResult.Rejected(); len(rejected) > 0 {
+				fmt.Fprintln(os.Stderr, "the new config has rejected objects:")
+				printRejected(os.Stderr, rejected)
+			}
+			if exitCode && len(changes) > 0 {
+				return errors.Errorf("%v changes", len(changes))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", outputText, "output format: text or json")
+	cmd.Flags().BoolVar(&exitCode, "exit-code", false, "exit with a non-zero status if there are any changes")
+
+	// the storage backend compared against when only one directory is given
+	flags.AddConfigStorageOptionFlags(cmd, &opts.Options)
+	flags.AddSecretStorageOptionFlags(cmd, &opts.Options)
+	flags.AddFileStorageOptionFlags(cmd, &opts.Options)
+	flags.AddKubernetesFlags(cmd, &opts.Options)
+	flags.AddConsulFlags(cmd, &opts.Options)
+	flags.AddEtcdFlags(cmd, &opts.Options)
+	flags.AddVaultFlags(cmd, &opts.Options)
+	return cmd
+}
+
+// secrets and files are expected in the same layout as the defaults of the file backends.
+// encrypted secrets of both directories are decrypted with the keys in secretKeyFile
+func dirOptions(dir, secretKeyFile string) bootstrap.FileOptions {
+	return bootstrap.FileOptions{
+		ConfigDir:     dir,
+		SecretDir:     filepath.Join(dir, "secrets"),
+		FilesDir:      filepath.Join(dir, "files"),
+		SecretKeyFile: secretKeyFile,
+	}
+}
+
+var changeSymbols = map[offline.ChangeType]string{
+	offline.Added:    "+",
+	offline.Removed:  "-",
+	offline.Modified: "~",
+}
+
+// gloo objects are printed first, followed by the envoy resources of each role
+func printChanges(w io.Writer, changes []offline.Change) {
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "no changes")
+		return
+	}
+	section := "-"
+	for _, change := range changes {
+		if change.Role != section {
+			section = change.Role
+			if section == "" {
+				fmt.Fprintln(w, "gloo config:")
+			} else {
+				fmt.Fprintf(w, "role %v:\n", section)
+			}
+		}
+		fmt.Fprintf(w, "  %v %v\n", changeSymbols[change.Type], change.Ref())
+		for _, detail := range change.Details {
+			fmt.Fprintf(w, "      %v\n", detail)
+		}
+	}
+}
+
+func printChangesJson(w io.Writer, changes []offline.Change) error {
+	if changes == nil {
+		changes = []offline.Change{}
+	}
+	data, err := json.MarshalIndent(changes, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
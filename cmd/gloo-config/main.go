@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/solo-io/gloo/internal/control-plane/bootstrap"
+	internalflags "github.com/solo-io/gloo/internal/control-plane/bootstrap/flags"
+	"github.com/solo-io/gloo/pkg/bootstrap/flags"
+
+	//register plugins
+	_ "github.com/solo-io/gloo/internal/control-plane/install"
+)
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+var opts bootstrap.Options
+
+var rootCmd = &cobra.Command{
+	Use:           "gloo-config",
+	Short:         "works with gloo config offline, without a running control plane",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	// config, secrets and files are read from local directories, and encrypted secrets are decrypted with --file.secret.keyfile
+	flags.AddFileFlags(rootCmd, &opts.Options)
+
+	// the listeners rendered for envoy depend on these
+	internalflags.AddIngressFlags(rootCmd, &opts)
+
+	rootCmd.AddCommand(validateCmd(), diffCmd())
+}
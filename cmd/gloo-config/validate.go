@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/solo-io/gloo/internal/control-plane/offline"
+	"github.com/solo-io/gloo/internal/control-plane/reporter"
+	"github.com/solo-io/gloo/internal/control-plane/translator"
+	"github.com/solo-io/gloo/internal/control-plane/xds"
+	"github.com/solo-io/gloo/pkg/plugins"
+)
+
+const (
+	outputYaml = "yaml"
+	outputJson = "json"
+)
+
+func validateCmd() *cobra.Command {
+	var (
+		output string
+		quiet  bool
+	)
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "translate the config for every role and print the resulting envoy resources",
+		Long: `translates the upstreams and virtual services in --file.config.dir, with the secrets in --file.secret.dir
+and the files in --file.files.dir, exactly as the control plane would.
+the clusters, routes and listeners of each role are printed to stdout, and every rejected config object and
+warning is printed to stderr. exits with a non-zero status if any object was rejected.
+credentials that plugins copy from secrets, and private keys, are never printed, only a hash of them.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != outputYaml && output != outputJson {
+				return errors.Errorf("invalid output format %q, must be %v or %v", output, outputYaml, outputJson)
+			}
+			snap, err := offline.LoadDir(opts.FileOptions)
+			if err != nil {
+				return errors.Wrap(err, "loading config")
+			}
+			trans := translator.NewTranslator(opts.IngressOptions, plugins.RegisteredPlugins())
+			result, err := offline.Translate(trans, snap)
+			if err != nil {
+				return err
+			}
+			if !quiet {
+				if err := printRoles(os.Stdout, result, output); err != nil {
+					return err
+				}
+			}
+			printWarnings(os.Stderr, result.Reports)
+			rejected := result.Rejected()
+			printRejected(os.Stderr, rejected)
+			if len(rejected) > 0 {
+				return errors.Errorf("%v config objects were rejected", len(rejected))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", outputYaml, "output format for the envoy resources: yaml or json")
+	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "only print rejected config objects")
+	return cmd
+}
+
+// endpoints are left out, as nothing is discovered offline
+type renderedRole struct {
+	Clusters  []json.RawMessage `json:"clusters"`
+	Routes    []json.RawMessage `json:"routes"`
+	Listeners []json.RawMessage `json:"listeners"`
+	Secrets   []json.RawMessage `json:"secrets"`
+}
+
+// roles are rendered like the admin server's /xds endpoint, with credentials redacted
+func printRoles(w io.Writer, result *offline.Result, output string) error {
+	roles := make(map[string]renderedRole)
+	for role, snap := range result.Snapshots {
+		rendered, err := xds.RenderSnapshot(snap)
+		if err != nil {
+			return errors.Wrapf(err, "rendering role %v", role)
+		}
+		roles[role] = renderedRole{
+			Clusters:  rendered.Clusters,
+			Routes:    rendered.Routes,
+			Listeners: rendered.Listeners,
+			Secrets:   rendered.Secrets,
+		}
+	}
+	data, err := json.MarshalIndent(roles, "", "  ")
+	if err != nil {
+		return err
+	}
+	if output == outputYaml {
+		data, err = yaml.JSONToYAML(data)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+func printRejected(w io.Writer, rejected []reporter.ConfigObjectReport) {
+	for _, rep := range rejected {
+		fmt.Fprintf(w, "%v rejected: %v\n", reporter.ObjectRef(rep.CfgObject), rep.Err)
+	}
+}
+
+// warnings don't cause objects to be rejected, but usually point out a mistake
+func printWarnings(w io.Writer, reports []reporter.ConfigObjectReport) {
+	for _, rep := range reports {
+		for _, warning := range rep.Warnings {
+			fmt.Fprintf(w, "%v warning: %v\n", reporter.ObjectRef(rep.CfgObject), warning)
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/solo-io/gloo/pkg/bootstrap/configstorage"
 	"github.com/solo-io/gloo/pkg/bootstrap/flags"
 	"github.com/solo-io/gloo/pkg/log"
+	"github.com/solo-io/gloo/pkg/metrics"
 	"github.com/solo-io/gloo/pkg/signals"
 	"github.com/solo-io/gloo/pkg/storage"
 )
@@ -34,6 +35,9 @@ var rootCmd = &cobra.Command{
 	Use:   "kube-ingress-controller",
 	Short: "Enables gloo to function as a kubernetes ingress controller",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := log.Configure(opts.LogOptions.Format, opts.LogOptions.Level); err != nil {
+			return err
+		}
 		store, err := configstorage.Bootstrap(opts)
 		if err != nil {
 			return errors.Wrap(err, "failed to create config store client")
@@ -43,6 +47,7 @@ var rootCmd = &cobra.Command{
 			return errors.Wrap(err, "failed to create kube restclient config")
 		}
 		stop := signals.SetupSignalHandler()
+		metrics.Start(opts.MetricsOptions, stop)
 
 		go runIngressController(cfg, store, stop)
 
@@ -60,10 +65,17 @@ func init() {
 	flags.AddFileFlags(rootCmd, &opts)
 	flags.AddKubernetesFlags(rootCmd, &opts)
 	flags.AddConsulFlags(rootCmd, &opts)
+	flags.AddEtcdFlags(rootCmd, &opts)
 
 	// ingress-specific
 	rootCmd.PersistentFlags().BoolVar(&globalIngress, "global", true, "use gloo as the cluster-wide kubernetes ingress")
 	rootCmd.PersistentFlags().StringVar(&ingressServiceName, "service", "", "The name of the proxy service (envoy) if running in-cluster. If --service is set, the ingress controller will update ingress objects with the load balancer endpoints")
+
+	// logging
+	flags.AddLogFlags(rootCmd, &opts)
+
+	// prometheus metrics
+	flags.AddMetricsFlags(rootCmd, &opts)
 }
 
 func runIngressController(cfg *rest.Config, store storage.Interface, stop <-chan struct{}) error {
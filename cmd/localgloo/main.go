@@ -17,6 +17,7 @@ import (
 	"github.com/solo-io/gloo/pkg/bootstrap/configstorage"
 	"github.com/solo-io/gloo/pkg/bootstrap/flags"
 	"github.com/solo-io/gloo/pkg/log"
+	"github.com/solo-io/gloo/pkg/metrics"
 	"github.com/solo-io/gloo/pkg/signals"
 	"github.com/spf13/cobra"
 
@@ -43,7 +44,11 @@ var rootCmd = &cobra.Command{
 	Use:   "localgloo",
 	Short: "runs the gloo control plane, upstream discovery, and function discovery in a single binary",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := log.Configure(baseOpts.LogOptions.Format, baseOpts.LogOptions.Level); err != nil {
+			return err
+		}
 		stop := signals.SetupSignalHandler()
+		metrics.Start(baseOpts.MetricsOptions, stop)
 		go runControlPlane(stop)
 		go runUpstreamDiscovery(stop)
 		go runFunctionDiscovery(stop)
@@ -62,6 +67,7 @@ func init() {
 	flags.AddFileFlags(rootCmd, &baseOpts)
 	flags.AddKubernetesFlags(rootCmd, &baseOpts)
 	flags.AddConsulFlags(rootCmd, &baseOpts)
+	flags.AddEtcdFlags(rootCmd, &baseOpts)
 	flags.AddCoPilotFlags(rootCmd, &baseOpts)
 	flags.AddVaultFlags(rootCmd, &baseOpts)
 
@@ -69,6 +75,12 @@ func init() {
 	initUpstreamDiscovery()
 	initFunctionDiscovery()
 
+	// logging
+	flags.AddLogFlags(rootCmd, &baseOpts)
+
+	// prometheus metrics
+	flags.AddMetricsFlags(rootCmd, &baseOpts)
+
 	controlPlaneOpts.Options = baseOpts
 	upstreamDiscoveryOpts.Options = baseOpts
 }
@@ -79,6 +91,15 @@ func initControlPlane() {
 
 	// Ingress flags
 	internalflags.AddIngressFlags(rootCmd, &controlPlaneOpts)
+
+	// xds role assignment flags
+	internalflags.AddXdsFlags(rootCmd, &controlPlaneOpts)
+
+	// admin api flags
+	internalflags.AddAdminFlags(rootCmd, &controlPlaneOpts)
+
+	// acme certificate issuance flags
+	internalflags.AddAcmeFlags(rootCmd, &controlPlaneOpts)
 }
 
 func initUpstreamDiscovery() {
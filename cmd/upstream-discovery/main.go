@@ -13,6 +13,7 @@ import (
 	"github.com/solo-io/gloo/pkg/bootstrap/configstorage"
 	"github.com/solo-io/gloo/pkg/bootstrap/flags"
 	"github.com/solo-io/gloo/pkg/log"
+	"github.com/solo-io/gloo/pkg/metrics"
 	"github.com/solo-io/gloo/pkg/signals"
 )
 
@@ -29,11 +30,15 @@ var rootCmd = &cobra.Command{
 	Use:   "upstream-discovery",
 	Short: "discovers services on various platforms and publishes them as Gloo upstreams",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := log.Configure(opts.LogOptions.Format, opts.LogOptions.Level); err != nil {
+			return err
+		}
 		store, err := configstorage.Bootstrap(opts.Options)
 		if err != nil {
 			return errors.Wrap(err, "failed to create config store client")
 		}
 		stop := signals.SetupSignalHandler()
+		metrics.Start(opts.MetricsOptions, stop)
 
 		// enable kubernetes service discovery by default if no discovery option has been enabled
 		if !opts.UpstreamDiscoveryOptions.DiscoveryEnabled() {
@@ -59,6 +64,7 @@ func init() {
 	// storage backends
 	flags.AddFileFlags(rootCmd, baseOpts)
 	flags.AddConsulFlags(rootCmd, baseOpts)
+	flags.AddEtcdFlags(rootCmd, baseOpts)
 
 	// kubernetes flags
 	// used for both storage and ud
@@ -70,4 +76,10 @@ func init() {
 
 	// upstream discovery options
 	internalflags.AddUpstreamDiscoveryFlags(rootCmd, &opts)
+
+	// logging
+	flags.AddLogFlags(rootCmd, baseOpts)
+
+	// prometheus metrics
+	flags.AddMetricsFlags(rootCmd, baseOpts)
 }
@@ -0,0 +1,15 @@
+package acme
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/solo-io/gloo/pkg/log"
+)
+
+func TestAcme(t *testing.T) {
+	RegisterFailHandler(Fail)
+	log.DefaultOut = GinkgoWriter
+	RunSpecs(t, "Acme Suite")
+}
@@ -0,0 +1,46 @@
+package acme
+
+import (
+	"sort"
+
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+)
+
+// ChallengePathPrefix is the path the ACME server requests HTTP-01 challenges on, followed by the token
+const ChallengePathPrefix = "/.well-known/acme-challenge/"
+
+// Challenge is a pending HTTP-01 challenge. envoy answers requests for its path with the key authorization,
+// on the HTTP listener of the roles of the virtual service it proves the domain of
+type Challenge struct {
+	VirtualService   string
+	Domain           string
+	Token            string
+	KeyAuthorization string
+}
+
+// Path is the path the ACME server requests the challenge on
+func (c Challenge) Path() string {
+	return ChallengePathPrefix + c.Token
+}
+
+// ForVirtualServices returns the challenges of these virtual services, sorted by token
+func ForVirtualServices(challenges []Challenge, virtualServices []*v1.VirtualService) []Challenge {
+	names := make(map[string]bool)
+	for _, vs := range virtualServices {
+		names[vs.Name] = true
+	}
+	var filtered []Challenge
+	for _, challenge := range challenges {
+		if names[challenge.VirtualService] {
+			filtered = append(filtered, challenge)
+		}
+	}
+	sortChallenges(filtered)
+	return filtered
+}
+
+func sortChallenges(challenges []Challenge) {
+	sort.SliceStable(challenges, func(i, j int) bool {
+		return challenges[i].Token < challenges[j].Token
+	})
+}
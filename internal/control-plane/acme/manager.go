@@ -0,0 +1,507 @@
+package acme
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/acme"
+
+	"github.com/solo-io/gloo/internal/control-plane/bootstrap"
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/log"
+	"github.com/solo-io/gloo/pkg/secretwatcher"
+	"github.com/solo-io/gloo/pkg/storage"
+	"github.com/solo-io/gloo/pkg/storage/dependencies"
+)
+
+var logger = log.With(log.Component("acme"))
+
+const (
+	// how often certificates are checked for renewal, and how long to wait before retrying a failed issuance
+	resyncInterval = 10 * time.Minute
+	// how long a single issuance may take, including the validation of every challenge
+	issueTimeout = 10 * time.Minute
+	// the maximum number of certificates issued at the same time
+	maxConcurrentIssues = 4
+)
+
+// Manager issues the certificates of virtual services with auto_certificate from an ACME server,
+// and renews them before they expire. domains are validated with HTTP-01 challenges,
+// which the translator answers with routes on the envoy HTTP listener
+type Manager struct {
+	opts    bootstrap.AcmeOptions
+	secrets dependencies.SecretStorage
+
+	httpClient *http.Client
+	now        func() time.Time
+
+	// the account client is created with the first issuance
+	clientLock sync.Mutex
+	client     *acme.Client
+
+	lock sync.Mutex
+	// pending challenges, by token
+	challenges map[string]Challenge
+	// virtual services whose certificate is being issued
+	issuing map[string]bool
+	// the error of the last issuance of each virtual service
+	errs map[string]error
+	// when the certificate of a virtual service may be checked again, after its issuance failed,
+	// or before the secret watcher delivered the certificate that was just issued
+	retryAt map[string]time.Time
+	// the inputs of the last sync, checked again for renewals
+	lastCfg     *v1.Config
+	lastSecrets secretwatcher.SecretMap
+
+	issueSlots chan struct{}
+	updates    chan struct{}
+}
+
+// NewManager creates a manager that issues certificates from the directory in opts.
+// if the directory url is empty, no certificates are issued and secrets may be nil
+func NewManager(opts bootstrap.AcmeOptions, secrets dependencies.SecretStorage) (*Manager, error) {
+	httpClient, err := newHTTPClient(opts.CAFile)
+	if err != nil {
+		return nil, err
+	}
+	return &Manager{
+		opts:       opts,
+		secrets:    secrets,
+		httpClient: httpClient,
+		now:        time.Now,
+		challenges: make(map[string]Challenge),
+		issuing:    make(map[string]bool),
+		errs:       make(map[string]error),
+		retryAt:    make(map[string]time.Time),
+		issueSlots: make(chan struct{}, maxConcurrentIssues),
+		updates:    make(chan struct{}, 1),
+	}, nil
+}
+
+func newHTTPClient(caFile string) (*http.Client, error) {
+	if caFile == "" {
+		return http.DefaultClient, nil
+	}
+	caPem, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading acme ca file")
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(caPem) {
+		return nil, errors.Errorf("no PEM encoded certificates found in acme ca file %v", caFile)
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{RootCAs: pool},
+		},
+	}, nil
+}
+
+// Run periodically checks the certificates of the last synced config for renewal
+func (m *Manager) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(resyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			m.lock.Lock()
+			cfg, secrets := m.lastCfg, m.lastSecrets
+			m.lock.Unlock()
+			if cfg != nil {
+				m.Sync(cfg, secrets)
+			}
+		}
+	}
+}
+
+// Updates signals that the pending challenges or the issuance errors changed
+func (m *Manager) Updates() <-chan struct{} {
+	return m.updates
+}
+
+// Challenges returns the pending challenges, sorted by token
+func (m *Manager) Challenges() []Challenge {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var challenges []Challenge
+	for _, challenge := range m.challenges {
+		challenges = append(challenges, challenge)
+	}
+	sortChallenges(challenges)
+	return challenges
+}
+
+// Error returns why the certificate of the virtual service could not be issued, if it could not
+func (m *Manager) Error(virtualService string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.errs[virtualService]
+}
+
+// Sync starts issuing a certificate for each virtual service with auto_certificate whose secret is missing,
+// holds an invalid certificate, a certificate that does not cover its domains, or one that expires soon.
+// it returns without waiting for the certificates to be issued
+func (m *Manager) Sync(cfg *v1.Config, secrets secretwatcher.SecretMap) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.lastCfg, m.lastSecrets = cfg, secrets
+
+	now := m.now()
+	autoCertificates := make(map[string]bool)
+	for _, vs := range cfg.VirtualServices {
+		if vs.SslConfig == nil || !vs.SslConfig.AutoCertificate || vs.SslConfig.SecretRef == "" {
+			continue
+		}
+		autoCertificates[vs.Name] = true
+		if m.opts.DirectoryURL == "" {
+			m.errs[vs.Name] = errors.New("certificates can not be issued: acme is not configured. " +
+				"start gloo with --acme.directory-url")
+			continue
+		}
+		domains, err := certificateDomains(vs)
+		if err != nil {
+			m.errs[vs.Name] = err
+			continue
+		}
+		if m.issuing[vs.Name] || now.Before(m.retryAt[vs.Name]) {
+			continue
+		}
+		reason, ok := needsCertificate(secrets[vs.SslConfig.SecretRef], domains, m.opts.RenewBefore, now)
+		if !ok {
+			delete(m.errs, vs.Name)
+			continue
+		}
+		logger.With(log.Resource("virtualservice/"+vs.Name)).Printf("issuing a certificate for %v: %v",
+			strings.Join(domains, ", "), reason)
+		m.issuing[vs.Name] = true
+		go m.issue(vs.Name, vs.SslConfig.SecretRef, domains)
+	}
+
+	// forget virtual services that were deleted or no longer use auto_certificate
+	for name := range m.errs {
+		if !autoCertificates[name] {
+			delete(m.errs, name)
+		}
+	}
+	for name := range m.retryAt {
+		if !autoCertificates[name] {
+			delete(m.retryAt, name)
+		}
+	}
+}
+
+// certificateDomains returns the domains of the virtual service to issue a certificate for, without their port
+func certificateDomains(vs *v1.VirtualService) ([]string, error) {
+	var domains []string
+	seen := make(map[string]bool)
+	for _, domain := range vs.Domains {
+		domain = strings.ToLower(domain)
+		if host, _, err := net.SplitHostPort(domain); err == nil {
+			domain = host
+		}
+		if domain == "" || strings.Contains(domain, "*") {
+			return nil, errors.Errorf("can not issue a certificate for the domain %q: "+
+				"wildcard domains can not be validated with HTTP-01", domain)
+		}
+		if seen[domain] {
+			continue
+		}
+		seen[domain] = true
+		domains = append(domains, domain)
+	}
+	if len(domains) == 0 {
+		return nil, errors.New("can not issue a certificate for a virtual service without domains")
+	}
+	return domains, nil
+}
+
+// needsCertificate returns why a certificate should be issued, if the certificate in the secret
+// can not be served for the domains until the renewal window
+func needsCertificate(secret *dependencies.Secret, domains []string, renewBefore time.Duration, now time.Time) (string, bool) {
+	if secret == nil {
+		return "the secret does not exist", true
+	}
+	certChain, _, _, err := secretwatcher.TlsCertificate(secret)
+	if err != nil {
+		return err.Error(), true
+	}
+	cert, err := secretwatcher.LeafCertificate(certChain)
+	if err != nil {
+		return "invalid certificate: " + err.Error(), true
+	}
+	if uncovered := secretwatcher.UncoveredDomains(domains, cert); len(uncovered) > 0 {
+		return "the certificate is not valid for " + strings.Join(uncovered, ", "), true
+	}
+	if cert.NotAfter.Sub(now) < renewBefore {
+		return "the certificate expires at " + cert.NotAfter.UTC().Format(time.RFC3339), true
+	}
+	return "", false
+}
+
+func (m *Manager) issue(virtualService, secretRef string, domains []string) {
+	m.issueSlots <- struct{}{}
+	defer func() { <-m.issueSlots }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), issueTimeout)
+	defer cancel()
+	err := m.issueCertificate(ctx, virtualService, secretRef, domains)
+
+	vsLogger := logger.With(log.Resource("virtualservice/" + virtualService))
+	m.lock.Lock()
+	delete(m.issuing, virtualService)
+	m.retryAt[virtualService] = m.now().Add(resyncInterval)
+	if err != nil {
+		err = errors.Wrapf(err, "issuing a certificate for %v", strings.Join(domains, ", "))
+		vsLogger.Warnf("%v", err)
+		m.errs[virtualService] = err
+	} else {
+		vsLogger.Printf("stored the certificate for %v in secret %v", strings.Join(domains, ", "), secretRef)
+		delete(m.errs, virtualService)
+	}
+	m.lock.Unlock()
+	m.signalUpdate()
+}
+
+// issueCertificate orders a certificate for the domains, answers the challenges of the order,
+// and stores the certificate chain and a new private key in the secret
+func (m *Manager) issueCertificate(ctx context.Context, virtualService, secretRef string, domains []string) error {
+	client, err := m.acmeClient(ctx)
+	if err != nil {
+		return err
+	}
+	order, err := client.AuthorizeOrder(ctx, acme.DomainIDs(domains...))
+	if err != nil {
+		return errors.Wrap(err, "creating order")
+	}
+
+	// authorizations may still be valid from a previous order
+	var pending []*acme.Authorization
+	for _, url := range order.AuthzURLs {
+		authz, err := client.GetAuthorization(ctx, url)
+		if err != nil {
+			return errors.Wrap(err, "getting authorization")
+		}
+		if authz.Status != acme.StatusValid {
+			pending = append(pending, authz)
+		}
+	}
+
+	var challenges []*acme.Challenge
+	defer func() { m.removeChallenges(challenges) }()
+	for _, authz := range pending {
+		challenge := http01Challenge(authz)
+		if challenge == nil {
+			return errors.Errorf("the acme server offered no http-01 challenge for %v", authz.Identifier.Value)
+		}
+		keyAuth, err := client.HTTP01ChallengeResponse(challenge.Token)
+		if err != nil {
+			return err
+		}
+		m.addChallenge(Challenge{
+			VirtualService:   virtualService,
+			Domain:           authz.Identifier.Value,
+			Token:            challenge.Token,
+			KeyAuthorization: keyAuth,
+		})
+		challenges = append(challenges, challenge)
+	}
+	if len(challenges) > 0 {
+		m.signalUpdate()
+		// give envoy time to receive the challenge routes
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(m.opts.PropagationDelay):
+		}
+	}
+	for i, challenge := range challenges {
+		if _, err := client.Accept(ctx, challenge); err != nil {
+			return errors.Wrapf(err, "accepting challenge for %v", pending[i].Identifier.Value)
+		}
+	}
+	for _, authz := range pending {
+		if _, err := client.WaitAuthorization(ctx, authz.URI); err != nil {
+			return errors.Wrapf(err, "validating %v", authz.Identifier.Value)
+		}
+	}
+
+	// orders fetched from the server do not carry their url
+	orderURL := order.URI
+	order, err = client.WaitOrder(ctx, orderURL)
+	if err != nil {
+		return errors.Wrap(err, "waiting for order")
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return errors.Wrap(err, "generating private key")
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: domains[0]},
+		DNSNames: domains,
+	}, key)
+	if err != nil {
+		return errors.Wrap(err, "creating certificate request")
+	}
+	chain, _, err := client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		// servers that issue the certificate asynchronously, like pebble, may not return the url of the order
+		// from the finalize request, which CreateOrderCert needs to wait for the certificate
+		finalized, waitErr := client.WaitOrder(ctx, orderURL)
+		if waitErr != nil || finalized.Status != acme.StatusValid {
+			return errors.Wrap(err, "finalizing order")
+		}
+		chain, err = client.FetchCert(ctx, finalized.CertURL, true)
+		if err != nil {
+			return errors.Wrap(err, "fetching certificate")
+		}
+	}
+
+	var certPem []byte
+	for _, der := range chain {
+		certPem = append(certPem, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	keyPem, err := encodeKey(key)
+	if err != nil {
+		return err
+	}
+	return m.writeSecret(secretRef, map[string]string{
+		secretwatcher.KubeTlsCertKey: string(certPem),
+		secretwatcher.KubeTlsKeyKey:  string(keyPem),
+	})
+}
+
+func http01Challenge(authz *acme.Authorization) *acme.Challenge {
+	for _, challenge := range authz.Challenges {
+		if challenge.Type == "http-01" {
+			return challenge
+		}
+	}
+	return nil
+}
+
+// acmeClient returns the client of the account, registering the account the first time it is used
+func (m *Manager) acmeClient(ctx context.Context) (*acme.Client, error) {
+	m.clientLock.Lock()
+	defer m.clientLock.Unlock()
+	if m.client != nil {
+		return m.client, nil
+	}
+	key, err := m.accountKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "loading acme account key")
+	}
+	client := &acme.Client{
+		Key:          key,
+		DirectoryURL: m.opts.DirectoryURL,
+		HTTPClient:   m.httpClient,
+		UserAgent:    "gloo",
+	}
+	account := &acme.Account{}
+	if m.opts.Email != "" {
+		account.Contact = []string{"mailto:" + m.opts.Email}
+	}
+	if _, err := client.Register(ctx, account, acme.AcceptTOS); err != nil && err != acme.ErrAccountAlreadyExists {
+		return nil, errors.Wrap(err, "registering acme account")
+	}
+	m.client = client
+	return client, nil
+}
+
+// accountKey reads the account key from its secret, creating a new key the first time
+func (m *Manager) accountKey() (crypto.Signer, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	keyPem, err := encodeKey(key)
+	if err != nil {
+		return nil, err
+	}
+	_, err = m.secrets.Create(&dependencies.Secret{
+		Ref:  m.opts.AccountSecretRef,
+		Data: map[string]string{secretwatcher.GlooTlsPrivateKeyKey: string(keyPem)},
+	})
+	if err == nil {
+		return key, nil
+	}
+	if !storage.IsAlreadyExists(err) {
+		return nil, err
+	}
+	existing, err := m.secrets.Get(m.opts.AccountSecretRef)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode([]byte(existing.Data[secretwatcher.GlooTlsPrivateKeyKey]))
+	if block == nil {
+		return nil, errors.Errorf("secret %v has no PEM encoded %v", m.opts.AccountSecretRef, secretwatcher.GlooTlsPrivateKeyKey)
+	}
+	return x509.ParseECPrivateKey(block.Bytes)
+}
+
+func encodeKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding private key")
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
+
+// writeSecret creates the secret, or replaces the data of the existing secret
+func (m *Manager) writeSecret(ref string, data map[string]string) error {
+	_, err := m.secrets.Create(&dependencies.Secret{Ref: ref, Data: data})
+	if err == nil || !storage.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "writing secret %v", ref)
+	}
+	existing, err := m.secrets.Get(ref)
+	if err != nil {
+		return errors.Wrapf(err, "reading secret %v", ref)
+	}
+	existing.Data = data
+	_, err = m.secrets.Update(existing)
+	return errors.Wrapf(err, "writing secret %v", ref)
+}
+
+func (m *Manager) addChallenge(challenge Challenge) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.challenges[challenge.Token] = challenge
+}
+
+func (m *Manager) removeChallenges(challenges []*acme.Challenge) {
+	if len(challenges) == 0 {
+		return
+	}
+	m.lock.Lock()
+	for _, challenge := range challenges {
+		delete(m.challenges, challenge.Token)
+	}
+	m.lock.Unlock()
+	m.signalUpdate()
+}
+
+func (m *Manager) signalUpdate() {
+	select {
+	case m.updates <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,242 @@
+package acme
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo/internal/control-plane/bootstrap"
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/secretwatcher"
+	"github.com/solo-io/gloo/pkg/storage/dependencies"
+	"github.com/solo-io/gloo/pkg/storage/dependencies/memory"
+	"github.com/solo-io/gloo/test/helpers"
+	"github.com/solo-io/gloo/test/helpers/local"
+)
+
+var _ = Describe("Manager", func() {
+	autoCertificateVirtualService := func(name string, domains ...string) *v1.VirtualService {
+		return &v1.VirtualService{
+			Name:      name,
+			Domains:   domains,
+			SslConfig: &v1.SSLConfig{SecretRef: name + "-tls", AutoCertificate: true},
+		}
+	}
+
+	Describe("certificateDomains", func() {
+		It("issues for the hosts of the domains", func() {
+			domains, err := certificateDomains(autoCertificateVirtualService("petstore", "Petstore.com", "petstore.com:8080", "api.petstore.com"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(domains).To(Equal([]string{"petstore.com", "api.petstore.com"}))
+		})
+		It("can not issue for wildcard domains", func() {
+			_, err := certificateDomains(autoCertificateVirtualService("petstore", "petstore.com", "*.petstore.com"))
+			Expect(err).To(MatchError(ContainSubstring("wildcard domains can not be validated with HTTP-01")))
+			_, err = certificateDomains(autoCertificateVirtualService("default", "*"))
+			Expect(err).To(HaveOccurred())
+		})
+		It("can not issue without domains", func() {
+			_, err := certificateDomains(autoCertificateVirtualService("default"))
+			Expect(err).To(MatchError("can not issue a certificate for a virtual service without domains"))
+		})
+	})
+
+	Describe("needsCertificate", func() {
+		now := time.Now()
+		tlsSecret := func(dnsNames []string, notAfter time.Time) *dependencies.Secret {
+			cert, err := helpers.NewTestCert(dnsNames[0], dnsNames, notAfter, nil)
+			Expect(err).NotTo(HaveOccurred())
+			return &dependencies.Secret{Ref: "petstore-tls", Data: map[string]string{
+				secretwatcher.KubeTlsCertKey: cert.CertPEM,
+				secretwatcher.KubeTlsKeyKey:  cert.KeyPEM,
+			}}
+		}
+		It("issues a certificate when the secret does not exist", func() {
+			_, ok := needsCertificate(nil, []string{"petstore.com"}, time.Hour, now)
+			Expect(ok).To(BeTrue())
+		})
+		It("issues a certificate when the secret has no valid certificate", func() {
+			reason, ok := needsCertificate(&dependencies.Secret{Ref: "petstore-tls", Data: map[string]string{
+				secretwatcher.KubeTlsCertKey: "garbage",
+				secretwatcher.KubeTlsKeyKey:  "garbage",
+			}}, []string{"petstore.com"}, time.Hour, now)
+			Expect(ok).To(BeTrue())
+			Expect(reason).To(ContainSubstring("invalid certificate"))
+		})
+		It("issues a certificate when the certificate does not cover a domain", func() {
+			reason, ok := needsCertificate(tlsSecret([]string{"petstore.com"}, now.Add(90*24*time.Hour)),
+				[]string{"petstore.com", "api.petstore.com"}, time.Hour, now)
+			Expect(ok).To(BeTrue())
+			Expect(reason).To(Equal("the certificate is not valid for api.petstore.com"))
+		})
+		It("renews certificates that expire within the renewal window", func() {
+			secret := tlsSecret([]string{"petstore.com"}, now.Add(20*24*time.Hour))
+			_, ok := needsCertificate(secret, []string{"petstore.com"}, 30*24*time.Hour, now)
+			Expect(ok).To(BeTrue())
+			_, ok = needsCertificate(secret, []string{"petstore.com"}, 10*24*time.Hour, now)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("Sync", func() {
+		It("reports that certificates can not be issued when acme is not configured", func() {
+			manager, err := NewManager(bootstrap.AcmeOptions{}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			manager.Sync(&v1.Config{VirtualServices: []*v1.VirtualService{
+				autoCertificateVirtualService("petstore", "petstore.com"),
+				{Name: "plain", Domains: []string{"plain.com"}},
+			}}, nil)
+			Expect(manager.Error("petstore")).To(MatchError(ContainSubstring("acme is not configured")))
+			Expect(manager.Error("plain")).NotTo(HaveOccurred())
+
+			// the error is forgotten once the virtual service is deleted
+			manager.Sync(&v1.Config{}, nil)
+			Expect(manager.Error("petstore")).NotTo(HaveOccurred())
+		})
+		It("reports virtual services it can not issue a certificate for", func() {
+			manager, err := NewManager(bootstrap.AcmeOptions{DirectoryURL: "https://acme.example.com/directory"}, memory.NewSecretStorage())
+			Expect(err).NotTo(HaveOccurred())
+			manager.Sync(&v1.Config{VirtualServices: []*v1.VirtualService{
+				autoCertificateVirtualService("wildcard", "*.petstore.com"),
+			}}, nil)
+			Expect(manager.Error("wildcard")).To(MatchError(ContainSubstring("wildcard domains")))
+			Expect(manager.Challenges()).To(BeEmpty())
+		})
+	})
+
+	Describe("ForVirtualServices", func() {
+		It("returns the challenges of the virtual services sorted by token", func() {
+			challenges := []Challenge{
+				{VirtualService: "b", Domain: "b.com", Token: "2"},
+				{VirtualService: "c", Domain: "c.com", Token: "3"},
+				{VirtualService: "a", Domain: "a.com", Token: "1"},
+			}
+			filtered := ForVirtualServices(challenges, []*v1.VirtualService{{Name: "b"}, {Name: "a"}})
+			Expect(filtered).To(Equal([]Challenge{
+				{VirtualService: "a", Domain: "a.com", Token: "1"},
+				{VirtualService: "b", Domain: "b.com", Token: "2"},
+			}))
+			Expect(filtered[0].Path()).To(Equal("/.well-known/acme-challenge/1"))
+		})
+	})
+
+	Describe("issuing certificates from pebble", func() {
+		// pebble validates challenges on this port. the test serves the challenges of the manager
+		// on it, like envoy does on its http listener
+		const challengePort = 5002
+
+		var (
+			pebbleFactory  *localhelpers.PebbleFactory
+			pebbleInstance *localhelpers.PebbleInstance
+			challengeSrv   *http.Server
+			secrets        dependencies.SecretStorage
+			manager        *Manager
+		)
+		BeforeEach(func() {
+			if os.Getenv("RUN_PEBBLE_TESTS") != "1" {
+				Skip("This test downloads and runs pebble and is disabled by default. To enable, set RUN_PEBBLE_TESTS=1 in your env.")
+			}
+			var err error
+			pebbleFactory, err = localhelpers.NewPebbleFactory()
+			Expect(err).NotTo(HaveOccurred())
+			pebbleInstance, err = pebbleFactory.NewPebbleInstance(challengePort)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pebbleInstance.Run()).To(Succeed())
+
+			secrets = memory.NewSecretStorage()
+			manager, err = NewManager(bootstrap.AcmeOptions{
+				DirectoryURL:     localhelpers.PebbleDirectoryURL,
+				Email:            "admin@example.com",
+				AccountSecretRef: "acme-account",
+				RenewBefore:      30 * 24 * time.Hour,
+				CAFile:           pebbleInstance.CAFile(),
+			}, secrets)
+			Expect(err).NotTo(HaveOccurred())
+
+			challengeSrv = &http.Server{
+				Addr: fmt.Sprintf(":%v", challengePort),
+				Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					for _, challenge := range manager.Challenges() {
+						if r.URL.Path == challenge.Path() {
+							w.Write([]byte(challenge.KeyAuthorization))
+							return
+						}
+					}
+					http.NotFound(w, r)
+				}),
+			}
+			go challengeSrv.ListenAndServe()
+		})
+		AfterEach(func() {
+			if challengeSrv != nil {
+				challengeSrv.Close()
+			}
+			if pebbleInstance != nil {
+				pebbleInstance.Clean()
+			}
+			pebbleFactory.Clean()
+		})
+
+		issuedCertificate := func(ref string) func() string {
+			return func() string {
+				secret, err := secrets.Get(ref)
+				if err != nil {
+					return ""
+				}
+				return secret.Data[secretwatcher.KubeTlsCertKey]
+			}
+		}
+		issuing := func() bool {
+			manager.lock.Lock()
+			defer manager.lock.Unlock()
+			return manager.issuing["local"]
+		}
+		secretMap := func() secretwatcher.SecretMap {
+			list, err := secrets.List()
+			Expect(err).NotTo(HaveOccurred())
+			secretMap := make(secretwatcher.SecretMap)
+			for _, secret := range list {
+				secretMap[secret.Ref] = secret
+			}
+			return secretMap
+		}
+
+		It("issues a certificate for the domains of the virtual service, and renews it", func() {
+			cfg := &v1.Config{VirtualServices: []*v1.VirtualService{autoCertificateVirtualService("local", "localhost")}}
+			manager.Sync(cfg, secretMap())
+			Eventually(issuing, 30*time.Second, 250*time.Millisecond).Should(BeFalse())
+			Expect(manager.Error("local")).NotTo(HaveOccurred())
+			Expect(manager.Challenges()).To(BeEmpty())
+
+			issued := issuedCertificate("local-tls")()
+			cert, err := secretwatcher.LeafCertificate(issued)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cert.DNSNames).To(Equal([]string{"localhost"}))
+
+			// the certificate is valid, so it is not issued again
+			manager.Sync(cfg, secretMap())
+			Consistently(issuedCertificate("local-tls"), time.Second).Should(Equal(issued))
+
+			// renew once the certificate is within the renewal window
+			manager.lock.Lock()
+			manager.now = func() time.Time { return cert.NotAfter.Add(-24 * time.Hour) }
+			manager.lock.Unlock()
+			manager.Sync(cfg, secretMap())
+			Eventually(issuing, 30*time.Second, 250*time.Millisecond).Should(BeFalse())
+			Expect(manager.Error("local")).NotTo(HaveOccurred())
+			renewed, err := secretwatcher.LeafCertificate(issuedCertificate("local-tls")())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(renewed.SerialNumber).NotTo(Equal(cert.SerialNumber))
+			Expect(renewed.DNSNames).To(Equal([]string{"localhost"}))
+
+			// the account key is stored for the next start of gloo
+			account, err := secrets.Get("acme-account")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(account.Data).To(HaveKey(secretwatcher.GlooTlsPrivateKeyKey))
+		})
+	})
+})
@@ -0,0 +1,15 @@
+package admin_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/solo-io/gloo/pkg/log"
+)
+
+func TestAdmin(t *testing.T) {
+	RegisterFailHandler(Fail)
+	log.DefaultOut = GinkgoWriter
+	RunSpecs(t, "Admin Suite")
+}
@@ -0,0 +1,318 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+
+	envoycache "github.com/envoyproxy/go-control-plane/pkg/cache"
+	"github.com/ghodss/yaml"
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/internal/control-plane/bootstrap"
+	"github.com/solo-io/gloo/internal/control-plane/reporter"
+	"github.com/solo-io/gloo/internal/control-plane/snapshot"
+	"github.com/solo-io/gloo/internal/control-plane/translator"
+	"github.com/solo-io/gloo/internal/control-plane/xds"
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/endpointdiscovery"
+	"github.com/solo-io/gloo/pkg/log"
+	"github.com/solo-io/gloo/pkg/protoutil"
+)
+
+const (
+	pathInputs  = "/inputs"
+	pathXds     = "/xds"
+	pathReports = "/reports"
+	pathPlugins = "/plugins"
+	pathNodes   = "/nodes"
+	pathLogging = "/logging"
+)
+
+// Source provides the state of the control plane event loop
+type Source interface {
+	// Inputs returns the most recent snapshot received by the event loop
+	Inputs() *snapshot.Cache
+	// XdsSnapshots returns the xds snapshot currently served to each role
+	XdsSnapshots() map[string]*envoycache.Snapshot
+	// Reports returns the most recently written report for each config object
+	Reports() []reporter.ConfigObjectReport
+}
+
+// Server serves the state of the control plane over http for debugging.
+// every endpoint returns json, or yaml if the format=yaml query parameter is set
+type Server struct {
+	source  Source
+	nodes   *xds.NodeTracker
+	plugins func() []translator.PluginInfo
+}
+
+func NewServer(source Source, nodes *xds.NodeTracker, plugins func() []translator.PluginInfo) *Server {
+	return &Server{
+		source:  source,
+		nodes:   nodes,
+		plugins: plugins,
+	}
+}
+
+// Handler returns the http handler for the admin api
+func (s *Server) Handler() http.Handler {
+	m := http.NewServeMux()
+	m.HandleFunc("/", s.indexHandler)
+	m.HandleFunc(pathInputs, s.inputsHandler)
+	m.HandleFunc(pathXds, s.xdsHandler)
+	m.HandleFunc(pathXds+"/", s.xdsHandler)
+	m.HandleFunc(pathReports, s.reportsHandler)
+	m.HandleFunc(pathPlugins, s.pluginsHandler)
+	m.HandleFunc(pathNodes, s.nodesHandler)
+	m.Handle(pathLogging, log.LevelHandler())
+	return m
+}
+
+// Run serves the admin api until stop is closed
+func (s *Server) Run(opts bootstrap.AdminOptions, stop <-chan struct{}) error {
+	addr := fmt.Sprintf("%v:%v", opts.BindAddress, opts.Port)
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen on %v", addr)
+	}
+	server := &http.Server{Handler: s.Handler()}
+	go func() {
+		<-stop
+		server.Shutdown(context.Background())
+	}()
+	log.Printf("admin api listening on %v", addr)
+	if err := server.Serve(lis); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) indexHandler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	render(w, r, map[string]string{
+		pathInputs:          "the config, secrets, files and endpoints most recently received by the control plane",
+		pathXds:             "the xds snapshot served to each role",
+		pathXds + "/<role>": "the xds snapshot served to a single role",
+		pathReports:         "the status most recently reported for each config object",
+		pathPlugins:         "the translator plugins and the http filters they added, in the order they are run",
+		pathNodes:           "the envoy nodes currently connected, with their roles and acked versions",
+		pathLogging:         "the current log level. PUT {\"level\": \"debug\"} to change it",
+		"?format=yaml":      "render any endpoint as yaml instead of json",
+	})
+}
+
+type inputs struct {
+	Config    json.RawMessage                  `json:"config"`
+	Secrets   map[string]secret                `json:"secrets"`
+	Files     map[string]file                  `json:"files"`
+	Endpoints endpointdiscovery.EndpointGroups `json:"endpoints"`
+}
+
+// secret values are never exposed
+type secret struct {
+	ResourceVersion string   `json:"resource_version,omitempty"`
+	Keys            []string `json:"keys"`
+}
+
+type file struct {
+	ResourceVersion string `json:"resource_version,omitempty"`
+	Size            int    `json:"size"`
+}
+
+func (s *Server) inputsHandler(w http.ResponseWriter, r *http.Request) {
+	snap := s.source.Inputs()
+	if snap == nil {
+		http.Error(w, "the control plane has not received any config yet", http.StatusServiceUnavailable)
+		return
+	}
+	out := inputs{
+		Secrets:   make(map[string]secret),
+		Files:     make(map[string]file),
+		Endpoints: snap.Endpoints,
+	}
+	if snap.Cfg != nil {
+		cfg, err := protoJSON(snap.Cfg)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		out.Config = cfg
+	}
+	for ref, sec := range snap.Secrets {
+		var keys []string
+		for key := range sec.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		out.Secrets[ref] = secret{ResourceVersion: sec.ResourceVersion, Keys: keys}
+	}
+	for ref, f := range snap.Files {
+		out.Files[ref] = file{ResourceVersion: f.ResourceVersion, Size: len(f.Contents)}
+	}
+	render(w, r, out)
+}
+
+func (s *Server) xdsHandler(w http.ResponseWriter, r *http.Request) {
+	snapshots := s.source.XdsSnapshots()
+	role := strings.Trim(strings.TrimPrefix(r.URL.Path, pathXds), "/")
+	if role == "" {
+		out := make(map[string]xds.RenderedSnapshot)
+		for role, snap := range snapshots {
+			converted, err := xds.RenderSnapshot(snap)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			out[role] = converted
+		}
+		render(w, r, out)
+		return
+	}
+	snap, ok := snapshots[role]
+	if !ok {
+		http.Error(w, fmt.Sprintf("no snapshot is being served for role %v", role), http.StatusNotFound)
+		return
+	}
+	out, err := xds.RenderSnapshot(snap)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	render(w, r, out)
+}
+
+type report struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	State string `json:"state"`
+	Error string `json:"error,omitempty"`
+
+	Warnings      []string `json:"warnings,omitempty"`
+	AcceptedRoles []string `json:"accepted_roles,omitempty"`
+}
+
+func (s *Server) reportsHandler(w http.ResponseWriter, r *http.Request) {
+	out := []report{}
+	for _, rep := range s.source.Reports() {
+		out = append(out, convertReport(rep))
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Type != out[j].Type {
+			return out[i].Type < out[j].Type
+		}
+		return out[i].Name < out[j].Name
+	})
+	render(w, r, out)
+}
+
+func convertReport(rep reporter.ConfigObjectReport) report {
+	out := report{
+		Name:          rep.CfgObject.GetName(),
+		State:         v1.Status_Accepted.String(),
+		AcceptedRoles: rep.AcceptedRoles,
+	}
+	for _, warning := range rep.Warnings {
+		out.Warnings = append(out.Warnings, warning.Error())
+	}
+	switch rep.CfgObject.(type) {
+	case *v1.Upstream:
+		out.Type = "upstream"
+	case *v1.VirtualService:
+		out.Type = "virtualservice"
+	case *v1.Role:
+		out.Type = "role"
+	}
+	if rep.Err != nil {
+		out.Error = rep.Err.Error()
+		out.State = v1.Status_Rejected.String()
+		if rep.PartiallyApplied {
+			out.State = v1.Status_PartiallyApplied.String()
+		}
+	}
+	return out
+}
+
+func (s *Server) pluginsHandler(w http.ResponseWriter, r *http.Request) {
+	render(w, r, s.plugins())
+}
+
+type node struct {
+	ID   string `json:"id"`
+	Role string `json:"role"`
+	// acked versions, by type url
+	AckedVersions map[string]string `json:"acked_versions"`
+	Nacks         []nack            `json:"nacks,omitempty"`
+}
+
+type nack struct {
+	TypeUrl string `json:"type_url"`
+	Version string `json:"version"`
+	Message string `json:"message"`
+}
+
+func (s *Server) nodesHandler(w http.ResponseWriter, r *http.Request) {
+	out := []node{}
+	for _, status := range s.nodes.Nodes() {
+		n := node{
+			ID:            status.NodeID,
+			Role:          status.Role,
+			AckedVersions: status.AckedVersions,
+		}
+		for _, typeUrl := range sortedKeys(status.Nacks) {
+			rejected := status.Nacks[typeUrl]
+			n.Nacks = append(n.Nacks, nack{
+				TypeUrl: typeUrl,
+				Version: rejected.Version,
+				Message: rejected.Message,
+			})
+		}
+		out = append(out, n)
+	}
+	render(w, r, out)
+}
+
+func sortedKeys(nacks map[string]xds.Nack) []string {
+	var keys []string
+	for key := range nacks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// protos must be marshalled with jsonpb to render oneofs and well known types correctly
+func protoJSON(pb proto.Message) (json.RawMessage, error) {
+	data, err := protoutil.Marshal(pb)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(data), nil
+}
+
+func render(w http.ResponseWriter, r *http.Request, v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	contentType := "application/json"
+	if r.URL.Query().Get("format") == "yaml" {
+		data, err = yaml.JSONToYAML(data)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		contentType = "application/x-yaml"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Write(data)
+}
@@ -0,0 +1,146 @@
+package admin_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	envoyapi "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	"github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	envoycache "github.com/envoyproxy/go-control-plane/pkg/cache"
+	"github.com/ghodss/yaml"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	. "github.com/solo-io/gloo/internal/control-plane/admin"
+	"github.com/solo-io/gloo/internal/control-plane/bootstrap"
+	"github.com/solo-io/gloo/internal/control-plane/reporter"
+	"github.com/solo-io/gloo/internal/control-plane/snapshot"
+	"github.com/solo-io/gloo/internal/control-plane/translator"
+	"github.com/solo-io/gloo/internal/control-plane/xds"
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/secretwatcher"
+	"github.com/solo-io/gloo/pkg/storage/dependencies"
+	"github.com/solo-io/gloo/test/helpers"
+)
+
+type fakeSource struct {
+	inputs    *snapshot.Cache
+	snapshots map[string]*envoycache.Snapshot
+	reports   []reporter.ConfigObjectReport
+}
+
+func (s *fakeSource) Inputs() *snapshot.Cache                       { return s.inputs }
+func (s *fakeSource) XdsSnapshots() map[string]*envoycache.Snapshot { return s.snapshots }
+func (s *fakeSource) Reports() []reporter.ConfigObjectReport        { return s.reports }
+
+var _ = Describe("Server", func() {
+	var (
+		source  *fakeSource
+		tracker *xds.NodeTracker
+		server  *httptest.Server
+	)
+	BeforeEach(func() {
+		cfg := helpers.NewTestConfig()
+		source = &fakeSource{
+			inputs: &snapshot.Cache{
+				Cfg: cfg,
+				Secrets: secretwatcher.SecretMap{
+					"ssl": &dependencies.Secret{Ref: "ssl", Data: map[string]string{"private_key": "supersecret"}},
+				},
+			},
+			snapshots: map[string]*envoycache.Snapshot{
+				"ingress": {
+					Clusters: envoycache.NewResources("1", []envoycache.Resource{&envoyapi.Cluster{Name: "my-upstream"}}),
+				},
+			},
+			reports: []reporter.ConfigObjectReport{
+				{CfgObject: cfg.VirtualServices[0], Err: errors.New("bad route")},
+				{CfgObject: cfg.Upstreams[0]},
+			},
+		}
+		roles, err := xds.NewRoleAssigner(bootstrap.XdsOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		tracker = xds.NewNodeTracker(roles)
+		plugins := func() []translator.PluginInfo {
+			return []translator.PluginInfo{{
+				Name:    "aws.Plugin",
+				Kinds:   []string{"UpstreamPlugin", "FilterPlugin"},
+				Filters: []translator.FilterInfo{{Name: "io.solo.aws", Stage: "OutAuth"}},
+			}}
+		}
+		server = httptest.NewServer(NewServer(source, tracker, plugins).Handler())
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+	get := func(path string) (int, []byte) {
+		res, err := http.Get(server.URL + path)
+		Expect(err).NotTo(HaveOccurred())
+		defer res.Body.Close()
+		body, err := ioutil.ReadAll(res.Body)
+		Expect(err).NotTo(HaveOccurred())
+		return res.StatusCode, body
+	}
+	getJSON := func(path string, into interface{}) {
+		code, body := get(path)
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(json.Unmarshal(body, into)).NotTo(HaveOccurred())
+	}
+	It("serves the inputs without secret values", func() {
+		code, body := get("/inputs")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(string(body)).To(ContainSubstring("private_key"))
+		Expect(string(body)).NotTo(ContainSubstring("supersecret"))
+		var inputs map[string]interface{}
+		Expect(json.Unmarshal(body, &inputs)).NotTo(HaveOccurred())
+		Expect(inputs).To(HaveKey("config"))
+	})
+	It("serves the xds snapshot for a role", func() {
+		var snap map[string]interface{}
+		getJSON("/xds/ingress", &snap)
+		Expect(snap["versions"]).To(HaveKeyWithValue(envoycache.ClusterType, "1"))
+		Expect(snap["clusters"]).To(HaveLen(1))
+	})
+	It("returns not found for unknown roles", func() {
+		code, _ := get("/xds/nope")
+		Expect(code).To(Equal(http.StatusNotFound))
+	})
+	It("serves yaml", func() {
+		code, body := get("/xds?format=yaml")
+		Expect(code).To(Equal(http.StatusOK))
+		var snaps map[string]interface{}
+		Expect(yaml.Unmarshal(body, &snaps)).NotTo(HaveOccurred())
+		Expect(snaps).To(HaveKey("ingress"))
+	})
+	It("serves the reports sorted by type and name", func() {
+		var reports []map[string]string
+		getJSON("/reports", &reports)
+		Expect(reports).To(HaveLen(2))
+		Expect(reports[0]["type"]).To(Equal("upstream"))
+		Expect(reports[0]["state"]).To(Equal(v1.Status_Accepted.String()))
+		Expect(reports[1]["type"]).To(Equal("virtualservice"))
+		Expect(reports[1]["state"]).To(Equal(v1.Status_Rejected.String()))
+		Expect(reports[1]["error"]).To(Equal("bad route"))
+	})
+	It("serves the plugins", func() {
+		var plugins []translator.PluginInfo
+		getJSON("/plugins", &plugins)
+		Expect(plugins).To(HaveLen(1))
+		Expect(plugins[0].Filters[0].Stage).To(Equal("OutAuth"))
+	})
+	It("serves the connected nodes", func() {
+		tracker.OnStreamOpen(1, envoycache.ClusterType)
+		tracker.OnStreamRequest(1, &envoyapi.DiscoveryRequest{Node: &core.Node{Id: "ingress~1"}, TypeUrl: envoycache.ClusterType})
+		tracker.OnStreamResponse(1, nil, &envoyapi.DiscoveryResponse{TypeUrl: envoycache.ClusterType, VersionInfo: "1", Nonce: "a"})
+		tracker.OnStreamRequest(1, &envoyapi.DiscoveryRequest{TypeUrl: envoycache.ClusterType, VersionInfo: "1", ResponseNonce: "a"})
+		var nodes []map[string]interface{}
+		getJSON("/nodes", &nodes)
+		Expect(nodes).To(HaveLen(1))
+		Expect(nodes[0]["id"]).To(Equal("ingress~1"))
+		Expect(nodes[0]["role"]).To(Equal("ingress"))
+		Expect(nodes[0]["acked_versions"]).To(HaveKeyWithValue(envoycache.ClusterType, "1"))
+	})
+})
@@ -0,0 +1,23 @@
+package flags
+
+import (
+	"time"
+
+	"github.com/solo-io/gloo/internal/control-plane/bootstrap"
+	"github.com/spf13/cobra"
+)
+
+func AddAcmeFlags(cmd *cobra.Command, opts *bootstrap.Options) {
+	cmd.PersistentFlags().StringVar(&opts.AcmeOptions.DirectoryURL, "acme.directory-url", "", "directory url of the ACME server to issue "+
+		"the certificates of virtual services with auto_certificate, e.g. https://acme-v02.api.letsencrypt.org/directory. "+
+		"if empty, certificates are not issued.")
+	cmd.PersistentFlags().StringVar(&opts.AcmeOptions.Email, "acme.email", "", "contact email of the ACME account.")
+	cmd.PersistentFlags().StringVar(&opts.AcmeOptions.AccountSecretRef, "acme.account-secret-ref", "gloo-acme-account",
+		"secret to store the private key of the ACME account in. it is created if it does not exist.")
+	cmd.PersistentFlags().DurationVar(&opts.AcmeOptions.RenewBefore, "acme.renew-before", 30*24*time.Hour,
+		"renew certificates this long before they expire.")
+	cmd.PersistentFlags().StringVar(&opts.AcmeOptions.CAFile, "acme.ca-file", "", "PEM bundle of CAs to trust for the ACME server, "+
+		"e.g. the CA of a local test server.")
+	cmd.PersistentFlags().DurationVar(&opts.AcmeOptions.PropagationDelay, "acme.propagation-delay", 5*time.Second,
+		"how long to wait for envoy to serve a HTTP-01 challenge before asking the ACME server to validate it.")
+}
@@ -0,0 +1,12 @@
+package flags
+
+import (
+	"github.com/solo-io/gloo/internal/control-plane/bootstrap"
+	"github.com/spf13/cobra"
+)
+
+func AddAdminFlags(cmd *cobra.Command, opts *bootstrap.Options) {
+	cmd.PersistentFlags().StringVar(&opts.AdminOptions.BindAddress, "admin.bind-address", "127.0.0.1", "bind address for the control plane admin api. "+
+		"the admin api exposes the full gloo config, so it is only served locally by default.")
+	cmd.PersistentFlags().IntVar(&opts.AdminOptions.Port, "admin.port", 9091, "port to serve the control plane admin api on. set to 0 to disable the admin api.")
+}
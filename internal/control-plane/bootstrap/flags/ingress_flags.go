@@ -1,6 +1,8 @@
 package flags
 
 import (
+	"time"
+
 	"github.com/solo-io/gloo/internal/control-plane/bootstrap"
 	"github.com/spf13/cobra"
 )
@@ -10,4 +12,6 @@ func AddIngressFlags(cmd *cobra.Command, opts *bootstrap.Options) {
 	cmd.PersistentFlags().StringVar(&opts.IngressOptions.BindAddress, "envoy.bind-adress", "::", "The address that the ingress envoy should bind to.")
 	cmd.PersistentFlags().Uint32Var(&opts.IngressOptions.Port, "envoy.port", 8080, "The HTTP port envoy uses.")
 	cmd.PersistentFlags().Uint32Var(&opts.IngressOptions.SecurePort, "envoy.secure-port", 8443, "The HTTPS port envoy uses.")
+	cmd.PersistentFlags().DurationVar(&opts.IngressOptions.CertExpiryWarning, "envoy.cert-expiry-warning", 30*24*time.Hour,
+		"Warn on the status of virtual services whose certificate expires within this duration. 0 disables the warning.")
 }
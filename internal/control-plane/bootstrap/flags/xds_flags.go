@@ -0,0 +1,21 @@
+package flags
+
+import (
+	"github.com/solo-io/gloo/internal/control-plane/bootstrap"
+	"github.com/spf13/cobra"
+)
+
+func AddXdsFlags(cmd *cobra.Command, opts *bootstrap.Options) {
+	cmd.PersistentFlags().StringArrayVar(&opts.XdsOptions.NodeRoles, "xds.node-role", nil, "assign a role to an envoy node by its id, in the format <node-id>=<role>. can be repeated.")
+	cmd.PersistentFlags().StringVar(&opts.XdsOptions.RoleMetadataKey, "xds.role-metadata-key", "role", "envoy node metadata field used to select the node's role.")
+	cmd.PersistentFlags().StringArrayVar(&opts.XdsOptions.RoleSelectors, "xds.role-selector", nil, "assign a role to envoy nodes with matching labels, in the format <role>:<key>=<value>,<key>=<value>. "+
+		"labels are read from the node metadata, and from the node's cluster, region, zone and sub_zone. can be repeated, the first matching selector wins.")
+	cmd.PersistentFlags().BoolVar(&opts.XdsOptions.RoleFromCluster, "xds.role-from-cluster", false, "use the envoy node's cluster as its role.")
+	cmd.PersistentFlags().StringVar(&opts.XdsOptions.FallbackRole, "xds.fallback-role", "", "role assigned to envoy nodes that match no other rule. if empty, such nodes receive an error response config.")
+	cmd.PersistentFlags().StringVar(&opts.XdsOptions.TLSSecretRef, "xds.tls-secret-ref", "", "secret containing the certificate chain (ca_chain), private key (private_key) and optionally "+
+		"the root ca used to verify clients (root_ca) for the xDS server. if empty, the xDS server will not use TLS.")
+	cmd.PersistentFlags().BoolVar(&opts.XdsOptions.RequireClientCert, "xds.require-client-cert", false, "require envoy to present a client certificate signed by the root_ca in the xDS tls secret.")
+	cmd.PersistentFlags().StringVar(&opts.XdsOptions.AuthSecretRef, "xds.auth-secret-ref", "", "secret mapping client identities (certificate common names and SANs, or token names) "+
+		"to the comma-separated roles they may subscribe to. use * to allow all roles. if empty, any client may subscribe to any role.")
+	cmd.PersistentFlags().StringVar(&opts.XdsOptions.TokenSecretRef, "xds.token-secret-ref", "", "secret mapping token names to bearer tokens envoy may send in the authorization header of its xDS requests.")
+}
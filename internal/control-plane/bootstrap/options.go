@@ -1,16 +1,75 @@
 package bootstrap
 
 import (
+	"time"
+
 	"github.com/solo-io/gloo/pkg/bootstrap"
 )
 
 type Options struct {
 	bootstrap.Options
 	IngressOptions IngressOptions
+	XdsOptions     XdsOptions
+	AdminOptions   AdminOptions
+	AcmeOptions    AcmeOptions
 }
 
 type IngressOptions struct {
 	BindAddress string
 	Port        uint32
 	SecurePort  uint32
+	// CertExpiryWarning is how long before their certificate expires virtual services are warned about it.
+	// 0 disables the warning
+	CertExpiryWarning time.Duration
+}
+
+// XdsOptions configure how envoy nodes are assigned to roles
+type XdsOptions struct {
+	// NodeRoles assign a role to a specific node, in the format <node-id>=<role>
+	NodeRoles []string
+	// RoleMetadataKey is the node metadata field containing the role
+	RoleMetadataKey string
+	// RoleSelectors assign a role to nodes whose labels match, in the format <role>:<key>=<value>,<key>=<value>
+	// labels are read from the node metadata, and from the node's cluster, region, zone and sub_zone
+	RoleSelectors []string
+	// RoleFromCluster uses the node's cluster as its role
+	RoleFromCluster bool
+	// FallbackRole is assigned to nodes that match no other rule
+	FallbackRole string
+	// TLSSecretRef is the secret containing the server certificate chain and private key for the xDS server.
+	// if empty, the xDS server does not use TLS
+	TLSSecretRef string
+	// RequireClientCert requires envoy to present a client certificate signed by the root_ca in the tls secret
+	RequireClientCert bool
+	// AuthSecretRef is the secret mapping client identities to the comma-separated roles they may subscribe to.
+	// identities are client certificate common names and SANs, or the names of tokens in the token secret.
+	// if empty, any client may subscribe to any role
+	AuthSecretRef string
+	// TokenSecretRef is the secret mapping identities to bearer tokens envoy may send
+	// in the authorization header of its xDS requests
+	TokenSecretRef string
+}
+
+// AdminOptions configure the admin api, which serves the state of the control plane for debugging
+type AdminOptions struct {
+	BindAddress string
+	// Port to serve the admin api on. 0 disables the admin api
+	Port int
+}
+
+// AcmeOptions configure the ACME client that issues the certificates of virtual services with auto_certificate
+type AcmeOptions struct {
+	// DirectoryURL is the directory of the ACME server, e.g. https://acme-v02.api.letsencrypt.org/directory.
+	// if empty, certificates are not issued
+	DirectoryURL string
+	// Email is the contact address of the ACME account
+	Email string
+	// AccountSecretRef is the secret the private key of the ACME account is stored in. it is created if it does not exist
+	AccountSecretRef string
+	// RenewBefore is how long before their certificate expires certificates are renewed
+	RenewBefore time.Duration
+	// CAFile is a PEM bundle of CAs to trust for the ACME server, in addition to the system roots
+	CAFile string
+	// PropagationDelay is how long to wait for envoy to serve a challenge before asking the ACME server to validate it
+	PropagationDelay time.Duration
 }
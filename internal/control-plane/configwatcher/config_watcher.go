@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/d4l3k/messagediff"
 	"github.com/pkg/errors"
@@ -14,6 +15,13 @@ import (
 	"github.com/gogo/protobuf/proto"
 )
 
+// storage.Apply writes upstreams before the virtual services that route to them, but the watches of each
+// kind may deliver the changes in any order. a config whose virtual services route to upstreams that are not
+// stored is held back until the upstreams arrive, for at most missingUpstreamsGracePeriod.
+// upstreams that were already missing from the last config sent are not waited for again, so a destination
+// that dangles for good does not delay every later change
+var missingUpstreamsGracePeriod = 5 * time.Second
+
 type configWatcher struct {
 	watchers []*storage.Watcher
 	configs  chan *v1.Config
@@ -52,7 +60,47 @@ func NewConfigWatcher(storageClient storage.Interface) (*configWatcher, error) {
 		configs <- cache
 	}()
 
+	var (
+		lock      sync.Mutex
+		heldSince time.Time
+		release   *time.Timer
+		// the upstreams missing from the last config sent
+		sentMissing = missingUpstreams(cache)
+	)
+	// send must be called with lock held
+	var send func(force bool)
+	send = func(force bool) {
+		allMissing := missingUpstreams(cache)
+		if missing := newlyMissing(allMissing, sentMissing); len(missing) > 0 {
+			if heldSince.IsZero() {
+				heldSince = time.Now()
+			}
+			if !force {
+				if release == nil {
+					log.Debugf("holding back config until upstreams %v are stored", missing)
+					release = time.AfterFunc(missingUpstreamsGracePeriod-time.Since(heldSince), func() {
+						lock.Lock()
+						defer lock.Unlock()
+						release = nil
+						send(true)
+					})
+				}
+				return
+			}
+			log.Warnf("virtual services route to upstreams %v which were not stored within %v", missing, missingUpstreamsGracePeriod)
+		}
+		heldSince = time.Time{}
+		if release != nil {
+			release.Stop()
+			release = nil
+		}
+		sentMissing = allMissing
+		configs <- proto.Clone(cache).(*v1.Config)
+	}
+
 	syncUpstreams := func(updatedList []*v1.Upstream, _ *v1.Upstream) {
+		lock.Lock()
+		defer lock.Unlock()
 		sort.SliceStable(updatedList, func(i, j int) bool {
 			return updatedList[i].GetName() < updatedList[j].GetName()
 		})
@@ -64,7 +112,7 @@ func NewConfigWatcher(storageClient storage.Interface) (*configWatcher, error) {
 		log.GreyPrintf("change detected in upstream: %v", diff)
 
 		cache.Upstreams = updatedList
-		configs <- proto.Clone(cache).(*v1.Config)
+		send(false)
 	}
 	upstreamWatcher, err := storageClient.V1().Upstreams().Watch(&storage.UpstreamEventHandlerFuncs{
 		AddFunc:    syncUpstreams,
@@ -76,6 +124,8 @@ func NewConfigWatcher(storageClient storage.Interface) (*configWatcher, error) {
 	}
 
 	syncvServices := func(updatedList []*v1.VirtualService, _ *v1.VirtualService) {
+		lock.Lock()
+		defer lock.Unlock()
 		sort.SliceStable(updatedList, func(i, j int) bool {
 			return updatedList[i].GetName() < updatedList[j].GetName()
 		})
@@ -87,7 +137,7 @@ func NewConfigWatcher(storageClient storage.Interface) (*configWatcher, error) {
 		log.GreyPrintf("change detected in virtualservices: %v", diff)
 
 		cache.VirtualServices = updatedList
-		configs <- proto.Clone(cache).(*v1.Config)
+		send(false)
 	}
 	vServiceWatcher, err := storageClient.V1().VirtualServices().Watch(&storage.VirtualServiceEventHandlerFuncs{
 		AddFunc:    syncvServices,
@@ -100,6 +150,8 @@ func NewConfigWatcher(storageClient storage.Interface) (*configWatcher, error) {
 
 
 	syncroles := func(updatedList []*v1.Role, _ *v1.Role) {
+		lock.Lock()
+		defer lock.Unlock()
 		sort.SliceStable(updatedList, func(i, j int) bool {
 			return updatedList[i].GetName() < updatedList[j].GetName()
 		})
@@ -111,7 +163,7 @@ func NewConfigWatcher(storageClient storage.Interface) (*configWatcher, error) {
 		log.GreyPrintf("change detected in virtualservices: %v", diff)
 
 		cache.Roles = updatedList
-		configs <- proto.Clone(cache).(*v1.Config)
+		send(false)
 	}
 	roleWatcher, err := storageClient.V1().Roles().Watch(&storage.RoleEventHandlerFuncs{
 		AddFunc:    syncroles,
@@ -148,3 +200,55 @@ func (w *configWatcher) Config() <-chan *v1.Config {
 func (w *configWatcher) Error() <-chan error {
 	return w.errs
 }
+
+// missingUpstreams returns the upstreams that virtual services of config route to, but that are not in config
+func missingUpstreams(config *v1.Config) []string {
+	upstreams := make(map[string]bool)
+	for _, us := range config.Upstreams {
+		upstreams[us.Name] = true
+	}
+	missing := make(map[string]bool)
+	addMissing := func(dest *v1.Destination) {
+		var name string
+		switch {
+		case dest.GetUpstream() != nil:
+			name = dest.GetUpstream().Name
+		case dest.GetFunction() != nil:
+			name = dest.GetFunction().UpstreamName
+		}
+		if name != "" && !upstreams[name] {
+			missing[name] = true
+		}
+	}
+	for _, vs := range config.VirtualServices {
+		for _, route := range vs.Routes {
+			if route.SingleDestination != nil {
+				addMissing(route.SingleDestination)
+			}
+			for _, dest := range route.MultipleDestinations {
+				addMissing(dest.Destination)
+			}
+		}
+	}
+	var names []string
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newlyMissing returns the missing upstreams that were not missing before
+func newlyMissing(missing, before []string) []string {
+	wasMissing := make(map[string]bool)
+	for _, name := range before {
+		wasMissing[name] = true
+	}
+	var names []string
+	for _, name := range missing {
+		if !wasMissing[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
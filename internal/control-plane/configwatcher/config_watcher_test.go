@@ -0,0 +1,80 @@
+package configwatcher
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/storage"
+	"github.com/solo-io/gloo/pkg/storage/memory"
+)
+
+var _ = Describe("ConfigWatcher", func() {
+	var (
+		store   storage.Interface
+		watcher *configWatcher
+		stop    chan struct{}
+	)
+	vsRoutingTo := func(upstream string) *v1.VirtualService {
+		return &v1.VirtualService{
+			Name: "vs",
+			Routes: []*v1.Route{{
+				SingleDestination: &v1.Destination{
+					DestinationType: &v1.Destination_Upstream{Upstream: &v1.UpstreamDestination{Name: upstream}},
+				},
+			}},
+		}
+	}
+	BeforeEach(func() {
+		store = memory.NewStorage()
+		var err error
+		watcher, err = NewConfigWatcher(store)
+		Expect(err).NotTo(HaveOccurred())
+		stop = make(chan struct{})
+		go watcher.Run(stop)
+		// the initial config
+		Eventually(watcher.Config()).Should(Receive())
+	})
+	AfterEach(func() {
+		close(stop)
+		missingUpstreamsGracePeriod = 5 * time.Second
+	})
+
+	It("holds back configs with virtual services routing to missing upstreams until the upstreams are stored", func() {
+		_, err := store.V1().VirtualServices().Create(vsRoutingTo("us"))
+		Expect(err).NotTo(HaveOccurred())
+		Consistently(watcher.Config(), 200*time.Millisecond).ShouldNot(Receive())
+
+		_, err = store.V1().Upstreams().Create(&v1.Upstream{Name: "us", Type: "test"})
+		Expect(err).NotTo(HaveOccurred())
+		var config *v1.Config
+		Eventually(watcher.Config()).Should(Receive(&config))
+		Expect(config.Upstreams).To(HaveLen(1))
+		Expect(config.VirtualServices).To(HaveLen(1))
+	})
+	It("sends configs with missing upstreams after the grace period", func() {
+		missingUpstreamsGracePeriod = 100 * time.Millisecond
+		_, err := store.V1().VirtualServices().Create(vsRoutingTo("missing"))
+		Expect(err).NotTo(HaveOccurred())
+		var config *v1.Config
+		Eventually(watcher.Config()).Should(Receive(&config))
+		Expect(config.VirtualServices).To(HaveLen(1))
+		Expect(missingUpstreams(config)).To(Equal([]string{"missing"}))
+	})
+	It("does not hold back changes while a destination stays missing", func() {
+		missingUpstreamsGracePeriod = 100 * time.Millisecond
+		_, err := store.V1().VirtualServices().Create(vsRoutingTo("missing"))
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(watcher.Config()).Should(Receive())
+
+		missingUpstreamsGracePeriod = 5 * time.Second
+		_, err = store.V1().Upstreams().Create(&v1.Upstream{Name: "other", Type: "test"})
+		Expect(err).NotTo(HaveOccurred())
+		var config *v1.Config
+		Eventually(watcher.Config(), time.Second).Should(Receive(&config))
+		Expect(config.Upstreams).To(HaveLen(1))
+		Expect(missingUpstreams(config)).To(Equal([]string{"missing"}))
+	})
+})
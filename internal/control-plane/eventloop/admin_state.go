@@ -0,0 +1,54 @@
+package eventloop
+
+import (
+	"sync"
+
+	envoycache "github.com/envoyproxy/go-control-plane/pkg/cache"
+
+	"github.com/solo-io/gloo/internal/control-plane/reporter"
+	"github.com/solo-io/gloo/internal/control-plane/snapshot"
+)
+
+// adminState is the state of the event loop served by the admin api.
+// it is written by the event loop and read concurrently by the admin server
+type adminState struct {
+	lock      sync.RWMutex
+	inputs    *snapshot.Cache
+	snapshots map[string]*envoycache.Snapshot
+	reports   []reporter.ConfigObjectReport
+}
+
+func (s *adminState) setInputs(inputs *snapshot.Cache, servedRoles map[string]servedRole) {
+	snapshots := make(map[string]*envoycache.Snapshot)
+	for role, served := range servedRoles {
+		snapshots[role] = served.snapshot
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.inputs = inputs
+	s.snapshots = snapshots
+}
+
+func (s *adminState) setReports(reports []reporter.ConfigObjectReport) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.reports = reports
+}
+
+func (s *adminState) Inputs() *snapshot.Cache {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.inputs
+}
+
+func (s *adminState) XdsSnapshots() map[string]*envoycache.Snapshot {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.snapshots
+}
+
+func (s *adminState) Reports() []reporter.ConfigObjectReport {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.reports
+}
@@ -1,10 +1,13 @@
 package eventloop
 
 import (
+	"time"
+
 	envoycache "github.com/envoyproxy/go-control-plane/pkg/cache"
 	"github.com/pkg/errors"
-	"github.com/solo-io/gloo/pkg/endpointdiscovery"
 
+	"github.com/solo-io/gloo/internal/control-plane/acme"
+	"github.com/solo-io/gloo/internal/control-plane/admin"
 	"github.com/solo-io/gloo/internal/control-plane/bootstrap"
 	"github.com/solo-io/gloo/internal/control-plane/configwatcher"
 	"github.com/solo-io/gloo/internal/control-plane/endpointswatcher"
@@ -16,18 +19,37 @@ import (
 	"github.com/solo-io/gloo/pkg/api/types/v1"
 	"github.com/solo-io/gloo/pkg/bootstrap/artifactstorage"
 	"github.com/solo-io/gloo/pkg/bootstrap/configstorage"
+	"github.com/solo-io/gloo/pkg/bootstrap/secretstorage"
 	secretwatchersetup "github.com/solo-io/gloo/pkg/bootstrap/secretwatcher"
 	"github.com/solo-io/gloo/pkg/log"
 	"github.com/solo-io/gloo/pkg/plugins"
+	"github.com/solo-io/gloo/pkg/secretwatcher"
 )
 
-const defaultRole = "ingress"
+var logger = log.With(log.Component("control-plane"))
 
 type eventLoop struct {
 	snapshotEmitter *snapshot.Emitter
 	reporter        reporter.Interface
 	translator      *translator.Translator
 	xdsConfig       envoycache.SnapshotCache
+	nodeTracker     *xds.NodeTracker
+	lastKnownGood   *lastKnownGood
+	acme            *acme.Manager
+
+	// the snapshot currently served to each role
+	servedRoles map[string]servedRole
+	// the reports from the latest translation
+	lastReports []reporter.ConfigObjectReport
+
+	// served by the admin api
+	admin *adminState
+
+	// fires when the next certificate expires, or starts expiring within the warning window
+	certificateCheck   <-chan time.Time
+	certificateCheckAt time.Time
+	now                func() time.Time
+	after              func(d time.Duration) <-chan time.Time
 }
 
 func Setup(opts bootstrap.Options, xdsPort int, stop <-chan struct{}) (*eventLoop, error) {
@@ -62,15 +84,32 @@ func Setup(opts bootstrap.Options, xdsPort int, stop <-chan struct{}) (*eventLoo
 
 	endpointsWatcher := endpointswatcher.NewEndpointsWatcher(opts.Options, edPlugins...)
 
-	snapshotEmitter := snapshot.NewEmitter(cfgWatcher, secretWatcher,
-		fileWatcher, endpointsWatcher, getDependenciesFor(plugs))
-
 	trans := translator.NewTranslator(opts.IngressOptions, plugs)
 
+	snapshotEmitter := snapshot.NewEmitter(cfgWatcher, secretWatcher,
+		fileWatcher, endpointsWatcher, trans.GetDependencies)
+
 	// create a snapshot to give to misconfigured envoy instances
 	badNodeSnapshot := xds.BadNodeSnapshot(opts.IngressOptions.BindAddress, opts.IngressOptions.Port)
 
-	xdsConfig, _, err := xds.RunXDS(xdsPort, badNodeSnapshot)
+	roles, err := xds.NewRoleAssigner(opts.XdsOptions)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid xds role options")
+	}
+
+	nodeTracker := xds.NewNodeTracker(roles)
+
+	security, err := setupXdsSecurity(opts, roles, stop)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to set up xds security")
+	}
+
+	acmeManager, err := setupAcme(opts, stop)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to set up acme")
+	}
+
+	xdsConfig, _, err := xds.RunXDS(xdsPort, badNodeSnapshot, roles, nodeTracker, security)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to start xds server")
 	}
@@ -79,24 +118,64 @@ func Setup(opts bootstrap.Options, xdsPort int, stop <-chan struct{}) (*eventLoo
 		snapshotEmitter: snapshotEmitter,
 		translator:      trans,
 		xdsConfig:       xdsConfig,
+		nodeTracker:     nodeTracker,
 		reporter:        reporter.NewReporter(store),
+		lastKnownGood:   newLastKnownGood(),
+		acme:            acmeManager,
+		servedRoles:     make(map[string]servedRole),
+		admin:           &adminState{},
+		now:             time.Now,
+		after:           time.After,
+	}
+
+	if opts.AdminOptions.Port != 0 {
+		adminServer := admin.NewServer(e.admin, nodeTracker, trans.Plugins)
+		go func() {
+			if err := adminServer.Run(opts.AdminOptions, stop); err != nil {
+				logger.Warnf("admin api failed: %v", err)
+			}
+		}()
 	}
 
 	return e, nil
 }
 
-func getDependenciesFor(translatorPlugins []plugins.TranslatorPlugin) func(cfg *v1.Config) []*plugins.Dependencies {
-	return func(cfg *v1.Config) []*plugins.Dependencies {
-		var dependencies []*plugins.Dependencies
-		// secrets plugins need
-		for _, plug := range translatorPlugins {
-			dep := plug.GetDependencies(cfg)
-			if dep != nil {
-				dependencies = append(dependencies, dep)
-			}
-		}
-		return dependencies
+// loads the xds server certificates and authorization rules, and reloads them when they change
+func setupXdsSecurity(opts bootstrap.Options, roles *xds.RoleAssigner, stop <-chan struct{}) (*xds.Security, error) {
+	security := xds.NewSecurity(opts.XdsOptions, roles)
+	if len(security.SecretRefs()) == 0 {
+		return security, nil
+	}
+	secretStorage, err := secretstorage.Bootstrap(opts.Options)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating secret storage client")
+	}
+	if err := security.Load(secretStorage); err != nil {
+		return nil, err
+	}
+	watcher, err := secretwatcher.NewSecretWatcher(secretStorage)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating secret watcher")
 	}
+	go security.Run(watcher, stop)
+	return security, nil
+}
+
+// issues the certificates of virtual services with auto_certificate, if an acme directory is configured
+func setupAcme(opts bootstrap.Options, stop <-chan struct{}) (*acme.Manager, error) {
+	if opts.AcmeOptions.DirectoryURL == "" {
+		return acme.NewManager(opts.AcmeOptions, nil)
+	}
+	secretStorage, err := secretstorage.Bootstrap(opts.Options)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating secret storage client")
+	}
+	manager, err := acme.NewManager(opts.AcmeOptions, secretStorage)
+	if err != nil {
+		return nil, err
+	}
+	go manager.Run(stop)
+	return manager, nil
 }
 
 func setupFileWatcher(opts bootstrap.Options) (filewatcher.Interface, error) {
@@ -111,42 +190,66 @@ func (e *eventLoop) Run(stop <-chan struct{}) {
 	go e.snapshotEmitter.Run(stop)
 
 	// cache the most recent read for any of these
-	var oldHash uint64
+	var (
+		oldHash  uint64
+		lastSnap *snapshot.Cache
+	)
 	for {
 		select {
 		case <-stop:
-			log.Printf("event loop shutting down")
+			logger.Printf("event loop shutting down")
 			return
 		case snap := <-e.snapshotEmitter.Snapshot():
 			newHash := snap.Hash()
-			log.Printf("\nold hash: %v\nnew hash: %v", oldHash, newHash)
+			logger.Debugf("old hash: %v, new hash: %v", oldHash, newHash)
 			if newHash == oldHash {
 				continue
 			}
-			log.Debugf("new snapshot received")
+			logger.Debugf("new snapshot received")
 			oldHash = newHash
+			lastSnap = snap
 			e.updateXds(snap)
+		case <-e.acme.Updates():
+			// the acme challenges changed, or a certificate could not be issued
+			if lastSnap != nil {
+				e.updateXds(lastSnap)
+			}
+		case <-e.certificateCheck:
+			logger.Debugf("a certificate expired or started expiring within the warning window")
+			e.certificateCheck, e.certificateCheckAt = nil, time.Time{}
+			if lastSnap != nil {
+				e.updateXds(lastSnap)
+			}
+		case <-e.nodeTracker.Updates():
+			logger.Debugf("envoy nodes updated their acked versions")
+			e.writeReports()
 		case err := <-e.snapshotEmitter.Error():
-			log.Warnf("error in control plane event loop: %v", err)
+			logger.Warnf("error in control plane event loop: %v", err)
 		}
 	}
 }
 
 func (e *eventLoop) updateXds(snap *snapshot.Cache) {
 	if !snap.Ready() {
-		log.Debugf("snapshot is not ready for translation yet")
+		logger.Debugf("snapshot is not ready for translation yet")
 		return
 	}
 
 	// map each virtual service to one or more roles
 	// if no roles are defined, we fall back to the default Role, which is 'ingress'
-	virtualServicesByRole := make(map[string][]*v1.VirtualService)
-	for _, vs := range snap.Cfg.VirtualServices {
-		if len(vs.Roles) == 0 {
-			virtualServicesByRole[defaultRole] = append(virtualServicesByRole[defaultRole], vs)
-		}
-		for _, role := range vs.Roles {
-			virtualServicesByRole[role] = append(virtualServicesByRole[role], vs)
+	virtualServicesByRole := snap.VirtualServicesByRole()
+
+	// deleted objects should not be served as last known good
+	e.lastKnownGood.prune(snap.Cfg)
+
+	// issue missing and expiring certificates. the challenges are answered by the roles of their virtual service
+	e.acme.Sync(snap.Cfg, snap.Secrets)
+	challenges := e.acme.Challenges()
+
+	// forget roles which no longer have any virtual services
+	for role := range e.servedRoles {
+		if _, ok := virtualServicesByRole[role]; !ok {
+			delete(e.servedRoles, role)
 		}
 	}
 
@@ -156,123 +259,164 @@ func (e *eventLoop) updateXds(snap *snapshot.Cache) {
 	// translate each set of resources (grouped by role) individually
 	// and set the snapshot for that role
 	for role, virtualServices := range virtualServicesByRole {
+		roleLogger := logger.With(log.Role(role))
 		if len(virtualServices) == 0 {
-			log.Printf("nothing to do yet for role %v", role)
+			roleLogger.Printf("nothing to do yet for role %v", role)
 			continue
 		}
 
 		// get only the upstreams required for these virtual services
-		upstreams := destinationUpstreams(snap.Cfg.Upstreams, virtualServices)
-		endpoints := destinationEndpoints(upstreams, snap.Endpoints)
-		roleSnapshot := &snapshot.Cache{
-			Cfg: &v1.Config{
-				Upstreams:       upstreams,
-				VirtualServices: virtualServices,
-			},
-			Secrets:   snap.Secrets,
-			Files:     snap.Files,
-			Endpoints: endpoints,
-		}
+		roleSnapshot := snap.ForRole(virtualServices)
+		roleSnapshot.Challenges = acme.ForVirtualServices(challenges, virtualServices)
 
-		log.Debugf("\nRole: %v\nGloo Snapshot (%v): %v", role, snap.Hash(), snap)
+		roleLogger.Debugf("translating role %v: %v upstreams, %v virtual services", role, len(roleSnapshot.Cfg.Upstreams), len(virtualServices))
 
 		// create new role object
 		// this will be used to store the report for role-level errors
-		var vsNames []string
-		for _, vs := range virtualServices {
-			vsNames = append(vsNames, vs.Name)
-		}
-		roleObject := &v1.Role{
-			Name:            role,
-			VirtualServices: vsNames,
-		}
+		roleObject := snapshot.NewRole(role, virtualServices)
 
+		translationStart := time.Now()
 		xdsSnapshot, reports, err := e.translator.Translate(roleObject, roleSnapshot)
+		translationDuration.WithLabelValues(role).Observe(time.Since(translationStart).Seconds())
 		if err != nil {
 			// TODO: panic or handle these internal errors smartly
-			log.Warnf("INTERNAL ERROR: failed to run translator for role %v: %v", role, err)
+			roleLogger.Warnf("INTERNAL ERROR: failed to run translator for role %v: %v", role, err)
 			continue
 		}
 
-		var roleRejected bool
 		// merge reports them together
+		// an object rejected in any role is reported as rejected
 		for _, rep := range reports {
-			allReports[rep.CfgObject.GetName()] = rep
+			key := reporter.ObjectRef(rep.CfgObject)
+			if existing, ok := allReports[key]; ok {
+				allReports[key] = reporter.MergeReports(existing, rep)
+			} else {
+				allReports[key] = rep
+			}
 
 			if rep.Err != nil {
-				log.Warnf("user config in role %v failed with err %v", role, rep.Err.Error())
-				roleRejected = true
+				roleLogger.With(log.Resource(key)).Warnf("user config in role %v failed with err %v", role, rep.Err.Error())
 			}
 		}
 
-		if roleRejected {
-			log.Warnf("role %v rejected", role)
-			continue
+		rejected := getRejectedObjects(reports)
+
+		// remember the objects that were accepted before replacing the rejected ones
+		e.lastKnownGood.update(role, reports)
+
+		servedCfg := roleSnapshot.Cfg
+		if !rejected.empty() {
+			roleLogger.Warnf("role %v partially rejected: serving last known good config for %v upstreams and %v virtual services",
+				role, len(rejected.upstreams), len(rejected.virtualServices))
+			xdsSnapshot, servedCfg, err = e.translateAccepted(roleObject, roleSnapshot, rejected)
+			if err != nil {
+				roleLogger.Warnf("INTERNAL ERROR: failed to run translator for role %v: %v", role, err)
+				continue
+			}
 		}
 
-		log.Debugf("Setting xDS Snapshot for Role %v: %v", role, xdsSnapshot)
+		roleLogger.Debugf("setting xDS snapshot for role %v: clusters %v, endpoints %v, routes %v, listeners %v, secrets %v", role,
+			xdsSnapshot.Clusters.Version, xdsSnapshot.Endpoints.Version, xdsSnapshot.Routes.Version, xdsSnapshot.Listeners.Version,
+			xdsSnapshot.Secrets.Version)
 		e.xdsConfig.SetSnapshot(role, *xdsSnapshot)
+		snapshotPushes.WithLabelValues(role).Inc()
+		e.servedRoles[role] = servedRole{snapshot: xdsSnapshot, cfg: servedCfg, role: roleObject}
 	}
 
 	var reports []reporter.ConfigObjectReport
 	for _, rep := range allReports {
+		// certificates that could not be issued are reported on their virtual service
+		if vs, ok := rep.CfgObject.(*v1.VirtualService); ok {
+			if err := e.acme.Error(vs.Name); err != nil {
+				rep.Warnings = append(rep.Warnings, err)
+			}
+		}
 		reports = append(reports, rep)
 	}
+	e.lastReports = reports
+	e.admin.setInputs(snap, e.servedRoles)
 
-	if err := e.reporter.WriteReports(reports); err != nil {
-		log.Warnf("error writing reports: %v", err)
+	e.writeReports()
+	e.scheduleCertificateCheck()
+
+	var activeRoles []string
+	for role := range virtualServicesByRole {
+		activeRoles = append(activeRoles, role)
+	}
+	if err := e.reporter.PruneRoles(activeRoles); err != nil {
+		logger.Warnf("error cleaning up roles without virtual services: %v", err)
 	}
 }
 
-// gets the subset of upstreams which are destinations for at least one route in at least one
-// virtual service
-func destinationUpstreams(allUpstreams []*v1.Upstream, virtualServices []*v1.VirtualService) []*v1.Upstream {
-	destinationUpstreamNames := make(map[string]bool)
-	for _, vs := range virtualServices {
-		for _, route := range vs.Routes {
-			dests := getAllDestinations(route)
-			for _, dest := range dests {
-				var upstreamName string
-				switch typedDest := dest.DestinationType.(type) {
-				case *v1.Destination_Upstream:
-					upstreamName = typedDest.Upstream.Name
-				case *v1.Destination_Function:
-					upstreamName = typedDest.Function.UpstreamName
-				default:
-					panic("unknown destination type")
-				}
-				destinationUpstreamNames[upstreamName] = true
-			}
-		}
+// certificates expire without any change to the config, so the config is
+// translated again when the next certificate reported by the latest translation does
+func (e *eventLoop) scheduleCertificateCheck() {
+	now := e.now()
+	next, ok := e.translator.NextCertificateCheck(e.lastReports, now)
+	if !ok {
+		e.certificateCheck, e.certificateCheckAt = nil, time.Time{}
+		return
 	}
-	var destinationUpstreams []*v1.Upstream
-	for _, us := range allUpstreams {
-		if _, ok := destinationUpstreamNames[us.Name]; ok {
-			destinationUpstreams = append(destinationUpstreams, us)
-		}
+	if next.Equal(e.certificateCheckAt) {
+		return
 	}
-	return destinationUpstreams
+	e.certificateCheck, e.certificateCheckAt = e.after(next.Sub(now)), next
 }
 
-func getAllDestinations(route *v1.Route) []*v1.Destination {
-	var dests []*v1.Destination
-	if route.SingleDestination != nil {
-		dests = append(dests, route.SingleDestination)
+// writes the reports from the latest translation, overridden by
+// any rejections reported by envoy for the config currently being served
+func (e *eventLoop) writeReports() {
+	reports := make(map[string]reporter.ConfigObjectReport)
+	for _, rep := range e.lastReports {
+		reports[reporter.ObjectRef(rep.CfgObject)] = rep
+	}
+	for _, rep := range nackReports(e.servedRoles, e.nodeTracker) {
+		key := reporter.ObjectRef(rep.CfgObject)
+		// errors from translation take precedence. objects rejected by envoy
+		// keep the warnings and accepted roles from translation
+		if existing, ok := reports[key]; ok {
+			rep = reporter.MergeReports(existing, rep)
+		}
+		reports[key] = rep
+	}
+
+	var merged []reporter.ConfigObjectReport
+	for _, rep := range reports {
+		merged = append(merged, rep)
 	}
-	for _, dest := range route.MultipleDestinations {
-		dests = append(dests, dest.Destination)
+	e.admin.setReports(merged)
+	recordRejectedObjects(merged)
+	recordCertificateExpiry(merged)
+	if err := e.reporter.WriteReports(merged); err != nil {
+		logger.Warnf("error writing reports: %v", err)
 	}
-	return dests
 }
 
-func destinationEndpoints(upstreams []*v1.Upstream, allEndpoints endpointdiscovery.EndpointGroups) endpointdiscovery.EndpointGroups {
-	destinationEndpoints := make(endpointdiscovery.EndpointGroups)
-	for _, us := range upstreams {
-		eps, ok := allEndpoints[us.Name]
-		if !ok {
-			continue
+// translateAccepted re-runs translation for a role after replacing rejected objects with
+// their last known good version. objects that have no good version are dropped.
+// if the result is still rejected (e.g. a last known good virtual service points to an
+// upstream that was since deleted), the offending objects are dropped until the
+// translation succeeds. each iteration drops at least one object, so this terminates.
+func (e *eventLoop) translateAccepted(role *v1.Role, roleSnapshot *snapshot.Cache, rejected rejectedObjects) (*envoycache.Snapshot, *v1.Config, error) {
+	cfg := e.lastKnownGood.replaceRejected(role.Name, roleSnapshot.Cfg, rejected)
+	for {
+		acceptedSnapshot := &snapshot.Cache{
+			Cfg:        cfg,
+			Secrets:    roleSnapshot.Secrets,
+			Files:      roleSnapshot.Files,
+			Endpoints:  roleSnapshot.Endpoints,
+			Challenges: roleSnapshot.Challenges,
+		}
+		xdsSnapshot, reports, err := e.translator.Translate(role, acceptedSnapshot)
+		if err != nil {
+			return nil, nil, err
+		}
+		stillRejected := getRejectedObjects(reports)
+		if stillRejected.empty() {
+			return xdsSnapshot, cfg, nil
 		}
-		destinationEndpoints[us.Name] = eps
+		logger.Debugf("dropping %v upstreams and %v virtual services from role %v",
+			len(stillRejected.upstreams), len(stillRejected.virtualServices), role.Name)
+		cfg = withoutRejected(cfg, stillRejected)
 	}
-	return destinationEndpoints
 }
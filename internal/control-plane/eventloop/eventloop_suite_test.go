@@ -0,0 +1,15 @@
+package eventloop
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/solo-io/gloo/pkg/log"
+)
+
+func TestEventloop(t *testing.T) {
+	RegisterFailHandler(Fail)
+	log.DefaultOut = GinkgoWriter
+	RunSpecs(t, "Eventloop Suite")
+}
@@ -0,0 +1,67 @@
+package eventloop
+
+import (
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo/internal/control-plane/bootstrap"
+	"github.com/solo-io/gloo/internal/control-plane/reporter"
+	"github.com/solo-io/gloo/internal/control-plane/translator"
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+)
+
+var _ = Describe("scheduleCertificateCheck", func() {
+	var (
+		now       time.Time
+		scheduled []time.Duration
+		e         *eventLoop
+	)
+	certificate := func(notAfter time.Time) []*v1.Certificate {
+		ts, err := types.TimestampProto(notAfter)
+		Expect(err).NotTo(HaveOccurred())
+		return []*v1.Certificate{{SecretRef: "tls", NotAfter: ts}}
+	}
+	BeforeEach(func() {
+		now = time.Date(2018, 5, 1, 12, 0, 0, 0, time.UTC)
+		scheduled = nil
+		e = &eventLoop{
+			translator: translator.NewTranslator(bootstrap.IngressOptions{CertExpiryWarning: 24 * time.Hour}, nil),
+			now:        func() time.Time { return now },
+			after: func(d time.Duration) <-chan time.Time {
+				scheduled = append(scheduled, d)
+				return make(chan time.Time)
+			},
+		}
+	})
+	It("translates again when the next certificate starts expiring within the warning window, then when it expires", func() {
+		e.lastReports = []reporter.ConfigObjectReport{
+			{CfgObject: &v1.VirtualService{Name: "later"}, Certificates: certificate(now.Add(10 * 24 * time.Hour))},
+			{CfgObject: &v1.VirtualService{Name: "soon"}, Certificates: certificate(now.Add(72 * time.Hour))},
+			{CfgObject: &v1.VirtualService{Name: "plain"}},
+		}
+		e.scheduleCertificateCheck()
+		Expect(scheduled).To(Equal([]time.Duration{48 * time.Hour}))
+		Expect(e.certificateCheck).NotTo(BeNil())
+
+		// translating again for another reason keeps the pending check
+		e.scheduleCertificateCheck()
+		Expect(scheduled).To(HaveLen(1))
+
+		// the check fires, and the config is translated again
+		now = now.Add(48 * time.Hour)
+		e.certificateCheck, e.certificateCheckAt = nil, time.Time{}
+		e.scheduleCertificateCheck()
+		Expect(scheduled).To(Equal([]time.Duration{48 * time.Hour, 24 * time.Hour}))
+	})
+	It("does not schedule a check without certificates that expire in the future", func() {
+		e.lastReports = []reporter.ConfigObjectReport{
+			{CfgObject: &v1.VirtualService{Name: "expired"}, Certificates: certificate(now.Add(-time.Hour))},
+		}
+		e.scheduleCertificateCheck()
+		Expect(scheduled).To(BeEmpty())
+		Expect(e.certificateCheck).To(BeNil())
+	})
+})
@@ -0,0 +1,141 @@
+package eventloop
+
+import (
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/solo-io/gloo/internal/control-plane/reporter"
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+)
+
+// lastKnownGood stores, per role, the most recent version of every upstream
+// and virtual service that was accepted by the translator.
+// when an object is rejected, its last accepted version is served instead,
+// so that one broken object does not block updates for the whole role
+type lastKnownGood struct {
+	upstreams       map[string]map[string]*v1.Upstream
+	virtualServices map[string]map[string]*v1.VirtualService
+}
+
+func newLastKnownGood() *lastKnownGood {
+	return &lastKnownGood{
+		upstreams:       make(map[string]map[string]*v1.Upstream),
+		virtualServices: make(map[string]map[string]*v1.VirtualService),
+	}
+}
+
+// forget any object which no longer exists in storage
+// deleted objects should never be resurrected
+func (l *lastKnownGood) prune(cfg *v1.Config) {
+	existingUpstreams := make(map[string]bool)
+	for _, us := range cfg.Upstreams {
+		existingUpstreams[us.Name] = true
+	}
+	existingVirtualServices := make(map[string]bool)
+	for _, vs := range cfg.VirtualServices {
+		existingVirtualServices[vs.Name] = true
+	}
+	for _, upstreams := range l.upstreams {
+		for name := range upstreams {
+			if !existingUpstreams[name] {
+				delete(upstreams, name)
+			}
+		}
+	}
+	for _, virtualServices := range l.virtualServices {
+		for name := range virtualServices {
+			if !existingVirtualServices[name] {
+				delete(virtualServices, name)
+			}
+		}
+	}
+}
+
+// record the objects that were accepted for this role
+func (l *lastKnownGood) update(role string, reports []reporter.ConfigObjectReport) {
+	if l.upstreams[role] == nil {
+		l.upstreams[role] = make(map[string]*v1.Upstream)
+	}
+	if l.virtualServices[role] == nil {
+		l.virtualServices[role] = make(map[string]*v1.VirtualService)
+	}
+	for _, rep := range reports {
+		if rep.Err != nil {
+			continue
+		}
+		switch obj := rep.CfgObject.(type) {
+		case *v1.Upstream:
+			l.upstreams[role][obj.Name] = proto.Clone(obj).(*v1.Upstream)
+		case *v1.VirtualService:
+			l.virtualServices[role][obj.Name] = proto.Clone(obj).(*v1.VirtualService)
+		}
+	}
+}
+
+// returns a copy of the config where each rejected object is replaced
+// by its last accepted version for this role, or dropped if it was never accepted
+func (l *lastKnownGood) replaceRejected(role string, cfg *v1.Config, rejected rejectedObjects) *v1.Config {
+	out := &v1.Config{}
+	for _, us := range cfg.Upstreams {
+		if !rejected.upstreams[us.Name] {
+			out.Upstreams = append(out.Upstreams, us)
+			continue
+		}
+		if good, ok := l.upstreams[role][us.Name]; ok {
+			out.Upstreams = append(out.Upstreams, good)
+		}
+	}
+	for _, vs := range cfg.VirtualServices {
+		if !rejected.virtualServices[vs.Name] {
+			out.VirtualServices = append(out.VirtualServices, vs)
+			continue
+		}
+		if good, ok := l.virtualServices[role][vs.Name]; ok {
+			out.VirtualServices = append(out.VirtualServices, good)
+		}
+	}
+	return out
+}
+
+type rejectedObjects struct {
+	upstreams       map[string]bool
+	virtualServices map[string]bool
+}
+
+func (r rejectedObjects) empty() bool {
+	return len(r.upstreams) == 0 && len(r.virtualServices) == 0
+}
+
+func getRejectedObjects(reports []reporter.ConfigObjectReport) rejectedObjects {
+	rejected := rejectedObjects{
+		upstreams:       make(map[string]bool),
+		virtualServices: make(map[string]bool),
+	}
+	for _, rep := range reports {
+		if rep.Err == nil {
+			continue
+		}
+		switch obj := rep.CfgObject.(type) {
+		case *v1.Upstream:
+			rejected.upstreams[obj.Name] = true
+		case *v1.VirtualService:
+			rejected.virtualServices[obj.Name] = true
+		}
+	}
+	return rejected
+}
+
+// drops every rejected object from the config
+func withoutRejected(cfg *v1.Config, rejected rejectedObjects) *v1.Config {
+	out := &v1.Config{}
+	for _, us := range cfg.Upstreams {
+		if !rejected.upstreams[us.Name] {
+			out.Upstreams = append(out.Upstreams, us)
+		}
+	}
+	for _, vs := range cfg.VirtualServices {
+		if !rejected.virtualServices[vs.Name] {
+			out.VirtualServices = append(out.VirtualServices, vs)
+		}
+	}
+	return out
+}
@@ -0,0 +1,73 @@
+package eventloop
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/internal/control-plane/reporter"
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+)
+
+var _ = Describe("LastKnownGood", func() {
+	var (
+		lkg  *lastKnownGood
+		role = "myrole"
+	)
+	BeforeEach(func() {
+		lkg = newLastKnownGood()
+	})
+	Context("replacing rejected objects", func() {
+		It("serves the last accepted version of a rejected object", func() {
+			good := &v1.VirtualService{Name: "vs", Domains: []string{"good.example.com"}}
+			lkg.update(role, []reporter.ConfigObjectReport{{CfgObject: good}})
+
+			bad := &v1.VirtualService{Name: "vs", Domains: []string{"bad.example.com"}}
+			other := &v1.VirtualService{Name: "other"}
+			cfg := &v1.Config{VirtualServices: []*v1.VirtualService{bad, other}}
+			rejected := getRejectedObjects([]reporter.ConfigObjectReport{
+				{CfgObject: bad, Err: errors.New("bad")},
+				{CfgObject: other},
+			})
+			out := lkg.replaceRejected(role, cfg, rejected)
+			Expect(out.VirtualServices).To(HaveLen(2))
+			Expect(out.VirtualServices[0]).To(Equal(good))
+			Expect(out.VirtualServices[1]).To(Equal(other))
+		})
+		It("drops rejected objects that were never accepted", func() {
+			bad := &v1.Upstream{Name: "us"}
+			cfg := &v1.Config{Upstreams: []*v1.Upstream{bad}}
+			rejected := getRejectedObjects([]reporter.ConfigObjectReport{
+				{CfgObject: bad, Err: errors.New("bad")},
+			})
+			out := lkg.replaceRejected(role, cfg, rejected)
+			Expect(out.Upstreams).To(BeEmpty())
+		})
+		It("does not share last known good objects between roles", func() {
+			good := &v1.VirtualService{Name: "vs"}
+			lkg.update("otherrole", []reporter.ConfigObjectReport{{CfgObject: good}})
+			cfg := &v1.Config{VirtualServices: []*v1.VirtualService{good}}
+			rejected := getRejectedObjects([]reporter.ConfigObjectReport{
+				{CfgObject: good, Err: errors.New("bad")},
+			})
+			out := lkg.replaceRejected(role, cfg, rejected)
+			Expect(out.VirtualServices).To(BeEmpty())
+		})
+		It("forgets deleted objects", func() {
+			good := &v1.VirtualService{Name: "vs"}
+			lkg.update(role, []reporter.ConfigObjectReport{{CfgObject: good}})
+			lkg.prune(&v1.Config{})
+			cfg := &v1.Config{VirtualServices: []*v1.VirtualService{good}}
+			rejected := getRejectedObjects([]reporter.ConfigObjectReport{
+				{CfgObject: good, Err: errors.New("bad")},
+			})
+			out := lkg.replaceRejected(role, cfg, rejected)
+			Expect(out.VirtualServices).To(BeEmpty())
+		})
+	})
+	Context("report keys", func() {
+		It("distinguishes objects of different types with the same name", func() {
+			Expect(reporter.ObjectRef(&v1.Upstream{Name: "foo"})).NotTo(Equal(reporter.ObjectRef(&v1.VirtualService{Name: "foo"})))
+		})
+	})
+})
@@ -0,0 +1,124 @@
+package eventloop
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/solo-io/gloo/internal/control-plane/reporter"
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/metrics"
+)
+
+const subsystem = "control_plane"
+
+var (
+	translationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystem,
+		Name:      "translation_duration_seconds",
+		Help:      "time taken to translate the gloo config for a role into an xDS snapshot",
+	}, []string{"role"})
+	snapshotPushes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystem,
+		Name:      "snapshot_pushes_total",
+		Help:      "number of xDS snapshots set for a role",
+	}, []string{"role"})
+	rejectedObjects = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystem,
+		Name:      "rejected_objects",
+		Help:      "number of config objects currently reported as rejected, by object type",
+	}, []string{"type"})
+	certificateExpiry = &certificateExpiryCollector{
+		desc: prometheus.NewDesc(prometheus.BuildFQName(metrics.Namespace, subsystem, "certificate_expiry_days"),
+			"days until the tls certificate of a virtual service expires, negative once it expired",
+			[]string{"virtual_service", "secret_ref"}, nil),
+	}
+)
+
+func init() {
+	prometheus.MustRegister(translationDuration, snapshotPushes, rejectedObjects, certificateExpiry)
+}
+
+// recordRejectedObjects sets the number of rejected objects of every type,
+// so that types with no remaining rejections go back to 0
+func recordRejectedObjects(reports []reporter.ConfigObjectReport) {
+	counts := map[string]int{
+		"upstream":       0,
+		"virtualservice": 0,
+		"role":           0,
+	}
+	for _, rep := range reports {
+		if rep.Err == nil {
+			continue
+		}
+		switch rep.CfgObject.(type) {
+		case *v1.Upstream:
+			counts["upstream"]++
+		case *v1.VirtualService:
+			counts["virtualservice"]++
+		case *v1.Role:
+			counts["role"]++
+		}
+	}
+	for objectType, count := range counts {
+		rejectedObjects.WithLabelValues(objectType).Set(float64(count))
+	}
+}
+
+type certificateKey struct {
+	virtualService string
+	secretRef      string
+}
+
+// certificateExpiryCollector computes the days until certificates expire when it is scraped,
+// so the days keep counting down while the config does not change
+type certificateExpiryCollector struct {
+	desc *prometheus.Desc
+	now  func() time.Time
+
+	lock     sync.Mutex
+	notAfter map[certificateKey]time.Time
+}
+
+func (c *certificateExpiryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *certificateExpiryCollector) Collect(ch chan<- prometheus.Metric) {
+	now := time.Now()
+	if c.now != nil {
+		now = c.now()
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for key, notAfter := range c.notAfter {
+		days := notAfter.Sub(now).Hours() / 24
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, days, key.virtualService, key.secretRef)
+	}
+}
+
+// recordCertificateExpiry replaces the certificates exported with the certificates of the reported virtual services
+func recordCertificateExpiry(reports []reporter.ConfigObjectReport) {
+	notAfter := make(map[certificateKey]time.Time)
+	for _, rep := range reports {
+		vs, ok := rep.CfgObject.(*v1.VirtualService)
+		if !ok {
+			continue
+		}
+		for _, cert := range rep.Certificates {
+			expiry, err := types.TimestampFromProto(cert.NotAfter)
+			if err != nil {
+				continue
+			}
+			notAfter[certificateKey{virtualService: vs.Name, secretRef: cert.SecretRef}] = expiry
+		}
+	}
+	certificateExpiry.lock.Lock()
+	certificateExpiry.notAfter = notAfter
+	certificateExpiry.lock.Unlock()
+}
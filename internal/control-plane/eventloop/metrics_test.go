@@ -0,0 +1,76 @@
+package eventloop
+
+import (
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/solo-io/gloo/internal/control-plane/reporter"
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+)
+
+var _ = Describe("Metrics", func() {
+	rejected := func(objectType string) float64 {
+		m := &dto.Metric{}
+		Expect(rejectedObjects.WithLabelValues(objectType).Write(m)).NotTo(HaveOccurred())
+		return m.GetGauge().GetValue()
+	}
+	It("counts the currently rejected objects by type", func() {
+		recordRejectedObjects([]reporter.ConfigObjectReport{
+			{CfgObject: &v1.Upstream{Name: "a"}, Err: errors.New("bad")},
+			{CfgObject: &v1.Upstream{Name: "b"}, Err: errors.New("bad")},
+			{CfgObject: &v1.Upstream{Name: "c"}},
+			{CfgObject: &v1.VirtualService{Name: "vs"}, Err: errors.New("bad")},
+		})
+		Expect(rejected("upstream")).To(Equal(2.0))
+		Expect(rejected("virtualservice")).To(Equal(1.0))
+		Expect(rejected("role")).To(Equal(0.0))
+	})
+	It("resets the count when objects are no longer rejected", func() {
+		recordRejectedObjects([]reporter.ConfigObjectReport{
+			{CfgObject: &v1.Upstream{Name: "a"}, Err: errors.New("bad")},
+		})
+		recordRejectedObjects([]reporter.ConfigObjectReport{
+			{CfgObject: &v1.Upstream{Name: "a"}},
+		})
+		Expect(rejected("upstream")).To(Equal(0.0))
+	})
+	It("exports the days until the certificates of virtual services expire", func() {
+		now := time.Date(2018, 5, 1, 12, 0, 0, 0, time.UTC)
+		certificateExpiry.now = func() time.Time { return now }
+		defer func() { certificateExpiry.now = nil }()
+		certificate := func(ref string, notAfter time.Time) *v1.Certificate {
+			ts, err := types.TimestampProto(notAfter)
+			Expect(err).NotTo(HaveOccurred())
+			return &v1.Certificate{SecretRef: ref, NotAfter: ts}
+		}
+		recordCertificateExpiry([]reporter.ConfigObjectReport{
+			{CfgObject: &v1.VirtualService{Name: "soon"}, Certificates: []*v1.Certificate{certificate("soon-tls", now.Add(36*time.Hour))}},
+			{CfgObject: &v1.VirtualService{Name: "expired"}, Certificates: []*v1.Certificate{certificate("expired-tls", now.Add(-24*time.Hour))}},
+			{CfgObject: &v1.VirtualService{Name: "plain"}},
+		})
+
+		ch := make(chan prometheus.Metric, 10)
+		certificateExpiry.Collect(ch)
+		close(ch)
+		days := make(map[string]float64)
+		for metric := range ch {
+			m := &dto.Metric{}
+			Expect(metric.Write(m)).NotTo(HaveOccurred())
+			labels := make(map[string]string)
+			for _, label := range m.Label {
+				labels[label.GetName()] = label.GetValue()
+			}
+			days[labels["virtual_service"]+"/"+labels["secret_ref"]] = m.GetGauge().GetValue()
+		}
+		Expect(days).To(Equal(map[string]float64{
+			"soon/soon-tls":       1.5,
+			"expired/expired-tls": -1,
+		}))
+	})
+})
@@ -0,0 +1,151 @@
+package eventloop
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	envoycache "github.com/envoyproxy/go-control-plane/pkg/cache"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/internal/control-plane/reporter"
+	"github.com/solo-io/gloo/internal/control-plane/translator"
+	"github.com/solo-io/gloo/internal/control-plane/xds"
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+)
+
+// servedRole is the xds snapshot currently served for a role,
+// along with the gloo config it was translated from
+type servedRole struct {
+	snapshot *envoycache.Snapshot
+	cfg      *v1.Config
+	// role-level errors are reported on the role object
+	role *v1.Role
+}
+
+type nackedObject struct {
+	cfgObject v1.ConfigObject
+	err       error
+	nodes     map[string]bool
+}
+
+// nackReports creates a report for every object which produced config that is currently rejected
+// by at least one envoy node. if only some of the nodes in the role rejected it,
+// the object is reported as partially applied. rejected config that can not be traced back
+// to an object, e.g. a rejected listener, is reported on the role
+func nackReports(servedRoles map[string]servedRole, tracker *xds.NodeTracker) []reporter.ConfigObjectReport {
+	nacked := make(map[string]*nackedObject)
+	nodesPerObject := make(map[string]int)
+	for role, served := range servedRoles {
+		nodes := tracker.NodesForRole(role)
+		for _, node := range nodes {
+			for _, typeUrl := range sortedTypeUrls(node.Nacks) {
+				nack := node.Nacks[typeUrl]
+				// the node rejected an older version, which is no longer served
+				if nack.Version != snapshotVersion(served.snapshot, nack.TypeUrl) {
+					continue
+				}
+				objs := objectsForNack(served.cfg, nack)
+				if len(objs) == 0 {
+					objs = []v1.ConfigObject{served.role}
+				}
+				for _, obj := range objs {
+					key := reporter.ObjectRef(obj)
+					if _, ok := nacked[key]; !ok {
+						nacked[key] = &nackedObject{cfgObject: obj, nodes: make(map[string]bool)}
+					}
+					nacked[key].err = multierror.Append(nacked[key].err, errors.Errorf("envoy node %v rejected "+
+						"%v version %v: %v", nack.NodeID, typeUrl, nack.Version, nack.Message))
+					nacked[key].nodes[nack.NodeID] = true
+				}
+			}
+		}
+		// an object may belong to multiple roles
+		for _, obj := range append(cfgObjects(served.cfg), served.role) {
+			nodesPerObject[reporter.ObjectRef(obj)] += len(nodes)
+		}
+	}
+
+	var keys []string
+	for key := range nacked {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var reports []reporter.ConfigObjectReport
+	for _, key := range keys {
+		obj := nacked[key]
+		reports = append(reports, reporter.ConfigObjectReport{
+			CfgObject:        obj.cfgObject,
+			Err:              obj.err,
+			PartiallyApplied: len(obj.nodes) < nodesPerObject[key],
+		})
+	}
+	return reports
+}
+
+func sortedTypeUrls(nacks map[string]xds.Nack) []string {
+	var typeUrls []string
+	for typeUrl := range nacks {
+		typeUrls = append(typeUrls, typeUrl)
+	}
+	sort.Strings(typeUrls)
+	return typeUrls
+}
+
+func snapshotVersion(snap *envoycache.Snapshot, typeUrl string) string {
+	switch typeUrl {
+	case envoycache.EndpointType:
+		return snap.Endpoints.Version
+	case envoycache.ClusterType:
+		return snap.Clusters.Version
+	case envoycache.RouteType:
+		return snap.Routes.Version
+	case envoycache.ListenerType:
+		return snap.Listeners.Version
+	case envoycache.SecretType:
+		return snap.Secrets.Version
+	}
+	return ""
+}
+
+// maps the resources rejected by envoy back to the objects that produced them, by the exact names of the
+// resources. envoy names the rejected resources in its request, or in the error message. the names in requests
+// for routes are the names of route configurations, which are shared by every virtual service of the role,
+// so only the error message is used to find the virtual hosts of rejected routes
+func objectsForNack(cfg *v1.Config, nack xds.Nack) []v1.ConfigObject {
+	names := make(map[string]bool)
+	switch nack.TypeUrl {
+	case envoycache.EndpointType, envoycache.ClusterType, envoycache.SecretType:
+		for _, name := range nack.ResourceNames {
+			names[name] = true
+		}
+	}
+	for _, word := range wordsInMessage(nack.Message) {
+		names[word] = true
+	}
+	return translator.ResourceOwners(cfg, nack.TypeUrl, names)
+}
+
+// wordsInMessage splits an error message from envoy into the words that may be resource names,
+// e.g. "Error adding/updating cluster(s) petstore: ..." contains petstore
+func wordsInMessage(message string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(message, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(",;:'\"`()[]{}<>", r)
+	}) {
+		words = append(words, word, strings.TrimRight(word, "."))
+	}
+	return words
+}
+
+func cfgObjects(cfg *v1.Config) []v1.ConfigObject {
+	var objs []v1.ConfigObject
+	for _, us := range cfg.Upstreams {
+		objs = append(objs, us)
+	}
+	for _, vs := range cfg.VirtualServices {
+		objs = append(objs, vs)
+	}
+	return objs
+}
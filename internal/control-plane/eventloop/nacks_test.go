@@ -0,0 +1,117 @@
+package eventloop
+
+import (
+	"github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	"github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	envoycache "github.com/envoyproxy/go-control-plane/pkg/cache"
+	"github.com/gogo/googleapis/google/rpc"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo/internal/control-plane/bootstrap"
+	"github.com/solo-io/gloo/internal/control-plane/xds"
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+)
+
+var _ = Describe("Nacks", func() {
+	var (
+		tracker *xds.NodeTracker
+		served  map[string]servedRole
+		good    = &v1.Upstream{Name: "good"}
+		bad     = &v1.Upstream{Name: "bad"}
+		role    = &v1.Role{Name: "myrole"}
+	)
+	nackWithMessage := func(stream int64, nodeID, message string) {
+		node := &core.Node{Id: "myrole~" + nodeID}
+		tracker.OnStreamOpen(stream, "")
+		tracker.OnStreamRequest(stream, &v2.DiscoveryRequest{Node: node, TypeUrl: envoycache.ClusterType})
+		tracker.OnStreamResponse(stream, nil, &v2.DiscoveryResponse{TypeUrl: envoycache.ClusterType, VersionInfo: "1", Nonce: "a"})
+		tracker.OnStreamRequest(stream, &v2.DiscoveryRequest{
+			Node:          node,
+			TypeUrl:       envoycache.ClusterType,
+			ResponseNonce: "a",
+			ErrorDetail:   &rpc.Status{Message: message},
+		})
+	}
+	nack := func(stream int64, nodeID string) {
+		nackWithMessage(stream, nodeID, "Error adding/updating cluster(s) bad: cluster bad is invalid")
+	}
+	ack := func(stream int64, nodeID string) {
+		node := &core.Node{Id: "myrole~" + nodeID}
+		tracker.OnStreamOpen(stream, "")
+		tracker.OnStreamRequest(stream, &v2.DiscoveryRequest{Node: node, TypeUrl: envoycache.ClusterType})
+		tracker.OnStreamResponse(stream, nil, &v2.DiscoveryResponse{TypeUrl: envoycache.ClusterType, VersionInfo: "1", Nonce: "a"})
+		tracker.OnStreamRequest(stream, &v2.DiscoveryRequest{
+			Node:          node,
+			TypeUrl:       envoycache.ClusterType,
+			VersionInfo:   "1",
+			ResponseNonce: "a",
+		})
+	}
+	BeforeEach(func() {
+		roles, err := xds.NewRoleAssigner(bootstrap.XdsOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		tracker = xds.NewNodeTracker(roles)
+		snap := &envoycache.Snapshot{Clusters: envoycache.NewResources("1", nil)}
+		served = map[string]servedRole{
+			"myrole": {snapshot: snap, cfg: &v1.Config{Upstreams: []*v1.Upstream{good, bad}}, role: role},
+		}
+	})
+	It("reports the object named in the error as rejected when every node rejects it", func() {
+		nack(1, "node-1")
+		reports := nackReports(served, tracker)
+		Expect(reports).To(HaveLen(1))
+		Expect(reports[0].CfgObject).To(Equal(bad))
+		Expect(reports[0].Err.Error()).To(ContainSubstring("cluster bad is invalid"))
+		Expect(reports[0].PartiallyApplied).To(BeFalse())
+	})
+	It("reports the object as partially applied when some nodes accept it", func() {
+		nack(1, "node-1")
+		ack(2, "node-2")
+		reports := nackReports(served, tracker)
+		Expect(reports).To(HaveLen(1))
+		Expect(reports[0].PartiallyApplied).To(BeTrue())
+	})
+	It("reports the virtual services whose certificates were rejected", func() {
+		secure := &v1.VirtualService{Name: "secure", SslConfig: &v1.SSLConfig{SecretRef: "secure-cert"}}
+		other := &v1.VirtualService{Name: "other", SslConfig: &v1.SSLConfig{SecretRef: "other-cert"}}
+		plain := &v1.VirtualService{Name: "plain"}
+		cfg := &v1.Config{VirtualServices: []*v1.VirtualService{secure, other, plain}}
+		Expect(objectsForNack(cfg, xds.Nack{TypeUrl: envoycache.SecretType, ResourceNames: []string{"ssl-certificate/secure-cert"}})).
+			To(Equal([]v1.ConfigObject{secure}))
+		Expect(objectsForNack(cfg, xds.Nack{TypeUrl: envoycache.SecretType, Message: "invalid certificate"})).
+			To(BeEmpty())
+	})
+	It("matches the exact names of the rejected resources", func() {
+		foo := &v1.Upstream{Name: "foo"}
+		foobar := &v1.Upstream{Name: "foobar"}
+		cfg := &v1.Config{
+			Upstreams:       []*v1.Upstream{foo, foobar},
+			VirtualServices: []*v1.VirtualService{{Name: "foo"}, {Name: "routes-8080"}},
+		}
+		Expect(objectsForNack(cfg, xds.Nack{TypeUrl: envoycache.ClusterType, Message: "cluster 'foobar': invalid"})).
+			To(Equal([]v1.ConfigObject{foobar}))
+		Expect(objectsForNack(cfg, xds.Nack{TypeUrl: envoycache.EndpointType, ResourceNames: []string{"foo"}})).
+			To(Equal([]v1.ConfigObject{foo}))
+		Expect(objectsForNack(cfg, xds.Nack{TypeUrl: envoycache.SecretType, ResourceNames: []string{"client-certificate-1/foo"}})).
+			To(Equal([]v1.ConfigObject{foo}))
+		// the names requested for routes are route configurations, not virtual hosts
+		Expect(objectsForNack(cfg, xds.Nack{TypeUrl: envoycache.RouteType, ResourceNames: []string{"routes-8080"}})).
+			To(BeEmpty())
+		Expect(objectsForNack(cfg, xds.Nack{TypeUrl: envoycache.RouteType, Message: "virtual host foo has duplicate domains."})).
+			To(Equal([]v1.ConfigObject{cfg.VirtualServices[0]}))
+	})
+	It("reports rejected config that no object produced on the role", func() {
+		nackWithMessage(1, "node-1", "cluster foobar is invalid")
+		reports := nackReports(served, tracker)
+		Expect(reports).To(HaveLen(1))
+		Expect(reports[0].CfgObject).To(Equal(role))
+		Expect(reports[0].Err.Error()).To(ContainSubstring("cluster foobar is invalid"))
+		Expect(reports[0].PartiallyApplied).To(BeFalse())
+	})
+	It("ignores nacks for versions that are no longer served", func() {
+		nack(1, "node-1")
+		served["myrole"].snapshot.Clusters.Version = "2"
+		Expect(nackReports(served, tracker)).To(BeEmpty())
+	})
+})
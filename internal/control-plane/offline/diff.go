@@ -0,0 +1,349 @@
+package offline
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/d4l3k/messagediff"
+	envoyapi "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	envoyauth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
+	envoycore "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	envoycache "github.com/envoyproxy/go-control-plane/pkg/cache"
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/solo-io/gloo/internal/control-plane/snapshot"
+	"github.com/solo-io/gloo/internal/control-plane/xds"
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+)
+
+// ChangeType describes how an object differs between two snapshots
+type ChangeType string
+
+const (
+	Added    ChangeType = "added"
+	Removed  ChangeType = "removed"
+	Modified ChangeType = "modified"
+)
+
+// Change is a difference in a single gloo object or envoy resource
+type Change struct {
+	// the role the envoy resource is served to. empty for gloo objects
+	Role string `json:"role,omitempty"`
+	// upstream, virtualservice, role, secret or file for gloo objects.
+	// cluster, route, listener or tls for envoy resources
+	Kind string     `json:"kind"`
+	Name string     `json:"name"`
+	Type ChangeType `json:"type"`
+	// what changed, for modified objects
+	Details []string `json:"details,omitempty"`
+}
+
+// Ref identifies the changed object as <kind>/<name>
+func (c Change) Ref() string {
+	return c.Kind + "/" + c.Name
+}
+
+// DiffConfig compares the gloo objects, secrets and files of two snapshots.
+// statuses and resource versions are ignored, and secret values are never shown
+func DiffConfig(old, new *snapshot.Cache) []Change {
+	var changes []Change
+	changes = append(changes, diffObjects("", "upstream", upstreams(old), upstreams(new))...)
+	changes = append(changes, diffObjects("", "virtualservice", virtualServices(old), virtualServices(new))...)
+	changes = append(changes, diffObjects("", "role", roles(old), roles(new))...)
+	changes = append(changes, diffObjects("", "secret", secrets(old), secrets(new))...)
+	changes = append(changes, diffObjects("", "file", files(old), files(new))...)
+	return changes
+}
+
+// DiffEnvoy compares the envoy resources served to each role.
+// tls certificate chains are compared on their own, and private keys and the
+// credentials plugins copy into clusters and routes are never shown, only a hash of them
+func DiffEnvoy(old, new *Result) []Change {
+	roleNames := make(map[string]bool)
+	for role := range old.Snapshots {
+		roleNames[role] = true
+	}
+	for role := range new.Snapshots {
+		roleNames[role] = true
+	}
+	var changes []Change
+	for _, role := range sortedNames(roleNames) {
+		oldSnap, newSnap := old.Snapshots[role], new.Snapshots[role]
+		oldRedacted, newRedacted := xds.RedactSnapshot(oldSnap), xds.RedactSnapshot(newSnap)
+		changes = append(changes, diffObjects(role, "cluster", resources(oldRedacted, envoycache.ClusterType), resources(newRedacted, envoycache.ClusterType))...)
+		changes = append(changes, diffObjects(role, "route", resources(oldRedacted, envoycache.RouteType), resources(newRedacted, envoycache.RouteType))...)
+		oldListeners, oldChains := listeners(oldSnap)
+		newListeners, newChains := listeners(newSnap)
+		addSecretChains(oldSnap, oldChains)
+		addSecretChains(newSnap, newChains)
+		changes = append(changes, diffObjects(role, "listener", oldListeners, newListeners)...)
+		changes = append(changes, diffObjects(role, "tls", oldChains, newChains)...)
+	}
+	return changes
+}
+
+func diffObjects(role, kind string, old, new map[string]interface{}) []Change {
+	names := make(map[string]bool)
+	for name := range old {
+		names[name] = true
+	}
+	for name := range new {
+		names[name] = true
+	}
+	var changes []Change
+	for _, name := range sortedNames(names) {
+		change := Change{Role: role, Kind: kind, Name: name}
+		oldObj, inOld := old[name]
+		newObj, inNew := new[name]
+		switch {
+		case !inOld:
+			change.Type = Added
+		case !inNew:
+			change.Type = Removed
+		default:
+			diff, equal := messagediff.PrettyDiff(oldObj, newObj)
+			if equal {
+				continue
+			}
+			change.Type = Modified
+			change.Details = strings.Split(strings.TrimSpace(diff), "\n")
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func sortedNames(names map[string]bool) []string {
+	var sorted []string
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// the resource version changes whenever the object is written, so it is not compared.
+// statuses are cleared by the callers for the same reason
+func withoutResourceVersion(metadata *v1.Metadata) *v1.Metadata {
+	if metadata == nil {
+		return nil
+	}
+	metadata.ResourceVersion = ""
+	return metadata
+}
+
+func upstreams(snap *snapshot.Cache) map[string]interface{} {
+	out := make(map[string]interface{})
+	for _, us := range snap.Cfg.Upstreams {
+		us = proto.Clone(us).(*v1.Upstream)
+		us.Status = nil
+		us.Metadata = withoutResourceVersion(us.Metadata)
+		out[us.Name] = us
+	}
+	return out
+}
+
+func virtualServices(snap *snapshot.Cache) map[string]interface{} {
+	out := make(map[string]interface{})
+	for _, vs := range snap.Cfg.VirtualServices {
+		vs = proto.Clone(vs).(*v1.VirtualService)
+		vs.Status = nil
+		vs.Metadata = withoutResourceVersion(vs.Metadata)
+		out[vs.Name] = vs
+	}
+	return out
+}
+
+func roles(snap *snapshot.Cache) map[string]interface{} {
+	out := make(map[string]interface{})
+	for _, role := range snap.Cfg.Roles {
+		role = proto.Clone(role).(*v1.Role)
+		role.Status = nil
+		role.Metadata = withoutResourceVersion(role.Metadata)
+		out[role.Name] = role
+	}
+	return out
+}
+
+// secret values are replaced with a short hash, so changes are visible without revealing them
+func secrets(snap *snapshot.Cache) map[string]interface{} {
+	out := make(map[string]interface{})
+	for ref, secret := range snap.Secrets {
+		hashed := make(map[string]string)
+		for key, value := range secret.Data {
+			hashed[key] = fingerprint([]byte(value))
+		}
+		out[ref] = hashed
+	}
+	return out
+}
+
+type fileContents struct {
+	Size   int
+	SHA256 string
+}
+
+func files(snap *snapshot.Cache) map[string]interface{} {
+	out := make(map[string]interface{})
+	for ref, f := range snap.Files {
+		out[ref] = fileContents{Size: len(f.Contents), SHA256: fingerprint(f.Contents)}
+	}
+	return out
+}
+
+func fingerprint(data []byte) string {
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("sha256:%x", sum[:8])
+}
+
+func resources(snap *envoycache.Snapshot, typeUrl string) map[string]interface{} {
+	out := make(map[string]interface{})
+	if snap == nil {
+		return out
+	}
+	var res envoycache.Resources
+	switch typeUrl {
+	case envoycache.ClusterType:
+		res = snap.Clusters
+	case envoycache.RouteType:
+		res = snap.Routes
+	case envoycache.ListenerType:
+		res = snap.Listeners
+	case envoycache.SecretType:
+		res = snap.Secrets
+	}
+	for name, resource := range res.Items {
+		out[name] = resource
+	}
+	return out
+}
+
+// tlsChain summarizes the certificates served by a filter chain
+type tlsChain struct {
+	Certificates []certificate
+	PrivateKey   string
+}
+
+type certificate struct {
+	Subject   string
+	Issuer    string
+	DNSNames  []string
+	NotBefore string
+	NotAfter  string
+	SHA256    string
+}
+
+// listeners are returned without their certificates and private keys.
+// the certificates of each filter chain are returned separately, named
+// after the listener and the names in the leaf certificate
+func listeners(snap *envoycache.Snapshot) (map[string]interface{}, map[string]interface{}) {
+	listenersOut := make(map[string]interface{})
+	chainsOut := make(map[string]interface{})
+	for name, resource := range resources(snap, envoycache.ListenerType) {
+		listener, ok := resource.(*envoyapi.Listener)
+		if !ok {
+			listenersOut[name] = resource
+			continue
+		}
+		listener = proto.Clone(listener).(*envoyapi.Listener)
+		for _, filterChain := range listener.FilterChains {
+			if filterChain.TlsContext == nil || filterChain.TlsContext.CommonTlsContext == nil {
+				continue
+			}
+			for _, tlsCert := range filterChain.TlsContext.CommonTlsContext.TlsCertificates {
+				chain := tlsChain{
+					Certificates: parseCertificates(dataSource(tlsCert.CertificateChain)),
+					PrivateKey:   fingerprint([]byte(dataSource(tlsCert.PrivateKey))),
+				}
+				chainName := name + "/" + chainIdentity(chain)
+				// filter chains for the same names are told apart by their order
+				for i := 1; chainsOut[chainName] != nil; i++ {
+					chainName = fmt.Sprintf("%v/%v-%v", name, chainIdentity(chain), i)
+				}
+				chainsOut[chainName] = chain
+				tlsCert.CertificateChain = nil
+				tlsCert.PrivateKey = nil
+			}
+		}
+		listenersOut[name] = listener
+	}
+	return listenersOut, chainsOut
+}
+
+// certificates served over sds are named after the secret and the names in the leaf certificate
+func addSecretChains(snap *envoycache.Snapshot, chains map[string]interface{}) {
+	for name, resource := range resources(snap, envoycache.SecretType) {
+		secret, ok := resource.(*envoyauth.Secret)
+		if !ok || secret.GetTlsCertificate() == nil {
+			continue
+		}
+		tlsCert := secret.GetTlsCertificate()
+		chain := tlsChain{
+			Certificates: parseCertificates(dataSource(tlsCert.CertificateChain)),
+			PrivateKey:   fingerprint([]byte(dataSource(tlsCert.PrivateKey))),
+		}
+		chains[name+"/"+chainIdentity(chain)] = chain
+	}
+}
+
+func dataSource(ds *envoycore.DataSource) string {
+	if ds == nil {
+		return ""
+	}
+	switch specifier := ds.Specifier.(type) {
+	case *envoycore.DataSource_InlineString:
+		return specifier.InlineString
+	case *envoycore.DataSource_InlineBytes:
+		return string(specifier.InlineBytes)
+	case *envoycore.DataSource_Filename:
+		return "file:" + specifier.Filename
+	}
+	return ""
+}
+
+func parseCertificates(chain string) []certificate {
+	var certs []certificate
+	rest := []byte(chain)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			certs = append(certs, certificate{Subject: "invalid certificate: " + err.Error(), SHA256: fingerprint(block.Bytes)})
+			continue
+		}
+		certs = append(certs, certificate{
+			Subject:   cert.Subject.String(),
+			Issuer:    cert.Issuer.String(),
+			DNSNames:  cert.DNSNames,
+			NotBefore: cert.NotBefore.UTC().Format(time.RFC3339),
+			NotAfter:  cert.NotAfter.UTC().Format(time.RFC3339),
+			SHA256:    fingerprint(block.Bytes),
+		})
+	}
+	return certs
+}
+
+// the names the leaf certificate is valid for identify the chain, so a renewed
+// certificate shows up as modified rather than as removed and added
+func chainIdentity(chain tlsChain) string {
+	if len(chain.Certificates) == 0 {
+		return "unknown"
+	}
+	leaf := chain.Certificates[0]
+	if len(leaf.DNSNames) > 0 {
+		return strings.Join(leaf.DNSNames, ",")
+	}
+	return leaf.Subject
+}
@@ -0,0 +1,172 @@
+package offline_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo/internal/control-plane/bootstrap"
+	. "github.com/solo-io/gloo/internal/control-plane/offline"
+	"github.com/solo-io/gloo/internal/control-plane/snapshot"
+	"github.com/solo-io/gloo/internal/control-plane/translator"
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/coreplugins/service"
+	"github.com/solo-io/gloo/pkg/plugins"
+	"github.com/solo-io/gloo/pkg/plugins/aws"
+	"github.com/solo-io/gloo/pkg/secretwatcher"
+	"github.com/solo-io/gloo/pkg/storage/dependencies"
+	"github.com/solo-io/gloo/test/helpers"
+)
+
+func refs(changes []Change) []string {
+	var out []string
+	for _, change := range changes {
+		out = append(out, string(change.Type)+" "+change.Role+" "+change.Ref())
+	}
+	return out
+}
+
+func sslSnapshot(cert *helpers.TestCert) *snapshot.Cache {
+	vs := newVirtualService("vs", "example.com", "us")
+	vs.SslConfig = &v1.SSLConfig{SecretRef: "tls"}
+	return &snapshot.Cache{
+		Cfg: &v1.Config{
+			Upstreams:       []*v1.Upstream{newUpstream("us")},
+			VirtualServices: []*v1.VirtualService{vs},
+		},
+		Secrets: secretwatcher.SecretMap{
+			"tls": {Ref: "tls", Data: map[string]string{"ca_chain": cert.CertPEM, "private_key": cert.KeyPEM}},
+		},
+	}
+}
+
+var _ = Describe("Diff", func() {
+	var trans *translator.Translator
+	BeforeEach(func() {
+		trans = translator.NewTranslator(bootstrap.IngressOptions{BindAddress: "::", Port: 8080, SecurePort: 8443},
+			[]plugins.TranslatorPlugin{service.NewPlugin()})
+	})
+
+	Describe("DiffConfig", func() {
+		It("reports added, removed and modified objects, ignoring status and resource versions", func() {
+			changedUs := newUpstream("changed")
+			old := &snapshot.Cache{Cfg: &v1.Config{
+				Upstreams: []*v1.Upstream{newUpstream("same"), newUpstream("removed"), changedUs},
+			}}
+			sameUs := newUpstream("same")
+			sameUs.Status = &v1.Status{State: v1.Status_Accepted}
+			sameUs.Metadata = &v1.Metadata{ResourceVersion: "2"}
+			changedUs = newUpstream("changed")
+			changedUs.Type = "other"
+			new := &snapshot.Cache{Cfg: &v1.Config{
+				Upstreams: []*v1.Upstream{sameUs, newUpstream("added"), changedUs},
+			}}
+			changes := DiffConfig(old, new)
+			Expect(refs(changes)).To(Equal([]string{
+				"added  upstream/added",
+				"modified  upstream/changed",
+				"removed  upstream/removed",
+			}))
+			Expect(strings.Join(changes[1].Details, "\n")).To(ContainSubstring("other"))
+		})
+		It("never shows secret values", func() {
+			old := &snapshot.Cache{Cfg: &v1.Config{}, Secrets: secretwatcher.SecretMap{
+				"s": {Ref: "s", Data: map[string]string{"password": "hunter2"}},
+			}}
+			new := &snapshot.Cache{Cfg: &v1.Config{}, Secrets: secretwatcher.SecretMap{
+				"s": {Ref: "s", Data: map[string]string{"password": "correct horse"}},
+			}}
+			changes := DiffConfig(old, new)
+			Expect(refs(changes)).To(Equal([]string{"modified  secret/s"}))
+			details := strings.Join(changes[0].Details, "\n")
+			Expect(details).To(ContainSubstring("password"))
+			Expect(details).NotTo(ContainSubstring("hunter2"))
+			Expect(details).NotTo(ContainSubstring("correct horse"))
+		})
+		It("compares file contents", func() {
+			old := &snapshot.Cache{Cfg: &v1.Config{}, Files: map[string]*dependencies.File{
+				"f": {Ref: "f", Contents: []byte("a")},
+			}}
+			new := &snapshot.Cache{Cfg: &v1.Config{}, Files: map[string]*dependencies.File{
+				"f": {Ref: "f", Contents: []byte("b")},
+			}}
+			Expect(refs(DiffConfig(old, new))).To(Equal([]string{"modified  file/f"}))
+		})
+	})
+
+	Describe("DiffEnvoy", func() {
+		It("reports the envoy resources that change for each role", func() {
+			old, err := Translate(trans, &snapshot.Cache{Cfg: &v1.Config{
+				Upstreams:       []*v1.Upstream{newUpstream("us")},
+				VirtualServices: []*v1.VirtualService{newVirtualService("vs", "example.com", "us")},
+			}})
+			Expect(err).NotTo(HaveOccurred())
+			new, err := Translate(trans, &snapshot.Cache{Cfg: &v1.Config{
+				Upstreams: []*v1.Upstream{newUpstream("us"), newUpstream("us2")},
+				VirtualServices: []*v1.VirtualService{
+					newVirtualService("vs", "example.com", "us2"),
+					newVirtualService("edge", "edge.example.com", "us", "edge"),
+				},
+			}})
+			Expect(err).NotTo(HaveOccurred())
+			changes := refs(DiffEnvoy(old, new))
+			Expect(changes).To(ContainElement("added edge cluster/us"))
+			Expect(changes).To(ContainElement("added edge listener/listener-gloo-rds-http"))
+			Expect(changes).To(ContainElement("added ingress cluster/us2"))
+			Expect(changes).To(ContainElement("removed ingress cluster/us"))
+			Expect(changes).To(ContainElement("modified ingress route/gloo-rds-http"))
+			Expect(changes).NotTo(ContainElement("modified ingress listener/listener-gloo-rds-http"))
+		})
+		It("compares tls certificate chains without showing private keys", func() {
+			oldCert, err := helpers.NewTestCert("example.com", []string{"example.com"}, time.Now().Add(time.Hour), nil)
+			Expect(err).NotTo(HaveOccurred())
+			newCert, err := helpers.NewTestCert("example.com", []string{"example.com"}, time.Now().Add(48*time.Hour), nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			old, err := Translate(trans, sslSnapshot(oldCert))
+			Expect(err).NotTo(HaveOccurred())
+			new, err := Translate(trans, sslSnapshot(newCert))
+			Expect(err).NotTo(HaveOccurred())
+
+			changes := DiffEnvoy(old, new)
+			Expect(refs(changes)).To(Equal([]string{"modified ingress tls/tls/example.com"}))
+			details := strings.Join(changes[0].Details, "\n")
+			Expect(details).To(ContainSubstring("NotAfter"))
+			Expect(details).To(ContainSubstring("PrivateKey"))
+			Expect(details).NotTo(ContainSubstring("PRIVATE KEY"))
+		})
+		It("never shows the aws credentials of lambda upstreams", func() {
+			trans = translator.NewTranslator(bootstrap.IngressOptions{BindAddress: "::", Port: 8080, SecurePort: 8443},
+				[]plugins.TranslatorPlugin{service.NewPlugin(), &aws.Plugin{}})
+			lambdaSnapshot := func(secretKey string) *snapshot.Cache {
+				return &snapshot.Cache{
+					Cfg: &v1.Config{
+						Upstreams: []*v1.Upstream{{
+							Name: "lambda",
+							Type: aws.UpstreamTypeAws,
+							Spec: aws.EncodeUpstreamSpec(aws.UpstreamSpec{Region: "us-east-1", SecretRef: "aws"}),
+						}},
+						VirtualServices: []*v1.VirtualService{newVirtualService("vs", "example.com", "lambda")},
+					},
+					Secrets: secretwatcher.SecretMap{
+						"aws": {Ref: "aws", Data: map[string]string{aws.AwsAccessKey: "AKIDEXAMPLE", aws.AwsSecretKey: secretKey}},
+					},
+				}
+			}
+			old, err := Translate(trans, lambdaSnapshot("old-secret-key"))
+			Expect(err).NotTo(HaveOccurred())
+			new, err := Translate(trans, lambdaSnapshot("new-secret-key"))
+			Expect(err).NotTo(HaveOccurred())
+
+			changes := DiffEnvoy(old, new)
+			Expect(refs(changes)).To(Equal([]string{"modified ingress cluster/lambda"}))
+			details := strings.Join(changes[0].Details, "\n")
+			Expect(details).To(ContainSubstring("redacted-sha256-"))
+			Expect(details).NotTo(ContainSubstring("old-secret-key"))
+			Expect(details).NotTo(ContainSubstring("new-secret-key"))
+			Expect(details).NotTo(ContainSubstring("AKIDEXAMPLE"))
+		})
+	})
+})
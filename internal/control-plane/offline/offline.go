@@ -0,0 +1,173 @@
+package offline
+
+import (
+	"os"
+	"sort"
+	"time"
+
+	envoycache "github.com/envoyproxy/go-control-plane/pkg/cache"
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/internal/control-plane/filewatcher"
+	"github.com/solo-io/gloo/internal/control-plane/reporter"
+	"github.com/solo-io/gloo/internal/control-plane/snapshot"
+	"github.com/solo-io/gloo/internal/control-plane/translator"
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/bootstrap"
+	"github.com/solo-io/gloo/pkg/bootstrap/artifactstorage"
+	"github.com/solo-io/gloo/pkg/bootstrap/configstorage"
+	"github.com/solo-io/gloo/pkg/bootstrap/secretstorage"
+	"github.com/solo-io/gloo/pkg/endpointdiscovery"
+	"github.com/solo-io/gloo/pkg/secretwatcher"
+	"github.com/solo-io/gloo/pkg/storage"
+	"github.com/solo-io/gloo/pkg/storage/dependencies"
+	filestorage "github.com/solo-io/gloo/pkg/storage/dependencies/file"
+	"github.com/solo-io/gloo/pkg/storage/file"
+)
+
+// nothing is watched, so the sync frequency is irrelevant
+const syncFrequency = time.Minute
+
+// LoadDir reads the config, secrets and files from local directories,
+// in the layout used by the file storage backends
+func LoadDir(opts bootstrap.FileOptions) (*snapshot.Cache, error) {
+	if _, err := os.Stat(opts.ConfigDir); err != nil {
+		return nil, errors.Wrap(err, "reading config dir")
+	}
+	store, err := file.NewStorage(opts.ConfigDir, syncFrequency)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating config storage")
+	}
+	secretKeys, err := filestorage.LoadKeyring(opts.SecretKeyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading secret keys")
+	}
+	secrets, err := filestorage.NewSecretStorage(opts.SecretDir, syncFrequency, secretKeys)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating secret storage")
+	}
+	files, err := filestorage.NewFileStorage(opts.FilesDir, syncFrequency)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating file storage")
+	}
+	return Load(store, secrets, files)
+}
+
+// LoadStorage reads the config, secrets and files from the storage backends selected in the options
+func LoadStorage(opts bootstrap.Options) (*snapshot.Cache, error) {
+	store, err := configstorage.Bootstrap(opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating config storage")
+	}
+	secrets, err := secretstorage.Bootstrap(opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating secret storage")
+	}
+	files, err := artifactstorage.Bootstrap(opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating file storage")
+	}
+	return Load(store, secrets, files)
+}
+
+// Load lists everything in storage once, rather than watching it.
+// endpoints are not discovered, so the snapshot contains none
+func Load(store storage.Interface, secrets dependencies.SecretStorage, files dependencies.FileStorage) (*snapshot.Cache, error) {
+	upstreams, err := store.V1().Upstreams().List()
+	if err := ignoreMissingDir(err); err != nil {
+		return nil, errors.Wrap(err, "listing upstreams")
+	}
+	virtualServices, err := store.V1().VirtualServices().List()
+	if err := ignoreMissingDir(err); err != nil {
+		return nil, errors.Wrap(err, "listing virtual services")
+	}
+	roles, err := store.V1().Roles().List()
+	if err := ignoreMissingDir(err); err != nil {
+		return nil, errors.Wrap(err, "listing roles")
+	}
+	secretList, err := secrets.List()
+	if err := ignoreMissingDir(err); err != nil {
+		return nil, errors.Wrap(err, "listing secrets")
+	}
+	fileList, err := files.List()
+	if err := ignoreMissingDir(err); err != nil {
+		return nil, errors.Wrap(err, "listing files")
+	}
+
+	snap := &snapshot.Cache{
+		Cfg: &v1.Config{
+			Upstreams:       upstreams,
+			VirtualServices: virtualServices,
+			Roles:           roles,
+		},
+		Secrets:   make(secretwatcher.SecretMap),
+		Files:     make(filewatcher.Files),
+		Endpoints: make(endpointdiscovery.EndpointGroups),
+	}
+	for _, secret := range secretList {
+		snap.Secrets[secret.Ref] = secret
+	}
+	for _, f := range fileList {
+		snap.Files[f.Ref] = f
+	}
+	return snap, nil
+}
+
+// the file backends fail to list a directory that doesn't exist.
+// e.g. a config dir without a roles directory simply has no roles
+func ignoreMissingDir(err error) error {
+	if err != nil && os.IsNotExist(errors.Cause(err)) {
+		return nil
+	}
+	return err
+}
+
+// Result is the output of translating every role
+type Result struct {
+	// the xds snapshot for each role
+	Snapshots map[string]*envoycache.Snapshot
+	// one report per config object, sorted by type and name.
+	// an object rejected in any role is reported as rejected
+	Reports []reporter.ConfigObjectReport
+}
+
+// Rejected returns the reports with errors
+func (r *Result) Rejected() []reporter.ConfigObjectReport {
+	var rejected []reporter.ConfigObjectReport
+	for _, rep := range r.Reports {
+		if rep.Err != nil {
+			rejected = append(rejected, rep)
+		}
+	}
+	return rejected
+}
+
+// Translate groups the virtual services by role and translates each role,
+// the same way the control plane does
+func Translate(trans *translator.Translator, snap *snapshot.Cache) (*Result, error) {
+	result := &Result{Snapshots: make(map[string]*envoycache.Snapshot)}
+	allReports := make(map[string]reporter.ConfigObjectReport)
+	for role, virtualServices := range snap.VirtualServicesByRole() {
+		xdsSnapshot, reports, err := trans.Translate(snapshot.NewRole(role, virtualServices), snap.ForRole(virtualServices))
+		if err != nil {
+			return nil, errors.Wrapf(err, "translating role %v", role)
+		}
+		result.Snapshots[role] = xdsSnapshot
+		for _, rep := range reports {
+			key := reporter.ObjectRef(rep.CfgObject)
+			if existing, ok := allReports[key]; ok {
+				rep = reporter.MergeReports(existing, rep)
+			}
+			allReports[key] = rep
+		}
+	}
+	var keys []string
+	for key := range allReports {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		result.Reports = append(result.Reports, allReports[key])
+	}
+	return result, nil
+}
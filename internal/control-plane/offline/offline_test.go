@@ -0,0 +1,141 @@
+package offline_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo/internal/control-plane/bootstrap"
+	. "github.com/solo-io/gloo/internal/control-plane/offline"
+	"github.com/solo-io/gloo/internal/control-plane/reporter"
+	"github.com/solo-io/gloo/internal/control-plane/snapshot"
+	"github.com/solo-io/gloo/internal/control-plane/translator"
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	gloobootstrap "github.com/solo-io/gloo/pkg/bootstrap"
+	"github.com/solo-io/gloo/pkg/coreplugins/service"
+	"github.com/solo-io/gloo/pkg/plugins"
+	"github.com/solo-io/gloo/pkg/storage"
+	"github.com/solo-io/gloo/pkg/storage/file"
+)
+
+func newUpstream(name string) *v1.Upstream {
+	return &v1.Upstream{
+		Name: name,
+		Type: service.UpstreamTypeService,
+		Spec: service.EncodeUpstreamSpec(service.UpstreamSpec{
+			Hosts: []service.Host{{Addr: "localhost", Port: 1234}},
+		}),
+	}
+}
+
+func newVirtualService(name, domain, upstream string, roles ...string) *v1.VirtualService {
+	return &v1.VirtualService{
+		Name:    name,
+		Domains: []string{domain},
+		Roles:   roles,
+		Routes: []*v1.Route{{
+			Matcher: &v1.Route_RequestMatcher{
+				RequestMatcher: &v1.RequestMatcher{
+					Path: &v1.RequestMatcher_PathPrefix{PathPrefix: "/"},
+				},
+			},
+			SingleDestination: &v1.Destination{
+				DestinationType: &v1.Destination_Upstream{
+					Upstream: &v1.UpstreamDestination{Name: upstream},
+				},
+			},
+		}},
+	}
+}
+
+func rejectedRefs(reports []reporter.ConfigObjectReport) []string {
+	var refs []string
+	for _, rep := range reports {
+		refs = append(refs, reporter.ObjectRef(rep.CfgObject))
+	}
+	return refs
+}
+
+var _ = Describe("Offline", func() {
+	var (
+		dir   string
+		store storage.Interface
+		opts  gloobootstrap.FileOptions
+		trans *translator.Translator
+	)
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "offline-test")
+		Expect(err).NotTo(HaveOccurred())
+		opts = gloobootstrap.FileOptions{
+			ConfigDir: filepath.Join(dir, "config"),
+			SecretDir: filepath.Join(dir, "secrets"),
+			FilesDir:  filepath.Join(dir, "files"),
+		}
+		store, err = file.NewStorage(opts.ConfigDir, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(store.V1().Register()).To(Succeed())
+		trans = translator.NewTranslator(bootstrap.IngressOptions{BindAddress: "::", Port: 8080, SecurePort: 8443},
+			[]plugins.TranslatorPlugin{service.NewPlugin()})
+	})
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	Describe("LoadDir", func() {
+		It("loads the config, and treats missing secret and file dirs as empty", func() {
+			_, err := store.V1().Upstreams().Create(newUpstream("us"))
+			Expect(err).NotTo(HaveOccurred())
+			_, err = store.V1().VirtualServices().Create(newVirtualService("vs", "example.com", "us"))
+			Expect(err).NotTo(HaveOccurred())
+
+			snap, err := LoadDir(opts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(snap.Cfg.Upstreams).To(HaveLen(1))
+			Expect(snap.Cfg.VirtualServices).To(HaveLen(1))
+			Expect(snap.Secrets).To(BeEmpty())
+			Expect(snap.Files).To(BeEmpty())
+		})
+		It("fails when the config dir does not exist", func() {
+			opts.ConfigDir = filepath.Join(dir, "nothing-here")
+			_, err := LoadDir(opts)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Translate", func() {
+		It("translates each role", func() {
+			snap := &snapshot.Cache{Cfg: &v1.Config{
+				Upstreams: []*v1.Upstream{newUpstream("us")},
+				VirtualServices: []*v1.VirtualService{
+					newVirtualService("vs1", "one.example.com", "us"),
+					newVirtualService("vs2", "two.example.com", "us", "edge"),
+				},
+			}}
+			result, err := Translate(trans, snap)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Snapshots).To(HaveKey(snapshot.DefaultRole))
+			Expect(result.Snapshots).To(HaveKey("edge"))
+			Expect(result.Snapshots["edge"].Clusters.Items).To(HaveLen(1))
+			Expect(result.Snapshots["edge"].Listeners.Items).To(HaveLen(1))
+			Expect(result.Rejected()).To(BeEmpty())
+		})
+		It("reports objects rejected in any role", func() {
+			snap := &snapshot.Cache{Cfg: &v1.Config{
+				Upstreams: []*v1.Upstream{newUpstream("us")},
+				VirtualServices: []*v1.VirtualService{
+					newVirtualService("good", "good.example.com", "us"),
+					newVirtualService("bad", "bad.example.com", "missing", "edge"),
+				},
+			}}
+			result, err := Translate(trans, snap)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rejectedRefs(result.Rejected())).To(Equal([]string{"virtualservice/bad"}))
+			Expect(result.Rejected()[0].Err.Error()).To(ContainSubstring("upstream missing was not found"))
+		})
+	})
+})
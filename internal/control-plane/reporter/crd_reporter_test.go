@@ -198,11 +198,18 @@ var _ = Describe("CrdReporter", func() {
 				// zero out fields we dont care about & expect to be different
 				for _, role := range roleList {
 					role.Metadata = &v1.Metadata{}
+					Expect(role.Status.LastTransitionTime).NotTo(BeNil())
+					role.Status.LastTransitionTime = nil
+					role.Status.ObservedResourceVersion = ""
 				}
 				for _, role := range roles {
 					role.Status = &v1.Status{
 						State:  v1.Status_Rejected,
 						Reason: "oh no an error what did u do!",
+						Conditions: []*v1.Condition{{
+							Severity: v1.Condition_Error,
+							Message:  "oh no an error what did u do!",
+						}},
 					}
 					role.Metadata = &v1.Metadata{}
 					Expect(roleList).To(ContainElement(role))
@@ -1,14 +1,91 @@
 package reporter
 
 import (
+	"fmt"
+	"sort"
+
 	"github.com/solo-io/gloo/pkg/api/types/v1"
 )
 
 type ConfigObjectReport struct {
 	CfgObject v1.ConfigObject
 	Err       error
+	// Warnings are problems that do not cause the object to be rejected,
+	// e.g. a route to a function that its upstream does not declare
+	Warnings []error
+	// PartiallyApplied is set when the error was reported by some,
+	// but not all of the envoy proxies the object was sent to
+	PartiallyApplied bool
+	// AcceptedRoles are the roles the object was translated for without errors
+	AcceptedRoles []string
+	// Certificates are the tls certificates served for the object
+	Certificates []*v1.Certificate
+}
+
+// RouteError is an error or warning for a single route of a virtual service.
+// it is reported as a condition on that route
+type RouteError struct {
+	// the index of the route in the virtual service
+	Index int
+	Err   error
+}
+
+func (e *RouteError) Error() string {
+	return fmt.Sprintf("route %v: %v", e.Index, e.Err)
+}
+
+// MergeReports combines two reports for the same object, e.g. from translating
+// different roles. an object rejected in either report is reported as rejected,
+// and the warnings and accepted roles of both reports are kept
+func MergeReports(existing, report ConfigObjectReport) ConfigObjectReport {
+	merged := existing
+	if merged.Err == nil {
+		merged.Err = report.Err
+		merged.PartiallyApplied = report.PartiallyApplied
+	}
+	merged.Warnings = nil
+	seenWarnings := make(map[string]bool)
+	for _, warning := range append(append([]error{}, existing.Warnings...), report.Warnings...) {
+		if seenWarnings[warning.Error()] {
+			continue
+		}
+		seenWarnings[warning.Error()] = true
+		merged.Warnings = append(merged.Warnings, warning)
+	}
+	merged.AcceptedRoles = nil
+	seenRoles := make(map[string]bool)
+	for _, role := range append(append([]string{}, existing.AcceptedRoles...), report.AcceptedRoles...) {
+		if seenRoles[role] {
+			continue
+		}
+		seenRoles[role] = true
+		merged.AcceptedRoles = append(merged.AcceptedRoles, role)
+	}
+	sort.Strings(merged.AcceptedRoles)
+	// the certificates of an object are the same in every role
+	if len(merged.Certificates) == 0 {
+		merged.Certificates = report.Certificates
+	}
+	return merged
 }
 
 type Interface interface {
 	WriteReports(statuses []ConfigObjectReport) error
+	// PruneRoles cleans up the roles that are not in activeRoles,
+	// i.e. roles that no longer have any virtual services
+	PruneRoles(activeRoles []string) error
+}
+
+// ObjectRef identifies a config object as <type>/<name>, e.g. upstream/my-upstream.
+// it is used to merge reports for objects that belong to multiple roles
+func ObjectRef(cfgObject v1.ConfigObject) string {
+	switch cfgObject.(type) {
+	case *v1.Upstream:
+		return "upstream/" + cfgObject.GetName()
+	case *v1.VirtualService:
+		return "virtualservice/" + cfgObject.GetName()
+	case *v1.Role:
+		return "role/" + cfgObject.GetName()
+	}
+	return cfgObject.GetName()
 }
@@ -1,39 +1,98 @@
 package reporter
 
 import (
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 	"github.com/solo-io/gloo/pkg/storage"
 
 	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/backoff"
 	"github.com/solo-io/gloo/pkg/log"
 )
 
+const (
+	// roles created by the reporter are annotated with their owner,
+	// so they can be deleted once they no longer have any virtual services
+	ownerAnnotationKey = "generated_by"
+	generatedBy        = "gloo-control-plane"
+
+	// the number of objects whose status is written at the same time
+	writeBatchSize = 10
+)
+
+// writes that fail because the object was updated since it was read
+// are retried with the latest version of the object
+var defaultRetryOptions = backoff.Options{
+	InitialInterval: 100 * time.Millisecond,
+	MaxElapsedTime:  2 * time.Second,
+}
+
 type reporter struct {
 	store storage.Interface
+	now   func() time.Time
+	retry backoff.Options
+
+	// the resource version of each object after its status was last written by the reporter.
+	// an object at that version has the same spec as the version its status was observed for
+	writtenLock sync.Mutex
+	written     map[string]string
 }
 
 func NewReporter(store storage.Interface) *reporter {
-	return &reporter{store: store}
+	return &reporter{
+		store:   store,
+		now:     time.Now,
+		retry:   defaultRetryOptions,
+		written: make(map[string]string),
+	}
 }
 
+// WriteReports writes the status of each object independently, so a failure for one
+// object does not prevent the others from being written. the returned error
+// contains the failures for every object that could not be written
 func (r *reporter) WriteReports(reports []ConfigObjectReport) error {
-	for _, report := range reports {
-		if err := r.writeReport(report); err != nil {
-			return errors.Wrapf(err, "failed to write report for config object %v", report.CfgObject)
+	var (
+		errs     error
+		errsLock sync.Mutex
+	)
+	for start := 0; start < len(reports); start += writeBatchSize {
+		end := start + writeBatchSize
+		if end > len(reports) {
+			end = len(reports)
 		}
-		log.Debugf("wrote report for %v", report.CfgObject.GetName())
+		var wg sync.WaitGroup
+		for _, report := range reports[start:end] {
+			wg.Add(1)
+			go func(report ConfigObjectReport) {
+				defer wg.Done()
+				var err error
+				backoff.WithBackoffOptions(func() error {
+					err = r.writeReport(report)
+					// other errors won't be resolved by retrying
+					if storage.IsConflict(err) {
+						return err
+					}
+					return nil
+				}, nil, r.retry)
+				if err != nil {
+					errsLock.Lock()
+					errs = multierror.Append(errs, errors.Wrapf(err, "failed to write report for %v", ObjectRef(report.CfgObject)))
+					errsLock.Unlock()
+					return
+				}
+				log.Debugf("wrote report for %v", ObjectRef(report.CfgObject))
+			}(report)
+		}
+		wg.Wait()
 	}
-	return nil
+	return errs
 }
 
 func (r *reporter) writeReport(report ConfigObjectReport) error {
-	status := &v1.Status{
-		State: v1.Status_Accepted,
-	}
-	if report.Err != nil {
-		status.State = v1.Status_Rejected
-		status.Reason = report.Err.Error()
-	}
 	name := report.CfgObject.GetName()
 	switch report.CfgObject.(type) {
 	case *v1.Upstream:
@@ -41,44 +100,134 @@ func (r *reporter) writeReport(report ConfigObjectReport) error {
 		if err != nil {
 			return errors.Wrapf(err, "failed to find upstream %v", name)
 		}
+		status := r.status(report, us)
 		// only update if status doesn't match
 		if us.Status.Equal(status) {
 			return nil
 		}
 		us.Status = status
-		if _, err := r.store.V1().Upstreams().Update(us); err != nil {
+		updated, err := r.store.V1().Upstreams().Update(us)
+		if err != nil {
 			return errors.Wrapf(err, "failed to update upstream store with status report")
 		}
+		r.setWritten(updated)
 	case *v1.VirtualService:
 		virtualService, err := r.store.V1().VirtualServices().Get(name)
 		if err != nil {
 			return errors.Wrapf(err, "failed to find virtualservice %v", name)
 		}
+		status := r.status(report, virtualService)
 		// only update if status doesn't match
 		if virtualService.Status.Equal(status) {
 			return nil
 		}
 		virtualService.Status = status
-		if _, err := r.store.V1().VirtualServices().Update(virtualService); err != nil {
+		updated, err := r.store.V1().VirtualServices().Update(virtualService)
+		if err != nil {
 			return errors.Wrapf(err, "failed to update virtualservice store with status report")
 		}
+		r.setWritten(updated)
 	case *v1.Role:
 		role, err := r.store.V1().Roles().Get(name)
 		if err != nil {
 			// try to create the role
-			role, err = r.store.V1().Roles().Create(report.CfgObject.(*v1.Role))
+			role, err = r.store.V1().Roles().Create(generatedRole(report.CfgObject.(*v1.Role)))
 			if err != nil {
 				return errors.Wrapf(err, "failed to find or create role %v", name)
 			}
 		}
+		status := r.status(report, role)
 		// only update if status doesn't match
 		if role.Status.Equal(status) {
 			return nil
 		}
 		role.Status = status
-		if _, err := r.store.V1().Roles().Update(role); err != nil {
+		updated, err := r.store.V1().Roles().Update(role)
+		if err != nil {
 			return errors.Wrapf(err, "failed to update role store with status report")
 		}
+		r.setWritten(updated)
 	}
 	return nil
 }
+
+// PruneRoles cleans up roles that no longer have any virtual services.
+// roles created by the reporter are deleted, and the status of roles created
+// by users is cleared, as it no longer describes anything that is served
+func (r *reporter) PruneRoles(activeRoles []string) error {
+	roles, err := r.store.V1().Roles().List()
+	if err != nil {
+		return errors.Wrap(err, "failed to list roles")
+	}
+	active := make(map[string]bool)
+	for _, role := range activeRoles {
+		active[role] = true
+	}
+	var errs error
+	for _, role := range roles {
+		if active[role.Name] {
+			continue
+		}
+		if role.Metadata.GetAnnotations()[ownerAnnotationKey] == generatedBy {
+			if err := r.store.V1().Roles().Delete(role.Name); err != nil {
+				errs = multierror.Append(errs, errors.Wrapf(err, "failed to delete role %v", role.Name))
+				continue
+			}
+			r.forgetWritten(role)
+			log.Printf("deleted role %v, which no longer has any virtual services", role.Name)
+			continue
+		}
+		if role.Status == nil {
+			continue
+		}
+		role.Status = nil
+		if _, err := r.store.V1().Roles().Update(role); err != nil {
+			errs = multierror.Append(errs, errors.Wrapf(err, "failed to clear status of role %v", role.Name))
+		}
+	}
+	return errs
+}
+
+// roles are created by the reporter when a virtual service names a role that doesn't exist yet
+func generatedRole(role *v1.Role) *v1.Role {
+	role = proto.Clone(role).(*v1.Role)
+	if role.Metadata == nil {
+		role.Metadata = &v1.Metadata{}
+	}
+	if role.Metadata.Annotations == nil {
+		role.Metadata.Annotations = make(map[string]string)
+	}
+	role.Metadata.Annotations[ownerAnnotationKey] = generatedBy
+	return role
+}
+
+// status creates the status for the stored version of the reported object
+func (r *reporter) status(report ConfigObjectReport, stored v1.ConfigObject) *v1.Status {
+	previous := stored.GetStatus()
+	status := NewStatus(report, previous, r.now())
+	// writing a status changes the resource version, but not the spec that was observed.
+	// keeping the previous observed version prevents every status write from causing another
+	if previous != nil && r.isWritten(report.CfgObject) {
+		status.ObservedResourceVersion = previous.ObservedResourceVersion
+	}
+	return status
+}
+
+func (r *reporter) isWritten(cfgObject v1.ConfigObject) bool {
+	r.writtenLock.Lock()
+	defer r.writtenLock.Unlock()
+	version, ok := r.written[ObjectRef(cfgObject)]
+	return ok && version == cfgObject.GetMetadata().GetResourceVersion()
+}
+
+func (r *reporter) setWritten(cfgObject v1.ConfigObject) {
+	r.writtenLock.Lock()
+	defer r.writtenLock.Unlock()
+	r.written[ObjectRef(cfgObject)] = cfgObject.GetMetadata().GetResourceVersion()
+}
+
+func (r *reporter) forgetWritten(cfgObject v1.ConfigObject) {
+	r.writtenLock.Lock()
+	defer r.writtenLock.Unlock()
+	delete(r.written, ObjectRef(cfgObject))
+}
@@ -0,0 +1,171 @@
+package reporter
+
+import (
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/backoff"
+	"github.com/solo-io/gloo/pkg/storage"
+	"github.com/solo-io/gloo/pkg/storage/file"
+	. "github.com/solo-io/gloo/test/helpers"
+)
+
+// failingStorage fails updates of the virtual services in failures,
+// as many times as their count. the failures are conflicts if conflict is set
+type failingStorage struct {
+	storage.Interface
+	lock     sync.Mutex
+	failures map[string]int
+	conflict bool
+}
+
+func (s *failingStorage) V1() storage.V1 {
+	return &failingV1{V1: s.Interface.V1(), store: s}
+}
+
+type failingV1 struct {
+	storage.V1
+	store *failingStorage
+}
+
+func (v *failingV1) VirtualServices() storage.VirtualServices {
+	return &failingVirtualServices{VirtualServices: v.V1.VirtualServices(), store: v.store}
+}
+
+type failingVirtualServices struct {
+	storage.VirtualServices
+	store *failingStorage
+}
+
+func (c *failingVirtualServices) Update(vs *v1.VirtualService) (*v1.VirtualService, error) {
+	c.store.lock.Lock()
+	defer c.store.lock.Unlock()
+	if c.store.failures[vs.Name] > 0 {
+		c.store.failures[vs.Name]--
+		err := errors.Errorf("update of %v failed", vs.Name)
+		if c.store.conflict {
+			return nil, storage.NewConflictErr(err)
+		}
+		return nil, err
+	}
+	return c.VirtualServices.Update(vs)
+}
+
+var _ = Describe("Reporter", func() {
+	var (
+		dir   string
+		store *failingStorage
+		rptr  *reporter
+	)
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "reporter-test")
+		Expect(err).NotTo(HaveOccurred())
+		fileStore, err := file.NewStorage(dir, time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fileStore.V1().Register()).To(Succeed())
+		store = &failingStorage{Interface: fileStore, failures: make(map[string]int)}
+		rptr = NewReporter(store)
+		rptr.retry = backoff.Options{InitialInterval: time.Millisecond, MaxElapsedTime: 20 * time.Millisecond}
+	})
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+	createVirtualService := func(name string) *v1.VirtualService {
+		vs, err := store.V1().VirtualServices().Create(NewTestVirtualService(name, NewTestRoute1()))
+		Expect(err).NotTo(HaveOccurred())
+		return vs
+	}
+	state := func(name string) v1.Status_State {
+		vs, err := store.V1().VirtualServices().Get(name)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(vs.Status).NotTo(BeNil())
+		return vs.Status.State
+	}
+
+	Describe("WriteReports", func() {
+		It("retries writes that conflict", func() {
+			vs := createVirtualService("flaky")
+			store.failures["flaky"] = 2
+			store.conflict = true
+			Expect(rptr.WriteReports([]ConfigObjectReport{{CfgObject: vs}})).To(Succeed())
+			Expect(state("flaky")).To(Equal(v1.Status_Accepted))
+		})
+		It("does not retry other errors", func() {
+			vs := createVirtualService("broken")
+			store.failures["broken"] = 1
+			Expect(rptr.WriteReports([]ConfigObjectReport{{CfgObject: vs}})).NotTo(Succeed())
+			Expect(store.failures["broken"]).To(Equal(0))
+			Expect(rptr.WriteReports([]ConfigObjectReport{{CfgObject: vs}})).To(Succeed())
+		})
+		It("writes the latest version of an object that changed since it was translated", func() {
+			vs := createVirtualService("changed")
+			changed := proto.Clone(vs).(*v1.VirtualService)
+			changed.Domains = []string{"changed.com"}
+			_, err := store.V1().VirtualServices().Update(changed)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rptr.WriteReports([]ConfigObjectReport{{CfgObject: vs}})).To(Succeed())
+			stored, err := store.V1().VirtualServices().Get("changed")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored.Domains).To(Equal([]string{"changed.com"}))
+			Expect(stored.Status.State).To(Equal(v1.Status_Accepted))
+		})
+		It("writes the other reports when one of them can't be written", func() {
+			var reports []ConfigObjectReport
+			for _, name := range []string{"a", "broken", "b"} {
+				reports = append(reports, ConfigObjectReport{CfgObject: createVirtualService(name)})
+			}
+			store.failures["broken"] = 1000
+			err := rptr.WriteReports(reports)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("failed to write report for virtualservice/broken"))
+			Expect(err.Error()).NotTo(ContainSubstring("virtualservice/a"))
+			Expect(state("a")).To(Equal(v1.Status_Accepted))
+			Expect(state("b")).To(Equal(v1.Status_Accepted))
+		})
+		It("writes more reports than fit in one batch", func() {
+			var reports []ConfigObjectReport
+			for i := 0; i < writeBatchSize*2+1; i++ {
+				vs := createVirtualService(string('a' + rune(i)))
+				reports = append(reports, ConfigObjectReport{CfgObject: vs})
+			}
+			Expect(rptr.WriteReports(reports)).To(Succeed())
+			for _, rep := range reports {
+				Expect(state(rep.CfgObject.GetName())).To(Equal(v1.Status_Accepted))
+			}
+		})
+	})
+
+	Describe("PruneRoles", func() {
+		It("deletes generated roles without virtual services and keeps user roles", func() {
+			Expect(rptr.WriteReports([]ConfigObjectReport{
+				{CfgObject: &v1.Role{Name: "generated-active"}},
+				{CfgObject: &v1.Role{Name: "generated-inactive"}},
+			})).To(Succeed())
+			_, err := store.V1().Roles().Create(&v1.Role{Name: "user", Status: &v1.Status{State: v1.Status_Accepted}})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(rptr.PruneRoles([]string{"generated-active"})).To(Succeed())
+
+			roles, err := store.V1().Roles().List()
+			Expect(err).NotTo(HaveOccurred())
+			names := make(map[string]*v1.Role)
+			for _, role := range roles {
+				names[role.Name] = role
+			}
+			Expect(names).To(HaveLen(2))
+			Expect(names).To(HaveKey("generated-active"))
+			Expect(names["generated-active"].Metadata.Annotations).To(HaveKeyWithValue(ownerAnnotationKey, generatedBy))
+			Expect(names).To(HaveKey("user"))
+			Expect(names["user"].Status).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,73 @@
+package reporter
+
+import (
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+)
+
+// NewStatus creates the status for a report. every error and warning in the report
+// becomes a condition, and errors for a single route are reported on that route.
+// the last transition time of the previous status is kept if the state did not change
+func NewStatus(report ConfigObjectReport, previous *v1.Status, now time.Time) *v1.Status {
+	status := &v1.Status{
+		State:         v1.Status_Accepted,
+		AcceptedRoles: report.AcceptedRoles,
+		Certificates:  report.Certificates,
+	}
+	if report.Err != nil {
+		status.State = v1.Status_Rejected
+		if report.PartiallyApplied {
+			status.State = v1.Status_PartiallyApplied
+		}
+		status.Reason = report.Err.Error()
+	}
+	if report.CfgObject != nil {
+		status.ObservedResourceVersion = report.CfgObject.GetMetadata().GetResourceVersion()
+	}
+	status.Conditions = conditions(v1.Condition_Error, report.Err)
+	status.Conditions = append(status.Conditions, conditions(v1.Condition_Warning, report.Warnings...)...)
+
+	if previous != nil && previous.State == status.State && previous.LastTransitionTime != nil {
+		status.LastTransitionTime = previous.LastTransitionTime
+	} else {
+		// the time can only fail to convert if it is outside the range of a timestamp
+		status.LastTransitionTime, _ = types.TimestampProto(now)
+	}
+	return status
+}
+
+// multierrors are split into one condition per error
+func conditions(severity v1.Condition_Severity, errs ...error) []*v1.Condition {
+	var out []*v1.Condition
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		merr, ok := multierror.Flatten(err).(*multierror.Error)
+		if !ok {
+			out = append(out, condition(severity, err))
+			continue
+		}
+		for _, err := range merr.Errors {
+			out = append(out, condition(severity, err))
+		}
+	}
+	return out
+}
+
+func condition(severity v1.Condition_Severity, err error) *v1.Condition {
+	cond := &v1.Condition{
+		Severity: severity,
+		Message:  err.Error(),
+	}
+	if routeErr, ok := errors.Cause(err).(*RouteError); ok {
+		cond.Message = routeErr.Err.Error()
+		cond.Route = &v1.RouteRef{Index: uint32(routeErr.Index)}
+	}
+	return cond
+}
@@ -0,0 +1,170 @@
+package reporter_test
+
+import (
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/hashicorp/go-multierror"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	. "github.com/solo-io/gloo/internal/control-plane/reporter"
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/storage"
+	"github.com/solo-io/gloo/pkg/storage/file"
+	. "github.com/solo-io/gloo/test/helpers"
+)
+
+var _ = Describe("Status", func() {
+	var (
+		vs  *v1.VirtualService
+		now time.Time
+	)
+	BeforeEach(func() {
+		vs = NewTestVirtualService("petstore", NewTestRoute1(), NewTestRoute2())
+		vs.Metadata = &v1.Metadata{ResourceVersion: "7"}
+		now = time.Date(2018, 5, 1, 12, 0, 0, 0, time.UTC)
+	})
+	timestamp := func(t time.Time) *types.Timestamp {
+		ts, err := types.TimestampProto(t)
+		Expect(err).NotTo(HaveOccurred())
+		return ts
+	}
+
+	Describe("NewStatus", func() {
+		It("reports each error and warning as a condition", func() {
+			var err error
+			err = multierror.Append(err, &RouteError{Index: 1, Err: errors.New("upstream missing was not found")})
+			err = multierror.Append(err, errors.New("domain * is shared"))
+			status := NewStatus(ConfigObjectReport{
+				CfgObject: vs,
+				Err:       err,
+				Warnings:  []error{&RouteError{Index: 0, Err: errors.New("function petstore/nope was not found")}},
+			}, nil, now)
+			Expect(status.State).To(Equal(v1.Status_Rejected))
+			Expect(status.Reason).To(Equal(err.Error()))
+			Expect(status.Conditions).To(Equal([]*v1.Condition{
+				{Severity: v1.Condition_Error, Message: "upstream missing was not found", Route: &v1.RouteRef{Index: 1}},
+				{Severity: v1.Condition_Error, Message: "domain * is shared"},
+				{Severity: v1.Condition_Warning, Message: "function petstore/nope was not found", Route: &v1.RouteRef{Index: 0}},
+			}))
+		})
+		It("reports warnings on accepted objects", func() {
+			status := NewStatus(ConfigObjectReport{
+				CfgObject:     vs,
+				Warnings:      []error{errors.New("watch out")},
+				AcceptedRoles: []string{"ingress", "mesh"},
+			}, nil, now)
+			Expect(status.State).To(Equal(v1.Status_Accepted))
+			Expect(status.Reason).To(BeEmpty())
+			Expect(status.Conditions).To(Equal([]*v1.Condition{{Severity: v1.Condition_Warning, Message: "watch out"}}))
+			Expect(status.AcceptedRoles).To(Equal([]string{"ingress", "mesh"}))
+		})
+		It("sets the observed resource version", func() {
+			status := NewStatus(ConfigObjectReport{CfgObject: vs}, nil, now)
+			Expect(status.ObservedResourceVersion).To(Equal("7"))
+		})
+		It("reports the certificates of the object", func() {
+			cert := &v1.Certificate{
+				SecretRef: "petstore-tls",
+				Subject:   "CN=petstore.com",
+				DnsNames:  []string{"petstore.com"},
+				NotAfter:  timestamp(now.Add(24 * time.Hour)),
+			}
+			status := NewStatus(ConfigObjectReport{CfgObject: vs, Certificates: []*v1.Certificate{cert}}, nil, now)
+			Expect(status.Certificates).To(Equal([]*v1.Certificate{cert}))
+		})
+		It("only changes the last transition time when the state changes", func() {
+			accepted := NewStatus(ConfigObjectReport{CfgObject: vs}, nil, now)
+			Expect(accepted.LastTransitionTime).To(Equal(timestamp(now)))
+
+			later := now.Add(time.Hour)
+			stillAccepted := NewStatus(ConfigObjectReport{CfgObject: vs, Warnings: []error{errors.New("hmm")}}, accepted, later)
+			Expect(stillAccepted.LastTransitionTime).To(Equal(timestamp(now)))
+
+			rejected := NewStatus(ConfigObjectReport{CfgObject: vs, Err: errors.New("bad")}, stillAccepted, later)
+			Expect(rejected.LastTransitionTime).To(Equal(timestamp(later)))
+		})
+	})
+
+	Describe("MergeReports", func() {
+		It("keeps the error, warnings and accepted roles of both reports", func() {
+			a, b := errors.New("a"), errors.New("b")
+			merged := MergeReports(ConfigObjectReport{
+				CfgObject:     vs,
+				Warnings:      []error{a, b},
+				AcceptedRoles: []string{"mesh"},
+			}, ConfigObjectReport{
+				CfgObject: vs,
+				Err:       errors.New("rejected in ingress"),
+				Warnings:  []error{errors.New("b")},
+			})
+			Expect(merged.Err).To(MatchError("rejected in ingress"))
+			Expect(merged.Warnings).To(Equal([]error{a, b}))
+			Expect(merged.AcceptedRoles).To(Equal([]string{"mesh"}))
+		})
+		It("keeps the first error", func() {
+			merged := MergeReports(ConfigObjectReport{CfgObject: vs, Err: errors.New("first"), AcceptedRoles: []string{"b"}},
+				ConfigObjectReport{CfgObject: vs, Err: errors.New("second"), PartiallyApplied: true, AcceptedRoles: []string{"a"}})
+			Expect(merged.Err).To(MatchError("first"))
+			Expect(merged.PartiallyApplied).To(BeFalse())
+			Expect(merged.AcceptedRoles).To(Equal([]string{"a", "b"}))
+		})
+	})
+
+	Describe("writing reports", func() {
+		var (
+			dir   string
+			store storage.Interface
+		)
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "reporter-test")
+			Expect(err).NotTo(HaveOccurred())
+			store, err = file.NewStorage(dir, time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(store.V1().Register()).To(Succeed())
+			vs.Metadata = nil
+			vs, err = store.V1().VirtualServices().Create(vs)
+			Expect(err).NotTo(HaveOccurred())
+		})
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+		It("writes conditions and accepted roles to the stored object", func() {
+			rptr := NewReporter(store)
+			err := rptr.WriteReports([]ConfigObjectReport{{
+				CfgObject:     vs,
+				Warnings:      []error{&RouteError{Index: 1, Err: errors.New("function petstore/nope was not found")}},
+				AcceptedRoles: []string{"ingress"},
+			}})
+			Expect(err).NotTo(HaveOccurred())
+			stored, err := store.V1().VirtualServices().Get(vs.Name)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored.Status.State).To(Equal(v1.Status_Accepted))
+			Expect(stored.Status.AcceptedRoles).To(Equal([]string{"ingress"}))
+			Expect(stored.Status.ObservedResourceVersion).To(Equal(vs.Metadata.ResourceVersion))
+			Expect(stored.Status.LastTransitionTime).NotTo(BeNil())
+			Expect(stored.Status.Conditions).To(Equal([]*v1.Condition{{
+				Severity: v1.Condition_Warning,
+				Message:  "function petstore/nope was not found",
+				Route:    &v1.RouteRef{Index: 1},
+			}}))
+		})
+		It("does not rewrite a status after translating the object it was written to", func() {
+			rptr := NewReporter(store)
+			Expect(rptr.WriteReports([]ConfigObjectReport{{CfgObject: vs}})).To(Succeed())
+			written, err := store.V1().VirtualServices().Get(vs.Name)
+			Expect(err).NotTo(HaveOccurred())
+
+			// the status write triggers another translation of the written object
+			Expect(rptr.WriteReports([]ConfigObjectReport{{CfgObject: written}})).To(Succeed())
+			stored, err := store.V1().VirtualServices().Get(vs.Name)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored).To(Equal(written))
+		})
+	})
+})
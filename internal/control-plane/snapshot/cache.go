@@ -1,6 +1,7 @@
 package snapshot
 
 import (
+	"github.com/solo-io/gloo/internal/control-plane/acme"
 	"github.com/solo-io/gloo/pkg/secretwatcher"
 	"github.com/solo-io/gloo/internal/control-plane/filewatcher"
 	"github.com/solo-io/gloo/pkg/endpointdiscovery"
@@ -15,6 +16,9 @@ type Cache struct {
 	Secrets   secretwatcher.SecretMap
 	Files     filewatcher.Files
 	Endpoints endpointdiscovery.EndpointGroups
+	// the pending acme challenges of the virtual services, answered on the http listener.
+	// set by the event loop for each role, and not part of the hash
+	Challenges []acme.Challenge
 }
 
 func newCache() *Cache {
@@ -0,0 +1,104 @@
+package snapshot
+
+import (
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/endpointdiscovery"
+)
+
+// DefaultRole is assigned to virtual services which do not specify any roles
+const DefaultRole = "ingress"
+
+// VirtualServicesByRole maps each role to its virtual services.
+// virtual services with no roles belong to the DefaultRole
+func (c *Cache) VirtualServicesByRole() map[string][]*v1.VirtualService {
+	virtualServicesByRole := make(map[string][]*v1.VirtualService)
+	for _, vs := range c.Cfg.VirtualServices {
+		if len(vs.Roles) == 0 {
+			virtualServicesByRole[DefaultRole] = append(virtualServicesByRole[DefaultRole], vs)
+		}
+		for _, role := range vs.Roles {
+			virtualServicesByRole[role] = append(virtualServicesByRole[role], vs)
+		}
+	}
+	return virtualServicesByRole
+}
+
+// ForRole returns the inputs needed to translate the given virtual services:
+// only the upstreams they route to, and the endpoints of those upstreams
+func (c *Cache) ForRole(virtualServices []*v1.VirtualService) *Cache {
+	upstreams := destinationUpstreams(c.Cfg.Upstreams, virtualServices)
+	return &Cache{
+		Cfg: &v1.Config{
+			Upstreams:       upstreams,
+			VirtualServices: virtualServices,
+		},
+		Secrets:   c.Secrets,
+		Files:     c.Files,
+		Endpoints: destinationEndpoints(upstreams, c.Endpoints),
+	}
+}
+
+// NewRole creates the role object that role-level errors are reported on
+func NewRole(name string, virtualServices []*v1.VirtualService) *v1.Role {
+	var vsNames []string
+	for _, vs := range virtualServices {
+		vsNames = append(vsNames, vs.Name)
+	}
+	return &v1.Role{
+		Name:            name,
+		VirtualServices: vsNames,
+	}
+}
+
+// gets the subset of upstreams which are destinations for at least one route in at least one
+// virtual service
+func destinationUpstreams(allUpstreams []*v1.Upstream, virtualServices []*v1.VirtualService) []*v1.Upstream {
+	destinationUpstreamNames := make(map[string]bool)
+	for _, vs := range virtualServices {
+		for _, route := range vs.Routes {
+			dests := getAllDestinations(route)
+			for _, dest := range dests {
+				var upstreamName string
+				switch typedDest := dest.DestinationType.(type) {
+				case *v1.Destination_Upstream:
+					upstreamName = typedDest.Upstream.Name
+				case *v1.Destination_Function:
+					upstreamName = typedDest.Function.UpstreamName
+				default:
+					panic("unknown destination type")
+				}
+				destinationUpstreamNames[upstreamName] = true
+			}
+		}
+	}
+	var destinationUpstreams []*v1.Upstream
+	for _, us := range allUpstreams {
+		if _, ok := destinationUpstreamNames[us.Name]; ok {
+			destinationUpstreams = append(destinationUpstreams, us)
+		}
+	}
+	return destinationUpstreams
+}
+
+func getAllDestinations(route *v1.Route) []*v1.Destination {
+	var dests []*v1.Destination
+	if route.SingleDestination != nil {
+		dests = append(dests, route.SingleDestination)
+	}
+	for _, dest := range route.MultipleDestinations {
+		dests = append(dests, dest.Destination)
+	}
+	return dests
+}
+
+func destinationEndpoints(upstreams []*v1.Upstream, allEndpoints endpointdiscovery.EndpointGroups) endpointdiscovery.EndpointGroups {
+	destinationEndpoints := make(endpointdiscovery.EndpointGroups)
+	for _, us := range upstreams {
+		eps, ok := allEndpoints[us.Name]
+		if !ok {
+			continue
+		}
+		destinationEndpoints[us.Name] = eps
+	}
+	return destinationEndpoints
+}
@@ -0,0 +1,54 @@
+package snapshot
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/endpointdiscovery"
+)
+
+func routeTo(upstream string) *v1.Route {
+	return &v1.Route{
+		SingleDestination: &v1.Destination{
+			DestinationType: &v1.Destination_Upstream{
+				Upstream: &v1.UpstreamDestination{Name: upstream},
+			},
+		},
+	}
+}
+
+var _ = Describe("Roles", func() {
+	vs1 := &v1.VirtualService{Name: "vs1", Routes: []*v1.Route{routeTo("us1")}}
+	vs2 := &v1.VirtualService{Name: "vs2", Roles: []string{"a", "b"}, Routes: []*v1.Route{routeTo("us2")}}
+	c := &Cache{
+		Cfg: &v1.Config{
+			Upstreams:       []*v1.Upstream{{Name: "us1"}, {Name: "us2"}, {Name: "us3"}},
+			VirtualServices: []*v1.VirtualService{vs1, vs2},
+		},
+		Endpoints: endpointdiscovery.EndpointGroups{
+			"us2": {{Address: "1.2.3.4", Port: 80}},
+			"us3": {{Address: "5.6.7.8", Port: 80}},
+		},
+	}
+	It("groups virtual services by role, falling back to the default role", func() {
+		Expect(c.VirtualServicesByRole()).To(Equal(map[string][]*v1.VirtualService{
+			DefaultRole: {vs1},
+			"a":         {vs2},
+			"b":         {vs2},
+		}))
+	})
+	It("only includes the upstreams and endpoints the virtual services route to", func() {
+		roleCache := c.ForRole([]*v1.VirtualService{vs2})
+		Expect(roleCache.Cfg.Upstreams).To(Equal([]*v1.Upstream{{Name: "us2"}}))
+		Expect(roleCache.Cfg.VirtualServices).To(Equal([]*v1.VirtualService{vs2}))
+		Expect(roleCache.Endpoints).To(HaveLen(1))
+		Expect(roleCache.Endpoints).To(HaveKey("us2"))
+	})
+	It("creates the role object", func() {
+		Expect(NewRole("a", []*v1.VirtualService{vs1, vs2})).To(Equal(&v1.Role{
+			Name:            "a",
+			VirtualServices: []string{"vs1", "vs2"},
+		}))
+	})
+})
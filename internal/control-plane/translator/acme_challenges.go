@@ -0,0 +1,66 @@
+package translator
+
+import (
+	envoycore "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	envoyroute "github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
+
+	"github.com/solo-io/gloo/internal/control-plane/acme"
+)
+
+// answers acme challenges for requests with no other virtual host on the http listener
+const acmeChallengesVirtualHostName = "gloo-acme-challenges"
+
+// addAcmeChallengeRoutes answers the pending HTTP-01 challenges on the http listener.
+// the challenge routes are added in front of the routes of every virtual host, regardless of its domains,
+// so that challenges are answered whatever host the acme server validates. when no virtual host
+// matches every domain, a catch-all virtual host is added for the challenges
+func addAcmeChallengeRoutes(virtualHosts []envoyroute.VirtualHost, challenges []acme.Challenge) []envoyroute.VirtualHost {
+	if len(challenges) == 0 {
+		return virtualHosts
+	}
+	var challengeRoutes []envoyroute.Route
+	for _, challenge := range challenges {
+		challengeRoutes = append(challengeRoutes, acmeChallengeRoute(challenge))
+	}
+
+	hasCatchAll := false
+	var withChallenges []envoyroute.VirtualHost
+	for _, virtualHost := range virtualHosts {
+		for _, domain := range virtualHost.Domains {
+			if domain == "*" {
+				hasCatchAll = true
+			}
+		}
+		virtualHost.Routes = append(append([]envoyroute.Route{}, challengeRoutes...), virtualHost.Routes...)
+		withChallenges = append(withChallenges, virtualHost)
+	}
+	if !hasCatchAll {
+		withChallenges = append(withChallenges, envoyroute.VirtualHost{
+			Name:    acmeChallengesVirtualHostName,
+			Domains: []string{"*"},
+			Routes:  challengeRoutes,
+		})
+		sortVirtualHosts(withChallenges)
+	}
+	return withChallenges
+}
+
+func acmeChallengeRoute(challenge acme.Challenge) envoyroute.Route {
+	return envoyroute.Route{
+		Match: envoyroute.RouteMatch{
+			PathSpecifier: &envoyroute.RouteMatch_Path{
+				Path: challenge.Path(),
+			},
+		},
+		Action: &envoyroute.Route_DirectResponse{
+			DirectResponse: &envoyroute.DirectResponseAction{
+				Status: 200,
+				Body: &envoycore.DataSource{
+					Specifier: &envoycore.DataSource_InlineString{
+						InlineString: challenge.KeyAuthorization,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,94 @@
+package translator
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/internal/control-plane/reporter"
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/secretwatcher"
+)
+
+// the certificates of virtual services are parsed during translation, and reported on their status.
+// certificates that expired, or that are not valid for the domains of their virtual service, are rejected
+// instead of being served to envoy
+
+// validateVirtualServiceSSLConfig returns the certificate of the virtual service, a warning if it expires
+// within the configured window, and an error if it can not be served for the virtual service
+func (t *Translator) validateVirtualServiceSSLConfig(virtualService *v1.VirtualService, secrets secretwatcher.SecretMap, now time.Time) (*v1.Certificate, []error, error) {
+	if virtualService.SslConfig == nil {
+		return nil, nil, nil
+	}
+	if virtualService.SslConfig.SecretRef == "" {
+		if virtualService.SslConfig.AutoCertificate {
+			return nil, nil, errors.New("auto_certificate requires a secret_ref to store the certificate in")
+		}
+		return nil, nil, nil
+	}
+	ref := virtualService.SslConfig.SecretRef
+	if _, ok := secrets[ref]; !ok && virtualService.SslConfig.AutoCertificate {
+		return nil, nil, errors.Errorf("waiting for the certificate in secret %v to be issued", ref)
+	}
+	certChain, _, err := getSslSecrets(ref, secrets)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := secretwatcher.LeafCertificate(certChain)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "invalid certificate in secret %v", ref)
+	}
+	certificate := &v1.Certificate{
+		SecretRef: ref,
+		Subject:   cert.Subject.String(),
+		DnsNames:  cert.DNSNames,
+	}
+	// the time can only fail to convert if it is outside the range of a timestamp
+	certificate.NotAfter, _ = types.TimestampProto(cert.NotAfter)
+
+	var (
+		warnings []error
+		certErr  error
+	)
+	if uncovered := secretwatcher.UncoveredDomains(virtualService.Domains, cert); len(uncovered) > 0 {
+		certErr = multierror.Append(certErr, errors.Errorf("certificate in secret %v is not valid for the domains [%v]. "+
+			"it is valid for [%v]", ref, strings.Join(uncovered, ", "), strings.Join(secretwatcher.CertificateNames(cert), ", ")))
+	}
+	expiresIn := cert.NotAfter.Sub(now)
+	switch {
+	case expiresIn <= 0:
+		certErr = multierror.Append(certErr, errors.Errorf("certificate in secret %v expired at %v",
+			ref, cert.NotAfter.UTC().Format(time.RFC3339)))
+	case expiresIn < t.config.CertExpiryWarning:
+		warnings = append(warnings, errors.Errorf("certificate in secret %v expires at %v, in %v days",
+			ref, cert.NotAfter.UTC().Format(time.RFC3339), int(expiresIn.Hours()/24)))
+	}
+	return certificate, warnings, certErr
+}
+
+// NextCertificateCheck returns the first time after now at which a certificate in the reports expires,
+// or starts expiring within the warning window. nothing else changes when that happens, so the
+// config must be translated again at that time to stop serving the certificate or to warn about it
+func (t *Translator) NextCertificateCheck(reports []reporter.ConfigObjectReport, now time.Time) (time.Time, bool) {
+	var next time.Time
+	for _, report := range reports {
+		for _, certificate := range report.Certificates {
+			if certificate.NotAfter == nil {
+				continue
+			}
+			notAfter, err := types.TimestampFromProto(certificate.NotAfter)
+			if err != nil {
+				continue
+			}
+			for _, deadline := range []time.Time{notAfter.Add(-t.config.CertExpiryWarning), notAfter} {
+				if deadline.After(now) && (next.IsZero() || deadline.Before(next)) {
+					next = deadline
+				}
+			}
+		}
+	}
+	return next, !next.IsZero()
+}
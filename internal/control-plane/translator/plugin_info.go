@@ -0,0 +1,66 @@
+package translator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/solo-io/gloo/pkg/plugins"
+)
+
+// PluginInfo describes a plugin run by the translator
+type PluginInfo struct {
+	// the go type of the plugin
+	Name string `json:"name"`
+	// the plugin interfaces implemented, e.g. UpstreamPlugin, RoutePlugin
+	Kinds []string `json:"kinds"`
+	// the http filters the plugin added during the most recent translation
+	Filters []FilterInfo `json:"filters,omitempty"`
+}
+
+type FilterInfo struct {
+	Name  string `json:"name"`
+	Stage string `json:"stage"`
+}
+
+// Plugins returns the plugins in the order they are run
+func (t *Translator) Plugins() []PluginInfo {
+	t.filtersLock.RLock()
+	defer t.filtersLock.RUnlock()
+	var infos []PluginInfo
+	for i, plug := range t.plugins {
+		info := PluginInfo{
+			Name:  strings.TrimPrefix(fmt.Sprintf("%T", plug), "*"),
+			Kinds: pluginKinds(plug),
+		}
+		if i < len(t.lastFilters) {
+			for _, filter := range t.lastFilters[i] {
+				info.Filters = append(info.Filters, FilterInfo{
+					Name:  filter.HttpFilter.Name,
+					Stage: filter.Stage.String(),
+				})
+			}
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+func pluginKinds(plug plugins.TranslatorPlugin) []string {
+	var kinds []string
+	if _, ok := plug.(plugins.UpstreamPlugin); ok {
+		kinds = append(kinds, "UpstreamPlugin")
+	}
+	if _, ok := plug.(plugins.EndpointDiscoveryPlugin); ok {
+		kinds = append(kinds, "EndpointDiscoveryPlugin")
+	}
+	if _, ok := plug.(plugins.FunctionPlugin); ok {
+		kinds = append(kinds, "FunctionPlugin")
+	}
+	if _, ok := plug.(plugins.RoutePlugin); ok {
+		kinds = append(kinds, "RoutePlugin")
+	}
+	if _, ok := plug.(plugins.FilterPlugin); ok {
+		kinds = append(kinds, "FilterPlugin")
+	}
+	return kinds
+}
@@ -0,0 +1,60 @@
+package translator
+
+import (
+	"strconv"
+	"strings"
+
+	envoycache "github.com/envoyproxy/go-control-plane/pkg/cache"
+
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+)
+
+// ResourceOwners returns the objects in cfg that produced the envoy resources of type typeUrl with one of these names.
+// clusters and cluster load assignments are named after their upstream, virtual hosts after their virtual service,
+// and secrets after the secret ref of a virtual service or the cluster of an upstream.
+// route configurations and listeners hold the config of every virtual service in a role, so they have no owner
+func ResourceOwners(cfg *v1.Config, typeUrl string, names map[string]bool) []v1.ConfigObject {
+	var owners []v1.ConfigObject
+	switch typeUrl {
+	case envoycache.EndpointType, envoycache.ClusterType:
+		for _, us := range cfg.Upstreams {
+			if names[clusterName(us.Name)] {
+				owners = append(owners, us)
+			}
+		}
+	case envoycache.RouteType:
+		for _, vs := range cfg.VirtualServices {
+			if names[virtualHostName(vs.Name)] {
+				owners = append(owners, vs)
+			}
+		}
+	case envoycache.SecretType:
+		for _, vs := range cfg.VirtualServices {
+			if vs.SslConfig != nil && vs.SslConfig.SecretRef != "" && names[sslCertificateSecretName(vs.SslConfig.SecretRef)] {
+				owners = append(owners, vs)
+			}
+		}
+		for _, us := range cfg.Upstreams {
+			for name := range names {
+				if isUpstreamCertificateSecretName(name, clusterName(us.Name)) {
+					owners = append(owners, us)
+					break
+				}
+			}
+		}
+	}
+	return owners
+}
+
+// isUpstreamCertificateSecretName is true for the names of the client certificates of the cluster
+func isUpstreamCertificateSecretName(name, clusterName string) bool {
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) != 2 || parts[1] != clusterName {
+		return false
+	}
+	if parts[0] == "client-certificate" {
+		return true
+	}
+	i, err := strconv.Atoi(strings.TrimPrefix(parts[0], "client-certificate-"))
+	return err == nil && i > 0 && name == upstreamCertificateSecretName(clusterName, i)
+}
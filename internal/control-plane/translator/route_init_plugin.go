@@ -1,6 +1,8 @@
 package translator
 
 import (
+	"sort"
+
 	envoycore "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
 	envoyroute "github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
 	"github.com/gogo/protobuf/types"
@@ -108,12 +110,23 @@ func processMultipleDestinationRoute(destinations []*v1.WeightedDestination, pre
 		clusterWeights[clusterName(upstreamName)] += uint32(destination.Weight)
 	}
 	// set weights for function routes
-	for upstreamName, functionalDestinations := range upstreamDestinationsWithFuncs {
-		addClusterFuncsToMetadata(clusterName(upstreamName), functionalDestinations, out)
+	var upstreamNames []string
+	for upstreamName := range upstreamDestinationsWithFuncs {
+		upstreamNames = append(upstreamNames, upstreamName)
+	}
+	sort.Strings(upstreamNames)
+	for _, upstreamName := range upstreamNames {
+		addClusterFuncsToMetadata(clusterName(upstreamName), upstreamDestinationsWithFuncs[upstreamName], out)
 	}
 	// set weights for clusters (functional or non)
-	for clusterName, weight := range clusterWeights {
-		addWeightedCluster(clusterName, weight, out)
+	// sorted so that the weighted cluster list is stable between translations
+	var clusterNames []string
+	for clusterName := range clusterWeights {
+		clusterNames = append(clusterNames, clusterName)
+	}
+	sort.Strings(clusterNames)
+	for _, clusterName := range clusterNames {
+		addWeightedCluster(clusterName, clusterWeights[clusterName], out)
 	}
 	setPrefixRewrite(prefixRewrite, out)
 	setTotalWeight(totalWeight, out)
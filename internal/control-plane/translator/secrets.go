@@ -0,0 +1,84 @@
+package translator
+
+import (
+	"fmt"
+
+	envoyapi "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	envoyauth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
+	envoycore "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+)
+
+// certificates and keys are served to envoy over SDS instead of being inlined in listeners and clusters.
+// listeners and clusters only reference the secrets by name, so rotating a certificate only changes
+// the secret, and envoy does not drain the connections of the listener
+
+// sdsSecretConfig references the secret with this name, served over ADS
+func sdsSecretConfig(name string) *envoyauth.SdsSecretConfig {
+	return &envoyauth.SdsSecretConfig{
+		Name: name,
+		SdsConfig: &envoycore.ConfigSource{
+			ConfigSourceSpecifier: &envoycore.ConfigSource_Ads{
+				Ads: &envoycore.AggregatedConfigSource{},
+			},
+		},
+	}
+}
+
+func newTlsCertificateSecret(name, certChain, privateKey string) *envoyauth.Secret {
+	return &envoyauth.Secret{
+		Name: name,
+		Type: &envoyauth.Secret_TlsCertificate{
+			TlsCertificate: &envoyauth.TlsCertificate{
+				CertificateChain: &envoycore.DataSource{
+					Specifier: &envoycore.DataSource_InlineString{
+						InlineString: certChain,
+					},
+				},
+				PrivateKey: &envoycore.DataSource{
+					Specifier: &envoycore.DataSource_InlineString{
+						InlineString: privateKey,
+					},
+				},
+			},
+		},
+	}
+}
+
+// secrets of virtual services and upstreams share one namespace in envoy, so the kind of secret is the first
+// segment of its name. the segment never contains a slash, so a secret ref can not take the name of a
+// client certificate, whatever the names of the refs and clusters are
+
+// sslCertificateSecretName names the secret of the certificate a virtual service is served with
+func sslCertificateSecretName(secretRef string) string {
+	return "ssl-certificate/" + secretRef
+}
+
+// upstreamCertificateSecretName names the secret of the i'th client certificate of a cluster
+func upstreamCertificateSecretName(clusterName string, i int) string {
+	if i == 0 {
+		return "client-certificate/" + clusterName
+	}
+	return fmt.Sprintf("client-certificate-%v/%v", i, clusterName)
+}
+
+// moveUpstreamCertificatesToSds replaces the certificates that plugins inlined in the tls contexts
+// of clusters with references to secrets, and returns the secrets
+func moveUpstreamCertificatesToSds(clusters []*envoyapi.Cluster) []*envoyauth.Secret {
+	var secrets []*envoyauth.Secret
+	for _, cluster := range clusters {
+		if cluster.TlsContext == nil || cluster.TlsContext.CommonTlsContext == nil {
+			continue
+		}
+		tlsContext := cluster.TlsContext.CommonTlsContext
+		for i, cert := range tlsContext.TlsCertificates {
+			name := upstreamCertificateSecretName(cluster.Name, i)
+			secrets = append(secrets, &envoyauth.Secret{
+				Name: name,
+				Type: &envoyauth.Secret_TlsCertificate{TlsCertificate: cert},
+			})
+			tlsContext.TlsCertificateSdsSecretConfigs = append(tlsContext.TlsCertificateSdsSecretConfigs, sdsSecretConfig(name))
+		}
+		tlsContext.TlsCertificates = nil
+	}
+	return secrets
+}
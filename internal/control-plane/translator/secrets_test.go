@@ -0,0 +1,49 @@
+package translator
+
+import (
+	envoyapi "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	envoyauth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
+	envoycore "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("moveUpstreamCertificatesToSds", func() {
+	It("replaces the client certificates of clusters with references to secrets", func() {
+		cert := &envoyauth.TlsCertificate{
+			CertificateChain: &envoycore.DataSource{Specifier: &envoycore.DataSource_InlineString{InlineString: "cert"}},
+			PrivateKey:       &envoycore.DataSource{Specifier: &envoycore.DataSource_InlineString{InlineString: "key"}},
+		}
+		withCert := &envoyapi.Cluster{
+			Name: "with-cert",
+			TlsContext: &envoyauth.UpstreamTlsContext{
+				CommonTlsContext: &envoyauth.CommonTlsContext{TlsCertificates: []*envoyauth.TlsCertificate{cert}},
+				Sni:              "example.com",
+			},
+		}
+		withoutCert := &envoyapi.Cluster{
+			Name:       "without-cert",
+			TlsContext: &envoyauth.UpstreamTlsContext{Sni: "example.com"},
+		}
+
+		secrets := moveUpstreamCertificatesToSds([]*envoyapi.Cluster{withCert, withoutCert})
+		Expect(secrets).To(HaveLen(1))
+		Expect(secrets[0].Name).To(Equal("client-certificate/with-cert"))
+		Expect(secrets[0].GetTlsCertificate()).To(Equal(cert))
+
+		tlsContext := withCert.TlsContext.CommonTlsContext
+		Expect(tlsContext.TlsCertificates).To(BeEmpty())
+		Expect(tlsContext.TlsCertificateSdsSecretConfigs).To(Equal([]*envoyauth.SdsSecretConfig{sdsSecretConfig("client-certificate/with-cert")}))
+		Expect(withoutCert.TlsContext.CommonTlsContext).To(BeNil())
+	})
+})
+
+var _ = Describe("secret names", func() {
+	It("never names the certificates of virtual services and upstreams alike", func() {
+		Expect(sslCertificateSecretName("foo-client-certificate")).NotTo(Equal(upstreamCertificateSecretName("foo", 0)))
+		Expect(sslCertificateSecretName("client-certificate/foo")).NotTo(Equal(upstreamCertificateSecretName("foo", 0)))
+		Expect(upstreamCertificateSecretName("foo/1", 0)).NotTo(Equal(upstreamCertificateSecretName("foo", 1)))
+		Expect(isUpstreamCertificateSecretName(upstreamCertificateSecretName("foo", 2), "foo")).To(BeTrue())
+		Expect(isUpstreamCertificateSecretName(sslCertificateSecretName("client-certificate/foo"), "foo")).To(BeFalse())
+	})
+})
@@ -1,8 +1,9 @@
 package translator
 
 import (
-	"fmt"
 	"sort"
+	"sync"
+	"time"
 
 	envoyapi "github.com/envoyproxy/go-control-plane/envoy/api/v2"
 	envoyauth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
@@ -15,7 +16,6 @@ import (
 	envoyutil "github.com/envoyproxy/go-control-plane/pkg/util"
 
 	"github.com/hashicorp/go-multierror"
-	"github.com/mitchellh/hashstructure"
 	"github.com/pkg/errors"
 
 	"github.com/solo-io/gloo/internal/control-plane/bootstrap"
@@ -47,6 +47,12 @@ const (
 type Translator struct {
 	plugins []plugins.TranslatorPlugin
 	config  bootstrap.IngressOptions
+	// certificates are checked for expiry against this clock
+	now func() time.Time
+
+	// the filters added by each plugin in the most recent translation, indexed like plugins
+	filtersLock sync.RWMutex
+	lastFilters [][]plugins.StagedFilter
 }
 
 // all built-in plugins should go here
@@ -83,9 +89,22 @@ func NewTranslator(opts bootstrap.IngressOptions, translatorPlugins []plugins.Tr
 	return &Translator{
 		plugins: translatorPlugins,
 		config:  opts,
+		now:     time.Now,
 	}
 }
 
+// GetDependencies returns the secrets and files needed by the plugins of the translator, including the core plugins
+func (t *Translator) GetDependencies(cfg *v1.Config) []*plugins.Dependencies {
+	var dependencies []*plugins.Dependencies
+	for _, plug := range t.plugins {
+		dep := plug.GetDependencies(cfg)
+		if dep != nil {
+			dependencies = append(dependencies, dep)
+		}
+	}
+	return dependencies
+}
+
 type pluginDependencies struct {
 	Secrets secretwatcher.SecretMap
 	Files   filewatcher.Files
@@ -102,7 +121,7 @@ func (t *Translator) Translate(role *v1.Role, inputs *snapshot.Cache) (*envoycac
 	clusterLoadAssignments := computeClusterEndpoints(cfg.Upstreams, endpoints)
 
 	// clusters
-	clusters, upstreamReports := t.computeClusters(cfg, dependencies, endpoints)
+	clusters, upstreamReports := t.computeClusters(role, cfg, dependencies, endpoints)
 
 	// mark errored upstreams; routes that point to them are considered invalid
 	errored := getErroredUpstreams(upstreamReports)
@@ -110,6 +129,9 @@ func (t *Translator) Translate(role *v1.Role, inputs *snapshot.Cache) (*envoycac
 	// envoy virtual hosts
 	sslVirtualHosts, noSslVirtualHosts, virtualServiceReports := t.computeVirtualHosts(role, cfg, errored, secrets)
 
+	// pending acme challenges are answered on the http listener
+	noSslVirtualHosts = addAcmeChallengeRoutes(noSslVirtualHosts, inputs.Challenges)
+
 	noSslRouteConfig := &envoyapi.RouteConfiguration{
 		Name:         noSslRdsName,
 		VirtualHosts: noSslVirtualHosts,
@@ -140,7 +162,7 @@ func (t *Translator) Translate(role *v1.Role, inputs *snapshot.Cache) (*envoycac
 	}
 
 	// finally, the listeners
-	httpsListener, err := t.constructHttpsListener(sslListenerName,
+	httpsListener, sslSecrets, err := t.constructHttpsListener(sslListenerName,
 		t.config.SecurePort,
 		sslFilters,
 		cfg.VirtualServices,
@@ -150,6 +172,15 @@ func (t *Translator) Translate(role *v1.Role, inputs *snapshot.Cache) (*envoycac
 		return nil, nil, errors.Wrapf(err, "constructing https listener %v", sslListenerName)
 	}
 
+	// certificates in the tls contexts of clusters are served over sds, like the certificates of listeners
+	upstreamSecrets := moveUpstreamCertificatesToSds(clusters)
+
+	// sort everything by name so that identical inputs always produce identical output,
+	// regardless of the order in which they were read from storage
+	sortClusterLoadAssignments(clusterLoadAssignments)
+	sortClusters(clusters)
+	sortSecrets(upstreamSecrets)
+
 	// proto-ify everything
 	var endpointsProto []envoycache.Resource
 	for _, cla := range clusterLoadAssignments {
@@ -161,7 +192,7 @@ func (t *Translator) Translate(role *v1.Role, inputs *snapshot.Cache) (*envoycac
 		clustersProto = append(clustersProto, cluster)
 	}
 
-	var listenersProto, routesProto []envoycache.Resource
+	var listenersProto, routesProto, secretsProto []envoycache.Resource
 
 	// only add http listener and route config if we have no ssl vServices
 	if len(noSslVirtualHosts) > 0 && len(noSslListener.FilterChains) > 0 {
@@ -169,25 +200,25 @@ func (t *Translator) Translate(role *v1.Role, inputs *snapshot.Cache) (*envoycac
 		routesProto = append(routesProto, noSslRouteConfig)
 	}
 
-	// only add https listener and route config if we have ssl vServices
+	// only add https listener, route config and the certificates of the listener if we have ssl vServices
 	if len(sslVirtualHosts) > 0 && len(httpsListener.FilterChains) > 0 {
 		listenersProto = append(listenersProto, httpsListener)
 		routesProto = append(routesProto, sslRouteConfig)
+		for _, secret := range sslSecrets {
+			secretsProto = append(secretsProto, secret)
+		}
+	}
+	for _, secret := range upstreamSecrets {
+		secretsProto = append(secretsProto, secret)
 	}
 
-	// construct version
-	// TODO: investigate whether we need a more sophisticated versionining algorithm
-	version, err := hashstructure.Hash([][]envoycache.Resource{
-		endpointsProto,
-		clustersProto,
-		routesProto,
-		listenersProto,
-	}, nil)
+	// construct snapshot
+	// each resource type is versioned individually so that a change to one type
+	// (e.g. endpoints) does not cause envoy to re-fetch the others (e.g. listeners)
+	xdsSnapshot, err := newVersionedSnapshot(endpointsProto, clustersProto, routesProto, listenersProto, secretsProto)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "constructing version hash for envoy snapshot components")
 	}
-	// construct snapshot
-	xdsSnapshot := envoycache.NewSnapshot(fmt.Sprintf("%v", version), endpointsProto, clustersProto, routesProto, listenersProto)
 
 	// aggregate reports
 	reports := append(upstreamReports, virtualServiceReports...)
@@ -230,6 +261,7 @@ func loadAssignmentForCluster(clusterName string, addresses []endpointdiscovery.
 		}
 		endpoints = append(endpoints, lbEndpoint)
 	}
+	sortLbEndpoints(endpoints)
 
 	return &envoyapi.ClusterLoadAssignment{
 		ClusterName: clusterName,
@@ -241,7 +273,7 @@ func loadAssignmentForCluster(clusterName string, addresses []endpointdiscovery.
 
 // Clusters
 
-func (t *Translator) computeClusters(cfg *v1.Config, dependencies *pluginDependencies, endpoints endpointdiscovery.EndpointGroups) ([]*envoyapi.Cluster, []reporter.ConfigObjectReport) {
+func (t *Translator) computeClusters(role *v1.Role, cfg *v1.Config, dependencies *pluginDependencies, endpoints endpointdiscovery.EndpointGroups) ([]*envoyapi.Cluster, []reporter.ConfigObjectReport) {
 	var (
 		reports  []reporter.ConfigObjectReport
 		clusters []*envoyapi.Cluster
@@ -253,7 +285,7 @@ func (t *Translator) computeClusters(cfg *v1.Config, dependencies *pluginDepende
 		if err == nil {
 			clusters = append(clusters, cluster)
 		}
-		reports = append(reports, createReport(upstream, err))
+		reports = append(reports, createReport(role, upstream, err, nil))
 	}
 	return clusters, reports
 }
@@ -347,13 +379,22 @@ func (t *Translator) computeVirtualHosts(role *v1.Role,
 		roleErr error
 	)
 
+	now := t.now()
+
 	// check for bad domains, then add those errors to the vService error list
 	vServicesWithBadDomains := findVirtualServicesWithConflictingDomains(cfg.VirtualServices)
 
 	for _, virtualService := range cfg.VirtualServices {
 		roleErr = vServicesWithBadDomains[virtualService.Name]
 
-		envoyVirtualHost, err := t.computeVirtualHost(cfg.Upstreams, virtualService, erroredUpstreams, secrets)
+		envoyVirtualHost, warnings, err := t.computeVirtualHost(cfg.Upstreams, virtualService, erroredUpstreams)
+
+		// validate ssl config if the host specifies one
+		certificate, certWarnings, certErr := t.validateVirtualServiceSSLConfig(virtualService, secrets, now)
+		if certErr != nil {
+			err = multierror.Append(err, certErr)
+		}
+		warnings = append(warnings, certWarnings...)
 		if roleErr != nil {
 			// report the role err on the virtualservice too
 			// TODO: find a way to connect errors from roles to the virtualservice
@@ -361,7 +402,11 @@ func (t *Translator) computeVirtualHosts(role *v1.Role,
 			// this virtualservice
 			err = multierror.Append(err, roleErr)
 		}
-		reports = append(reports, createReport(virtualService, err))
+		report := createReport(role, virtualService, err, warnings)
+		if certificate != nil {
+			report.Certificates = []*v1.Certificate{certificate}
+		}
+		reports = append(reports, report)
 		// don't append errored virtual services to the success list
 		if err != nil {
 			continue
@@ -376,7 +421,10 @@ func (t *Translator) computeVirtualHosts(role *v1.Role,
 	}
 
 	// add report for the role
-	reports = append(reports, createReport(role, roleErr))
+	reports = append(reports, createReport(role, role, roleErr, nil))
+
+	sortVirtualHosts(sslVirtualHosts)
+	sortVirtualHosts(noSslVirtualHosts)
 
 	return sslVirtualHosts, noSslVirtualHosts, reports
 }
@@ -398,10 +446,18 @@ func findVirtualServicesWithConflictingDomains(virtualServices []*v1.VirtualServ
 			domainsToVirtualServices[domain] = append(domainsToVirtualServices[domain], vService.Name)
 		}
 	}
+	// iterate domains in order so the resulting errors are stable between translations
+	var domains []string
+	for domain := range domainsToVirtualServices {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
 	erroredVServices := make(map[string]error)
 	// see if we found any conflicts, if so, write reports
-	for domain, vServices := range domainsToVirtualServices {
+	for _, domain := range domains {
+		vServices := domainsToVirtualServices[domain]
 		if len(vServices) > 1 {
+			sort.Strings(vServices)
 			for _, name := range vServices {
 				erroredVServices[name] = multierror.Append(erroredVServices[name], errors.Errorf("domain %v is "+
 					"shared by the following virtual services: %v", domain, vServices))
@@ -413,13 +469,20 @@ func findVirtualServicesWithConflictingDomains(virtualServices []*v1.VirtualServ
 
 func (t *Translator) computeVirtualHost(upstreams []*v1.Upstream,
 	virtualService *v1.VirtualService,
-	erroredUpstreams map[string]bool,
-	secrets secretwatcher.SecretMap) (envoyroute.VirtualHost, error) {
+	erroredUpstreams map[string]bool) (envoyroute.VirtualHost, []error, error) {
 	var envoyRoutes []envoyroute.Route
-	var vServiceErrors error
-	for _, route := range virtualService.Routes {
-		if err := validateRouteDestinations(upstreams, route, erroredUpstreams); err != nil {
-			vServiceErrors = multierror.Append(vServiceErrors, err)
+	var (
+		vServiceErrors error
+		warnings       []error
+	)
+	// errors and warnings for a route are reported with the index of the route
+	for i, route := range virtualService.Routes {
+		routeWarnings, err := validateRouteDestinations(upstreams, route, erroredUpstreams)
+		if err != nil {
+			vServiceErrors = multierror.Append(vServiceErrors, &reporter.RouteError{Index: i, Err: err})
+		}
+		for _, warning := range routeWarnings {
+			warnings = append(warnings, &reporter.RouteError{Index: i, Err: warning})
 		}
 		out := envoyroute.Route{}
 		for _, plug := range t.plugins {
@@ -431,17 +494,12 @@ func (t *Translator) computeVirtualHost(upstreams []*v1.Upstream,
 				Upstreams: upstreams,
 			}
 			if err := routePlugin.ProcessRoute(params, route, &out); err != nil {
-				vServiceErrors = multierror.Append(vServiceErrors, err)
+				vServiceErrors = multierror.Append(vServiceErrors, &reporter.RouteError{Index: i, Err: err})
 			}
 		}
 		envoyRoutes = append(envoyRoutes, out)
 	}
 
-	// validate ssl config if the host specifies one
-	if err := validateVirtualServiceSSLConfig(virtualService, secrets); err != nil {
-		vServiceErrors = multierror.Append(vServiceErrors, err)
-	}
-
 	domains := virtualService.Domains
 	if len(domains) == 0 || (len(domains) == 1 && domains[0] == "") {
 		domains = []string{"*"}
@@ -453,10 +511,11 @@ func (t *Translator) computeVirtualHost(upstreams []*v1.Upstream,
 		Name:    virtualHostName(virtualService.Name),
 		Domains: domains,
 		Routes:  envoyRoutes,
-	}, vServiceErrors
+	}, warnings, vServiceErrors
 }
 
-func validateRouteDestinations(upstreams []*v1.Upstream, route *v1.Route, erroredUpstreams map[string]bool) error {
+// returns warnings for destinations that are likely mistakes, but can still be routed to
+func validateRouteDestinations(upstreams []*v1.Upstream, route *v1.Route, erroredUpstreams map[string]bool) ([]error, error) {
 	// collect existing upstreams/functions for matching
 	upstreamsAndTheirFunctions := make(map[string][]string)
 
@@ -480,7 +539,7 @@ func validateRouteDestinations(upstreams []*v1.Upstream, route *v1.Route, errore
 	case route.SingleDestination == nil && len(route.MultipleDestinations) > 0:
 		return validateMultiDestination(upstreamsAndTheirFunctions, route.MultipleDestinations)
 	}
-	return errors.Errorf("must specify either 'single_destination' or 'multiple_destinations' for route")
+	return nil, errors.Errorf("must specify either 'single_destination' or 'multiple_destinations' for route")
 }
 
 func getErroredUpstreams(clusterReports []reporter.ConfigObjectReport) map[string]bool {
@@ -497,23 +556,26 @@ func getErroredUpstreams(clusterReports []reporter.ConfigObjectReport) map[strin
 	return erroredUpstreams
 }
 
-func validateMultiDestination(upstreamsAndTheirFunctions map[string][]string, destinations []*v1.WeightedDestination) error {
+func validateMultiDestination(upstreamsAndTheirFunctions map[string][]string, destinations []*v1.WeightedDestination) ([]error, error) {
+	var warnings []error
 	for _, dest := range destinations {
-		if err := validateSingleDestination(upstreamsAndTheirFunctions, dest.Destination); err != nil {
-			return errors.Wrap(err, "invalid destination in weighted destination list")
+		destWarnings, err := validateSingleDestination(upstreamsAndTheirFunctions, dest.Destination)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid destination in weighted destination list")
 		}
+		warnings = append(warnings, destWarnings...)
 	}
-	return nil
+	return warnings, nil
 }
 
-func validateSingleDestination(upstreamsAndTheirFunctions map[string][]string, destination *v1.Destination) error {
+func validateSingleDestination(upstreamsAndTheirFunctions map[string][]string, destination *v1.Destination) ([]error, error) {
 	switch dest := destination.DestinationType.(type) {
 	case *v1.Destination_Upstream:
-		return validateUpstreamDestination(upstreamsAndTheirFunctions, dest)
+		return nil, validateUpstreamDestination(upstreamsAndTheirFunctions, dest)
 	case *v1.Destination_Function:
 		return validateFunctionDestination(upstreamsAndTheirFunctions, dest)
 	}
-	return errors.New("must specify either a function or upstream on a single destination")
+	return nil, errors.New("must specify either a function or upstream on a single destination")
 }
 
 func validateUpstreamDestination(upstreamsAndTheirFunctions map[string][]string, upstreamDestination *v1.Destination_Upstream) error {
@@ -524,17 +586,18 @@ func validateUpstreamDestination(upstreamsAndTheirFunctions map[string][]string,
 	return nil
 }
 
-func validateFunctionDestination(upstreamsAndTheirFunctions map[string][]string, functionDestination *v1.Destination_Function) error {
+// functions may be discovered after the route is created, so a missing function is only a warning
+func validateFunctionDestination(upstreamsAndTheirFunctions map[string][]string, functionDestination *v1.Destination_Function) ([]error, error) {
 	upstreamName := functionDestination.Function.UpstreamName
 	upstreamFuncs, ok := upstreamsAndTheirFunctions[upstreamName]
 	if !ok {
-		return errors.Errorf("upstream %v was not found or had errors for function destination", upstreamName)
+		return nil, errors.Errorf("upstream %v was not found or had errors for function destination", upstreamName)
 	}
 	functionName := functionDestination.Function.FunctionName
 	if !stringInSlice(upstreamFuncs, functionName) {
-		log.Warnf("function %v/%v was not found for function destination", upstreamName, functionName)
+		return []error{errors.Errorf("function %v/%v was not found for function destination", upstreamName, functionName)}, nil
 	}
-	return nil
+	return nil, nil
 }
 
 func stringInSlice(slice []string, s string) bool {
@@ -546,28 +609,9 @@ func stringInSlice(slice []string, s string) bool {
 	return false
 }
 
-func validateVirtualServiceSSLConfig(virtualService *v1.VirtualService, secrets secretwatcher.SecretMap) error {
-	if virtualService.SslConfig == nil || virtualService.SslConfig.SecretRef == "" {
-		return nil
-	}
-	_, _, err := getSslSecrets(virtualService.SslConfig.SecretRef, secrets)
-	return err
-}
-
+// the secret may be a kubernetes tls secret, a gloo tls secret or a PEM bundle
 func getSslSecrets(ref string, secrets secretwatcher.SecretMap) (string, string, error) {
-	sslSecrets, ok := secrets[ref]
-	if !ok {
-		return "", "", errors.Errorf("ssl secret not found for ref %v", ref)
-	}
-	certChain, ok := sslSecrets.Data[sslCertificateChainKey]
-	if !ok {
-		return "", "", errors.Errorf("key %v not found in ssl secrets", sslCertificateChainKey)
-	}
-	privateKey, ok := sslSecrets.Data[sslPrivateKeyKey]
-	if !ok {
-		return "", "", errors.Errorf("key %v not found in ssl secrets", sslPrivateKeyKey)
-	}
-	return certChain, privateKey, nil
+	return secretwatcher.TlsCertificateForRef(secrets, ref)
 }
 
 // Listener
@@ -598,22 +642,22 @@ func (t *Translator) constructHttpListener(name string, port uint32, filters []e
 	}
 }
 
-const (
-	sslCertificateChainKey = "ca_chain"
-	sslPrivateKeyKey       = "private_key"
-)
-
 func (t *Translator) constructHttpsListener(name string,
 	port uint32,
 	filters []envoylistener.Filter,
 	virtualServices []*v1.VirtualService,
 	virtualServiceReports []reporter.ConfigObjectReport,
-	secrets secretwatcher.SecretMap) (*envoyapi.Listener, error) {
+	secrets secretwatcher.SecretMap) (*envoyapi.Listener, []*envoyauth.Secret, error) {
 
 	// create the base filter chain
-	// we will copy the filter chain for each virtualservice that specifies an ssl config
-	var filterChains []envoylistener.FilterChain
-	for _, vService := range virtualServices {
+	// we will copy the filter chain for each virtualservice that specifies an ssl config.
+	// the certificates are served over sds as secrets named after the secret ref, see sslCertificateSecretName
+	var (
+		filterChains []envoylistener.FilterChain
+		sslSecrets   []*envoyauth.Secret
+		added        = make(map[string]bool)
+	)
+	for _, vService := range sortedVirtualServices(virtualServices) {
 		if vService.SslConfig == nil || vService.SslConfig.SecretRef == "" {
 			continue
 		}
@@ -623,9 +667,15 @@ func (t *Translator) constructHttpsListener(name string,
 			log.Warnf("skipping ssl vService with invalid secrets: %v", vService.Name)
 			continue
 		}
-		filterChain := newSslFilterChain(certChain, privateKey, filters)
+		secretName := sslCertificateSecretName(ref)
+		filterChain := newSslFilterChain(secretName, filters)
 		filterChains = append(filterChains, filterChain)
+		if !added[secretName] {
+			added[secretName] = true
+			sslSecrets = append(sslSecrets, newTlsCertificateSecret(secretName, certChain, privateKey))
+		}
 	}
+	sortSecrets(sslSecrets)
 
 	return &envoyapi.Listener{
 		Name: name,
@@ -642,10 +692,10 @@ func (t *Translator) constructHttpsListener(name string,
 			},
 		},
 		FilterChains: filterChains,
-	}, nil
+	}, sslSecrets, nil
 }
 
-func newSslFilterChain(certChain, privateKey string, filters []envoylistener.Filter) envoylistener.FilterChain {
+func newSslFilterChain(secretName string, filters []envoylistener.Filter) envoylistener.FilterChain {
 	return envoylistener.FilterChain{
 		Filters: filters,
 		TlsContext: &envoyauth.DownstreamTlsContext{
@@ -653,19 +703,8 @@ func newSslFilterChain(certChain, privateKey string, filters []envoylistener.Fil
 				// default params
 				TlsParams: &envoyauth.TlsParameters{},
 				// TODO: configure client certificates
-				TlsCertificates: []*envoyauth.TlsCertificate{
-					{
-						CertificateChain: &envoycore.DataSource{
-							Specifier: &envoycore.DataSource_InlineString{
-								InlineString: certChain,
-							},
-						},
-						PrivateKey: &envoycore.DataSource{
-							Specifier: &envoycore.DataSource_InlineString{
-								InlineString: privateKey,
-							},
-						},
-					},
+				TlsCertificateSdsSecretConfigs: []*envoyauth.SdsSecretConfig{
+					sdsSecretConfig(secretName),
 				},
 			},
 		},
@@ -674,7 +713,8 @@ func newSslFilterChain(certChain, privateKey string, filters []envoylistener.Fil
 
 func (t *Translator) createHttpFilters() []*envoyhttp.HttpFilter {
 	var filtersByStage []stagedFilter
-	for _, plug := range t.plugins {
+	lastFilters := make([][]plugins.StagedFilter, len(t.plugins))
+	for i, plug := range t.plugins {
 		filterPlugin, ok := plug.(plugins.FilterPlugin)
 		if !ok {
 			continue
@@ -686,6 +726,7 @@ func (t *Translator) createHttpFilters() []*envoyhttp.HttpFilter {
 				log.Warnf("plugin implements HttpFilters() but returned nil")
 				continue
 			}
+			lastFilters[i] = append(lastFilters[i], httpFilter)
 			filtersByStage = append(filtersByStage, stagedFilter{
 				filter: httpFilter.HttpFilter,
 				stage:  httpFilter.Stage,
@@ -693,6 +734,10 @@ func (t *Translator) createHttpFilters() []*envoyhttp.HttpFilter {
 		}
 	}
 
+	t.filtersLock.Lock()
+	t.lastFilters = lastFilters
+	t.filtersLock.Unlock()
+
 	// sort filters by stage
 	httpFilters := sortFilters(filtersByStage)
 	httpFilters = append(httpFilters, &envoyhttp.HttpFilter{Name: routerFilter})
@@ -758,9 +803,14 @@ func virtualHostName(virtualServiceName string) string {
 	return virtualServiceName
 }
 
-func createReport(cfgObject v1.ConfigObject, err error) reporter.ConfigObjectReport {
-	return reporter.ConfigObjectReport{
+func createReport(role *v1.Role, cfgObject v1.ConfigObject, err error, warnings []error) reporter.ConfigObjectReport {
+	report := reporter.ConfigObjectReport{
 		CfgObject: cfgObject,
 		Err:       err,
+		Warnings:  warnings,
+	}
+	if err == nil {
+		report.AcceptedRoles = []string{role.Name}
 	}
+	return report
 }
@@ -1,6 +1,8 @@
 package translator
 
 import (
+	envoyauth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
+	envoycore "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
 	envoyroute "github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
 	envoycache "github.com/envoyproxy/go-control-plane/pkg/cache"
 	"github.com/solo-io/gloo/pkg/plugins"
@@ -14,13 +16,31 @@ import (
 	"github.com/solo-io/gloo/pkg/api/types/v1"
 	"github.com/solo-io/gloo/pkg/coreplugins/route-extensions"
 	"github.com/solo-io/gloo/pkg/coreplugins/service"
+	"github.com/solo-io/gloo/pkg/endpointdiscovery"
 	"github.com/solo-io/gloo/pkg/storage/dependencies"
+	"github.com/hashicorp/go-multierror"
+	"github.com/solo-io/gloo/internal/control-plane/acme"
 	"github.com/solo-io/gloo/internal/control-plane/bootstrap"
+	"github.com/solo-io/gloo/internal/control-plane/reporter"
 	"github.com/solo-io/gloo/internal/control-plane/snapshot"
+	"github.com/solo-io/gloo/test/helpers"
 )
 
 func newTranslator() *Translator {
-	return NewTranslator(bootstrap.IngressOptions{"::", 8080, 8443}, []plugins.TranslatorPlugin{service.NewPlugin()})
+	opts := bootstrap.IngressOptions{BindAddress: "::", Port: 8080, SecurePort: 8443, CertExpiryWarning: 30 * 24 * time.Hour}
+	return NewTranslator(opts, []plugins.TranslatorPlugin{service.NewPlugin()})
+}
+
+// sslSecrets returns the secret for ssl-secret-ref, holding a certificate for the dns names that expires at notAfter
+func sslSecrets(dnsNames []string, notAfter time.Time) (secretwatcher.SecretMap, *helpers.TestCert) {
+	cert, err := helpers.NewTestCert("gloo-test", dnsNames, notAfter, nil)
+	Expect(err).NotTo(HaveOccurred())
+	return secretwatcher.SecretMap{
+		"ssl-secret-ref": &dependencies.Secret{Ref: "ssl-secret-ref", Data: map[string]string{
+			"tls.crt": cert.CertPEM,
+			"tls.key": cert.KeyPEM,
+		}},
+	}, cert
 }
 
 var _ = Describe("Translator", func() {
@@ -103,6 +123,9 @@ var _ = Describe("Translator", func() {
 				Expect(reports[1].Err.Error()).To(ContainSubstring("upstream invalid-service was not found or had " +
 					"errors for upstream destination"))
 				Expect(reports[1].CfgObject).To(Equal(cfg.VirtualServices[0]))
+				Expect(reports[1].Err.(*multierror.Error).Errors[0]).To(BeAssignableToTypeOf(&reporter.RouteError{}))
+				Expect(reports[1].AcceptedRoles).To(BeEmpty())
+				Expect(reports[0].AcceptedRoles).To(Equal([]string{role.Name}))
 				Expect(reports[2].Err).To(BeNil())
 				Expect(reports[2].CfgObject).To(Equal(role))
 				clas, clusters, routeConfigs, listeners := getSnapshotResources(snap)
@@ -112,6 +135,31 @@ var _ = Describe("Translator", func() {
 				Expect(listeners).To(HaveLen(0))
 			})
 		})
+		Context("with a route to a function the upstream does not declare", func() {
+			cfg := InvalidConfigNoUpstream()
+			cfg.VirtualServices[0].Routes[0].SingleDestination = &v1.Destination{
+				DestinationType: &v1.Destination_Function{
+					Function: &v1.FunctionDestination{
+						UpstreamName: "valid-service",
+						FunctionName: "missing-function",
+					},
+				},
+			}
+			t := newTranslator()
+			It("accepts the virtual service with a warning for the route", func() {
+				_, reports, err := t.Translate(role, &snapshot.Cache{Cfg: cfg})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(reports).To(HaveLen(3))
+				Expect(reports[1].CfgObject).To(Equal(cfg.VirtualServices[0]))
+				Expect(reports[1].Err).To(BeNil())
+				Expect(reports[1].AcceptedRoles).To(Equal([]string{role.Name}))
+				Expect(reports[1].Warnings).To(HaveLen(1))
+				routeErr, ok := reports[1].Warnings[0].(*reporter.RouteError)
+				Expect(ok).To(BeTrue())
+				Expect(routeErr.Index).To(Equal(0))
+				Expect(routeErr.Err.Error()).To(Equal("function valid-service/missing-function was not found for function destination"))
+			})
+		})
 	})
 	Context("valid config", func() {
 		Context("with no ssl vServices", func() {
@@ -156,15 +204,8 @@ var _ = Describe("Translator", func() {
 			})
 			Context("the desired ssl secret not present in the secret map", func() {
 				It("returns an empty ssl routeconfig and a len 1 nossl routeconfig", func() {
-					snap, reports, err := t.Translate(role, &snapshot.Cache{
-						Cfg: cfg,
-						Secrets: secretwatcher.SecretMap{
-							"ssl-secret-ref": &dependencies.Secret{Ref: "ssl-secret-ref", Data: map[string]string{
-								"ca_chain":    "1111",
-								"private_key": "1111",
-							},
-							}},
-					})
+					secrets, _ := sslSecrets(nil, time.Now().Add(365*24*time.Hour))
+					snap, reports, err := t.Translate(role, &snapshot.Cache{Cfg: cfg, Secrets: secrets})
 					Expect(err).NotTo(HaveOccurred())
 					Expect(reports).To(HaveLen(3))
 					Expect(reports[0].CfgObject).To(Equal(cfg.Upstreams[0]))
@@ -185,6 +226,276 @@ var _ = Describe("Translator", func() {
 			})
 		})
 	})
+	Context("tls secrets", func() {
+		It("accepts kubernetes tls secrets for virtual services", func() {
+			cfg := ValidConfigSsl()
+			secrets, cert := sslSecrets(nil, time.Now().Add(365*24*time.Hour))
+			snap, reports, err := newTranslator().Translate(role, &snapshot.Cache{Cfg: cfg, Secrets: secrets})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reports[1].Err).To(BeNil())
+			secret := snap.Secrets.Items["ssl-certificate/ssl-secret-ref"].(*envoyauth.Secret)
+			Expect(secret.GetTlsCertificate().CertificateChain.GetInlineString()).To(Equal(cert.CertPEM))
+		})
+		It("serves the client certificate of a service upstream over sds", func() {
+			cfg := ValidConfigNoSsl()
+			cfg.Upstreams[0].Spec = service.EncodeUpstreamSpec(service.UpstreamSpec{
+				Hosts:        []service.Host{{Addr: "localhost", Port: 1234}},
+				TLSSecretRef: "client-cert",
+			})
+			secrets := secretwatcher.SecretMap{
+				"client-cert": &dependencies.Secret{Ref: "client-cert", Data: map[string]string{
+					"tls.crt": "cert",
+					"tls.key": "key",
+				}},
+			}
+			snap, reports, err := newTranslator().Translate(role, &snapshot.Cache{Cfg: cfg, Secrets: secrets})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reports[0].Err).To(BeNil())
+
+			_, clusters, _, _ := getSnapshotResources(snap)
+			Expect(clusters).To(HaveLen(1))
+			tlsContext := clusters[0].TlsContext.CommonTlsContext
+			Expect(tlsContext.TlsCertificates).To(BeEmpty())
+			Expect(tlsContext.TlsCertificateSdsSecretConfigs).To(HaveLen(1))
+			Expect(tlsContext.TlsCertificateSdsSecretConfigs[0].Name).To(Equal("client-certificate/valid-service"))
+			secret := snap.Secrets.Items["client-certificate/valid-service"].(*envoyauth.Secret)
+			Expect(secret.GetTlsCertificate().PrivateKey.GetInlineString()).To(Equal("key"))
+		})
+		It("rejects upstreams whose client certificate secret is not a tls secret", func() {
+			cfg := ValidConfigNoSsl()
+			cfg.Upstreams[0].Spec = service.EncodeUpstreamSpec(service.UpstreamSpec{
+				Hosts:        []service.Host{{Addr: "localhost", Port: 1234}},
+				TLSSecretRef: "client-cert",
+			})
+			secrets := secretwatcher.SecretMap{
+				"client-cert": &dependencies.Secret{Ref: "client-cert", Data: map[string]string{"token": "abc"}},
+			}
+			_, reports, err := newTranslator().Translate(role, &snapshot.Cache{Cfg: cfg, Secrets: secrets})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reports[0].Err).To(HaveOccurred())
+			Expect(reports[0].Err.Error()).To(ContainSubstring("secret client-cert is not a tls secret"))
+		})
+	})
+	Context("virtual service certificates", func() {
+		var cfg *v1.Config
+		BeforeEach(func() {
+			cfg = ValidConfigSsl()
+			cfg.VirtualServices[0].Domains = []string{"example.com", "api.example.com"}
+		})
+		It("reports the subject, names and expiry of the certificate", func() {
+			notAfter := time.Now().Add(365 * 24 * time.Hour)
+			secrets, _ := sslSecrets([]string{"example.com", "*.example.com"}, notAfter)
+			_, reports, err := newTranslator().Translate(role, &snapshot.Cache{Cfg: cfg, Secrets: secrets})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reports[1].Err).To(BeNil())
+			Expect(reports[1].Warnings).To(BeEmpty())
+			Expect(reports[1].Certificates).To(HaveLen(1))
+			certificate := reports[1].Certificates[0]
+			Expect(certificate.SecretRef).To(Equal("ssl-secret-ref"))
+			Expect(certificate.Subject).To(Equal("CN=gloo-test"))
+			Expect(certificate.DnsNames).To(Equal([]string{"example.com", "*.example.com"}))
+			Expect(certificate.NotAfter.Seconds).To(Equal(notAfter.Unix()))
+		})
+		It("warns when the certificate expires within the warning window", func() {
+			secrets, _ := sslSecrets([]string{"example.com", "*.example.com"}, time.Now().Add(10*24*time.Hour+time.Hour))
+			_, reports, err := newTranslator().Translate(role, &snapshot.Cache{Cfg: cfg, Secrets: secrets})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reports[1].Err).To(BeNil())
+			Expect(reports[1].Warnings).To(HaveLen(1))
+			Expect(reports[1].Warnings[0].Error()).To(ContainSubstring("certificate in secret ssl-secret-ref expires at"))
+			Expect(reports[1].Warnings[0].Error()).To(ContainSubstring("in 10 days"))
+		})
+		It("rejects certificates that expired", func() {
+			secrets, _ := sslSecrets([]string{"example.com", "*.example.com"}, time.Now().Add(-time.Minute))
+			_, reports, err := newTranslator().Translate(role, &snapshot.Cache{Cfg: cfg, Secrets: secrets})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reports[1].Err).To(HaveOccurred())
+			Expect(reports[1].Err.Error()).To(ContainSubstring("certificate in secret ssl-secret-ref expired at"))
+			Expect(reports[1].Certificates).To(HaveLen(1))
+		})
+		It("checks expiry with the clock of the translator", func() {
+			notAfter := time.Now().Add(365 * 24 * time.Hour)
+			secrets, _ := sslSecrets([]string{"example.com", "*.example.com"}, notAfter)
+			trans := newTranslator()
+			trans.now = func() time.Time { return notAfter.Add(-10 * 24 * time.Hour) }
+			_, reports, err := trans.Translate(role, &snapshot.Cache{Cfg: cfg, Secrets: secrets})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reports[1].Err).To(BeNil())
+			Expect(reports[1].Warnings).To(HaveLen(1))
+
+			trans.now = func() time.Time { return notAfter.Add(time.Second) }
+			_, reports, err = trans.Translate(role, &snapshot.Cache{Cfg: cfg, Secrets: secrets})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reports[1].Err).To(MatchError(ContainSubstring("certificate in secret ssl-secret-ref expired at")))
+		})
+		It("returns when the next certificate starts expiring within the warning window, and when it expires", func() {
+			notAfter := time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)
+			secrets, _ := sslSecrets([]string{"example.com", "*.example.com"}, notAfter)
+			trans := newTranslator()
+			_, reports, err := trans.Translate(role, &snapshot.Cache{Cfg: cfg, Secrets: secrets})
+			Expect(err).NotTo(HaveOccurred())
+
+			warnAt := notAfter.Add(-30 * 24 * time.Hour)
+			next, ok := trans.NextCertificateCheck(reports, warnAt.Add(-time.Hour))
+			Expect(ok).To(BeTrue())
+			Expect(next).To(BeTemporally("==", warnAt))
+			next, ok = trans.NextCertificateCheck(reports, warnAt)
+			Expect(ok).To(BeTrue())
+			Expect(next).To(BeTemporally("==", notAfter))
+			_, ok = trans.NextCertificateCheck(reports, notAfter)
+			Expect(ok).To(BeFalse())
+		})
+		It("rejects certificates that are not valid for every domain", func() {
+			cfg.VirtualServices[0].Domains = append(cfg.VirtualServices[0].Domains, "other.com:8443")
+			secrets, _ := sslSecrets([]string{"example.com"}, time.Now().Add(365*24*time.Hour))
+			_, reports, err := newTranslator().Translate(role, &snapshot.Cache{Cfg: cfg, Secrets: secrets})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reports[1].Err).To(HaveOccurred())
+			Expect(reports[1].Err.Error()).To(ContainSubstring("certificate in secret ssl-secret-ref is not valid for the domains " +
+				"[api.example.com, other.com:8443]. it is valid for [example.com]"))
+		})
+		It("rejects secrets without a valid certificate", func() {
+			secrets := secretwatcher.SecretMap{
+				"ssl-secret-ref": &dependencies.Secret{Ref: "ssl-secret-ref", Data: map[string]string{
+					"tls.crt": "cert",
+					"tls.key": "key",
+				}},
+			}
+			_, reports, err := newTranslator().Translate(role, &snapshot.Cache{Cfg: cfg, Secrets: secrets})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reports[1].Err).To(HaveOccurred())
+			Expect(reports[1].Err.Error()).To(ContainSubstring("invalid certificate in secret ssl-secret-ref"))
+		})
+	})
+	Context("acme challenges", func() {
+		challenges := []acme.Challenge{{
+			VirtualService:   "valid-vservice",
+			Domain:           "example.com",
+			Token:            "token",
+			KeyAuthorization: "token.thumbprint",
+		}}
+		challengeRoute := envoyroute.Route{
+			Match: envoyroute.RouteMatch{
+				PathSpecifier: &envoyroute.RouteMatch_Path{Path: "/.well-known/acme-challenge/token"},
+			},
+			Action: &envoyroute.Route_DirectResponse{
+				DirectResponse: &envoyroute.DirectResponseAction{
+					Status: 200,
+					Body: &envoycore.DataSource{
+						Specifier: &envoycore.DataSource_InlineString{InlineString: "token.thumbprint"},
+					},
+				},
+			},
+		}
+		It("answers challenges on the http listener while the certificate is issued", func() {
+			cfg := ValidConfigSsl()
+			cfg.VirtualServices[0].Domains = []string{"example.com"}
+			cfg.VirtualServices[0].SslConfig.AutoCertificate = true
+			snap, reports, err := newTranslator().Translate(role, &snapshot.Cache{Cfg: cfg, Challenges: challenges})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reports[1].Err).To(MatchError(ContainSubstring("waiting for the certificate in secret ssl-secret-ref to be issued")))
+
+			_, _, routeConfigs, listeners := getSnapshotResources(snap)
+			Expect(listeners).To(HaveLen(1))
+			Expect(listeners[0].Name).To(Equal(noSslListenerName))
+			Expect(routeConfigs).To(HaveLen(1))
+			Expect(routeConfigs[0].VirtualHosts).To(Equal([]envoyroute.VirtualHost{{
+				Name:    acmeChallengesVirtualHostName,
+				Domains: []string{"*"},
+				Routes:  []envoyroute.Route{challengeRoute},
+			}}))
+		})
+		It("answers challenges before the routes of the http virtual hosts", func() {
+			cfg := ValidConfigNoSsl()
+			snap, _, err := newTranslator().Translate(role, &snapshot.Cache{Cfg: cfg, Challenges: challenges})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, routeConfigs, _ := getSnapshotResources(snap)
+			Expect(routeConfigs).To(HaveLen(1))
+			// the virtual service is the default virtual host, so no catch-all virtual host is added
+			Expect(routeConfigs[0].VirtualHosts).To(HaveLen(1))
+			routes := routeConfigs[0].VirtualHosts[0].Routes
+			Expect(routes).To(HaveLen(2))
+			Expect(routes[0]).To(Equal(challengeRoute))
+		})
+		It("requires a secret ref for auto certificates", func() {
+			cfg := ValidConfigSsl()
+			cfg.VirtualServices[0].SslConfig = &v1.SSLConfig{AutoCertificate: true}
+			_, reports, err := newTranslator().Translate(role, &snapshot.Cache{Cfg: cfg})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reports[1].Err).To(MatchError(ContainSubstring("auto_certificate requires a secret_ref")))
+		})
+	})
+	Context("deterministic output", func() {
+		It("produces the same versions regardless of input order", func() {
+			cfg := PartiallyValidConfig()
+			reversed := &v1.Config{
+				Upstreams:       []*v1.Upstream{cfg.Upstreams[1], cfg.Upstreams[0]},
+				VirtualServices: []*v1.VirtualService{cfg.VirtualServices[1], cfg.VirtualServices[0]},
+			}
+			t := newTranslator()
+			snap1, _, err := t.Translate(role, &snapshot.Cache{Cfg: cfg})
+			Expect(err).NotTo(HaveOccurred())
+			snap2, _, err := t.Translate(role, &snapshot.Cache{Cfg: reversed})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(snap1.Endpoints.Version).To(Equal(snap2.Endpoints.Version))
+			Expect(snap1.Clusters.Version).To(Equal(snap2.Clusters.Version))
+			Expect(snap1.Routes.Version).To(Equal(snap2.Routes.Version))
+			Expect(snap1.Listeners.Version).To(Equal(snap2.Listeners.Version))
+		})
+		It("produces the same routes for request matchers with multiple headers", func() {
+			cfg := ValidConfigNoSsl()
+			cfg.VirtualServices[0].Routes[0].Matcher.(*v1.Route_RequestMatcher).RequestMatcher.Headers = map[string]string{
+				"x-a": "a", "x-b": "b", "x-c": "c", "x-d": "d", "x-e": "e",
+			}
+			t := newTranslator()
+			snap1, _, err := t.Translate(role, &snapshot.Cache{Cfg: cfg})
+			Expect(err).NotTo(HaveOccurred())
+			for i := 0; i < 10; i++ {
+				snap2, _, err := t.Translate(role, &snapshot.Cache{Cfg: cfg})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(snap2.Routes).To(Equal(snap1.Routes))
+			}
+		})
+		It("only changes the endpoints version when endpoints change", func() {
+			cfg := ValidConfigNoSsl()
+			t := newTranslator()
+			snap1, _, err := t.Translate(role, &snapshot.Cache{Cfg: cfg, Endpoints: endpointdiscovery.EndpointGroups{
+				"valid-service": {{Address: "1.2.3.4", Port: 1234}},
+			}})
+			Expect(err).NotTo(HaveOccurred())
+			snap2, _, err := t.Translate(role, &snapshot.Cache{Cfg: cfg, Endpoints: endpointdiscovery.EndpointGroups{
+				"valid-service": {{Address: "1.2.3.4", Port: 1234}, {Address: "5.6.7.8", Port: 1234}},
+			}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(snap1.Endpoints.Version).NotTo(Equal(snap2.Endpoints.Version))
+			Expect(snap1.Clusters.Version).To(Equal(snap2.Clusters.Version))
+			Expect(snap1.Routes.Version).To(Equal(snap2.Routes.Version))
+			Expect(snap1.Listeners.Version).To(Equal(snap2.Listeners.Version))
+		})
+		It("only changes the secrets version when a certificate is rotated", func() {
+			cfg := ValidConfigSsl()
+			oldSecrets, _ := sslSecrets(nil, time.Now().Add(365*24*time.Hour))
+			newSecrets, rotated := sslSecrets(nil, time.Now().Add(2*365*24*time.Hour))
+			t := newTranslator()
+			snap1, _, err := t.Translate(role, &snapshot.Cache{Cfg: cfg, Secrets: oldSecrets})
+			Expect(err).NotTo(HaveOccurred())
+			snap2, _, err := t.Translate(role, &snapshot.Cache{Cfg: cfg, Secrets: newSecrets})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(snap1.Secrets.Version).NotTo(Equal(snap2.Secrets.Version))
+			Expect(snap1.Listeners.Version).To(Equal(snap2.Listeners.Version))
+
+			Expect(snap2.Secrets.Items).To(HaveKey("ssl-certificate/ssl-secret-ref"))
+			secret := snap2.Secrets.Items["ssl-certificate/ssl-secret-ref"].(*envoyauth.Secret)
+			Expect(secret.GetTlsCertificate().CertificateChain.GetInlineString()).To(Equal(rotated.CertPEM))
+			_, _, _, listeners := getSnapshotResources(snap2)
+			Expect(listeners).To(HaveLen(1))
+			tlsContext := listeners[0].FilterChains[0].TlsContext.CommonTlsContext
+			Expect(tlsContext.TlsCertificates).To(BeEmpty())
+			Expect(tlsContext.TlsCertificateSdsSecretConfigs).To(HaveLen(1))
+			Expect(tlsContext.TlsCertificateSdsSecretConfigs[0].Name).To(Equal("ssl-certificate/ssl-secret-ref"))
+		})
+	})
 })
 
 func getSnapshotResources(snap *envoycache.Snapshot) ([]*v2.ClusterLoadAssignment, []*v2.Cluster, []*v2.RouteConfiguration, []*v2.Listener) {
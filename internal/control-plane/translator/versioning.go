@@ -0,0 +1,116 @@
+package translator
+
+import (
+	"fmt"
+	"sort"
+
+	envoyapi "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	envoyauth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
+	envoycore "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	envoyendpoints "github.com/envoyproxy/go-control-plane/envoy/api/v2/endpoint"
+	envoyroute "github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
+	envoycache "github.com/envoyproxy/go-control-plane/pkg/cache"
+	"github.com/mitchellh/hashstructure"
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+)
+
+// newVersionedSnapshot creates an xds snapshot where each resource type
+// carries its own version, computed from the content of that type only
+func newVersionedSnapshot(endpoints, clusters, routes, listeners, secrets []envoycache.Resource) (*envoycache.Snapshot, error) {
+	endpointsVersion, err := resourcesVersion(endpoints)
+	if err != nil {
+		return nil, errors.Wrap(err, "versioning endpoints")
+	}
+	clustersVersion, err := resourcesVersion(clusters)
+	if err != nil {
+		return nil, errors.Wrap(err, "versioning clusters")
+	}
+	routesVersion, err := resourcesVersion(routes)
+	if err != nil {
+		return nil, errors.Wrap(err, "versioning routes")
+	}
+	listenersVersion, err := resourcesVersion(listeners)
+	if err != nil {
+		return nil, errors.Wrap(err, "versioning listeners")
+	}
+	secretsVersion, err := resourcesVersion(secrets)
+	if err != nil {
+		return nil, errors.Wrap(err, "versioning secrets")
+	}
+	return &envoycache.Snapshot{
+		Endpoints: envoycache.NewResources(endpointsVersion, endpoints),
+		Clusters:  envoycache.NewResources(clustersVersion, clusters),
+		Routes:    envoycache.NewResources(routesVersion, routes),
+		Listeners: envoycache.NewResources(listenersVersion, listeners),
+		Secrets:   envoycache.NewResources(secretsVersion, secrets),
+	}, nil
+}
+
+// resources must be sorted before calling resourcesVersion,
+// otherwise the same set of resources may produce different versions
+func resourcesVersion(resources []envoycache.Resource) (string, error) {
+	hash, err := hashstructure.Hash(resources, nil)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%v", hash), nil
+}
+
+func sortSecrets(secrets []*envoyauth.Secret) {
+	sort.SliceStable(secrets, func(i, j int) bool {
+		return secrets[i].Name < secrets[j].Name
+	})
+}
+
+func sortClusters(clusters []*envoyapi.Cluster) {
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return clusters[i].Name < clusters[j].Name
+	})
+}
+
+func sortClusterLoadAssignments(clas []*envoyapi.ClusterLoadAssignment) {
+	sort.SliceStable(clas, func(i, j int) bool {
+		return clas[i].ClusterName < clas[j].ClusterName
+	})
+}
+
+func sortLbEndpoints(endpoints []envoyendpoints.LbEndpoint) {
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		addri, porti := socketAddress(endpoints[i])
+		addrj, portj := socketAddress(endpoints[j])
+		if addri != addrj {
+			return addri < addrj
+		}
+		return porti < portj
+	})
+}
+
+func socketAddress(endpoint envoyendpoints.LbEndpoint) (string, uint32) {
+	if endpoint.Endpoint == nil || endpoint.Endpoint.Address == nil {
+		return "", 0
+	}
+	sockAddr, ok := endpoint.Endpoint.Address.Address.(*envoycore.Address_SocketAddress)
+	if !ok || sockAddr.SocketAddress == nil {
+		return "", 0
+	}
+	return sockAddr.SocketAddress.Address, sockAddr.SocketAddress.GetPortValue()
+}
+
+func sortVirtualHosts(virtualHosts []envoyroute.VirtualHost) {
+	sort.SliceStable(virtualHosts, func(i, j int) bool {
+		return virtualHosts[i].Name < virtualHosts[j].Name
+	})
+}
+
+// returns a copy of the virtual services sorted by name
+// the original slice is left untouched as its order determines the order of reports
+func sortedVirtualServices(virtualServices []*v1.VirtualService) []*v1.VirtualService {
+	sorted := make([]*v1.VirtualService, len(virtualServices))
+	copy(sorted, virtualServices)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
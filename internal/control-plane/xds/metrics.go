@@ -0,0 +1,28 @@
+package xds
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/solo-io/gloo/pkg/metrics"
+)
+
+const subsystem = "xds"
+
+var (
+	connectedStreams = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystem,
+		Name:      "streams",
+		Help:      "number of xDS streams currently open",
+	})
+	nacksTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystem,
+		Name:      "nacks_total",
+		Help:      "number of configuration updates rejected by envoy, by role and resource type",
+	}, []string{"role", "type_url"})
+)
+
+func init() {
+	prometheus.MustRegister(connectedStreams, nacksTotal)
+}
@@ -0,0 +1,210 @@
+package xds
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	"github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+
+	"github.com/solo-io/gloo/pkg/log"
+)
+
+// Nack describes a configuration update that was rejected by an envoy node
+type Nack struct {
+	NodeID string
+	Role   string
+	// the resource type that was rejected
+	TypeUrl string
+	// the version envoy rejected
+	Version string
+	// the resource names envoy was subscribed to, if any (only set for EDS, RDS and SDS)
+	ResourceNames []string
+	// the error message reported by envoy
+	Message string
+}
+
+// NodeStatus describes an envoy node connected to the xDS server
+type NodeStatus struct {
+	NodeID string
+	Role   string
+	// the last version the node acknowledged, per resource type
+	AckedVersions map[string]string
+	// the outstanding rejections for the node, per resource type
+	Nacks map[string]Nack
+}
+
+type streamState struct {
+	node *core.Node
+	role string
+	// the version of the most recent response sent for each nonce
+	sentVersions map[string]string
+	// most recent nonce sent per type url; used to forget older nonces
+	lastNonces    map[string]string
+	ackedVersions map[string]string
+	nacks         map[string]Nack
+}
+
+// NodeTracker keeps track of the config versions acked and nacked by every connected envoy node.
+// it implements the go-control-plane server callbacks
+type NodeTracker struct {
+	roles   *RoleAssigner
+	lock    sync.RWMutex
+	streams map[int64]*streamState
+	updates chan struct{}
+}
+
+func NewNodeTracker(roles *RoleAssigner) *NodeTracker {
+	return &NodeTracker{
+		roles:   roles,
+		streams: make(map[int64]*streamState),
+		// buffered so that a signal is never lost while the consumer is busy
+		updates: make(chan struct{}, 1),
+	}
+}
+
+// Updates signals whenever the set of outstanding nacks has changed
+func (t *NodeTracker) Updates() <-chan struct{} {
+	return t.updates
+}
+
+func (t *NodeTracker) notify() {
+	select {
+	case t.updates <- struct{}{}:
+	default:
+		// a notification is already pending
+	}
+}
+
+// Nodes returns the status of every node currently connected, sorted by node id
+func (t *NodeTracker) Nodes() []NodeStatus {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	var nodes []NodeStatus
+	for _, st := range t.streams {
+		if st.node == nil {
+			continue
+		}
+		status := NodeStatus{
+			NodeID:        st.node.Id,
+			Role:          st.role,
+			AckedVersions: make(map[string]string),
+			Nacks:         make(map[string]Nack),
+		}
+		for typeUrl, version := range st.ackedVersions {
+			status.AckedVersions[typeUrl] = version
+		}
+		for typeUrl, nack := range st.nacks {
+			status.Nacks[typeUrl] = nack
+		}
+		nodes = append(nodes, status)
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].NodeID < nodes[j].NodeID
+	})
+	return nodes
+}
+
+// NodesForRole returns the status of every node connected with the given role
+func (t *NodeTracker) NodesForRole(role string) []NodeStatus {
+	var nodes []NodeStatus
+	for _, node := range t.Nodes() {
+		if node.Role == role {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+func (t *NodeTracker) OnStreamOpen(id int64, typeUrl string) {
+	connectedStreams.Inc()
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.streams[id] = &streamState{
+		sentVersions:  make(map[string]string),
+		lastNonces:    make(map[string]string),
+		ackedVersions: make(map[string]string),
+		nacks:         make(map[string]Nack),
+	}
+}
+
+func (t *NodeTracker) OnStreamClosed(id int64) {
+	connectedStreams.Dec()
+	t.lock.Lock()
+	st, ok := t.streams[id]
+	delete(t.streams, id)
+	t.lock.Unlock()
+	// the node's nacks are no longer relevant
+	if ok && len(st.nacks) > 0 {
+		t.notify()
+	}
+}
+
+func (t *NodeTracker) OnStreamRequest(id int64, req *v2.DiscoveryRequest) {
+	t.lock.Lock()
+	st, ok := t.streams[id]
+	if !ok {
+		t.lock.Unlock()
+		return
+	}
+	// envoy sends the node on the first request of a stream
+	if req.Node != nil {
+		st.node = req.Node
+		st.role = badNodeKey
+		if role, ok := t.roles.Role(req.Node); ok {
+			st.role = role
+		}
+	}
+	var changed bool
+	if req.ErrorDetail == nil {
+		// an empty version means this is the initial request, not an ack
+		if req.VersionInfo != "" {
+			st.ackedVersions[req.TypeUrl] = req.VersionInfo
+		}
+		if _, nacked := st.nacks[req.TypeUrl]; nacked && req.VersionInfo != "" {
+			delete(st.nacks, req.TypeUrl)
+			changed = true
+		}
+	} else {
+		var nodeID string
+		if st.node != nil {
+			nodeID = st.node.Id
+		}
+		nack := Nack{
+			NodeID:        nodeID,
+			Role:          st.role,
+			TypeUrl:       req.TypeUrl,
+			Version:       st.sentVersions[req.ResponseNonce],
+			ResourceNames: req.ResourceNames,
+			Message:       req.ErrorDetail.Message,
+		}
+		log.Warnf("envoy node %v (role %v) rejected %v version %v: %v", nack.NodeID, nack.Role, nack.TypeUrl,
+			nack.Version, nack.Message)
+		st.nacks[req.TypeUrl] = nack
+		nacksTotal.WithLabelValues(nack.Role, nack.TypeUrl).Inc()
+		changed = true
+	}
+	t.lock.Unlock()
+	if changed {
+		t.notify()
+	}
+}
+
+func (t *NodeTracker) OnStreamResponse(id int64, req *v2.DiscoveryRequest, resp *v2.DiscoveryResponse) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	st, ok := t.streams[id]
+	if !ok {
+		return
+	}
+	// only the latest response for each type can be acked or nacked
+	if lastNonce, ok := st.lastNonces[resp.TypeUrl]; ok {
+		delete(st.sentVersions, lastNonce)
+	}
+	st.lastNonces[resp.TypeUrl] = resp.Nonce
+	st.sentVersions[resp.Nonce] = resp.VersionInfo
+}
+
+func (t *NodeTracker) OnFetchRequest(req *v2.DiscoveryRequest) {}
+
+func (t *NodeTracker) OnFetchResponse(req *v2.DiscoveryRequest, resp *v2.DiscoveryResponse) {}
@@ -0,0 +1,87 @@
+package xds_test
+
+import (
+	"github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	"github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	envoycache "github.com/envoyproxy/go-control-plane/pkg/cache"
+	"github.com/gogo/googleapis/google/rpc"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo/internal/control-plane/bootstrap"
+	. "github.com/solo-io/gloo/internal/control-plane/xds"
+)
+
+var _ = Describe("NodeTracker", func() {
+	var (
+		tracker *NodeTracker
+		node    = &core.Node{Id: "myrole~node-1"}
+	)
+	BeforeEach(func() {
+		roles, err := NewRoleAssigner(bootstrap.XdsOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		tracker = NewNodeTracker(roles)
+		tracker.OnStreamOpen(1, "")
+		tracker.OnStreamRequest(1, &v2.DiscoveryRequest{Node: node, TypeUrl: envoycache.ClusterType})
+		tracker.OnStreamResponse(1, nil, &v2.DiscoveryResponse{
+			TypeUrl:     envoycache.ClusterType,
+			VersionInfo: "1",
+			Nonce:       "a",
+		})
+	})
+	It("records the acked version for each node", func() {
+		tracker.OnStreamRequest(1, &v2.DiscoveryRequest{
+			Node:          node,
+			TypeUrl:       envoycache.ClusterType,
+			VersionInfo:   "1",
+			ResponseNonce: "a",
+		})
+		nodes := tracker.Nodes()
+		Expect(nodes).To(HaveLen(1))
+		Expect(nodes[0].NodeID).To(Equal("myrole~node-1"))
+		Expect(nodes[0].Role).To(Equal("myrole"))
+		Expect(nodes[0].AckedVersions[envoycache.ClusterType]).To(Equal("1"))
+		Expect(nodes[0].Nacks).To(BeEmpty())
+	})
+	It("records the rejected version and error message for nacks", func() {
+		tracker.OnStreamRequest(1, &v2.DiscoveryRequest{
+			Node:          node,
+			TypeUrl:       envoycache.ClusterType,
+			ResponseNonce: "a",
+			ErrorDetail:   &rpc.Status{Message: "bad cluster"},
+		})
+		Eventually(tracker.Updates()).Should(Receive())
+		nodes := tracker.NodesForRole("myrole")
+		Expect(nodes).To(HaveLen(1))
+		nack := nodes[0].Nacks[envoycache.ClusterType]
+		Expect(nack.Version).To(Equal("1"))
+		Expect(nack.Message).To(Equal("bad cluster"))
+		Expect(nack.Role).To(Equal("myrole"))
+	})
+	It("clears the nack once a newer version is acked", func() {
+		tracker.OnStreamRequest(1, &v2.DiscoveryRequest{
+			Node:          node,
+			TypeUrl:       envoycache.ClusterType,
+			ResponseNonce: "a",
+			ErrorDetail:   &rpc.Status{Message: "bad cluster"},
+		})
+		tracker.OnStreamResponse(1, nil, &v2.DiscoveryResponse{
+			TypeUrl:     envoycache.ClusterType,
+			VersionInfo: "2",
+			Nonce:       "b",
+		})
+		tracker.OnStreamRequest(1, &v2.DiscoveryRequest{
+			Node:          node,
+			TypeUrl:       envoycache.ClusterType,
+			VersionInfo:   "2",
+			ResponseNonce: "b",
+		})
+		nodes := tracker.Nodes()
+		Expect(nodes[0].Nacks).To(BeEmpty())
+		Expect(nodes[0].AckedVersions[envoycache.ClusterType]).To(Equal("2"))
+	})
+	It("forgets nodes when their stream is closed", func() {
+		tracker.OnStreamClosed(1)
+		Expect(tracker.Nodes()).To(BeEmpty())
+	})
+})
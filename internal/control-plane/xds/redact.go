@@ -0,0 +1,174 @@
+package xds
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"strings"
+
+	envoyapi "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	envoyauth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
+	envoycore "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	envoycache "github.com/envoyproxy/go-control-plane/pkg/cache"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+)
+
+// plugins copy some secret values into filter metadata: the aws lambda plugin
+// sets access_key and secret_key, and the azure functions plugin adds the
+// function key to the function path as ?code=
+var (
+	credentialFields      = map[string]bool{"access_key": true, "secret_key": true}
+	credentialQueryParams = []string{"code"}
+)
+
+// RedactSnapshot returns a copy of the snapshot for people to read, with the
+// credentials in cluster and route metadata and the inline private keys of
+// tls certificates replaced by a short hash of their value
+func RedactSnapshot(snap *envoycache.Snapshot) *envoycache.Snapshot {
+	if snap == nil {
+		return nil
+	}
+	out := *snap
+	out.Clusters = redactResources(snap.Clusters)
+	out.Routes = redactResources(snap.Routes)
+	out.Listeners = redactResources(snap.Listeners)
+	out.Secrets = redactResources(snap.Secrets)
+	return &out
+}
+
+func redactResources(resources envoycache.Resources) envoycache.Resources {
+	out := envoycache.Resources{Version: resources.Version, Items: make(map[string]envoycache.Resource)}
+	for name, resource := range resources.Items {
+		switch res := resource.(type) {
+		case *envoyapi.Cluster:
+			res = proto.Clone(res).(*envoyapi.Cluster)
+			redactMetadata(res.Metadata)
+			if res.TlsContext != nil {
+				redactTlsContext(res.TlsContext.CommonTlsContext)
+			}
+			resource = res
+		case *envoyapi.RouteConfiguration:
+			res = proto.Clone(res).(*envoyapi.RouteConfiguration)
+			for i := range res.VirtualHosts {
+				for j := range res.VirtualHosts[i].Routes {
+					redactMetadata(res.VirtualHosts[i].Routes[j].Metadata)
+				}
+			}
+			resource = res
+		case *envoyapi.Listener:
+			res = proto.Clone(res).(*envoyapi.Listener)
+			for _, filterChain := range res.FilterChains {
+				if filterChain.TlsContext != nil {
+					redactTlsContext(filterChain.TlsContext.CommonTlsContext)
+				}
+			}
+			resource = res
+		case *envoyauth.Secret:
+			res = proto.Clone(res).(*envoyauth.Secret)
+			redactTlsCertificate(res.GetTlsCertificate())
+			resource = res
+		}
+		out.Items[name] = resource
+	}
+	return out
+}
+
+func redactTlsContext(tlsContext *envoyauth.CommonTlsContext) {
+	if tlsContext == nil {
+		return
+	}
+	for _, tlsCert := range tlsContext.TlsCertificates {
+		redactTlsCertificate(tlsCert)
+	}
+}
+
+func redactTlsCertificate(tlsCert *envoyauth.TlsCertificate) {
+	if tlsCert == nil {
+		return
+	}
+	redactDataSource(tlsCert.PrivateKey)
+}
+
+// files are referred to by their path, which is left as it is
+func redactDataSource(ds *envoycore.DataSource) {
+	if ds == nil {
+		return
+	}
+	switch specifier := ds.Specifier.(type) {
+	case *envoycore.DataSource_InlineString:
+		ds.Specifier = &envoycore.DataSource_InlineString{InlineString: redacted(specifier.InlineString)}
+	case *envoycore.DataSource_InlineBytes:
+		ds.Specifier = &envoycore.DataSource_InlineString{InlineString: redacted(string(specifier.InlineBytes))}
+	}
+}
+
+func redactMetadata(metadata *envoycore.Metadata) {
+	if metadata == nil {
+		return
+	}
+	for _, filterMetadata := range metadata.FilterMetadata {
+		redactStruct(filterMetadata)
+	}
+}
+
+func redactStruct(s *types.Struct) {
+	if s == nil {
+		return
+	}
+	for key, value := range s.Fields {
+		if str, ok := value.GetKind().(*types.Value_StringValue); ok && credentialFields[key] {
+			value.Kind = &types.Value_StringValue{StringValue: redacted(str.StringValue)}
+			continue
+		}
+		redactValue(value)
+	}
+}
+
+func redactValue(value *types.Value) {
+	switch kind := value.GetKind().(type) {
+	case *types.Value_StructValue:
+		redactStruct(kind.StructValue)
+	case *types.Value_ListValue:
+		if kind.ListValue != nil {
+			for _, v := range kind.ListValue.Values {
+				redactValue(v)
+			}
+		}
+	case *types.Value_StringValue:
+		kind.StringValue = redactQuery(kind.StringValue)
+	}
+}
+
+// redactQuery replaces the credentials in the query of a path or url
+func redactQuery(s string) string {
+	i := strings.Index(s, "?")
+	if i < 0 {
+		return s
+	}
+	query, err := url.ParseQuery(s[i+1:])
+	if err != nil {
+		return s
+	}
+	changed := false
+	for _, param := range credentialQueryParams {
+		values, ok := query[param]
+		if !ok {
+			continue
+		}
+		for j, v := range values {
+			values[j] = redacted(v)
+		}
+		changed = true
+	}
+	if !changed {
+		return s
+	}
+	return s[:i+1] + query.Encode()
+}
+
+// the hash shows whether a credential changed without revealing it
+func redacted(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return fmt.Sprintf("redacted-sha256-%x", sum[:8])
+}
@@ -0,0 +1,72 @@
+package xds
+
+import (
+	"encoding/json"
+	"sort"
+
+	envoycache "github.com/envoyproxy/go-control-plane/pkg/cache"
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/pkg/protoutil"
+)
+
+// RenderedSnapshot is an xds snapshot converted to json, for humans to read
+type RenderedSnapshot struct {
+	// the version of each resource type
+	Versions  map[string]string `json:"versions"`
+	Endpoints []json.RawMessage `json:"endpoints"`
+	Clusters  []json.RawMessage `json:"clusters"`
+	Routes    []json.RawMessage `json:"routes"`
+	Listeners []json.RawMessage `json:"listeners"`
+	Secrets   []json.RawMessage `json:"secrets"`
+}
+
+// RenderSnapshot converts every resource in the snapshot to json, sorted by name.
+// credentials and private keys are redacted first
+func RenderSnapshot(snap *envoycache.Snapshot) (RenderedSnapshot, error) {
+	snap = RedactSnapshot(snap)
+	out := RenderedSnapshot{
+		Versions: map[string]string{
+			envoycache.EndpointType: snap.Endpoints.Version,
+			envoycache.ClusterType:  snap.Clusters.Version,
+			envoycache.RouteType:    snap.Routes.Version,
+			envoycache.ListenerType: snap.Listeners.Version,
+			envoycache.SecretType:   snap.Secrets.Version,
+		},
+	}
+	for _, res := range []struct {
+		resources envoycache.Resources
+		out       *[]json.RawMessage
+	}{
+		{snap.Endpoints, &out.Endpoints},
+		{snap.Clusters, &out.Clusters},
+		{snap.Routes, &out.Routes},
+		{snap.Listeners, &out.Listeners},
+		{snap.Secrets, &out.Secrets},
+	} {
+		converted, err := renderResources(res.resources)
+		if err != nil {
+			return RenderedSnapshot{}, err
+		}
+		*res.out = converted
+	}
+	return out, nil
+}
+
+// protos must be marshalled with jsonpb to render oneofs and well known types correctly
+func renderResources(resources envoycache.Resources) ([]json.RawMessage, error) {
+	var names []string
+	for name := range resources.Items {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	out := []json.RawMessage{}
+	for _, name := range names {
+		data, err := protoutil.Marshal(resources.Items[name])
+		if err != nil {
+			return nil, errors.Wrapf(err, "converting resource %v", name)
+		}
+		out = append(out, json.RawMessage(data))
+	}
+	return out, nil
+}
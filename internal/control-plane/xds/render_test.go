@@ -0,0 +1,91 @@
+package xds_test
+
+import (
+	"encoding/json"
+
+	envoyapi "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	envoyauth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
+	envoycore "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	envoyroute "github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
+	envoycache "github.com/envoyproxy/go-control-plane/pkg/cache"
+	"github.com/gogo/protobuf/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/solo-io/gloo/internal/control-plane/xds"
+)
+
+var _ = Describe("RenderSnapshot", func() {
+	stringValue := func(s string) *types.Value {
+		return &types.Value{Kind: &types.Value_StringValue{StringValue: s}}
+	}
+	inline := func(s string) *envoycore.DataSource {
+		return &envoycore.DataSource{Specifier: &envoycore.DataSource_InlineString{InlineString: s}}
+	}
+
+	It("redacts plugin credentials and private keys", func() {
+		cluster := &envoyapi.Cluster{
+			Name: "lambda",
+			Metadata: &envoycore.Metadata{FilterMetadata: map[string]*types.Struct{
+				"io.solo.aws": {Fields: map[string]*types.Value{
+					"access_key": stringValue("AKIDEXAMPLE"),
+					"secret_key": stringValue("wJalrXUtnFEMI"),
+					"region":     stringValue("us-east-1"),
+				}},
+				"io.solo.function_router": {Fields: map[string]*types.Value{
+					"functions": {Kind: &types.Value_StructValue{StructValue: &types.Struct{Fields: map[string]*types.Value{
+						"foo": {Kind: &types.Value_StructValue{StructValue: &types.Struct{Fields: map[string]*types.Value{
+							"path": stringValue("/api/foo?code=azurefunctionkey"),
+						}}}},
+					}}}},
+				}},
+			}},
+			TlsContext: &envoyauth.UpstreamTlsContext{CommonTlsContext: &envoyauth.CommonTlsContext{
+				TlsCertificates: []*envoyauth.TlsCertificate{{PrivateKey: inline("upstream client key")}},
+			}},
+		}
+		routes := &envoyapi.RouteConfiguration{
+			Name: "routes",
+			VirtualHosts: []envoyroute.VirtualHost{{
+				Name: "default",
+				Routes: []envoyroute.Route{{
+					Metadata: &envoycore.Metadata{FilterMetadata: map[string]*types.Struct{
+						"io.solo.azure_functions": {Fields: map[string]*types.Value{
+							"path": stringValue("/api/bar?code=routefunctionkey&name=bar"),
+						}},
+					}},
+				}},
+			}},
+		}
+		secret := &envoyauth.Secret{
+			Name: "ssl-certificate/ssl",
+			Type: &envoyauth.Secret_TlsCertificate{TlsCertificate: &envoyauth.TlsCertificate{
+				CertificateChain: inline("certificate chain"),
+				PrivateKey:       inline("server private key"),
+			}},
+		}
+		snap := &envoycache.Snapshot{
+			Clusters: envoycache.NewResources("1", []envoycache.Resource{cluster}),
+			Routes:   envoycache.NewResources("1", []envoycache.Resource{routes}),
+			Secrets:  envoycache.NewResources("1", []envoycache.Resource{secret}),
+		}
+
+		rendered, err := RenderSnapshot(snap)
+		Expect(err).NotTo(HaveOccurred())
+		data, err := json.Marshal(rendered)
+		Expect(err).NotTo(HaveOccurred())
+		out := string(data)
+		for _, credential := range []string{"AKIDEXAMPLE", "wJalrXUtnFEMI", "azurefunctionkey", "routefunctionkey",
+			"upstream client key", "server private key"} {
+			Expect(out).NotTo(ContainSubstring(credential))
+		}
+		Expect(out).To(ContainSubstring("redacted-sha256-"))
+		Expect(out).To(ContainSubstring("us-east-1"))
+		Expect(out).To(ContainSubstring("certificate chain"))
+		Expect(out).To(ContainSubstring("name=bar"))
+
+		// the served snapshot is left as it is
+		Expect(cluster.Metadata.FilterMetadata["io.solo.aws"].Fields["secret_key"].GetStringValue()).To(Equal("wJalrXUtnFEMI"))
+		Expect(secret.GetTlsCertificate().PrivateKey.GetInlineString()).To(Equal("server private key"))
+	})
+})
@@ -0,0 +1,167 @@
+package xds
+
+import (
+	"strings"
+
+	"github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	"github.com/gogo/protobuf/types"
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/internal/control-plane/bootstrap"
+	"github.com/solo-io/gloo/pkg/log"
+)
+
+// used to let nodes know they have a bad config
+// we assign a "fix me" virtualhost for bad nodes
+const badNodeKey = "misconfigured-node"
+
+type roleSelector struct {
+	role   string
+	labels map[string]string
+}
+
+// RoleAssigner determines the role of each envoy node.
+// roles are chosen in the following order:
+// 1. a role configured for the node's id
+// 2. the role field in the node's metadata
+// 3. the first role selector matching the node's labels
+// 4. the node's cluster, if enabled
+// 5. the legacy <role>~<id> node id format
+// 6. the fallback role, if configured
+// nodes that match none of these receive the misconfigured-node snapshot
+type RoleAssigner struct {
+	nodeRoles       map[string]string
+	roleMetadataKey string
+	selectors       []roleSelector
+	roleFromCluster bool
+	fallbackRole    string
+}
+
+func NewRoleAssigner(opts bootstrap.XdsOptions) (*RoleAssigner, error) {
+	nodeRoles := make(map[string]string)
+	for _, nodeRole := range opts.NodeRoles {
+		parts := strings.SplitN(nodeRole, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.Errorf("invalid node role %q, must be in the format <node-id>=<role>", nodeRole)
+		}
+		nodeRoles[parts[0]] = parts[1]
+	}
+	var selectors []roleSelector
+	for _, sel := range opts.RoleSelectors {
+		selector, err := parseRoleSelector(sel)
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+	}
+	return &RoleAssigner{
+		nodeRoles:       nodeRoles,
+		roleMetadataKey: opts.RoleMetadataKey,
+		selectors:       selectors,
+		roleFromCluster: opts.RoleFromCluster,
+		fallbackRole:    opts.FallbackRole,
+	}, nil
+}
+
+func parseRoleSelector(sel string) (roleSelector, error) {
+	parts := strings.SplitN(sel, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return roleSelector{}, errors.Errorf("invalid role selector %q, must be in the format <role>:<key>=<value>,<key>=<value>", sel)
+	}
+	labels := make(map[string]string)
+	for _, label := range strings.Split(parts[1], ",") {
+		kv := strings.SplitN(label, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return roleSelector{}, errors.Errorf("invalid label %q in role selector %q, must be in the format <key>=<value>", label, sel)
+		}
+		labels[kv[0]] = kv[1]
+	}
+	return roleSelector{role: parts[0], labels: labels}, nil
+}
+
+// ID implements the go-control-plane NodeHash, which groups nodes by their role
+func (a *RoleAssigner) ID(node *core.Node) string {
+	role, ok := a.Role(node)
+	if !ok {
+		log.Warnf("node has registered with invalid config: %v\n "+
+			"assigning error response virtual host", node)
+		return badNodeKey
+	}
+	log.Printf("node %v registered with role %v", node.Id, role)
+	return role
+}
+
+// Role returns the role for the node, or false if no role could be determined
+func (a *RoleAssigner) Role(node *core.Node) (string, bool) {
+	if role, ok := a.nodeRoles[node.Id]; ok {
+		return role, true
+	}
+	if a.roleMetadataKey != "" {
+		if role, ok := metadataValue(node.Metadata, a.roleMetadataKey); ok && role != "" {
+			return role, true
+		}
+	}
+	labels := nodeLabels(node)
+	for _, selector := range a.selectors {
+		if selector.matches(labels) {
+			return selector.role, true
+		}
+	}
+	if a.roleFromCluster && node.Cluster != "" {
+		return node.Cluster, true
+	}
+	if parts := strings.SplitN(node.Id, "~", 2); len(parts) == 2 && parts[0] != "" {
+		return parts[0], true
+	}
+	if a.fallbackRole != "" {
+		return a.fallbackRole, true
+	}
+	return "", false
+}
+
+func (s roleSelector) matches(labels map[string]string) bool {
+	for key, value := range s.labels {
+		if nodeValue, ok := labels[key]; !ok || nodeValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// labels available to role selectors
+// metadata fields take precedence over the node's built-in fields
+func nodeLabels(node *core.Node) map[string]string {
+	labels := make(map[string]string)
+	if node.Cluster != "" {
+		labels["cluster"] = node.Cluster
+	}
+	if node.Locality != nil {
+		labels["region"] = node.Locality.Region
+		labels["zone"] = node.Locality.Zone
+		labels["sub_zone"] = node.Locality.SubZone
+	}
+	if node.Metadata != nil {
+		for key := range node.Metadata.Fields {
+			if value, ok := metadataValue(node.Metadata, key); ok {
+				labels[key] = value
+			}
+		}
+	}
+	return labels
+}
+
+// only string metadata values can be used for role selection
+func metadataValue(metadata *types.Struct, key string) (string, bool) {
+	if metadata == nil {
+		return "", false
+	}
+	value, ok := metadata.Fields[key]
+	if !ok || value == nil {
+		return "", false
+	}
+	str, ok := value.Kind.(*types.Value_StringValue)
+	if !ok {
+		return "", false
+	}
+	return str.StringValue, true
+}
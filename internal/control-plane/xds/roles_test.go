@@ -0,0 +1,76 @@
+package xds_test
+
+import (
+	"github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	"github.com/gogo/protobuf/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo/internal/control-plane/bootstrap"
+	. "github.com/solo-io/gloo/internal/control-plane/xds"
+)
+
+var _ = Describe("RoleAssigner", func() {
+	metadata := func(kv map[string]string) *types.Struct {
+		s := &types.Struct{Fields: make(map[string]*types.Value)}
+		for k, v := range kv {
+			s.Fields[k] = &types.Value{Kind: &types.Value_StringValue{StringValue: v}}
+		}
+		return s
+	}
+	opts := bootstrap.XdsOptions{
+		NodeRoles:       []string{"special-node=special"},
+		RoleMetadataKey: "role",
+		RoleSelectors: []string{
+			"east-edge:zone=us-east,tier=edge",
+			"edge:tier=edge",
+		},
+		FallbackRole: "default",
+	}
+	var roles *RoleAssigner
+	BeforeEach(func() {
+		var err error
+		roles, err = NewRoleAssigner(opts)
+		Expect(err).NotTo(HaveOccurred())
+	})
+	It("uses the per-node override first", func() {
+		Expect(roles.ID(&core.Node{Id: "special-node", Metadata: metadata(map[string]string{"role": "other"})})).To(Equal("special"))
+	})
+	It("uses the role from the node metadata", func() {
+		Expect(roles.ID(&core.Node{Id: "sidecar~1.2.3.4", Metadata: metadata(map[string]string{"role": "meta"})})).To(Equal("meta"))
+	})
+	It("uses the first matching role selector", func() {
+		node := &core.Node{
+			Id:       "fixed-id",
+			Locality: &core.Locality{Zone: "us-east"},
+			Metadata: metadata(map[string]string{"tier": "edge"}),
+		}
+		Expect(roles.ID(node)).To(Equal("east-edge"))
+		node.Locality.Zone = "us-west"
+		Expect(roles.ID(node)).To(Equal("edge"))
+	})
+	It("supports the legacy role~id format", func() {
+		Expect(roles.ID(&core.Node{Id: "legacy~12345"})).To(Equal("legacy"))
+	})
+	It("uses the fallback role when nothing else matches", func() {
+		Expect(roles.ID(&core.Node{Id: "fixed-id"})).To(Equal("default"))
+	})
+	It("uses the node cluster when enabled", func() {
+		roles, err := NewRoleAssigner(bootstrap.XdsOptions{RoleFromCluster: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(roles.ID(&core.Node{Id: "fixed-id", Cluster: "mycluster"})).To(Equal("mycluster"))
+	})
+	It("assigns the misconfigured node role without a fallback", func() {
+		roles, err := NewRoleAssigner(bootstrap.XdsOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(roles.ID(&core.Node{Id: "badid"})).To(Equal("misconfigured-node"))
+	})
+	It("rejects invalid selectors", func() {
+		_, err := NewRoleAssigner(bootstrap.XdsOptions{RoleSelectors: []string{"edge"}})
+		Expect(err).To(HaveOccurred())
+		_, err = NewRoleAssigner(bootstrap.XdsOptions{RoleSelectors: []string{"edge:tier"}})
+		Expect(err).To(HaveOccurred())
+		_, err = NewRoleAssigner(bootstrap.XdsOptions{NodeRoles: []string{"node"}})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,327 @@
+package xds
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"strings"
+	"sync"
+
+	"github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	"github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/solo-io/gloo/internal/control-plane/bootstrap"
+	"github.com/solo-io/gloo/pkg/log"
+	"github.com/solo-io/gloo/pkg/secretwatcher"
+	"github.com/solo-io/gloo/pkg/storage/dependencies"
+)
+
+const (
+	tlsRootCaKey = "root_ca"
+
+	allRoles = "*"
+
+	authorizationHeader = "authorization"
+	bearerPrefix        = "Bearer "
+)
+
+// Security provides TLS and per-role authorization for the xDS server.
+// certificates, authorized identities and tokens are read from gloo secret storage,
+// and are reloaded whenever the secrets change
+type Security struct {
+	opts  bootstrap.XdsOptions
+	roles *RoleAssigner
+
+	lock      sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	// identity -> roles the identity may subscribe to
+	identityRoles map[string][]string
+	// token -> identity
+	tokens map[string]string
+}
+
+func NewSecurity(opts bootstrap.XdsOptions, roles *RoleAssigner) *Security {
+	return &Security{
+		opts:  opts,
+		roles: roles,
+	}
+}
+
+// TLSEnabled is true if the xDS server should serve TLS
+func (s *Security) TLSEnabled() bool {
+	return s.opts.TLSSecretRef != ""
+}
+
+// AuthorizationEnabled is true if nodes are restricted to the roles their identity allows
+func (s *Security) AuthorizationEnabled() bool {
+	return s.opts.AuthSecretRef != ""
+}
+
+// SecretRefs are the secrets required by the xDS server
+func (s *Security) SecretRefs() []string {
+	var refs []string
+	for _, ref := range []string{s.opts.TLSSecretRef, s.opts.AuthSecretRef, s.opts.TokenSecretRef} {
+		if ref != "" {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// Load reads the secrets from storage. it should be called before the xDS server starts,
+// so that misconfiguration is reported immediately
+func (s *Security) Load(secretStorage dependencies.SecretStorage) error {
+	secrets := make(secretwatcher.SecretMap)
+	for _, ref := range s.SecretRefs() {
+		secret, err := secretStorage.Get(ref)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read xds secret %v", ref)
+		}
+		secrets[ref] = secret
+	}
+	return s.Update(secrets)
+}
+
+// Run reloads the secrets whenever they change
+func (s *Security) Run(watcher secretwatcher.Interface, stop <-chan struct{}) {
+	go watcher.Run(stop)
+	go watcher.TrackSecrets(s.SecretRefs())
+	for {
+		select {
+		case <-stop:
+			return
+		case secrets := <-watcher.Secrets():
+			if err := s.Update(secrets); err != nil {
+				log.Warnf("failed to reload xds secrets, continuing with previous certificates: %v", err)
+				continue
+			}
+			log.Printf("reloaded xds secrets")
+		case err := <-watcher.Error():
+			log.Warnf("error watching xds secrets: %v", err)
+		}
+	}
+}
+
+// Update replaces the current certificates and authorization rules.
+// nothing is replaced if any of the secrets is invalid
+func (s *Security) Update(secrets secretwatcher.SecretMap) error {
+	var (
+		cert          *tls.Certificate
+		clientCAs     *x509.CertPool
+		identityRoles map[string][]string
+		tokens        map[string]string
+	)
+	if s.TLSEnabled() {
+		secret, ok := secrets[s.opts.TLSSecretRef]
+		if !ok {
+			return errors.Errorf("xds tls secret %v not found", s.opts.TLSSecretRef)
+		}
+		certChain, privateKey, _, err := secretwatcher.TlsCertificate(secret)
+		if err != nil {
+			return errors.Wrap(err, "invalid xds tls secret")
+		}
+		keyPair, err := tls.X509KeyPair([]byte(certChain), []byte(privateKey))
+		if err != nil {
+			return errors.Wrap(err, "invalid xds tls certificate")
+		}
+		cert = &keyPair
+		if rootCa, ok := secret.Data[tlsRootCaKey]; ok {
+			clientCAs = x509.NewCertPool()
+			if !clientCAs.AppendCertsFromPEM([]byte(rootCa)) {
+				return errors.Errorf("no valid certificates found in %v of xds tls secret", tlsRootCaKey)
+			}
+		}
+		if s.opts.RequireClientCert && clientCAs == nil {
+			return errors.Errorf("client certificates are required, but %v is missing from xds tls secret", tlsRootCaKey)
+		}
+	}
+	if s.AuthorizationEnabled() {
+		secret, ok := secrets[s.opts.AuthSecretRef]
+		if !ok {
+			return errors.Errorf("xds auth secret %v not found", s.opts.AuthSecretRef)
+		}
+		identityRoles = make(map[string][]string)
+		for identity, roles := range secret.Data {
+			for _, role := range strings.Split(roles, ",") {
+				if role = strings.TrimSpace(role); role != "" {
+					identityRoles[identity] = append(identityRoles[identity], role)
+				}
+			}
+		}
+	}
+	if s.opts.TokenSecretRef != "" {
+		secret, ok := secrets[s.opts.TokenSecretRef]
+		if !ok {
+			return errors.Errorf("xds token secret %v not found", s.opts.TokenSecretRef)
+		}
+		tokens = make(map[string]string)
+		for identity, token := range secret.Data {
+			tokens[token] = identity
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.cert = cert
+	s.clientCAs = clientCAs
+	s.identityRoles = identityRoles
+	s.tokens = tokens
+	return nil
+}
+
+// TLSConfig returns a tls config which always uses the most recently loaded certificates
+func (s *Security) TLSConfig() *tls.Config {
+	return &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			s.lock.RLock()
+			defer s.lock.RUnlock()
+			if s.cert == nil {
+				return nil, errors.New("xds tls certificate has not been loaded")
+			}
+			cfg := &tls.Config{
+				Certificates: []tls.Certificate{*s.cert},
+				ClientCAs:    s.clientCAs,
+				// required for grpc
+				NextProtos: []string{"h2"},
+			}
+			switch {
+			case s.opts.RequireClientCert:
+				cfg.ClientAuth = tls.RequireAndVerifyClientCert
+			case s.clientCAs != nil:
+				cfg.ClientAuth = tls.VerifyClientCertIfGiven
+			}
+			return cfg, nil
+		},
+	}
+}
+
+// ServerOptions returns the grpc server options for TLS, if enabled
+func (s *Security) ServerOptions() []grpc.ServerOption {
+	if !s.TLSEnabled() {
+		return nil
+	}
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(s.TLSConfig()))}
+}
+
+// StreamInterceptor rejects discovery requests for roles the client is not authorized for
+func (s *Security) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !s.AuthorizationEnabled() {
+			return handler(srv, ss)
+		}
+		return handler(srv, &authorizedStream{ServerStream: ss, security: s})
+	}
+}
+
+// UnaryInterceptor rejects fetches of config for roles the client is not authorized for
+func (s *Security) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if discoveryReq, ok := req.(*v2.DiscoveryRequest); ok {
+			// fetches are not part of a stream that could provide the node, and can not be answered without one
+			if discoveryReq.Node == nil {
+				return nil, status.Error(codes.InvalidArgument, "discovery request has no node")
+			}
+			if err := s.Authorize(ctx, discoveryReq.Node); err != nil {
+				log.Warnf("rejected xds fetch from node %v: %v", discoveryReq.Node.Id, err)
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Authorize returns an error if the client is not allowed to subscribe to the role of the node
+func (s *Security) Authorize(ctx context.Context, node *core.Node) error {
+	if !s.AuthorizationEnabled() {
+		return nil
+	}
+	role, ok := s.roles.Role(node)
+	// misconfigured nodes only receive the error response config
+	if !ok {
+		return nil
+	}
+	identities := s.identities(ctx)
+	if len(identities) == 0 {
+		return status.Errorf(codes.Unauthenticated, "no client certificate or token provided for node %v", node.Id)
+	}
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	for _, identity := range identities {
+		for _, allowed := range s.identityRoles[identity] {
+			if allowed == role || allowed == allRoles {
+				return nil
+			}
+		}
+	}
+	return status.Errorf(codes.PermissionDenied, "%v may not subscribe to role %v", identities, role)
+}
+
+// the identities of the client: the common name and SANs of its verified certificate,
+// and the name of its bearer token
+func (s *Security) identities(ctx context.Context) []string {
+	var identities []string
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.VerifiedChains) > 0 {
+			cert := tlsInfo.State.VerifiedChains[0][0]
+			if cert.Subject.CommonName != "" {
+				identities = append(identities, cert.Subject.CommonName)
+			}
+			identities = append(identities, cert.DNSNames...)
+			for _, uri := range cert.URIs {
+				identities = append(identities, uri.String())
+			}
+		}
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, header := range md[authorizationHeader] {
+			if !strings.HasPrefix(header, bearerPrefix) {
+				continue
+			}
+			s.lock.RLock()
+			identity, ok := s.tokens[strings.TrimPrefix(header, bearerPrefix)]
+			s.lock.RUnlock()
+			if ok {
+				identities = append(identities, identity)
+			}
+		}
+	}
+	return identities
+}
+
+type authorizedStream struct {
+	grpc.ServerStream
+	security *Security
+	// envoy only sends its node on the first request of a stream. requests without
+	// one are answered for the last node sent on the stream, or for an empty node
+	node *core.Node
+}
+
+func (a *authorizedStream) RecvMsg(m interface{}) error {
+	if err := a.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	req, ok := m.(*v2.DiscoveryRequest)
+	if !ok {
+		return nil
+	}
+	if req.Node != nil {
+		a.node = req.Node
+	}
+	node := a.node
+	if node == nil {
+		node = &core.Node{}
+	}
+	if err := a.security.Authorize(a.Context(), node); err != nil {
+		log.Warnf("rejected xds request from node %v: %v", node.Id, err)
+		return err
+	}
+	return nil
+}
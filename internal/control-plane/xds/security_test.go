@@ -0,0 +1,219 @@
+package xds_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"time"
+
+	"github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	"github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/solo-io/gloo/internal/control-plane/bootstrap"
+	. "github.com/solo-io/gloo/internal/control-plane/xds"
+	"github.com/solo-io/gloo/pkg/secretwatcher"
+	"github.com/solo-io/gloo/pkg/storage/dependencies"
+	. "github.com/solo-io/gloo/test/helpers"
+)
+
+var _ = Describe("Security", func() {
+	var (
+		ca, serverCert, clientCert *TestCert
+		opts                       bootstrap.XdsOptions
+		security                   *Security
+		secrets                    secretwatcher.SecretMap
+		edgeNode                   = &core.Node{Id: "edge~1"}
+		internalNode               = &core.Node{Id: "internal~1"}
+	)
+	BeforeEach(func() {
+		var err error
+		ca, err = NewTestCert("root", nil, time.Now().Add(time.Hour), nil)
+		Expect(err).NotTo(HaveOccurred())
+		serverCert, err = NewTestCert("gloo", []string{"gloo.example.com"}, time.Now().Add(time.Hour), ca)
+		Expect(err).NotTo(HaveOccurred())
+		clientCert, err = NewTestCert("edge-envoy", nil, time.Now().Add(time.Hour), ca)
+		Expect(err).NotTo(HaveOccurred())
+		opts = bootstrap.XdsOptions{
+			TLSSecretRef:      "xds-tls",
+			RequireClientCert: true,
+			AuthSecretRef:     "xds-auth",
+			TokenSecretRef:    "xds-tokens",
+		}
+		secrets = secretwatcher.SecretMap{
+			"xds-tls": {Ref: "xds-tls", Data: map[string]string{
+				"ca_chain":    serverCert.CertPEM,
+				"private_key": serverCert.KeyPEM,
+				"root_ca":     ca.CertPEM,
+			}},
+			"xds-auth": {Ref: "xds-auth", Data: map[string]string{
+				"edge-envoy": "edge",
+				"admin":      "*",
+			}},
+			"xds-tokens": {Ref: "xds-tokens", Data: map[string]string{
+				"admin": "secret-token",
+			}},
+		}
+		roles, err := NewRoleAssigner(bootstrap.XdsOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		security = NewSecurity(opts, roles)
+	})
+	certContext := func(cert *x509.Certificate) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{
+			AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{{cert}},
+			}},
+		})
+	}
+	tokenContext := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	}
+	Context("loading secrets", func() {
+		It("loads valid secrets", func() {
+			Expect(security.Update(secrets)).NotTo(HaveOccurred())
+			Expect(security.SecretRefs()).To(ConsistOf("xds-tls", "xds-auth", "xds-tokens"))
+		})
+		It("loads secrets from storage", func() {
+			Expect(security.Load(&fakeSecretStorage{secrets: secrets})).NotTo(HaveOccurred())
+		})
+		It("rejects an invalid certificate", func() {
+			secrets["xds-tls"].Data["private_key"] = "not a key"
+			Expect(security.Update(secrets)).To(HaveOccurred())
+		})
+		It("requires a root ca when client certificates are required", func() {
+			delete(secrets["xds-tls"].Data, "root_ca")
+			Expect(security.Update(secrets)).To(HaveOccurred())
+		})
+		It("serves the most recently loaded certificate", func() {
+			Expect(security.Update(secrets)).NotTo(HaveOccurred())
+			cfg, err := security.TLSConfig().GetConfigForClient(nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.ClientAuth).To(Equal(tls.RequireAndVerifyClientCert))
+			Expect(cfg.Certificates).To(HaveLen(1))
+
+			rotated, err := NewTestCert("gloo", nil, time.Now().Add(2*time.Hour), ca)
+			Expect(err).NotTo(HaveOccurred())
+			secrets["xds-tls"].Data["ca_chain"] = rotated.CertPEM
+			secrets["xds-tls"].Data["private_key"] = rotated.KeyPEM
+			Expect(security.Update(secrets)).NotTo(HaveOccurred())
+			cfg, err = security.TLSConfig().GetConfigForClient(nil)
+			Expect(err).NotTo(HaveOccurred())
+			leaf, err := x509.ParseCertificate(cfg.Certificates[0].Certificate[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(leaf.NotAfter).To(Equal(rotated.Cert.NotAfter))
+		})
+	})
+	Context("authorization", func() {
+		BeforeEach(func() {
+			Expect(security.Update(secrets)).NotTo(HaveOccurred())
+		})
+		It("allows a client certificate to subscribe to its roles", func() {
+			Expect(security.Authorize(certContext(clientCert.Cert), edgeNode)).NotTo(HaveOccurred())
+		})
+		It("denies a client certificate other roles", func() {
+			err := security.Authorize(certContext(clientCert.Cert), internalNode)
+			Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+		})
+		It("allows tokens with the wildcard role to subscribe to any role", func() {
+			Expect(security.Authorize(tokenContext("secret-token"), internalNode)).NotTo(HaveOccurred())
+		})
+		It("rejects unknown tokens", func() {
+			err := security.Authorize(tokenContext("wrong-token"), internalNode)
+			Expect(status.Code(err)).To(Equal(codes.Unauthenticated))
+		})
+		It("rejects unary fetches for roles the client is not authorized for", func() {
+			fetched := false
+			fetch := func(ctx context.Context, req interface{}) (interface{}, error) {
+				fetched = true
+				return &v2.DiscoveryResponse{}, nil
+			}
+			info := &grpc.UnaryServerInfo{FullMethod: "/envoy.service.discovery.v2.SecretDiscoveryService/FetchSecrets"}
+			_, err := security.UnaryInterceptor()(certContext(clientCert.Cert), &v2.DiscoveryRequest{Node: internalNode}, info, fetch)
+			Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+			Expect(fetched).To(BeFalse())
+
+			_, err = security.UnaryInterceptor()(certContext(clientCert.Cert), &v2.DiscoveryRequest{Node: edgeNode}, info, fetch)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fetched).To(BeTrue())
+		})
+		Context("requests without a node", func() {
+			BeforeEach(func() {
+				roles, err := NewRoleAssigner(bootstrap.XdsOptions{FallbackRole: "internal"})
+				Expect(err).NotTo(HaveOccurred())
+				security = NewSecurity(opts, roles)
+				Expect(security.Update(secrets)).NotTo(HaveOccurred())
+			})
+			It("rejects unary fetches", func() {
+				fetched := false
+				fetch := func(ctx context.Context, req interface{}) (interface{}, error) {
+					fetched = true
+					return &v2.DiscoveryResponse{}, nil
+				}
+				info := &grpc.UnaryServerInfo{FullMethod: "/envoy.service.discovery.v2.SecretDiscoveryService/FetchSecrets"}
+				_, err := security.UnaryInterceptor()(tokenContext("secret-token"), &v2.DiscoveryRequest{}, info, fetch)
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				Expect(fetched).To(BeFalse())
+			})
+			It("authorizes stream requests for the node sent earlier on the stream, or for an empty node", func() {
+				recv := func(ctx context.Context, requests ...*v2.DiscoveryRequest) error {
+					stream := &fakeServerStream{ctx: ctx, requests: requests}
+					return security.StreamInterceptor()(nil, stream, &grpc.StreamServerInfo{}, func(srv interface{}, ss grpc.ServerStream) error {
+						for range requests {
+							if err := ss.RecvMsg(&v2.DiscoveryRequest{}); err != nil {
+								return err
+							}
+						}
+						return nil
+					})
+				}
+				// an empty node is assigned the fallback role, which the edge client may not subscribe to
+				err := recv(certContext(clientCert.Cert), &v2.DiscoveryRequest{})
+				Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+
+				err = recv(certContext(clientCert.Cert), &v2.DiscoveryRequest{Node: edgeNode}, &v2.DiscoveryRequest{})
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+})
+
+// fakeServerStream receives the given requests in order
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	requests []*v2.DiscoveryRequest
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeServerStream) RecvMsg(m interface{}) error {
+	if len(s.requests) == 0 {
+		return io.EOF
+	}
+	*m.(*v2.DiscoveryRequest) = *s.requests[0]
+	s.requests = s.requests[1:]
+	return nil
+}
+
+type fakeSecretStorage struct {
+	dependencies.SecretStorage
+	secrets secretwatcher.SecretMap
+}
+
+func (s *fakeSecretStorage) Get(name string) (*dependencies.Secret, error) {
+	secret, ok := s.secrets[name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "%v not found", name)
+	}
+	return secret, nil
+}
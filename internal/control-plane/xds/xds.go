@@ -5,7 +5,6 @@ import (
 	"net"
 
 	"github.com/envoyproxy/go-control-plane/envoy/api/v2"
-	"github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
 	envoyv2 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v2"
 	envoycache "github.com/envoyproxy/go-control-plane/pkg/cache"
 	xds "github.com/envoyproxy/go-control-plane/pkg/server"
@@ -15,26 +14,8 @@ import (
 	"github.com/solo-io/gloo/pkg/log"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
-	"strings"
 )
 
-// used to let nodes know they have a bad config
-// we assign a "fix me" virtualhost for bad nodes
-const badNodeKey = "misconfigured-node"
-
-type hasher struct{}
-
-func (h hasher) ID(node *core.Node) string {
-	parts := strings.SplitN(node.Id, "~", 2)
-	if len(parts) != 2 {
-		log.Warnf("node has registered with invalid config: %v\n " +
-			"assigning error response virtual host", node)
-		return badNodeKey
-	}
-	log.Printf("node %v registered with role %v", parts[1], parts[0])
-	return parts[0]
-}
-
 type logger struct{}
 
 func (*logger) Infof(format string, args ...interface{}) {
@@ -44,13 +25,17 @@ func (*logger) Errorf(format string, args ...interface{}) {
 	log.Warnf(format, args...)
 }
 
-func RunXDS(port int, badNodeSnapshot envoycache.Snapshot) (envoycache.SnapshotCache, *grpc.Server, error) {
+// RunXDS starts the xDS server. nodes are served the snapshot of the role chosen by the role assigner.
+// the node tracker is notified of every request and response sent to envoy, which allows
+// observing the versions acked and nacked by each node. security provides TLS and restricts which
+// roles each client may subscribe to
+func RunXDS(port int, badNodeSnapshot envoycache.Snapshot, roles *RoleAssigner, tracker *NodeTracker, security *Security) (envoycache.SnapshotCache, *grpc.Server, error) {
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to listen: %v", err)
 	}
-	envoyCache := envoycache.NewSnapshotCache(true, hasher{}, &logger{})
-	grpcServer := grpc.NewServer(grpc.StreamInterceptor(
+	envoyCache := envoycache.NewSnapshotCache(true, roles, &logger{})
+	serverOpts := append(security.ServerOptions(), grpc.StreamInterceptor(
 		grpc_middleware.ChainStreamServer(
 			grpc_ctxtags.StreamServerInterceptor(),
 			grpc_zap.StreamServerInterceptor(zap.NewNop()),
@@ -58,14 +43,23 @@ func RunXDS(port int, badNodeSnapshot envoycache.Snapshot) (envoycache.SnapshotC
 				log.Debugf("xDS method called: %v", info.FullMethod)
 				return handler(srv, ss)
 			},
+			security.StreamInterceptor(),
+		)),
+		// the Fetch* rpcs are unary, and must be authorized like streams
+		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
+			grpc_ctxtags.UnaryServerInterceptor(),
+			grpc_zap.UnaryServerInterceptor(zap.NewNop()),
+			security.UnaryInterceptor(),
 		)),
 	)
-	xdsServer := xds.NewServer(envoyCache, nil)
+	grpcServer := grpc.NewServer(serverOpts...)
+	xdsServer := xds.NewServer(envoyCache, tracker)
 	envoyv2.RegisterAggregatedDiscoveryServiceServer(grpcServer, xdsServer)
 	v2.RegisterEndpointDiscoveryServiceServer(grpcServer, xdsServer)
 	v2.RegisterClusterDiscoveryServiceServer(grpcServer, xdsServer)
 	v2.RegisterRouteDiscoveryServiceServer(grpcServer, xdsServer)
 	v2.RegisterListenerDiscoveryServiceServer(grpcServer, xdsServer)
+	envoyv2.RegisterSecretDiscoveryServiceServer(grpcServer, xdsServer)
 
 	// set bad node virtualhost
 	envoyCache.SetSnapshot(badNodeKey, badNodeSnapshot)
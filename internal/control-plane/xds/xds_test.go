@@ -13,6 +13,7 @@ import (
 
 	"google.golang.org/grpc"
 	"time"
+	"github.com/solo-io/gloo/internal/control-plane/bootstrap"
 	. "github.com/solo-io/gloo/internal/control-plane/xds"
 	"net/http"
 	"io/ioutil"
@@ -26,6 +27,7 @@ var _ = Describe("Xds", func() {
 		listenerName    = "xds-test-listener"
 		nodeGroup       = "valid-group"
 		badNodeSnapshot = BadNodeSnapshot("0.0.0.0", 1234)
+		roles, _        = NewRoleAssigner(bootstrap.XdsOptions{})
 	)
 	var _ = BeforeEach(func() {
 		var err error
@@ -44,7 +46,7 @@ var _ = Describe("Xds", func() {
 			BeforeEach(func() {
 				err := envoyInstance.RunWithId("badid")
 				Expect(err).NotTo(HaveOccurred())
-				_, grpcSrv, err := RunXDS(8081, badNodeSnapshot)
+				_, grpcSrv, err := RunXDS(8081, badNodeSnapshot, roles, NewNodeTracker(roles), NewSecurity(bootstrap.XdsOptions{}, roles))
 				Expect(err).NotTo(HaveOccurred())
 				srv = grpcSrv
 			})
@@ -72,7 +74,7 @@ var _ = Describe("Xds", func() {
 			BeforeEach(func() {
 				err := envoyInstance.RunWithId(nodeGroup + "~12345")
 				Expect(err).NotTo(HaveOccurred())
-				cache, grpcSrv, err := RunXDS(8081, badNodeSnapshot)
+				cache, grpcSrv, err := RunXDS(8081, badNodeSnapshot, roles, NewNodeTracker(roles), NewSecurity(bootstrap.XdsOptions{}, roles))
 				Expect(err).NotTo(HaveOccurred())
 				srv = grpcSrv
 				snapshot, err := createSnapshot(routeConfigName, listenerName)
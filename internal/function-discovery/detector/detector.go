@@ -83,7 +83,9 @@ func (m *Marker) DetectFunctionalUpstream(us *v1.Upstream) (*v1.ServiceInfo, map
 	// try every possible detector concurrently
 	for _, d := range m.detectors {
 		go func(d Interface) {
+			name := detectorName(d)
 			err := backoff.WithBackoff(func() error {
+				detectionAttempts.WithLabelValues(name).Inc()
 				serviceInfo, annotations, err := d.DetectFunctionalService(us, addr)
 				if err != nil {
 					return err
@@ -98,6 +100,7 @@ func (m *Marker) DetectFunctionalUpstream(us *v1.Upstream) (*v1.ServiceInfo, map
 				return nil
 			}, stop)
 			if err != nil {
+				detectionFailures.WithLabelValues(name).Inc()
 				failed <- err
 			}
 		}(d)
@@ -0,0 +1,36 @@
+package detector
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/solo-io/gloo/pkg/metrics"
+)
+
+const subsystem = "function_discovery"
+
+var (
+	detectionAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystem,
+		Name:      "detection_attempts_total",
+		Help:      "number of attempts to detect the service type of an upstream, by detector",
+	}, []string{"detector"})
+	detectionFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystem,
+		Name:      "detection_failures_total",
+		Help:      "number of upstreams a detector failed to detect after retrying, by detector",
+	}, []string{"detector"})
+)
+
+func init() {
+	prometheus.MustRegister(detectionAttempts, detectionFailures)
+}
+
+// detectors are named after their type, e.g. swagger.swaggerDetector
+func detectorName(d Interface) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", d), "*")
+}
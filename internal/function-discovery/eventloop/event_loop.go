@@ -33,6 +33,8 @@ const (
 	maxThreadsPerUpstream = 25
 )
 
+var logger = log.With(log.Component("function-discovery"))
+
 type workItem struct {
 	upstream *v1.Upstream
 	secrets  secretwatcher.SecretMap
@@ -101,7 +103,7 @@ func Run(opts bootstrap.Options, discoveryOpts options.DiscoveryOptions, stop <-
 	workQueues := make(map[string]chan *workItem)
 
 	updateUpstream := func(us *v1.Upstream, secrets secretwatcher.SecretMap) {
-		log.Debugf("attempting update for %v", us.Name)
+		logger.With(log.Resource("upstream/"+us.Name)).Debugf("attempting update for %v", us.Name)
 		if err := updater.UpdateServiceInfo(store, us.Name, marker); err != nil {
 			errs <- errors.Wrapf(err, "updating upstream %v", us.Name)
 		}
@@ -115,7 +117,7 @@ func Run(opts bootstrap.Options, discoveryOpts options.DiscoveryOptions, stop <-
 		for _, us := range cache.upstreams {
 			usNames = append(usNames, us.Name)
 		}
-		log.Debugf("beginning update for %v upstreams: %v", usNames, len(cache.upstreams))
+		logger.Debugf("beginning update for %v upstreams: %v", usNames, len(cache.upstreams))
 
 		// clean queues for upstreams that have been deleted
 		for usName := range workQueues {
@@ -129,6 +131,7 @@ func Run(opts bootstrap.Options, discoveryOpts options.DiscoveryOptions, stop <-
 			if !upstreamFound {
 				close(workQueues[usName])
 				delete(workQueues, usName)
+				updater.ForgetUpstream(usName)
 			}
 		}
 
@@ -146,13 +149,13 @@ func Run(opts bootstrap.Options, discoveryOpts options.DiscoveryOptions, stop <-
 				workQueues[us.Name] = make(chan *workItem, maxThreadsPerUpstream)
 				// start worker thread for this upstream
 				go func(workQueues map[string]chan *workItem, usName string) {
-					log.Debugf("starting goroutine for %s", usName)
+					logger.Debugf("starting goroutine for %s", usName)
 					// allow upstream time to start up
 					time.Sleep(time.Second * 2)
 					for work := range workQueues[usName] {
 						updateUpstream(work.upstream, work.secrets)
 					}
-					log.Debugf("exiting goroutine for %s", usName)
+					logger.Debugf("exiting goroutine for %s", usName)
 				}(workQueues, us.Name)
 			}
 			workQueues[us.Name] <- &workItem{upstream: us, secrets: cache.secrets}
@@ -190,7 +193,7 @@ func createResolver(opts bootstrap.Options) resolver.Resolver {
 		return kube, nil
 	}()
 	if err != nil {
-		log.Warnf("create kube client failed: %v. functonal services running in kubernetes will not be discovered " +
+		logger.Warnf("create kube client failed: %v. functonal services running in kubernetes will not be discovered " +
 			"by function discovery")
 	}
 	consul, err := func() (*api.Client, error) {
@@ -198,7 +201,7 @@ func createResolver(opts bootstrap.Options) resolver.Resolver {
 		return api.NewClient(cfg)
 	}()
 	if err != nil {
-		log.Warnf("create consul client failed: %v. functional services running in consul will " +
+		logger.Warnf("create consul client failed: %v. functional services running in consul will " +
 			"not be discovered by function discovery")
 	}
 	return resolver.NewResolver(kube, consul)
@@ -0,0 +1,52 @@
+package updater
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/solo-io/gloo/internal/function-discovery/functiontypes"
+	"github.com/solo-io/gloo/pkg/metrics"
+)
+
+const subsystem = "function_discovery"
+
+var (
+	updateFunctionsDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystem,
+		Name:      "update_functions_duration_seconds",
+		Help:      "time taken to discover the functions of an upstream and write them to storage, by function type",
+	}, []string{"function_type"})
+	updateFunctionsFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystem,
+		Name:      "update_functions_failures_total",
+		Help:      "number of failed attempts to discover the functions of an upstream, by function type",
+	}, []string{"function_type"})
+	discoveredFunctions = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystem,
+		Name:      "functions",
+		Help:      "number of functions on an upstream after the most recent discovery",
+	}, []string{"upstream"})
+)
+
+func init() {
+	prometheus.MustRegister(updateFunctionsDuration, updateFunctionsFailures, discoveredFunctions)
+}
+
+func observeUpdateFunctions(functionType functiontypes.FunctionType, start time.Time, err error) {
+	if functionType == functiontypes.NonFunctional {
+		return
+	}
+	updateFunctionsDuration.WithLabelValues(string(functionType)).Observe(time.Since(start).Seconds())
+	if err != nil {
+		updateFunctionsFailures.WithLabelValues(string(functionType)).Inc()
+	}
+}
+
+// ForgetUpstream stops reporting metrics for an upstream that was deleted
+func ForgetUpstream(upstreamName string) {
+	discoveredFunctions.DeleteLabelValues(upstreamName)
+}
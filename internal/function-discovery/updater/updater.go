@@ -2,6 +2,7 @@ package updater
 
 import (
 	"sort"
+	"time"
 
 	"reflect"
 
@@ -60,14 +61,18 @@ func GetSecretRefsToWatch(upstreams []*v1.Upstream) []string {
 // else we can get into an update loop
 func UpdateFunctions(resolve resolver.Resolver, gloo storage.Interface, secretStore dependencies.SecretStorage,
 	files dependencies.FileStorage,
-	upstreamName string, secrets secretwatcher.SecretMap) error {
+	upstreamName string, secrets secretwatcher.SecretMap) (err error) {
 	us, err := gloo.V1().Upstreams().Get(upstreamName)
 	if err != nil {
 		return errors.Wrapf(err, "failed to get existing upstream with name %v", upstreamName)
 	}
 
+	functionType := functiontypes.GetFunctionType(us)
+	start := time.Now()
+	defer func() { observeUpdateFunctions(functionType, start, err) }()
+
 	var funcs []*v1.Function
-	switch functiontypes.GetFunctionType(us) {
+	switch functionType {
 	case functiontypes.FunctionTypeLambda:
 		if len(secrets) == 0 {
 			log.Warnf("lambda upstream detected, but no secrets have been read yet")
@@ -175,6 +180,20 @@ func updateUpstreamWithFuncs(gloo storage.Interface, upstreamName string, funcs
 		return funcs[i].Name < funcs[j].Name
 	})
 
+	// the upstream may be written concurrently, e.g. by the control plane reporting its status.
+	// conflicting writes are retried with the latest version of the upstream
+	var err error
+	backoff.WithBackoff(func() error {
+		err = mergeUpstreamFuncs(gloo, upstreamName, funcs)
+		if storage.IsConflict(err) {
+			return err
+		}
+		return nil
+	}, make(chan struct{}))
+	return err
+}
+
+func mergeUpstreamFuncs(gloo storage.Interface, upstreamName string, funcs []*v1.Function) error {
 	usToUpdate, err := gloo.V1().Upstreams().Get(upstreamName)
 	if err != nil {
 		return errors.Wrapf(err, "failed to get existing upstream with name %v", upstreamName)
@@ -182,16 +201,15 @@ func updateUpstreamWithFuncs(gloo storage.Interface, upstreamName string, funcs
 
 	// no update to do
 	if functionListsEqual(usToUpdate.Functions, funcs) {
+		discoveredFunctions.WithLabelValues(upstreamName).Set(float64(len(funcs)))
 		return nil
 	}
 
 	usToUpdate.Functions = mergeFuncs(usToUpdate.Functions, funcs)
+	discoveredFunctions.WithLabelValues(upstreamName).Set(float64(len(usToUpdate.Functions)))
 
 	_, err = gloo.V1().Upstreams().Update(usToUpdate)
-	if err != nil {
-		return err
-	}
-	return nil
+	return err
 }
 
 // get the unique set of funcs between two lists
@@ -0,0 +1,64 @@
+package glooctl
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/pkg/bootstrap"
+	"github.com/solo-io/gloo/pkg/bootstrap/artifactstorage"
+	"github.com/solo-io/gloo/pkg/bootstrap/configstorage"
+	"github.com/solo-io/gloo/pkg/bootstrap/secretstorage"
+	"github.com/solo-io/gloo/pkg/storage"
+	"github.com/solo-io/gloo/pkg/storage/dependencies"
+)
+
+// clients connects to each storage backend the first time it is used,
+// so e.g. managing upstreams does not require vault credentials
+type clients struct {
+	opts    *bootstrap.Options
+	store   storage.Interface
+	secrets dependencies.SecretStorage
+	files   dependencies.FileStorage
+}
+
+func newClients(opts *bootstrap.Options) *clients {
+	return &clients{opts: opts}
+}
+
+func (c *clients) config() (storage.Interface, error) {
+	if c.store != nil {
+		return c.store, nil
+	}
+	store, err := configstorage.Bootstrap(*c.opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating config storage client")
+	}
+	if err := store.V1().Register(); err != nil {
+		return nil, errors.Wrap(err, "registering config storage")
+	}
+	c.store = store
+	return store, nil
+}
+
+func (c *clients) secretStorage() (dependencies.SecretStorage, error) {
+	if c.secrets != nil {
+		return c.secrets, nil
+	}
+	secrets, err := secretstorage.Bootstrap(*c.opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating secret storage client")
+	}
+	c.secrets = secrets
+	return secrets, nil
+}
+
+func (c *clients) fileStorage() (dependencies.FileStorage, error) {
+	if c.files != nil {
+		return c.files, nil
+	}
+	files, err := artifactstorage.Bootstrap(*c.opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating file storage client")
+	}
+	c.files = files
+	return files, nil
+}
@@ -0,0 +1,186 @@
+package glooctl
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/storage/dependencies"
+)
+
+// builds an object with the given name from the flags of a create command
+type objectBuilder func(name string) (interface{}, error)
+
+func createCmd(opts *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "create an object from a yaml file, or from flags",
+	}
+	cmd.AddCommand(
+		createKindCmd(opts, upstreamKind(), "create an upstream. the spec is built from the flags for the upstream type",
+			upstreamFlags),
+		createKindCmd(opts, virtualServiceKind(), "create a virtual service. routes can only be created from a file",
+			virtualServiceFlags),
+		createKindCmd(opts, roleKind(), "create a role", roleFlags),
+		createKindCmd(opts, secretKind(), "create a secret", secretFlags),
+		createKindCmd(opts, fileKind(), "create a file", fileFlags),
+	)
+	return cmd
+}
+
+func createKindCmd(opts *Options, k *kind, short string, addFlags func(flags *pflag.FlagSet) objectBuilder) *cobra.Command {
+	var filename string
+	cmd := &cobra.Command{
+		Use:     k.name + " [NAME]",
+		Aliases: k.aliases,
+		Short:   short,
+		Args:    cobra.MaximumNArgs(1),
+	}
+	build := addFlags(cmd.Flags())
+	cmd.Flags().StringVarP(&filename, "filename", "f", "", "read the "+k.name+" from a yaml or json file, or - for stdin. other flags are ignored")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		var (
+			obj interface{}
+			err error
+		)
+		if filename != "" {
+			obj, err = readObject(k, filename)
+			if err == nil && len(args) == 1 && args[0] != objectName(obj) {
+				err = errors.Errorf("name %v does not match the name in %v", args[0], filename)
+			}
+		} else {
+			if len(args) != 1 {
+				return errors.Errorf("a name or --filename is required")
+			}
+			obj, err = build(args[0])
+		}
+		if err != nil {
+			return err
+		}
+		created, err := k.create(newClients(&opts.Options), obj)
+		if err != nil {
+			return errors.Wrapf(err, "creating %v %v", k.name, objectName(obj))
+		}
+		if opts.Output != OutputTable {
+			return printObject(opts.out, k, opts.Output, created)
+		}
+		fmt.Fprintf(opts.out, "%v %v created\n", k.name, objectName(created))
+		return nil
+	}
+	return cmd
+}
+
+func readObject(k *kind, filename string) (interface{}, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if filename == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(filename)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %v", filename)
+	}
+	return decodeYaml(k, data)
+}
+
+func upstreamFlags(flags *pflag.FlagSet) objectBuilder {
+	builders := specBuilders()
+	var upstreamType string
+	flags.StringVar(&upstreamType, "type", "", "upstream type, one of "+strings.Join(upstreamTypes(builders), ", "))
+	for _, builder := range builders {
+		builder.addFlags(flags)
+	}
+	return func(name string) (interface{}, error) {
+		return buildUpstream(name, upstreamType, builders)
+	}
+}
+
+func virtualServiceFlags(flags *pflag.FlagSet) objectBuilder {
+	var (
+		domains      []string
+		roles        []string
+		sslSecretRef string
+	)
+	flags.StringSliceVar(&domains, "domains", nil, "domains of the virtual service. defaults to all domains")
+	flags.StringSliceVar(&roles, "roles", nil, "roles of the envoy proxies the virtual service is served to")
+	flags.StringVar(&sslSecretRef, "ssl-secret-ref", "", "secret containing the certificate chain and private key to serve the virtual service with")
+	return func(name string) (interface{}, error) {
+		vs := &v1.VirtualService{
+			Name:    name,
+			Domains: domains,
+			Roles:   roles,
+		}
+		if sslSecretRef != "" {
+			vs.SslConfig = &v1.SSLConfig{SecretRef: sslSecretRef}
+		}
+		return vs, nil
+	}
+}
+
+func roleFlags(flags *pflag.FlagSet) objectBuilder {
+	var virtualServices []string
+	flags.StringSliceVar(&virtualServices, "virtual-services", nil, "virtual services of the role")
+	return func(name string) (interface{}, error) {
+		return &v1.Role{
+			Name:            name,
+			VirtualServices: virtualServices,
+		}, nil
+	}
+}
+
+func secretFlags(flags *pflag.FlagSet) objectBuilder {
+	var (
+		values    []string
+		fromFiles []string
+	)
+	flags.StringArrayVar(&values, "data", nil, "a value of the secret, as key=value. may be repeated")
+	flags.StringArrayVar(&fromFiles, "from-file", nil, "a value of the secret read from a file, as key=path. may be repeated")
+	return func(name string) (interface{}, error) {
+		data, err := parseKeyValues(values)
+		if err != nil {
+			return nil, err
+		}
+		paths, err := parseKeyValues(fromFiles)
+		if err != nil {
+			return nil, err
+		}
+		if data == nil {
+			data = make(map[string]string)
+		}
+		for key, path := range paths {
+			contents, err := ioutil.ReadFile(path)
+			if err != nil {
+				return nil, errors.Wrapf(err, "reading %v", path)
+			}
+			data[key] = string(contents)
+		}
+		if len(data) == 0 {
+			return nil, errors.New("a secret needs at least one value from --data or --from-file")
+		}
+		return &dependencies.Secret{Ref: name, Data: data}, nil
+	}
+}
+
+func fileFlags(flags *pflag.FlagSet) objectBuilder {
+	var path string
+	flags.StringVar(&path, "from-file", "", "path of the file to upload")
+	return func(name string) (interface{}, error) {
+		if path == "" {
+			return nil, errors.New("--from-file is required")
+		}
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %v", path)
+		}
+		return &dependencies.File{Ref: name, Contents: contents}, nil
+	}
+}
@@ -0,0 +1,30 @@
+package glooctl
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func deleteCmd(opts *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete KIND NAME...",
+		Short: "delete objects",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			k, err := kindFor(args[0])
+			if err != nil {
+				return err
+			}
+			c := newClients(&opts.Options)
+			for _, name := range args[1:] {
+				if err := k.delete(c, name); err != nil {
+					return errors.Wrapf(err, "deleting %v %v", k.name, name)
+				}
+				fmt.Fprintf(opts.out, "%v %v deleted\n", k.name, name)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,88 @@
+package glooctl
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func editCmd(opts *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit KIND NAME",
+		Short: "edit an object as yaml in $EDITOR, and update it when the editor exits",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			k, err := kindFor(args[0])
+			if err != nil {
+				return err
+			}
+			name := args[1]
+			c := newClients(&opts.Options)
+			obj, err := k.get(c, name)
+			if err != nil {
+				return errors.Wrapf(err, "getting %v %v", k.name, name)
+			}
+			original, err := toYaml(obj)
+			if err != nil {
+				return err
+			}
+			edited, err := editYaml(opts.editor, k.name+"-"+name, original)
+			if err != nil {
+				return err
+			}
+			if bytes.Equal(original, edited) {
+				fmt.Fprintln(opts.out, "edit cancelled, no changes made")
+				return nil
+			}
+			updated, err := decodeYaml(k, edited)
+			if err != nil {
+				return err
+			}
+			if objectName(updated) != name {
+				return errors.Errorf("the name of a %v cannot be changed", k.name)
+			}
+			if _, err := k.update(c, updated); err != nil {
+				return errors.Wrapf(err, "updating %v %v", k.name, name)
+			}
+			fmt.Fprintf(opts.out, "%v %v updated\n", k.name, name)
+			return nil
+		},
+	}
+}
+
+// editYaml writes the yaml to a temporary file, and returns its contents after running the editor on it
+func editYaml(editor func(path string) error, name string, original []byte) ([]byte, error) {
+	f, err := ioutil.TempFile("", "glooctl-"+name+"-")
+	if err != nil {
+		return nil, errors.Wrap(err, "creating temporary file")
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(original)
+	f.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "writing temporary file")
+	}
+	if err := editor(f.Name()); err != nil {
+		return nil, errors.Wrap(err, "running editor")
+	}
+	return ioutil.ReadFile(f.Name())
+}
+
+// runs $EDITOR, falling back to vi. the editor may include arguments, e.g. "code --wait"
+func runEditor(path string) error {
+	editor := strings.Fields(os.Getenv("EDITOR"))
+	if len(editor) == 0 {
+		editor = []string{"vi"}
+	}
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
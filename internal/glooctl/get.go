@@ -0,0 +1,66 @@
+package glooctl
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func getCmd(opts *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get KIND [NAME...]",
+		Short: "show objects of a kind. shows every object of the kind if no names are given",
+		Long: `shows upstreams, virtualservices, roles, secrets or files.
+the table output includes the status and the reason for any rejection. secret values are only shown in yaml or json output.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			k, err := kindFor(args[0])
+			if err != nil {
+				return err
+			}
+			c := newClients(&opts.Options)
+			names := args[1:]
+			if len(names) == 0 {
+				return list(opts, c, k)
+			}
+			if len(names) == 1 {
+				obj, err := k.get(c, names[0])
+				if err != nil {
+					return errors.Wrapf(err, "getting %v %v", k.name, names[0])
+				}
+				return printObject(opts.out, k, opts.Output, obj)
+			}
+			var objs []interface{}
+			for _, name := range names {
+				obj, err := k.get(c, name)
+				if err != nil {
+					return errors.Wrapf(err, "getting %v %v", k.name, name)
+				}
+				objs = append(objs, obj)
+			}
+			return printObjects(opts.out, k, opts.Output, objs)
+		},
+	}
+}
+
+func listCmd(opts *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list KIND",
+		Short: "list every object of a kind",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			k, err := kindFor(args[0])
+			if err != nil {
+				return err
+			}
+			return list(opts, newClients(&opts.Options), k)
+		},
+	}
+}
+
+func list(opts *Options, c *clients, k *kind) error {
+	objs, err := k.list(c)
+	if err != nil {
+		return errors.Wrapf(err, "listing %v", k.aliases[0])
+	}
+	return printObjects(opts.out, k, opts.Output, objs)
+}
@@ -0,0 +1,13 @@
+package glooctl
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestGlooctl(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Glooctl Suite")
+}
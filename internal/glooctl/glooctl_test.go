@@ -0,0 +1,231 @@
+package glooctl
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/coreplugins/service"
+	"github.com/solo-io/gloo/pkg/plugins/kubernetes"
+)
+
+var _ = Describe("glooctl", func() {
+	var (
+		dir    string
+		out    *bytes.Buffer
+		editor func(path string) error
+	)
+	run := func(args ...string) error {
+		out.Reset()
+		cmd := newRootCmd(&Options{out: out, editor: func(path string) error { return editor(path) }})
+		cmd.SetArgs(append(args,
+			"--file.config.dir", filepath.Join(dir, "config"),
+			"--file.secret.dir", filepath.Join(dir, "secrets"),
+			"--file.files.dir", filepath.Join(dir, "files"),
+		))
+		return cmd.Execute()
+	}
+	writeFile := func(name, contents string) string {
+		path := filepath.Join(dir, name)
+		Expect(ioutil.WriteFile(path, []byte(contents), 0644)).To(Succeed())
+		return path
+	}
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "glooctl-test")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.MkdirAll(filepath.Join(dir, "secrets"), 0755)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(dir, "files"), 0755)).To(Succeed())
+		out = &bytes.Buffer{}
+		editor = func(path string) error { return nil }
+	})
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	Describe("upstreams", func() {
+		It("creates upstreams with specs built from flags", func() {
+			Expect(run("create", "upstream", "petstore", "--type", "service", "--service.hosts", "petstore:8080")).To(Succeed())
+			Expect(out.String()).To(Equal("upstream petstore created\n"))
+
+			Expect(run("get", "upstream", "petstore", "-o", "yaml")).To(Succeed())
+			obj, err := decodeYaml(upstreamKind(), out.Bytes())
+			Expect(err).NotTo(HaveOccurred())
+			us := obj.(*v1.Upstream)
+			Expect(us.Type).To(Equal(service.UpstreamTypeService))
+			spec, err := service.DecodeUpstreamSpec(us.Spec)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.Hosts).To(Equal([]service.Host{{Addr: "petstore", Port: 8080}}))
+		})
+		It("validates specs with the plugin for the upstream type", func() {
+			err := run("create", "upstream", "petstore", "--type", "kubernetes", "--kubernetes.service-namespace", "default")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("service name must be set"))
+
+			Expect(run("create", "upstream", "petstore", "--type", "kubernetes",
+				"--kubernetes.service-name", "petstore", "--kubernetes.labels", "version=v1")).To(Succeed())
+			Expect(run("get", "upstream", "petstore", "-o", "json")).To(Succeed())
+			obj, err := upstreamKind().decode(out.Bytes())
+			Expect(err).NotTo(HaveOccurred())
+			spec, err := kubernetes.DecodeUpstreamSpec(obj.(*v1.Upstream).Spec)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.Labels).To(Equal(map[string]string{"version": "v1"}))
+		})
+		It("rejects unknown upstream types", func() {
+			err := run("create", "upstream", "petstore", "--type", "nope")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unknown upstream type"))
+		})
+		It("shows the status and reason in tables", func() {
+			path := writeFile("us.yaml", `
+name: petstore
+type: service
+spec:
+  hosts:
+  - addr: petstore
+    port: 8080
+status:
+  state: Rejected
+  reason: something went wrong
+`)
+			Expect(run("create", "upstream", "-f", path)).To(Succeed())
+			Expect(run("get", "upstreams")).To(Succeed())
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			Expect(lines).To(HaveLen(2))
+			Expect(strings.Fields(lines[0])).To(Equal([]string{"NAME", "TYPE", "FUNCTIONS", "STATUS", "REASON"}))
+			Expect(lines[1]).To(ContainSubstring("Rejected"))
+			Expect(lines[1]).To(ContainSubstring("something went wrong"))
+		})
+	})
+
+	Describe("virtual services", func() {
+		It("creates, lists, edits and deletes virtual services", func() {
+			Expect(run("create", "vs", "default", "--domains", "example.com", "--roles", "ingress")).To(Succeed())
+			Expect(run("create", "vs", "other", "--domains", "other.example.com")).To(Succeed())
+
+			Expect(run("list", "virtualservices", "-o", "json")).To(Succeed())
+			var list []map[string]interface{}
+			Expect(json.Unmarshal(out.Bytes(), &list)).To(Succeed())
+			Expect(list).To(HaveLen(2))
+			Expect(list[0]["name"]).To(Equal("default"))
+			Expect(list[1]["name"]).To(Equal("other"))
+
+			editor = func(path string) error {
+				data, err := ioutil.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				return ioutil.WriteFile(path, []byte(strings.Replace(string(data), "example.com", "edited.example.com", 1)), 0644)
+			}
+			Expect(run("edit", "vs", "default")).To(Succeed())
+			Expect(out.String()).To(Equal("virtualservice default updated\n"))
+			Expect(run("get", "vs", "default", "-o", "yaml")).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("edited.example.com"))
+
+			Expect(run("delete", "vs", "default", "other")).To(Succeed())
+			Expect(run("get", "vs", "-o", "json")).To(Succeed())
+			Expect(strings.TrimSpace(out.String())).To(Equal("[]"))
+		})
+		It("does not update objects that were not changed in the editor", func() {
+			Expect(run("create", "vs", "default")).To(Succeed())
+			Expect(run("edit", "vs", "default")).To(Succeed())
+			Expect(out.String()).To(Equal("edit cancelled, no changes made\n"))
+		})
+		It("does not allow renaming objects in the editor", func() {
+			Expect(run("create", "vs", "default")).To(Succeed())
+			editor = func(path string) error {
+				return ioutil.WriteFile(path, []byte("name: renamed\n"), 0644)
+			}
+			err := run("edit", "vs", "default")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("cannot be changed"))
+		})
+	})
+
+	Describe("secrets and files", func() {
+		It("only shows secret keys in tables", func() {
+			keyPath := writeFile("key.pem", "super secret key")
+			Expect(run("create", "secret", "tls", "--data", "user=admin", "--from-file", "private_key="+keyPath)).To(Succeed())
+			Expect(run("get", "secret", "tls")).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("private_key,user"))
+			Expect(out.String()).NotTo(ContainSubstring("super secret key"))
+
+			Expect(run("get", "secret", "tls", "-o", "yaml")).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("super secret key"))
+		})
+		It("uploads files", func() {
+			path := writeFile("swagger.json", `{"swagger": "2.0"}`)
+			Expect(run("create", "file", "petstore-swagger", "--from-file", path)).To(Succeed())
+			Expect(run("get", "files")).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("petstore-swagger"))
+		})
+		It("encrypts secret files with a generated key", func() {
+			Expect(run("create", "secret", "db", "--data", "password=hunter2")).To(Succeed())
+			Expect(run("secret-key", "generate")).To(Succeed())
+			keyPath := writeFile("secret.key", out.String())
+
+			Expect(run("secret-key", "rotate", "--file.secret.keyfile", keyPath)).To(Succeed())
+			Expect(out.String()).To(Equal("secret db re-encrypted\n"))
+			b, err := ioutil.ReadFile(filepath.Join(dir, "secrets", "db"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(b)).NotTo(ContainSubstring("hunter2"))
+
+			Expect(run("get", "secret", "db", "-o", "yaml", "--file.secret.keyfile", keyPath)).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("hunter2"))
+			Expect(run("get", "secret", "db")).To(MatchError(ContainSubstring("no secret key is configured")))
+		})
+	})
+
+	Describe("watch", func() {
+		It("prints an event for every added, updated and deleted upstream", func() {
+			Expect(run("create", "upstream", "petstore", "--type", "service", "--service.hosts", "petstore:8080")).To(Succeed())
+
+			watched := gbytes.NewBuffer()
+			stop := make(chan struct{})
+			defer close(stop)
+			cmd := newRootCmd(&Options{out: watched, stop: stop})
+			cmd.SetArgs([]string{"watch", "upstreams",
+				"--storage.refreshrate", "10ms",
+				"--file.config.dir", filepath.Join(dir, "config"),
+				"--file.secret.dir", filepath.Join(dir, "secrets"),
+				"--file.files.dir", filepath.Join(dir, "files"),
+			})
+			errs := make(chan error, 1)
+			go func() { errs <- cmd.Execute() }()
+			Eventually(watched).Should(gbytes.Say(`EVENT\s+NAME`))
+			Eventually(watched).Should(gbytes.Say(`ADDED\s+petstore\s`))
+
+			Expect(run("create", "upstream", "other", "--type", "service", "--service.hosts", "other:8080")).To(Succeed())
+			Eventually(watched).Should(gbytes.Say(`ADDED\s+other\s`))
+
+			editor = func(path string) error {
+				data, err := ioutil.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				return ioutil.WriteFile(path, []byte(strings.Replace(string(data), "8080", "9090", 1)), 0644)
+			}
+			Expect(run("edit", "upstream", "petstore")).To(Succeed())
+			Eventually(watched).Should(gbytes.Say(`UPDATED\s+petstore\s`))
+
+			Expect(run("delete", "upstream", "petstore")).To(Succeed())
+			Eventually(watched).Should(gbytes.Say(`DELETED\s+petstore\s`))
+			Consistently(func() string { return string(watched.Contents()) }).ShouldNot(MatchRegexp(`DELETED\s+other\s`))
+			Consistently(errs).ShouldNot(Receive())
+		})
+	})
+
+	It("rejects unknown kinds", func() {
+		err := run("get", "widgets")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("unknown kind"))
+	})
+})
@@ -0,0 +1,523 @@
+package glooctl
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/protoutil"
+	"github.com/solo-io/gloo/pkg/storage"
+	"github.com/solo-io/gloo/pkg/storage/dependencies"
+)
+
+const (
+	eventAdded   = "added"
+	eventUpdated = "updated"
+	eventDeleted = "deleted"
+)
+
+// kind is a type of object managed by glooctl.
+// objects are *v1.Upstream, *v1.VirtualService, *v1.Role, *dependencies.Secret or *dependencies.File
+type kind struct {
+	name    string
+	aliases []string
+
+	get    func(c *clients, name string) (interface{}, error)
+	list   func(c *clients) ([]interface{}, error)
+	create func(c *clients, obj interface{}) (interface{}, error)
+	update func(c *clients, obj interface{}) (interface{}, error)
+	delete func(c *clients, name string) error
+	// watch calls onList with every object of the kind, sorted by name, whenever one of them changes
+	watch func(c *clients, onList func(list []interface{})) (*storage.Watcher, error)
+	// decode reads an object from json
+	decode func(data []byte) (interface{}, error)
+
+	// for table output
+	columns []string
+	row     func(obj interface{}) []string
+}
+
+var kinds = []*kind{
+	upstreamKind(),
+	virtualServiceKind(),
+	roleKind(),
+	secretKind(),
+	fileKind(),
+}
+
+func kindFor(name string) (*kind, error) {
+	name = strings.ToLower(name)
+	for _, k := range kinds {
+		if k.name == name {
+			return k, nil
+		}
+		for _, alias := range k.aliases {
+			if alias == name {
+				return k, nil
+			}
+		}
+	}
+	var names []string
+	for _, k := range kinds {
+		names = append(names, k.name)
+	}
+	return nil, errors.Errorf("unknown kind %q, must be one of %v", name, strings.Join(names, ", "))
+}
+
+// secrets and files are not protos, so they get their own yaml format
+type secretDoc struct {
+	Name            string            `json:"name"`
+	Data            map[string]string `json:"data"`
+	ResourceVersion string            `json:"resource_version,omitempty"`
+}
+
+type fileDoc struct {
+	Name            string `json:"name"`
+	Contents        string `json:"contents"`
+	ResourceVersion string `json:"resource_version,omitempty"`
+}
+
+// encode converts an object to json
+func encode(obj interface{}) ([]byte, error) {
+	switch obj := obj.(type) {
+	case proto.Message:
+		return protoutil.Marshal(obj)
+	case *dependencies.Secret:
+		return json.Marshal(secretDoc{Name: obj.Ref, Data: obj.Data, ResourceVersion: obj.ResourceVersion})
+	case *dependencies.File:
+		return json.Marshal(fileDoc{Name: obj.Ref, Contents: string(obj.Contents), ResourceVersion: obj.ResourceVersion})
+	}
+	return nil, errors.Errorf("cannot encode %T", obj)
+}
+
+// decodeYaml reads an object of the given kind from yaml or json
+func decodeYaml(k *kind, data []byte) (interface{}, error) {
+	jsn, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing yaml")
+	}
+	obj, err := k.decode(jsn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing %v", k.name)
+	}
+	if objectName(obj) == "" {
+		return nil, errors.Errorf("%v must have a name", k.name)
+	}
+	return obj, nil
+}
+
+func objectName(obj interface{}) string {
+	switch obj := obj.(type) {
+	case v1.ConfigObject:
+		return obj.GetName()
+	case *dependencies.Secret:
+		return obj.Ref
+	case *dependencies.File:
+		return obj.Ref
+	}
+	return ""
+}
+
+func statusColumns(status *v1.Status) []string {
+	if status == nil {
+		return []string{v1.Status_Pending.String(), ""}
+	}
+	return []string{status.State.String(), status.Reason}
+}
+
+func toObjects(list interface{}) []interface{} {
+	var out []interface{}
+	switch list := list.(type) {
+	case []*v1.Upstream:
+		for _, obj := range list {
+			out = append(out, obj)
+		}
+	case []*v1.VirtualService:
+		for _, obj := range list {
+			out = append(out, obj)
+		}
+	case []*v1.Role:
+		for _, obj := range list {
+			out = append(out, obj)
+		}
+	case []*dependencies.Secret:
+		for _, obj := range list {
+			out = append(out, obj)
+		}
+	case []*dependencies.File:
+		for _, obj := range list {
+			out = append(out, obj)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return objectName(out[i]) < objectName(out[j])
+	})
+	return out
+}
+
+func upstreamKind() *kind {
+	upstreams := func(c *clients) (storage.Upstreams, error) {
+		store, err := c.config()
+		if err != nil {
+			return nil, err
+		}
+		return store.V1().Upstreams(), nil
+	}
+	return &kind{
+		name:    "upstream",
+		aliases: []string{"upstreams", "us"},
+		get: func(c *clients, name string) (interface{}, error) {
+			client, err := upstreams(c)
+			if err != nil {
+				return nil, err
+			}
+			return client.Get(name)
+		},
+		list: func(c *clients) ([]interface{}, error) {
+			client, err := upstreams(c)
+			if err != nil {
+				return nil, err
+			}
+			list, err := client.List()
+			return toObjects(list), err
+		},
+		create: func(c *clients, obj interface{}) (interface{}, error) {
+			client, err := upstreams(c)
+			if err != nil {
+				return nil, err
+			}
+			return client.Create(obj.(*v1.Upstream))
+		},
+		update: func(c *clients, obj interface{}) (interface{}, error) {
+			client, err := upstreams(c)
+			if err != nil {
+				return nil, err
+			}
+			return client.Update(obj.(*v1.Upstream))
+		},
+		delete: func(c *clients, name string) error {
+			client, err := upstreams(c)
+			if err != nil {
+				return err
+			}
+			return client.Delete(name)
+		},
+		watch: func(c *clients, onList func(list []interface{})) (*storage.Watcher, error) {
+			client, err := upstreams(c)
+			if err != nil {
+				return nil, err
+			}
+			changed := func(list []*v1.Upstream, _ *v1.Upstream) { onList(toObjects(list)) }
+			return client.Watch(&storage.UpstreamEventHandlerFuncs{
+				AddFunc:    changed,
+				UpdateFunc: changed,
+				DeleteFunc: changed,
+			})
+		},
+		decode: func(data []byte) (interface{}, error) {
+			var us v1.Upstream
+			err := protoutil.Unmarshal(data, &us)
+			return &us, err
+		},
+		columns: []string{"NAME", "TYPE", "FUNCTIONS", "STATUS", "REASON"},
+		row: func(obj interface{}) []string {
+			us := obj.(*v1.Upstream)
+			return append([]string{us.Name, us.Type, fmt.Sprintf("%v", len(us.Functions))}, statusColumns(us.Status)...)
+		},
+	}
+}
+
+func virtualServiceKind() *kind {
+	virtualServices := func(c *clients) (storage.VirtualServices, error) {
+		store, err := c.config()
+		if err != nil {
+			return nil, err
+		}
+		return store.V1().VirtualServices(), nil
+	}
+	return &kind{
+		name:    "virtualservice",
+		aliases: []string{"virtualservices", "vs"},
+		get: func(c *clients, name string) (interface{}, error) {
+			client, err := virtualServices(c)
+			if err != nil {
+				return nil, err
+			}
+			return client.Get(name)
+		},
+		list: func(c *clients) ([]interface{}, error) {
+			client, err := virtualServices(c)
+			if err != nil {
+				return nil, err
+			}
+			list, err := client.List()
+			return toObjects(list), err
+		},
+		create: func(c *clients, obj interface{}) (interface{}, error) {
+			client, err := virtualServices(c)
+			if err != nil {
+				return nil, err
+			}
+			return client.Create(obj.(*v1.VirtualService))
+		},
+		update: func(c *clients, obj interface{}) (interface{}, error) {
+			client, err := virtualServices(c)
+			if err != nil {
+				return nil, err
+			}
+			return client.Update(obj.(*v1.VirtualService))
+		},
+		delete: func(c *clients, name string) error {
+			client, err := virtualServices(c)
+			if err != nil {
+				return err
+			}
+			return client.Delete(name)
+		},
+		watch: func(c *clients, onList func(list []interface{})) (*storage.Watcher, error) {
+			client, err := virtualServices(c)
+			if err != nil {
+				return nil, err
+			}
+			changed := func(list []*v1.VirtualService, _ *v1.VirtualService) { onList(toObjects(list)) }
+			return client.Watch(&storage.VirtualServiceEventHandlerFuncs{
+				AddFunc:    changed,
+				UpdateFunc: changed,
+				DeleteFunc: changed,
+			})
+		},
+		decode: func(data []byte) (interface{}, error) {
+			var vs v1.VirtualService
+			err := protoutil.Unmarshal(data, &vs)
+			return &vs, err
+		},
+		columns: []string{"NAME", "DOMAINS", "ROUTES", "ROLES", "STATUS", "REASON"},
+		row: func(obj interface{}) []string {
+			vs := obj.(*v1.VirtualService)
+			return append([]string{
+				vs.Name,
+				strings.Join(vs.Domains, ","),
+				fmt.Sprintf("%v", len(vs.Routes)),
+				strings.Join(vs.Roles, ","),
+			}, statusColumns(vs.Status)...)
+		},
+	}
+}
+
+func roleKind() *kind {
+	roles := func(c *clients) (storage.Roles, error) {
+		store, err := c.config()
+		if err != nil {
+			return nil, err
+		}
+		return store.V1().Roles(), nil
+	}
+	return &kind{
+		name:    "role",
+		aliases: []string{"roles"},
+		get: func(c *clients, name string) (interface{}, error) {
+			client, err := roles(c)
+			if err != nil {
+				return nil, err
+			}
+			return client.Get(name)
+		},
+		list: func(c *clients) ([]interface{}, error) {
+			client, err := roles(c)
+			if err != nil {
+				return nil, err
+			}
+			list, err := client.List()
+			return toObjects(list), err
+		},
+		create: func(c *clients, obj interface{}) (interface{}, error) {
+			client, err := roles(c)
+			if err != nil {
+				return nil, err
+			}
+			return client.Create(obj.(*v1.Role))
+		},
+		update: func(c *clients, obj interface{}) (interface{}, error) {
+			client, err := roles(c)
+			if err != nil {
+				return nil, err
+			}
+			return client.Update(obj.(*v1.Role))
+		},
+		delete: func(c *clients, name string) error {
+			client, err := roles(c)
+			if err != nil {
+				return err
+			}
+			return client.Delete(name)
+		},
+		watch: func(c *clients, onList func(list []interface{})) (*storage.Watcher, error) {
+			client, err := roles(c)
+			if err != nil {
+				return nil, err
+			}
+			changed := func(list []*v1.Role, _ *v1.Role) { onList(toObjects(list)) }
+			return client.Watch(&storage.RoleEventHandlerFuncs{
+				AddFunc:    changed,
+				UpdateFunc: changed,
+				DeleteFunc: changed,
+			})
+		},
+		decode: func(data []byte) (interface{}, error) {
+			var role v1.Role
+			err := protoutil.Unmarshal(data, &role)
+			return &role, err
+		},
+		columns: []string{"NAME", "VIRTUAL SERVICES", "STATUS", "REASON"},
+		row: func(obj interface{}) []string {
+			role := obj.(*v1.Role)
+			return append([]string{role.Name, strings.Join(role.VirtualServices, ",")}, statusColumns(role.Status)...)
+		},
+	}
+}
+
+func secretKind() *kind {
+	return &kind{
+		name:    "secret",
+		aliases: []string{"secrets"},
+		get: func(c *clients, name string) (interface{}, error) {
+			client, err := c.secretStorage()
+			if err != nil {
+				return nil, err
+			}
+			return client.Get(name)
+		},
+		list: func(c *clients) ([]interface{}, error) {
+			client, err := c.secretStorage()
+			if err != nil {
+				return nil, err
+			}
+			list, err := client.List()
+			return toObjects(list), err
+		},
+		create: func(c *clients, obj interface{}) (interface{}, error) {
+			client, err := c.secretStorage()
+			if err != nil {
+				return nil, err
+			}
+			return client.Create(obj.(*dependencies.Secret))
+		},
+		update: func(c *clients, obj interface{}) (interface{}, error) {
+			client, err := c.secretStorage()
+			if err != nil {
+				return nil, err
+			}
+			return client.Update(obj.(*dependencies.Secret))
+		},
+		delete: func(c *clients, name string) error {
+			client, err := c.secretStorage()
+			if err != nil {
+				return err
+			}
+			return client.Delete(name)
+		},
+		watch: func(c *clients, onList func(list []interface{})) (*storage.Watcher, error) {
+			client, err := c.secretStorage()
+			if err != nil {
+				return nil, err
+			}
+			changed := func(list []*dependencies.Secret, _ *dependencies.Secret) { onList(toObjects(list)) }
+			return client.Watch(&dependencies.SecretEventHandlerFuncs{
+				AddFunc:    changed,
+				UpdateFunc: changed,
+				DeleteFunc: changed,
+			})
+		},
+		decode: func(data []byte) (interface{}, error) {
+			var doc secretDoc
+			if err := json.Unmarshal(data, &doc); err != nil {
+				return nil, err
+			}
+			return &dependencies.Secret{Ref: doc.Name, Data: doc.Data, ResourceVersion: doc.ResourceVersion}, nil
+		},
+		// secret values are only shown in yaml and json output
+		columns: []string{"NAME", "KEYS"},
+		row: func(obj interface{}) []string {
+			secret := obj.(*dependencies.Secret)
+			var keys []string
+			for key := range secret.Data {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			return []string{secret.Ref, strings.Join(keys, ",")}
+		},
+	}
+}
+
+func fileKind() *kind {
+	return &kind{
+		name:    "file",
+		aliases: []string{"files"},
+		get: func(c *clients, name string) (interface{}, error) {
+			client, err := c.fileStorage()
+			if err != nil {
+				return nil, err
+			}
+			return client.Get(name)
+		},
+		list: func(c *clients) ([]interface{}, error) {
+			client, err := c.fileStorage()
+			if err != nil {
+				return nil, err
+			}
+			list, err := client.List()
+			return toObjects(list), err
+		},
+		create: func(c *clients, obj interface{}) (interface{}, error) {
+			client, err := c.fileStorage()
+			if err != nil {
+				return nil, err
+			}
+			return client.Create(obj.(*dependencies.File))
+		},
+		update: func(c *clients, obj interface{}) (interface{}, error) {
+			client, err := c.fileStorage()
+			if err != nil {
+				return nil, err
+			}
+			return client.Update(obj.(*dependencies.File))
+		},
+		delete: func(c *clients, name string) error {
+			client, err := c.fileStorage()
+			if err != nil {
+				return err
+			}
+			return client.Delete(name)
+		},
+		watch: func(c *clients, onList func(list []interface{})) (*storage.Watcher, error) {
+			client, err := c.fileStorage()
+			if err != nil {
+				return nil, err
+			}
+			changed := func(list []*dependencies.File, _ *dependencies.File) { onList(toObjects(list)) }
+			return client.Watch(&dependencies.FileEventHandlerFuncs{
+				AddFunc:    changed,
+				UpdateFunc: changed,
+				DeleteFunc: changed,
+			})
+		},
+		decode: func(data []byte) (interface{}, error) {
+			var doc fileDoc
+			if err := json.Unmarshal(data, &doc); err != nil {
+				return nil, err
+			}
+			return &dependencies.File{Ref: doc.Name, Contents: []byte(doc.Contents), ResourceVersion: doc.ResourceVersion}, nil
+		},
+		columns: []string{"NAME", "SIZE"},
+		row: func(obj interface{}) []string {
+			f := obj.(*dependencies.File)
+			return []string{f.Ref, fmt.Sprintf("%v", len(f.Contents))}
+		},
+	}
+}
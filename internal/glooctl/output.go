@@ -0,0 +1,93 @@
+package glooctl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+const (
+	OutputTable = "table"
+	OutputYaml  = "yaml"
+	OutputJson  = "json"
+)
+
+func validateOutput(output string) error {
+	switch output {
+	case OutputTable, OutputYaml, OutputJson:
+		return nil
+	}
+	return errors.Errorf("invalid output format %q, must be %v, %v or %v", output, OutputTable, OutputYaml, OutputJson)
+}
+
+// printObjects writes the objects as a table, a list of yaml documents, or a json array
+func printObjects(w io.Writer, k *kind, output string, objs []interface{}) error {
+	switch output {
+	case OutputTable:
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(k.columns, "\t"))
+		for _, obj := range objs {
+			fmt.Fprintln(tw, strings.Join(k.row(obj), "\t"))
+		}
+		return tw.Flush()
+	case OutputYaml:
+		for i, obj := range objs {
+			if i > 0 {
+				fmt.Fprintln(w, "---")
+			}
+			data, err := toYaml(obj)
+			if err != nil {
+				return err
+			}
+			if _, err := w.Write(data); err != nil {
+				return err
+			}
+		}
+		return nil
+	case OutputJson:
+		docs := []json.RawMessage{}
+		for _, obj := range objs {
+			data, err := encode(obj)
+			if err != nil {
+				return err
+			}
+			docs = append(docs, data)
+		}
+		return printJson(w, docs)
+	}
+	return validateOutput(output)
+}
+
+// printObject writes a single object. json output is the object itself, rather than a list
+func printObject(w io.Writer, k *kind, output string, obj interface{}) error {
+	if output != OutputJson {
+		return printObjects(w, k, output, []interface{}{obj})
+	}
+	data, err := encode(obj)
+	if err != nil {
+		return err
+	}
+	return printJson(w, json.RawMessage(data))
+}
+
+func printJson(w io.Writer, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+func toYaml(obj interface{}) ([]byte, error) {
+	jsn, err := encode(obj)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.JSONToYAML(jsn)
+}
@@ -0,0 +1,66 @@
+package glooctl
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/solo-io/gloo/pkg/bootstrap"
+	"github.com/solo-io/gloo/pkg/bootstrap/flags"
+)
+
+// Options for every glooctl command
+type Options struct {
+	bootstrap.Options
+	// Output is the format for printed objects: table, yaml or json
+	Output string
+
+	// where objects are printed. defaults to stdout
+	out io.Writer
+	// where edit reads the modified object from. defaults to running $EDITOR
+	editor func(path string) error
+	// when watch stops. defaults to an interrupt or termination signal
+	stop <-chan struct{}
+}
+
+// NewRootCmd creates the glooctl command.
+// objects are read from and written to the storage backends selected with the storage flags
+func NewRootCmd() *cobra.Command {
+	return newRootCmd(&Options{out: os.Stdout, editor: runEditor})
+}
+
+func newRootCmd(opts *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "glooctl",
+		Short:         "manage gloo upstreams, virtual services, roles, secrets and files",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return validateOutput(opts.Output)
+		},
+	}
+
+	baseOpts := &opts.Options
+	flags.AddConfigStorageOptionFlags(cmd, baseOpts)
+	flags.AddSecretStorageOptionFlags(cmd, baseOpts)
+	flags.AddFileStorageOptionFlags(cmd, baseOpts)
+	flags.AddFileFlags(cmd, baseOpts)
+	flags.AddKubernetesFlags(cmd, baseOpts)
+	flags.AddConsulFlags(cmd, baseOpts)
+	flags.AddEtcdFlags(cmd, baseOpts)
+	flags.AddVaultFlags(cmd, baseOpts)
+
+	cmd.PersistentFlags().StringVarP(&opts.Output, "output", "o", OutputTable, "output format: table, yaml or json")
+
+	cmd.AddCommand(
+		createCmd(opts),
+		getCmd(opts),
+		listCmd(opts),
+		editCmd(opts),
+		deleteCmd(opts),
+		watchCmd(opts),
+		secretKeyCmd(opts),
+	)
+	return cmd
+}
@@ -0,0 +1,57 @@
+package glooctl
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/solo-io/gloo/pkg/bootstrap"
+	"github.com/solo-io/gloo/pkg/storage/dependencies/file"
+)
+
+func secretKeyCmd(opts *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secret-key",
+		Short: "manage the keys secret files are encrypted with",
+	}
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "generate",
+			Short: "print a new random key for --file.secret.keyfile",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				key, err := file.GenerateKey()
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(opts.out, key)
+				return nil
+			},
+		},
+		&cobra.Command{
+			Use:   "rotate",
+			Short: "re-encrypt every secret file with the first key, so the other keys can be removed",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				if opts.SecretStorageOptions.Type != bootstrap.WatcherTypeFile {
+					return errors.Errorf("secret keys are only used by the %v secret storage, not %v",
+						bootstrap.WatcherTypeFile, opts.SecretStorageOptions.Type)
+				}
+				keys, err := file.LoadKeyring(opts.FileOptions.SecretKeyFile)
+				if err != nil {
+					return errors.Wrap(err, "loading secret keys")
+				}
+				if keys == nil {
+					return errors.Errorf("either --file.secret.keyfile or the %v environment variable must be set", file.SecretKeyEnv)
+				}
+				rotated, err := file.RotateSecretKey(opts.FileOptions.SecretDir, keys)
+				for _, ref := range rotated {
+					fmt.Fprintf(opts.out, "secret %v re-encrypted\n", ref)
+				}
+				return err
+			},
+		},
+	)
+	return cmd
+}
@@ -0,0 +1,208 @@
+package glooctl
+
+import (
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/coreplugins/service"
+	"github.com/solo-io/gloo/pkg/plugins/aws"
+	"github.com/solo-io/gloo/pkg/plugins/azure"
+	"github.com/solo-io/gloo/pkg/plugins/cloudfoundry"
+	"github.com/solo-io/gloo/pkg/plugins/consul"
+	gfunc "github.com/solo-io/gloo/pkg/plugins/google"
+	"github.com/solo-io/gloo/pkg/plugins/kubernetes"
+)
+
+// specBuilder builds the spec of one type of upstream from flags.
+// the spec is validated by the plugin that owns the upstream type
+type specBuilder interface {
+	addFlags(flags *pflag.FlagSet)
+	build() (*types.Struct, error)
+}
+
+// the flags of each builder are prefixed with the upstream type, e.g. --aws.region
+func specBuilders() map[string]specBuilder {
+	return map[string]specBuilder{
+		aws.UpstreamTypeAws:         &awsSpec{},
+		azure.UpstreamTypeAzure:     &azureSpec{},
+		cloudfoundry.UpstreamTypeCF: &cloudFoundrySpec{},
+		consul.UpstreamTypeConsul:   &consulSpec{},
+		gfunc.UpstreamTypeGoogle:    &googleSpec{},
+		kubernetes.UpstreamTypeKube: &kubernetesSpec{},
+		service.UpstreamTypeService: &serviceSpec{},
+	}
+}
+
+func upstreamTypes(builders map[string]specBuilder) []string {
+	var names []string
+	for name := range builders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type awsSpec struct {
+	spec aws.UpstreamSpec
+}
+
+func (s *awsSpec) addFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&s.spec.Region, "aws.region", "us-east-1", "aws region of the lambda functions")
+	flags.StringVar(&s.spec.SecretRef, "aws.secret-ref", "", "secret containing the aws access_key and secret_key")
+}
+
+func (s *awsSpec) build() (*types.Struct, error) {
+	spec := aws.EncodeUpstreamSpec(s.spec)
+	_, err := aws.DecodeUpstreamSpec(spec)
+	return spec, err
+}
+
+type azureSpec struct {
+	spec azure.UpstreamSpec
+}
+
+func (s *azureSpec) addFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&s.spec.FunctionAppName, "azure.function-app-name", "", "name of the azure function app")
+	flags.StringVar(&s.spec.SecretRef, "azure.secret-ref", "", "secret containing the function app keys")
+}
+
+func (s *azureSpec) build() (*types.Struct, error) {
+	spec := azure.EncodeUpstreamSpec(s.spec)
+	_, err := azure.DecodeUpstreamSpec(spec)
+	return spec, err
+}
+
+type cloudFoundrySpec struct {
+	spec cloudfoundry.UpstreamSpec
+}
+
+func (s *cloudFoundrySpec) addFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&s.spec.Hostname, "cloudfoundry.hostname", "", "hostname of the cloud foundry route")
+}
+
+func (s *cloudFoundrySpec) build() (*types.Struct, error) {
+	spec := cloudfoundry.EncodeUpstreamSpec(s.spec)
+	_, err := cloudfoundry.DecodeUpstreamSpec(spec)
+	return spec, err
+}
+
+type consulSpec struct {
+	spec consul.UpstreamSpec
+}
+
+func (s *consulSpec) addFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&s.spec.ServiceName, "consul.service-name", "", "name of the consul service")
+	flags.StringSliceVar(&s.spec.ServiceTags, "consul.service-tags", nil, "only route to service instances with these tags")
+}
+
+func (s *consulSpec) build() (*types.Struct, error) {
+	spec := consul.EncodeUpstreamSpec(s.spec)
+	_, err := consul.DecodeUpstreamSpec(spec)
+	return spec, err
+}
+
+type googleSpec struct {
+	spec gfunc.UpstreamSpec
+}
+
+func (s *googleSpec) addFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&s.spec.Region, "google.region", "us-central1", "google cloud region of the functions")
+	flags.StringVar(&s.spec.ProjectId, "google.project-id", "", "google cloud project id")
+}
+
+func (s *googleSpec) build() (*types.Struct, error) {
+	spec := gfunc.EncodeUpstreamSpec(s.spec)
+	_, err := gfunc.DecodeUpstreamSpec(spec)
+	return spec, err
+}
+
+type kubernetesSpec struct {
+	spec   kubernetes.UpstreamSpec
+	labels []string
+}
+
+func (s *kubernetesSpec) addFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&s.spec.ServiceName, "kubernetes.service-name", "", "name of the kubernetes service")
+	flags.StringVar(&s.spec.ServiceNamespace, "kubernetes.service-namespace", "default", "namespace of the kubernetes service")
+	flags.Int32Var(&s.spec.ServicePort, "kubernetes.service-port", 0, "port of the kubernetes service. may be omitted if the service has a single port")
+	flags.StringSliceVar(&s.labels, "kubernetes.labels", nil, "only route to pods with these labels, as key=value")
+}
+
+func (s *kubernetesSpec) build() (*types.Struct, error) {
+	labels, err := parseKeyValues(s.labels)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid labels")
+	}
+	s.spec.Labels = labels
+	spec := kubernetes.EncodeUpstreamSpec(s.spec)
+	_, err = kubernetes.DecodeUpstreamSpec(spec)
+	return spec, err
+}
+
+type serviceSpec struct {
+	hosts      []string
+	enableIPv6 bool
+}
+
+func (s *serviceSpec) addFlags(flags *pflag.FlagSet) {
+	flags.StringSliceVar(&s.hosts, "service.hosts", nil, "addresses of the service, as host:port")
+	flags.BoolVar(&s.enableIPv6, "service.enable-ipv6", false, "resolve ipv6 addresses for the hosts")
+}
+
+func (s *serviceSpec) build() (*types.Struct, error) {
+	spec := service.UpstreamSpec{EnableIPv6: s.enableIPv6}
+	for _, hostPort := range s.hosts {
+		host, portStr, err := net.SplitHostPort(hostPort)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid host %v", hostPort)
+		}
+		port, err := strconv.ParseUint(portStr, 10, 32)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid port in %v", hostPort)
+		}
+		spec.Hosts = append(spec.Hosts, service.Host{Addr: host, Port: uint32(port)})
+	}
+	encoded := service.EncodeUpstreamSpec(spec)
+	_, err := service.DecodeUpstreamSpec(encoded)
+	return encoded, err
+}
+
+// builds an upstream of the given type from the flags of its spec builder
+func buildUpstream(name, upstreamType string, builders map[string]specBuilder) (*v1.Upstream, error) {
+	builder, ok := builders[upstreamType]
+	if !ok {
+		return nil, errors.Errorf("unknown upstream type %q, must be one of %v",
+			upstreamType, strings.Join(upstreamTypes(builders), ", "))
+	}
+	spec, err := builder.build()
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %v upstream spec", upstreamType)
+	}
+	return &v1.Upstream{
+		Name: name,
+		Type: upstreamType,
+		Spec: spec,
+	}, nil
+}
+
+func parseKeyValues(pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]string)
+	for _, pair := range pairs {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.Errorf("%q must be in the format key=value", pair)
+		}
+		out[parts[0]] = parts[1]
+	}
+	return out, nil
+}
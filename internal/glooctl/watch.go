@@ -0,0 +1,123 @@
+package glooctl
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/solo-io/gloo/pkg/signals"
+)
+
+func watchCmd(opts *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "watch KIND",
+		Short: "print objects of a kind as they are added, updated and deleted, until interrupted",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			k, err := kindFor(args[0])
+			if err != nil {
+				return err
+			}
+			stop := opts.stop
+			if stop == nil {
+				stop = signals.SetupSignalHandler()
+			}
+			return watch(opts, newClients(&opts.Options), k, stop)
+		},
+	}
+}
+
+// each event is printed as a table row, a yaml document, or a line of json
+func watch(opts *Options, c *clients, k *kind, stop <-chan struct{}) error {
+	var (
+		lock    sync.Mutex
+		printed bool
+		// the objects of the last list, by name
+		previous = make(map[string]interface{})
+	)
+	printEvent := func(event string, obj interface{}) {
+		switch opts.Output {
+		case OutputTable:
+			tw := tabwriter.NewWriter(opts.out, 0, 8, 2, ' ', 0)
+			if !printed {
+				fmt.Fprintln(tw, "EVENT\t"+strings.Join(k.columns, "\t"))
+			}
+			fmt.Fprintln(tw, strings.ToUpper(event)+"\t"+strings.Join(k.row(obj), "\t"))
+			tw.Flush()
+		case OutputYaml:
+			if printed {
+				fmt.Fprintln(opts.out, "---")
+			}
+			data, err := toYaml(obj)
+			if err != nil {
+				fmt.Fprintf(opts.out, "# %v: %v\n", event, err)
+				break
+			}
+			fmt.Fprintf(opts.out, "# %v\n%s", event, data)
+		case OutputJson:
+			data, err := encode(obj)
+			if err != nil {
+				fmt.Fprintf(opts.out, "{\"event\": %q, \"error\": %q}\n", event, err.Error())
+				break
+			}
+			fmt.Fprintf(opts.out, "{\"event\": %q, \"object\": %s}\n", event, data)
+		}
+		printed = true
+	}
+	// the storage clients hand over the whole list on every change, and not always the changed object,
+	// so events are found by comparing each list with the previous one.
+	// handlers may be called concurrently
+	onList := func(list []interface{}) {
+		lock.Lock()
+		defer lock.Unlock()
+		current := make(map[string]interface{})
+		for _, obj := range list {
+			name := objectName(obj)
+			current[name] = obj
+			old, ok := previous[name]
+			switch {
+			case !ok:
+				printEvent(eventAdded, obj)
+			case !sameObject(old, obj):
+				printEvent(eventUpdated, obj)
+			}
+		}
+		var deleted []string
+		for name := range previous {
+			if _, ok := current[name]; !ok {
+				deleted = append(deleted, name)
+			}
+		}
+		sort.Strings(deleted)
+		for _, name := range deleted {
+			printEvent(eventDeleted, previous[name])
+		}
+		previous = current
+	}
+	watcher, err := k.watch(c, onList)
+	if err != nil {
+		return errors.Wrapf(err, "watching %v", k.aliases[0])
+	}
+	errs := make(chan error)
+	go watcher.Run(stop, errs)
+	for {
+		select {
+		case <-stop:
+			return nil
+		case err := <-errs:
+			return errors.Wrapf(err, "watching %v", k.aliases[0])
+		}
+	}
+}
+
+func sameObject(a, b interface{}) bool {
+	dataA, errA := encode(a)
+	dataB, errB := encode(b)
+	return errA == nil && errB == nil && bytes.Equal(dataA, dataB)
+}
@@ -103,7 +103,11 @@ func (c *IngressController) Error() <-chan error {
 }
 
 func (c *IngressController) syncGlooResourcesWithIngresses() {
-	if err := c.syncGlooResources(); err != nil {
+	start := time.Now()
+	err := c.syncGlooResources()
+	syncDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		syncFailures.Inc()
 		c.errors <- err
 	}
 }
@@ -113,6 +117,8 @@ func (c *IngressController) syncGlooResources() error {
 	if err != nil {
 		return fmt.Errorf("failed to generate desired configObjects: %v", err)
 	}
+	generatedObjects.WithLabelValues("upstream").Set(float64(len(desiredUpstreams)))
+	generatedObjects.WithLabelValues("virtualservice").Set(float64(len(desiredVirtualServices)))
 	actualUpstreams, actualVirtualServices, err := c.getActualResources()
 	if err != nil {
 		return fmt.Errorf("failed to list actual configObjects: %v", err)
@@ -0,0 +1,40 @@
+package ingress
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/solo-io/gloo/pkg/metrics"
+)
+
+const subsystem = "ingress_controller"
+
+var (
+	syncDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystem,
+		Name:      "sync_duration_seconds",
+		Help:      "time taken to sync gloo config objects with kubernetes ingresses",
+	})
+	syncFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystem,
+		Name:      "sync_failures_total",
+		Help:      "number of failed syncs of gloo config objects with kubernetes ingresses",
+	})
+	generatedObjects = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystem,
+		Name:      "objects",
+		Help:      "number of config objects generated from ingresses by the most recent sync, by object type",
+	}, []string{"type"})
+	statusSyncFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystem,
+		Name:      "status_sync_failures_total",
+		Help:      "number of failed updates of ingress load balancer statuses",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(syncDuration, syncFailures, generatedObjects, statusSyncFailures)
+}
@@ -78,6 +78,7 @@ func NewIngressSyncer(cfg *rest.Config, resyncDuration time.Duration, stopCh <-c
 
 func (c *IngressSyncer) syncIngressStatus() {
 	if err := c.sync(); err != nil {
+		statusSyncFailures.Inc()
 		c.errors <- err
 	}
 }
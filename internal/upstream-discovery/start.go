@@ -2,7 +2,6 @@ package upstreamdiscovery
 
 import (
 	"context"
-	"log"
 	"time"
 
 	"github.com/pkg/errors"
@@ -10,6 +9,7 @@ import (
 	"github.com/solo-io/gloo/internal/upstream-discovery/consul"
 	"github.com/solo-io/gloo/internal/upstream-discovery/copilot"
 	"github.com/solo-io/gloo/internal/upstream-discovery/kube"
+	"github.com/solo-io/gloo/pkg/log"
 	"github.com/solo-io/gloo/pkg/plugins/cloudfoundry"
 	"github.com/solo-io/gloo/pkg/storage"
 	"k8s.io/client-go/tools/clientcmd"
@@ -56,17 +56,18 @@ func Start(opts bootstrap.Options, store storage.Interface, stop <-chan struct{}
 }
 
 func runController(name string, controller Controller, stop <-chan struct{}) {
+	logger := log.With(log.Component("upstream-discovery"), log.String("source", name))
 	go func(stop <-chan struct{}) {
 		for {
 			select {
 			case err := <-controller.Error():
-				log.Printf("%s service discovery encountered error: %v", name, err)
+				logger.Warnf("%s service discovery encountered error: %v", name, err)
 			case <-stop:
 				return
 			}
 		}
 	}(stop)
 
-	log.Printf("starting %s service discovery", name)
+	logger.Printf("starting %s service discovery", name)
 	controller.Run(stop)
 }
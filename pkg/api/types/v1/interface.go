@@ -55,3 +55,9 @@ func (item *Role) SetStatus(status *Status) {
 func (item *Role) SetMetadata(meta *Metadata) {
 	item.Metadata = meta
 }
+
+// DeepCopyInto is used by the deepcopy functions of the kubernetes crd types.
+// statuses contain conditions and timestamps, so they cannot be copied by value
+func (in *Status) DeepCopyInto(out *Status) {
+	*out = *proto.Clone(in).(*Status)
+}
@@ -18,10 +18,13 @@ var _ = math.Inf
 type Metadata struct {
 	// ResourceVersion keeps track of the resource version of a config resource. This mechanism is used by [gloo-storage](https://github.com/solo-io/gloo/pkg/storage) to ensure safety with concurrent writes/updates to a resource in storage.
 	ResourceVersion string `protobuf:"bytes,1,opt,name=resource_version,json=resourceVersion,proto3" json:"resource_version,omitempty" testdiff:"ignore"`
-	// Namespace is used for the namespacing of resources. Currently unused by gloo internally.
+	// Namespace is used for the namespacing of resources. Storage clients can list and watch the resources of a single namespace.
+	// Kubernetes storage uses the namespace of the resource, other storage backends keep it as a property of the resource, and require names to be unique across namespaces.
 	Namespace string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
 	// Annotations allow clients to tag resources for special use cases. gloo ignores annotations but preserved them on read/write from/to storage.
 	Annotations map[string]string `protobuf:"bytes,3,rep,name=annotations" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Labels are used to select resources. Storage clients can list and watch the resources that have all the labels of a selector.
+	Labels map[string]string `protobuf:"bytes,4,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (m *Metadata) Reset()                    { *m = Metadata{} }
@@ -50,6 +53,13 @@ func (m *Metadata) GetAnnotations() map[string]string {
 	return nil
 }
 
+func (m *Metadata) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Metadata)(nil), "gloo.api.v1.Metadata")
 }
@@ -86,29 +96,38 @@ func (this *Metadata) Equal(that interface{}) bool {
 			return false
 		}
 	}
+	if len(this.Labels) != len(that1.Labels) {
+		return false
+	}
+	for i := range this.Labels {
+		if this.Labels[i] != that1.Labels[i] {
+			return false
+		}
+	}
 	return true
 }
 
 func init() { proto.RegisterFile("metadata.proto", fileDescriptorMetadata) }
 
 var fileDescriptorMetadata = []byte{
-	// 274 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x90, 0x41, 0x4b, 0xc3, 0x30,
-	0x18, 0x86, 0xc9, 0x8a, 0xe2, 0x52, 0xd0, 0x1a, 0x26, 0x94, 0x21, 0x3a, 0x7a, 0x90, 0x5e, 0x4c,
-	0x98, 0x5e, 0x64, 0x07, 0xd1, 0x81, 0xe0, 0xc5, 0x4b, 0x0f, 0x1e, 0xbc, 0x48, 0xd6, 0x65, 0x31,
-	0xac, 0xcd, 0x17, 0x92, 0xb4, 0xd0, 0x7f, 0xe4, 0xaf, 0xf2, 0xe0, 0x4f, 0x10, 0x7f, 0x80, 0x74,
-	0xb5, 0x6c, 0xec, 0xf6, 0x7e, 0x2f, 0xcf, 0x0b, 0x0f, 0x1f, 0x3e, 0x2e, 0x85, 0xe7, 0x4b, 0xee,
-	0x39, 0x35, 0x16, 0x3c, 0x90, 0x50, 0x16, 0x00, 0x94, 0x1b, 0x45, 0xeb, 0xe9, 0x78, 0x24, 0x41,
-	0xc2, 0xa6, 0x67, 0x6d, 0xea, 0x90, 0xe4, 0x17, 0xe1, 0xa3, 0x97, 0xff, 0x15, 0x79, 0xc0, 0x91,
-	0x15, 0x0e, 0x2a, 0x9b, 0x8b, 0xf7, 0x5a, 0x58, 0xa7, 0x40, 0xc7, 0x68, 0x82, 0xd2, 0xe1, 0xfc,
-	0xec, 0xe7, 0xeb, 0xf2, 0xd4, 0x0b, 0xe7, 0x97, 0x6a, 0xb5, 0x9a, 0x25, 0x4a, 0x6a, 0xb0, 0x22,
-	0xc9, 0x4e, 0x7a, 0xfc, 0xb5, 0xa3, 0xc9, 0x39, 0x1e, 0x6a, 0x5e, 0x0a, 0x67, 0x78, 0x2e, 0xe2,
-	0x41, 0x3b, 0xcd, 0xb6, 0x05, 0x79, 0xc6, 0x21, 0xd7, 0x1a, 0x3c, 0xf7, 0x0a, 0xb4, 0x8b, 0x83,
-	0x49, 0x90, 0x86, 0x37, 0x57, 0x74, 0xc7, 0x92, 0xf6, 0x2e, 0xf4, 0x71, 0x0b, 0x3e, 0x69, 0x6f,
-	0x9b, 0x6c, 0x77, 0x3a, 0xbe, 0xc7, 0xd1, 0x3e, 0x40, 0x22, 0x1c, 0xac, 0x45, 0xd3, 0x09, 0x67,
-	0x6d, 0x24, 0x23, 0x7c, 0x50, 0xf3, 0xa2, 0xea, 0x4d, 0xba, 0x63, 0x36, 0xb8, 0x43, 0x73, 0xfa,
-	0xf9, 0x7d, 0x81, 0xde, 0x52, 0xa9, 0xfc, 0x47, 0xb5, 0xa0, 0x39, 0x94, 0xcc, 0x41, 0x01, 0xd7,
-	0x0a, 0x58, 0x2b, 0xc3, 0xcc, 0x5a, 0x32, 0x6e, 0x14, 0xf3, 0x8d, 0x11, 0x8e, 0xd5, 0xd3, 0xc5,
-	0xe1, 0xe6, 0x5b, 0xb7, 0x7f, 0x01, 0x00, 0x00, 0xff, 0xff, 0xc3, 0x7b, 0xb4, 0x2e, 0x62, 0x01,
-	0x00, 0x00,
+	// 304 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x95, 0x91, 0xbd, 0x4a, 0xc4, 0x40,
+	0x14, 0x85, 0x49, 0xa2, 0x8b, 0x3b, 0x01, 0x8d, 0xc3, 0x0a, 0x21, 0x88, 0xae, 0x29, 0x24, 0x8d,
+	0x33, 0xac, 0x36, 0xee, 0x16, 0xa2, 0x0b, 0x82, 0x85, 0x36, 0x29, 0x2c, 0x6c, 0x64, 0x92, 0x9d,
+	0x8d, 0xc3, 0x66, 0x73, 0x43, 0x66, 0x12, 0xc8, 0x1b, 0xf9, 0x54, 0x16, 0x96, 0x96, 0x3e, 0x81,
+	0xf9, 0xd9, 0xb0, 0x41, 0x6c, 0xec, 0xee, 0xbd, 0x7c, 0xe7, 0x70, 0x0e, 0x17, 0xed, 0xaf, 0xb9,
+	0x62, 0x0b, 0xa6, 0x18, 0x49, 0x33, 0x50, 0x80, 0xcd, 0x28, 0x06, 0x20, 0x2c, 0x15, 0xa4, 0x98,
+	0x38, 0xa3, 0x08, 0x22, 0x68, 0xee, 0xb4, 0x9e, 0x5a, 0xc4, 0xfd, 0xd2, 0xd1, 0xde, 0xd3, 0x46,
+	0x85, 0x6f, 0x91, 0x95, 0x71, 0x09, 0x79, 0x16, 0xf2, 0xd7, 0x82, 0x67, 0x52, 0x40, 0x62, 0x6b,
+	0x63, 0xcd, 0x1b, 0xce, 0x8f, 0xbe, 0x3f, 0x4e, 0x0f, 0x15, 0x97, 0x6a, 0x21, 0x96, 0xcb, 0x99,
+	0x2b, 0xa2, 0x04, 0x32, 0xee, 0xfa, 0x07, 0x1d, 0xfe, 0xdc, 0xd2, 0xf8, 0x18, 0x0d, 0x13, 0xb6,
+	0xe6, 0x32, 0x65, 0x21, 0xb7, 0xf5, 0x5a, 0xea, 0x6f, 0x0f, 0xf8, 0x01, 0x99, 0x2c, 0x49, 0x40,
+	0x31, 0x55, 0xb1, 0xd2, 0x36, 0xc6, 0x86, 0x67, 0x5e, 0x9e, 0x93, 0x5e, 0x4a, 0xd2, 0x65, 0x21,
+	0x77, 0x5b, 0xf0, 0x3e, 0x51, 0x59, 0xe9, 0xf7, 0xa5, 0x78, 0x8a, 0x06, 0x31, 0x0b, 0x78, 0x2c,
+	0xed, 0x9d, 0xc6, 0xe4, 0xec, 0x6f, 0x93, 0xc7, 0x86, 0x69, 0xf5, 0x1b, 0x81, 0x73, 0x83, 0xac,
+	0xdf, 0xde, 0xd8, 0x42, 0xc6, 0x8a, 0x97, 0x6d, 0x57, 0xbf, 0x1e, 0xf1, 0x08, 0xed, 0x16, 0x2c,
+	0xce, 0xbb, 0x12, 0xed, 0x32, 0xd3, 0xaf, 0x35, 0x67, 0x8a, 0xcc, 0x9e, 0xed, 0x7f, 0xa4, 0x73,
+	0xf2, 0xfe, 0x79, 0xa2, 0xbd, 0x78, 0x91, 0x50, 0x6f, 0x79, 0x40, 0x42, 0x58, 0x53, 0x09, 0x31,
+	0x5c, 0x88, 0xea, 0x23, 0x55, 0x7a, 0x9a, 0xae, 0x22, 0x5a, 0x35, 0xa0, 0xaa, 0x4c, 0xb9, 0xa4,
+	0xc5, 0x24, 0x18, 0x34, 0x3f, 0xba, 0xfa, 0x01, 0xff, 0x5c, 0x11, 0xab, 0xd8, 0x01, 0x00, 0x00,
 }
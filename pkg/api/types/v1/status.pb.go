@@ -6,6 +6,7 @@ package v1
 import proto "github.com/gogo/protobuf/proto"
 import fmt "fmt"
 import math "math"
+import google_protobuf "github.com/gogo/protobuf/types"
 import _ "github.com/gogo/protobuf/gogoproto"
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -23,17 +24,22 @@ const (
 	// Rejected indicates an invalid configuration by the user
 	// Rejected resources may be propagated to the xDS server depending on their severity
 	Status_Rejected Status_State = 2
+	// PartiallyApplied indicates the resource was accepted by gloo, but rejected by some of the
+	// envoy proxies it was sent to
+	Status_PartiallyApplied Status_State = 3
 )
 
 var Status_State_name = map[int32]string{
 	0: "Pending",
 	1: "Accepted",
 	2: "Rejected",
+	3: "PartiallyApplied",
 }
 var Status_State_value = map[string]int32{
-	"Pending":  0,
-	"Accepted": 1,
-	"Rejected": 2,
+	"Pending":          0,
+	"Accepted":         1,
+	"Rejected":         2,
+	"PartiallyApplied": 3,
 }
 
 func (x Status_State) String() string {
@@ -41,6 +47,29 @@ func (x Status_State) String() string {
 }
 func (Status_State) EnumDescriptor() ([]byte, []int) { return fileDescriptorStatus, []int{0, 0} }
 
+type Condition_Severity int32
+
+const (
+	// Error conditions cause the resource to be rejected
+	Condition_Error Condition_Severity = 0
+	// Warning conditions point out likely mistakes, but do not cause the resource to be rejected
+	Condition_Warning Condition_Severity = 1
+)
+
+var Condition_Severity_name = map[int32]string{
+	0: "Error",
+	1: "Warning",
+}
+var Condition_Severity_value = map[string]int32{
+	"Error":   0,
+	"Warning": 1,
+}
+
+func (x Condition_Severity) String() string {
+	return proto.EnumName(Condition_Severity_name, int32(x))
+}
+func (Condition_Severity) EnumDescriptor() ([]byte, []int) { return fileDescriptorStatus, []int{1, 0} }
+
 // *
 // Status indicates whether a config resource (currently only [virtualservices](../introduction/concepts.md) and [upstreams](../introduction/concepts.md)) has been (in)validated by gloo
 type Status struct {
@@ -48,6 +77,17 @@ type Status struct {
 	State Status_State `protobuf:"varint,1,opt,name=state,proto3,enum=gloo.api.v1.Status_State" json:"state,omitempty"`
 	// Reason is a description of the error for Rejected resources. If the resource is pending or accepted, this field will be empty
 	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Conditions are the individual errors and warnings found for the resource.
+	// Errors cause the resource to be rejected, warnings do not
+	Conditions []*Condition `protobuf:"bytes,3,rep,name=conditions" json:"conditions,omitempty"`
+	// ObservedResourceVersion is the resource version of the resource this status was reported for
+	ObservedResourceVersion string `protobuf:"bytes,4,opt,name=observed_resource_version,json=observedResourceVersion,proto3" json:"observed_resource_version,omitempty"`
+	// LastTransitionTime is the last time the state of the resource changed
+	LastTransitionTime *google_protobuf.Timestamp `protobuf:"bytes,5,opt,name=last_transition_time,json=lastTransitionTime" json:"last_transition_time,omitempty"`
+	// AcceptedRoles are the [roles](../introduction/concepts.md) the resource was translated for without errors
+	AcceptedRoles []string `protobuf:"bytes,6,rep,name=accepted_roles,json=acceptedRoles" json:"accepted_roles,omitempty"`
+	// Certificates are the tls certificates served for the resource, read from the secret of a virtual service's ssl_config
+	Certificates []*Certificate `protobuf:"bytes,7,rep,name=certificates" json:"certificates,omitempty"`
 }
 
 func (m *Status) Reset()                    { *m = Status{} }
@@ -69,9 +109,150 @@ func (m *Status) GetReason() string {
 	return ""
 }
 
+func (m *Status) GetConditions() []*Condition {
+	if m != nil {
+		return m.Conditions
+	}
+	return nil
+}
+
+func (m *Status) GetObservedResourceVersion() string {
+	if m != nil {
+		return m.ObservedResourceVersion
+	}
+	return ""
+}
+
+func (m *Status) GetLastTransitionTime() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.LastTransitionTime
+	}
+	return nil
+}
+
+func (m *Status) GetAcceptedRoles() []string {
+	if m != nil {
+		return m.AcceptedRoles
+	}
+	return nil
+}
+
+func (m *Status) GetCertificates() []*Certificate {
+	if m != nil {
+		return m.Certificates
+	}
+	return nil
+}
+
+// *
+// Condition is a single error or warning reported for a resource
+type Condition struct {
+	// Severity of the condition
+	Severity Condition_Severity `protobuf:"varint,1,opt,name=severity,proto3,enum=gloo.api.v1.Condition_Severity" json:"severity,omitempty"`
+	// Message describing the condition
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Route is set for conditions that only apply to a single route of a virtual service
+	Route *RouteRef `protobuf:"bytes,3,opt,name=route" json:"route,omitempty"`
+}
+
+func (m *Condition) Reset()                    { *m = Condition{} }
+func (m *Condition) String() string            { return proto.CompactTextString(m) }
+func (*Condition) ProtoMessage()               {}
+func (*Condition) Descriptor() ([]byte, []int) { return fileDescriptorStatus, []int{1} }
+
+func (m *Condition) GetSeverity() Condition_Severity {
+	if m != nil {
+		return m.Severity
+	}
+	return Condition_Error
+}
+
+func (m *Condition) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *Condition) GetRoute() *RouteRef {
+	if m != nil {
+		return m.Route
+	}
+	return nil
+}
+
+// *
+// RouteRef identifies a route of a virtual service
+type RouteRef struct {
+	// Index of the route in the virtual service's list of routes
+	Index uint32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+}
+
+func (m *RouteRef) Reset()                    { *m = RouteRef{} }
+func (m *RouteRef) String() string            { return proto.CompactTextString(m) }
+func (*RouteRef) ProtoMessage()               {}
+func (*RouteRef) Descriptor() ([]byte, []int) { return fileDescriptorStatus, []int{2} }
+
+func (m *RouteRef) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+// *
+// Certificate describes a tls certificate served for a resource
+type Certificate struct {
+	// SecretRef is the secret the certificate was read from
+	SecretRef string `protobuf:"bytes,1,opt,name=secret_ref,json=secretRef,proto3" json:"secret_ref,omitempty"`
+	// Subject is the distinguished name of the certificate
+	Subject string `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	// DnsNames are the DNS subject alternative names the certificate is valid for
+	DnsNames []string `protobuf:"bytes,3,rep,name=dns_names,json=dnsNames" json:"dns_names,omitempty"`
+	// NotAfter is the time the certificate expires
+	NotAfter *google_protobuf.Timestamp `protobuf:"bytes,4,opt,name=not_after,json=notAfter" json:"not_after,omitempty"`
+}
+
+func (m *Certificate) Reset()                    { *m = Certificate{} }
+func (m *Certificate) String() string            { return proto.CompactTextString(m) }
+func (*Certificate) ProtoMessage()               {}
+func (*Certificate) Descriptor() ([]byte, []int) { return fileDescriptorStatus, []int{3} }
+
+func (m *Certificate) GetSecretRef() string {
+	if m != nil {
+		return m.SecretRef
+	}
+	return ""
+}
+
+func (m *Certificate) GetSubject() string {
+	if m != nil {
+		return m.Subject
+	}
+	return ""
+}
+
+func (m *Certificate) GetDnsNames() []string {
+	if m != nil {
+		return m.DnsNames
+	}
+	return nil
+}
+
+func (m *Certificate) GetNotAfter() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.NotAfter
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Status)(nil), "gloo.api.v1.Status")
+	proto.RegisterType((*Condition)(nil), "gloo.api.v1.Condition")
+	proto.RegisterType((*RouteRef)(nil), "gloo.api.v1.RouteRef")
+	proto.RegisterType((*Certificate)(nil), "gloo.api.v1.Certificate")
 	proto.RegisterEnum("gloo.api.v1.Status_State", Status_State_name, Status_State_value)
+	proto.RegisterEnum("gloo.api.v1.Condition_Severity", Condition_Severity_name, Condition_Severity_value)
 }
 func (this *Status) Equal(that interface{}) bool {
 	if that == nil {
@@ -98,25 +279,169 @@ func (this *Status) Equal(that interface{}) bool {
 	if this.Reason != that1.Reason {
 		return false
 	}
+	if len(this.Conditions) != len(that1.Conditions) {
+		return false
+	}
+	for i := range this.Conditions {
+		if !this.Conditions[i].Equal(that1.Conditions[i]) {
+			return false
+		}
+	}
+	if this.ObservedResourceVersion != that1.ObservedResourceVersion {
+		return false
+	}
+	if !this.LastTransitionTime.Equal(that1.LastTransitionTime) {
+		return false
+	}
+	if len(this.AcceptedRoles) != len(that1.AcceptedRoles) {
+		return false
+	}
+	for i := range this.AcceptedRoles {
+		if this.AcceptedRoles[i] != that1.AcceptedRoles[i] {
+			return false
+		}
+	}
+	if len(this.Certificates) != len(that1.Certificates) {
+		return false
+	}
+	for i := range this.Certificates {
+		if !this.Certificates[i].Equal(that1.Certificates[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *Condition) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Condition)
+	if !ok {
+		that2, ok := that.(Condition)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Severity != that1.Severity {
+		return false
+	}
+	if this.Message != that1.Message {
+		return false
+	}
+	if !this.Route.Equal(that1.Route) {
+		return false
+	}
+	return true
+}
+func (this *RouteRef) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*RouteRef)
+	if !ok {
+		that2, ok := that.(RouteRef)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Index != that1.Index {
+		return false
+	}
+	return true
+}
+func (this *Certificate) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Certificate)
+	if !ok {
+		that2, ok := that.(Certificate)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.SecretRef != that1.SecretRef {
+		return false
+	}
+	if this.Subject != that1.Subject {
+		return false
+	}
+	if len(this.DnsNames) != len(that1.DnsNames) {
+		return false
+	}
+	for i := range this.DnsNames {
+		if this.DnsNames[i] != that1.DnsNames[i] {
+			return false
+		}
+	}
+	if !this.NotAfter.Equal(that1.NotAfter) {
+		return false
+	}
 	return true
 }
 
 func init() { proto.RegisterFile("status.proto", fileDescriptorStatus) }
 
 var fileDescriptorStatus = []byte{
-	// 210 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x8e, 0x41, 0x4a, 0xc5, 0x30,
-	0x10, 0x86, 0xcd, 0x83, 0x57, 0x35, 0xaf, 0x48, 0x09, 0x22, 0xd5, 0x85, 0x94, 0xae, 0xba, 0x31,
-	0x63, 0xf5, 0x04, 0x7a, 0x02, 0xa9, 0x3b, 0x77, 0x69, 0x3a, 0xc4, 0x68, 0xed, 0x84, 0x26, 0x2d,
-	0xb8, 0xf6, 0x32, 0x9e, 0xcb, 0x93, 0x48, 0x52, 0x17, 0xae, 0x92, 0x6f, 0xf8, 0x3e, 0xf8, 0x79,
-	0xee, 0x83, 0x0a, 0x8b, 0x97, 0x6e, 0xa6, 0x40, 0xe2, 0x60, 0x46, 0x22, 0xa9, 0x9c, 0x95, 0x6b,
-	0x7b, 0x75, 0x6e, 0xc8, 0x50, 0xba, 0x43, 0xfc, 0x6d, 0x4a, 0xfd, 0xc5, 0x78, 0xf6, 0x9c, 0x1a,
-	0x01, 0x7c, 0x1f, 0x6b, 0x2c, 0x59, 0xc5, 0x9a, 0xb3, 0xbb, 0x4b, 0xf9, 0xaf, 0x96, 0x9b, 0x93,
-	0x1e, 0xec, 0x36, 0x4f, 0x5c, 0xf0, 0x6c, 0x46, 0xe5, 0x69, 0x2a, 0x77, 0x15, 0x6b, 0x4e, 0xbb,
-	0x3f, 0xaa, 0x6f, 0xf9, 0x3e, 0x79, 0xe2, 0xc0, 0x8f, 0x9f, 0x70, 0x1a, 0xec, 0x64, 0x8a, 0x23,
-	0x91, 0xf3, 0x93, 0x07, 0xad, 0xd1, 0x05, 0x1c, 0x0a, 0x16, 0xa9, 0xc3, 0x37, 0xd4, 0x91, 0x76,
-	0x8f, 0xf2, 0xfb, 0xe7, 0x9a, 0xbd, 0x34, 0xc6, 0x86, 0xd7, 0xa5, 0x97, 0x9a, 0x3e, 0xc0, 0xd3,
-	0x48, 0x37, 0x96, 0x20, 0x6e, 0x00, 0xf7, 0x6e, 0x40, 0x39, 0x0b, 0xe1, 0xd3, 0xa1, 0x87, 0xb5,
-	0xed, 0xb3, 0x34, 0xfe, 0xfe, 0x37, 0x00, 0x00, 0xff, 0xff, 0xa3, 0xca, 0x59, 0x73, 0xef, 0x00,
-	0x00, 0x00,
+	// 563 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7d, 0x53, 0xd1, 0x8a, 0xd3, 0x40,
+	0x14, 0x35, 0x5b, 0xd3, 0x36, 0xb7, 0xdd, 0xa5, 0x0c, 0x75, 0xcd, 0xae, 0xe8, 0x96, 0x80, 0x50,
+	0x10, 0x13, 0xb6, 0x82, 0x82, 0xfa, 0x52, 0x45, 0x9f, 0x44, 0x96, 0xd9, 0x45, 0xc1, 0x97, 0x30,
+	0x4d, 0xa7, 0x31, 0x9a, 0x66, 0xc2, 0xcc, 0xa4, 0xd8, 0x7f, 0xf1, 0x03, 0x7c, 0xf7, 0x07, 0xfc,
+	0x16, 0xbf, 0xc4, 0x3b, 0x93, 0xa4, 0xb6, 0x20, 0x3e, 0x65, 0xee, 0xbd, 0xe7, 0x9e, 0xb9, 0xe7,
+	0xcc, 0x0d, 0x0c, 0x95, 0x66, 0xba, 0x52, 0x61, 0x29, 0x85, 0x16, 0x64, 0x90, 0xe6, 0x42, 0x84,
+	0xac, 0xcc, 0xc2, 0xcd, 0xe5, 0xf9, 0x45, 0x2a, 0x44, 0x9a, 0xf3, 0xc8, 0x96, 0x16, 0xd5, 0x2a,
+	0xd2, 0xd9, 0x9a, 0x23, 0x7c, 0x5d, 0xd6, 0xe8, 0xf3, 0x71, 0x2a, 0x52, 0x61, 0x8f, 0x91, 0x39,
+	0xd5, 0xd9, 0xe0, 0x57, 0x07, 0xba, 0xd7, 0x96, 0x94, 0x44, 0xe0, 0x1a, 0x7a, 0xee, 0x3b, 0x13,
+	0x67, 0x7a, 0x32, 0x3b, 0x0b, 0xf7, 0xe8, 0xc3, 0x1a, 0x63, 0x3f, 0x9c, 0xd6, 0x38, 0x72, 0x0a,
+	0x5d, 0xc9, 0x99, 0x12, 0x85, 0x7f, 0x84, 0x1d, 0x1e, 0x6d, 0x22, 0xf2, 0x14, 0x20, 0x11, 0xc5,
+	0x32, 0xd3, 0x99, 0x28, 0x94, 0xdf, 0x99, 0x74, 0xa6, 0x83, 0xd9, 0xe9, 0x01, 0xdb, 0xeb, 0xb6,
+	0x4c, 0xf7, 0x90, 0xe4, 0x39, 0x9c, 0x89, 0x85, 0xe2, 0x72, 0xc3, 0x97, 0xb1, 0xe4, 0x4a, 0x54,
+	0x32, 0xe1, 0xf1, 0x86, 0x4b, 0x85, 0x55, 0xff, 0xb6, 0xbd, 0xe2, 0x6e, 0x0b, 0xa0, 0x4d, 0xfd,
+	0x43, 0x5d, 0x26, 0xef, 0x60, 0x9c, 0x33, 0xa5, 0x63, 0x2d, 0x59, 0xa1, 0x2c, 0x5f, 0x6c, 0x0c,
+	0xf0, 0x5d, 0x6c, 0x1b, 0xcc, 0xce, 0xc3, 0xda, 0x9d, 0xb0, 0x75, 0x27, 0xbc, 0x69, 0xdd, 0xa1,
+	0xc4, 0xf4, 0xdd, 0xec, 0xda, 0x4c, 0x81, 0x3c, 0x84, 0x13, 0x96, 0x24, 0xbc, 0xd4, 0x66, 0x12,
+	0x91, 0x73, 0xe5, 0x77, 0x51, 0x85, 0x47, 0x8f, 0xdb, 0x2c, 0x35, 0x49, 0xf2, 0x12, 0x86, 0x09,
+	0x97, 0x3a, 0x5b, 0x65, 0x09, 0xfa, 0xa1, 0xfc, 0x9e, 0x95, 0xea, 0x1f, 0x4a, 0xfd, 0x0b, 0xa0,
+	0x07, 0xe8, 0xe0, 0x2d, 0xb8, 0xd6, 0x4e, 0x32, 0x80, 0xde, 0x15, 0x47, 0x17, 0x8a, 0x74, 0x74,
+	0x8b, 0x0c, 0xa1, 0x3f, 0x6f, 0x2e, 0x19, 0x39, 0x26, 0xa2, 0xfc, 0x0b, 0x4f, 0x4c, 0x74, 0x44,
+	0xc6, 0x30, 0xba, 0x62, 0xc8, 0xc0, 0xf2, 0x7c, 0x3b, 0x2f, 0xcb, 0x3c, 0xc3, 0x6c, 0x27, 0xf8,
+	0xe9, 0x80, 0xb7, 0x33, 0x94, 0xbc, 0x80, 0xbe, 0xe2, 0x68, 0x5a, 0xa6, 0xb7, 0xcd, 0x43, 0x5e,
+	0xfc, 0xdb, 0xfa, 0xf0, 0xba, 0x81, 0xd1, 0x5d, 0x03, 0xf1, 0xa1, 0x87, 0xb6, 0x28, 0x96, 0xf2,
+	0xe6, 0x49, 0xdb, 0x90, 0x3c, 0x02, 0x57, 0x8a, 0x0a, 0x97, 0xa3, 0x63, 0x0d, 0xbd, 0x73, 0xc0,
+	0x49, 0x4d, 0x85, 0xf2, 0x15, 0xad, 0x31, 0x41, 0x00, 0xfd, 0x96, 0x9c, 0x78, 0xe0, 0xbe, 0x91,
+	0x52, 0x48, 0x94, 0x86, 0x3a, 0x3f, 0x32, 0x59, 0x18, 0x9d, 0x4e, 0x30, 0x41, 0x65, 0x4d, 0x1b,
+	0xea, 0x72, 0xb3, 0x62, 0xc9, 0xbf, 0xd9, 0x81, 0x8f, 0x69, 0x1d, 0x04, 0xdf, 0x1d, 0x18, 0xec,
+	0xb9, 0x47, 0xee, 0x03, 0x28, 0x9e, 0x48, 0xae, 0x71, 0x39, 0x56, 0x16, 0xea, 0x51, 0xaf, 0xce,
+	0x18, 0x12, 0x9c, 0x5d, 0x55, 0x0b, 0xe3, 0x55, 0x3b, 0x7b, 0x13, 0x92, 0x7b, 0xe0, 0x2d, 0x0b,
+	0x15, 0x17, 0x0c, 0xc5, 0xd8, 0x75, 0xf4, 0x68, 0x1f, 0x13, 0xef, 0x4d, 0x4c, 0x9e, 0x81, 0x57,
+	0x08, 0x1d, 0xb3, 0x95, 0xe6, 0xd2, 0x2e, 0xd9, 0xff, 0xb7, 0xa5, 0x8f, 0xe0, 0xb9, 0xc1, 0xbe,
+	0x0a, 0x7f, 0xfc, 0x7e, 0xe0, 0x7c, 0x9a, 0xa6, 0x99, 0xfe, 0x5c, 0x2d, 0xc2, 0x44, 0xac, 0x23,
+	0x25, 0x72, 0xf1, 0x38, 0xc3, 0xdf, 0x0b, 0xad, 0x89, 0xca, 0xaf, 0x69, 0x84, 0xf6, 0x44, 0x7a,
+	0x5b, 0x72, 0x15, 0x6d, 0x2e, 0x17, 0x5d, 0xcb, 0xf6, 0xe4, 0x0f, 0x98, 0xd8, 0xfb, 0xcb, 0xc4,
+	0x03, 0x00, 0x00,
 }
@@ -656,12 +656,25 @@ func (m *UpstreamDestination) GetName() string {
 
 // SSLConfig contains the options necessary to configure a virtualservice to use TLS
 type SSLConfig struct {
-	// * SecretRef contains the secret ref<!--(TODO)--> to a gloo secret<!--(TODO)--> containing the following structure:
+	// * SecretRef contains the secret ref<!--(TODO)--> to a gloo secret<!--(TODO)--> containing the certificate chain
+	// and private key in one of the following structures:
+	// {
+	// "tls.crt": <certificate chain data...>,
+	// "tls.key": <private key data...>
+	// }
+	// (the format of kubernetes tls secrets),
 	// {
 	// "ca_chain": <ca chain data...>,
-	// "private key": <private key data...>
+	// "private_key": <private key data...>
 	// }
+	// or a single key containing a PEM bundle of the certificate chain and the private key
 	SecretRef string `protobuf:"bytes,1,opt,name=secret_ref,json=secretRef,proto3" json:"secret_ref,omitempty"`
+	// * AutoCertificate makes gloo issue the certificate for the domains of the virtual service with ACME,
+	// and renew it before it expires. gloo answers the HTTP-01 challenges on the envoy HTTP listener,
+	// and stores the certificate in the secret referenced by secret_ref as tls.crt and tls.key.
+	// Requires gloo to be started with an ACME directory url (--acme.directory-url).
+	// Wildcard domains can not be issued with HTTP-01.
+	AutoCertificate bool `protobuf:"varint,2,opt,name=auto_certificate,json=autoCertificate,proto3" json:"auto_certificate,omitempty"`
 }
 
 func (m *SSLConfig) Reset()                    { *m = SSLConfig{} }
@@ -676,6 +689,13 @@ func (m *SSLConfig) GetSecretRef() string {
 	return ""
 }
 
+func (m *SSLConfig) GetAutoCertificate() bool {
+	if m != nil {
+		return m.AutoCertificate
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*VirtualService)(nil), "gloo.api.v1.VirtualService")
 	proto.RegisterType((*Route)(nil), "gloo.api.v1.Route")
@@ -1167,65 +1187,69 @@ func (this *SSLConfig) Equal(that interface{}) bool {
 	if this.SecretRef != that1.SecretRef {
 		return false
 	}
+	if this.AutoCertificate != that1.AutoCertificate {
+		return false
+	}
 	return true
 }
 
 func init() { proto.RegisterFile("virtualservice.proto", fileDescriptorVirtualservice) }
 
 var fileDescriptorVirtualservice = []byte{
-	// 854 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x55, 0xdd, 0x6e, 0xdc, 0x44,
-	0x14, 0xce, 0x66, 0x37, 0x9b, 0xee, 0xd9, 0x1f, 0xd2, 0x49, 0x52, 0x4c, 0xf8, 0xc9, 0xe2, 0x0a,
-	0x69, 0xa9, 0xa8, 0xad, 0x04, 0x21, 0x50, 0x2e, 0xaa, 0x6a, 0x21, 0x25, 0x17, 0x14, 0xca, 0x84,
-	0x82, 0xc4, 0x8d, 0x35, 0xd9, 0x3d, 0xeb, 0x1d, 0xd5, 0xf6, 0x38, 0x33, 0xe3, 0x6d, 0xf6, 0x5d,
-	0xb8, 0x41, 0xe2, 0x82, 0x2b, 0x9e, 0x85, 0x27, 0xe8, 0x05, 0x8f, 0xc0, 0x13, 0xa0, 0x99, 0xb1,
-	0x13, 0xbb, 0x8a, 0x22, 0x71, 0x37, 0xe7, 0x9b, 0xef, 0xfb, 0xce, 0xf8, 0x9c, 0x33, 0x63, 0xd8,
-	0x5b, 0x71, 0xa9, 0x0b, 0x96, 0x28, 0x94, 0x2b, 0x3e, 0xc3, 0x20, 0x97, 0x42, 0x0b, 0xd2, 0x8f,
-	0x13, 0x21, 0x02, 0x96, 0xf3, 0x60, 0x75, 0x74, 0xf0, 0x41, 0x2c, 0x44, 0x9c, 0x60, 0x68, 0xb7,
-	0x2e, 0x8a, 0x45, 0xa8, 0xb4, 0x2c, 0x66, 0xda, 0x51, 0x0f, 0xf6, 0x62, 0x11, 0x0b, 0xbb, 0x0c,
-	0xcd, 0xaa, 0x44, 0x07, 0x4a, 0x33, 0x5d, 0xa8, 0x32, 0x1a, 0xa5, 0xa8, 0xd9, 0x9c, 0x69, 0xe6,
-	0x62, 0xff, 0xaf, 0x4d, 0x18, 0xfd, 0xec, 0xf2, 0x9e, 0xbb, 0xbc, 0x84, 0x40, 0x27, 0x63, 0x29,
-	0x7a, 0xad, 0x71, 0x6b, 0xd2, 0xa3, 0x76, 0x4d, 0x3c, 0xd8, 0x9e, 0x8b, 0x94, 0xf1, 0x4c, 0x79,
-	0x9b, 0xe3, 0xf6, 0xa4, 0x47, 0xab, 0x90, 0x3c, 0x82, 0xae, 0x14, 0x85, 0x46, 0xe5, 0xb5, 0xc7,
-	0xed, 0x49, 0xff, 0x98, 0x04, 0xb5, 0x03, 0x07, 0xd4, 0x6c, 0xd1, 0x92, 0x41, 0xbe, 0x00, 0x50,
-	0x2a, 0x89, 0x66, 0x22, 0x5b, 0xf0, 0xd8, 0xeb, 0x8c, 0x5b, 0x93, 0xfe, 0xf1, 0x83, 0x06, 0xff,
-	0xfc, 0xfc, 0xbb, 0xaf, 0xed, 0x2e, 0xed, 0x29, 0x95, 0xb8, 0x25, 0x99, 0x42, 0xd7, 0x7d, 0x83,
-	0xb7, 0x65, 0x25, 0xbb, 0x4d, 0x89, 0xdd, 0x9a, 0xee, 0xff, 0xfb, 0xe6, 0xf0, 0xbe, 0x46, 0xa5,
-	0xe7, 0x7c, 0xb1, 0x38, 0xf1, 0x79, 0x9c, 0x09, 0x89, 0x3e, 0x2d, 0x95, 0x64, 0x0f, 0xb6, 0xa4,
-	0x48, 0x50, 0x79, 0xdb, 0xf6, 0xf8, 0x2e, 0x20, 0x47, 0x70, 0xaf, 0xaa, 0x87, 0xd7, 0xb5, 0xde,
-	0xfb, 0x0d, 0xef, 0xe7, 0xe5, 0x26, 0xbd, 0xa6, 0xf9, 0xbf, 0xb5, 0x61, 0xcb, 0x7e, 0x15, 0x79,
-	0x06, 0xef, 0x48, 0xbc, 0x2c, 0x50, 0xe9, 0x28, 0x65, 0x7a, 0xb6, 0x44, 0x69, 0x4b, 0xd6, 0x3f,
-	0x7e, 0xbf, 0x59, 0x02, 0xc7, 0x79, 0xee, 0x28, 0x67, 0x1b, 0x74, 0x24, 0x1b, 0x08, 0x79, 0x0a,
-	0x43, 0x5c, 0x61, 0x76, 0xe3, 0xb2, 0x69, 0x5d, 0xde, 0x6b, 0xb8, 0x9c, 0x1a, 0xc6, 0x8d, 0xc7,
-	0x00, 0x6b, 0x31, 0x79, 0x09, 0xfb, 0x69, 0x91, 0x68, 0x9e, 0x27, 0x18, 0xcd, 0x51, 0x69, 0x9e,
-	0x31, 0xcd, 0x45, 0x56, 0xb5, 0x64, 0xdc, 0x70, 0xfa, 0x05, 0x79, 0xbc, 0xd4, 0x38, 0xff, 0xe6,
-	0x86, 0x48, 0xf7, 0x2a, 0x79, 0x0d, 0x54, 0xe4, 0x5b, 0x20, 0x8a, 0x67, 0x71, 0xd3, 0xb4, 0x6c,
-	0x9b, 0xd7, 0xf0, 0xac, 0x7b, 0xdd, 0x77, 0x9a, 0x1a, 0x44, 0x3e, 0x81, 0x51, 0x2e, 0x71, 0xc1,
-	0xaf, 0x22, 0x89, 0xaf, 0x25, 0xd7, 0x68, 0x1b, 0xd9, 0xa3, 0x43, 0x87, 0x52, 0x07, 0x92, 0x2f,
-	0x01, 0xf0, 0x4a, 0x63, 0xa6, 0xec, 0xd9, 0x5d, 0x3f, 0xde, 0x0d, 0xdc, 0xc8, 0x07, 0xd5, 0xc8,
-	0x07, 0xe7, 0x76, 0xe4, 0x69, 0x8d, 0x3a, 0xed, 0xc1, 0x76, 0x59, 0x3b, 0xff, 0x8f, 0x36, 0x8c,
-	0x9a, 0x15, 0x27, 0x1f, 0x43, 0x3f, 0x67, 0x7a, 0x19, 0xb9, 0x64, 0x6e, 0xac, 0xcf, 0x36, 0x28,
-	0x18, 0xf0, 0x85, 0xc5, 0xc8, 0x21, 0xd8, 0x28, 0x92, 0x18, 0xe3, 0x95, 0xad, 0xbf, 0x61, 0xf4,
-	0x0c, 0x46, 0x0d, 0x74, 0x4d, 0xc0, 0x2b, 0x36, 0xd3, 0x5e, 0xbb, 0x4e, 0x38, 0x35, 0x10, 0x99,
-	0xc2, 0xf6, 0x12, 0xd9, 0x1c, 0xa5, 0xf2, 0x3a, 0xb6, 0xe8, 0x93, 0x3b, 0x86, 0x20, 0x38, 0x73,
-	0xd4, 0xd3, 0x4c, 0xcb, 0x35, 0xad, 0x84, 0xe4, 0x07, 0x18, 0x5c, 0x16, 0x28, 0xd7, 0x51, 0xce,
-	0x24, 0x4b, 0xcd, 0xb4, 0x1b, 0xa3, 0xcf, 0xee, 0x32, 0xfa, 0xd1, 0xf0, 0x5f, 0x58, 0xba, 0x33,
-	0xeb, 0x5f, 0xde, 0x20, 0x66, 0xe8, 0x57, 0x28, 0x2f, 0x4c, 0x2d, 0xed, 0xd0, 0xdb, 0xe0, 0xe0,
-	0x04, 0x06, 0xf5, 0xfc, 0x64, 0x07, 0xda, 0xaf, 0x70, 0x5d, 0x5e, 0x77, 0xb3, 0xb4, 0x3a, 0x96,
-	0x14, 0xe8, 0x2a, 0x41, 0x5d, 0x70, 0xb2, 0xf9, 0x55, 0xeb, 0xe0, 0x09, 0xec, 0xbc, 0x9d, 0xf2,
-	0xff, 0xe8, 0xa7, 0x5d, 0xe8, 0x98, 0x9a, 0xf9, 0x8f, 0x61, 0x50, 0x9f, 0x68, 0xf2, 0x21, 0x80,
-	0xbb, 0x03, 0x7a, 0x9d, 0x57, 0x2f, 0x4f, 0xcf, 0x22, 0x3f, 0xad, 0x73, 0xf4, 0x05, 0xec, 0xde,
-	0x32, 0xb6, 0xe4, 0x29, 0xf4, 0xeb, 0x93, 0xd9, 0xba, 0x7b, 0x32, 0xa7, 0x9d, 0xbf, 0xdf, 0x1c,
-	0xb6, 0x68, 0x5d, 0x42, 0x1e, 0x40, 0xf7, 0xb5, 0x35, 0xb6, 0x47, 0x1d, 0xd2, 0x32, 0xf2, 0x7f,
-	0x6f, 0x41, 0xbf, 0x9e, 0xe9, 0x09, 0xdc, 0x5b, 0x14, 0xd9, 0xac, 0x96, 0xa6, 0x79, 0xa9, 0x9e,
-	0x95, 0x9b, 0x35, 0xcd, 0xd9, 0x06, 0xbd, 0xd6, 0x18, 0x7d, 0x91, 0x2b, 0x2d, 0x91, 0xa5, 0xe5,
-	0xf5, 0x6e, 0xea, 0x5f, 0x96, 0x9b, 0x6f, 0xe9, 0x2b, 0xcd, 0x94, 0xc0, 0x4e, 0xed, 0xd8, 0xb6,
-	0x4a, 0x7e, 0x04, 0xbb, 0xb7, 0xa4, 0x25, 0x0f, 0x61, 0x58, 0xc9, 0xa2, 0xda, 0x3b, 0x3e, 0xa8,
-	0xc0, 0xef, 0xcd, 0x7b, 0xfe, 0x10, 0x86, 0xd5, 0xd9, 0x1c, 0xc9, 0x75, 0x6a, 0x50, 0x81, 0x86,
-	0xe4, 0x7f, 0x0a, 0xbb, 0xb7, 0x9c, 0xeb, 0xb6, 0xff, 0x83, 0xff, 0x08, 0x7a, 0xd7, 0x4f, 0xb7,
-	0x69, 0xa6, 0xc2, 0x99, 0x44, 0x1d, 0x49, 0x5c, 0x54, 0xcd, 0x74, 0x08, 0xc5, 0xc5, 0x34, 0xf8,
-	0xf3, 0x9f, 0x8f, 0x5a, 0xbf, 0x4e, 0x62, 0xae, 0x97, 0xc5, 0x45, 0x30, 0x13, 0x69, 0xa8, 0x44,
-	0x22, 0x1e, 0x73, 0x11, 0x9a, 0x8a, 0x84, 0xf9, 0xab, 0x38, 0x64, 0x39, 0x0f, 0xcd, 0x37, 0xaa,
-	0x70, 0x75, 0x74, 0xd1, 0xb5, 0x57, 0xff, 0xf3, 0xff, 0x02, 0x00, 0x00, 0xff, 0xff, 0x62, 0xdb,
-	0xe1, 0x58, 0x20, 0x07, 0x00, 0x00,
+	// 865 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x95, 0x55, 0xcb, 0x6e, 0xd3, 0x40,
+	0x14, 0x25, 0x4d, 0x9b, 0x36, 0x37, 0x69, 0x28, 0xd3, 0x16, 0x42, 0x79, 0xb4, 0x18, 0x21, 0xb5,
+	0x08, 0x1c, 0xb5, 0x08, 0x81, 0xba, 0x40, 0x28, 0x50, 0x60, 0xc1, 0xa3, 0x4c, 0x29, 0x48, 0x6c,
+	0xac, 0xa9, 0x33, 0x76, 0x46, 0x75, 0x3c, 0x66, 0x3c, 0x0e, 0xed, 0xbf, 0xb0, 0x41, 0x62, 0xc1,
+	0x8a, 0x6f, 0xe1, 0x0b, 0x58, 0xf0, 0x09, 0x7c, 0x01, 0xf3, 0xb0, 0x1b, 0x1b, 0x55, 0x95, 0xd8,
+	0xcd, 0x3d, 0xf7, 0xdc, 0x87, 0xcf, 0xbd, 0x33, 0x86, 0xa5, 0x31, 0x13, 0x32, 0x23, 0x51, 0x4a,
+	0xc5, 0x98, 0xf9, 0xd4, 0x4d, 0x04, 0x97, 0x1c, 0xb5, 0xc2, 0x88, 0x73, 0x97, 0x24, 0xcc, 0x1d,
+	0x6f, 0xae, 0x5c, 0x0d, 0x39, 0x0f, 0x23, 0xda, 0x33, 0xae, 0x83, 0x2c, 0xe8, 0xa5, 0x52, 0x64,
+	0xbe, 0xb4, 0xd4, 0x95, 0xa5, 0x90, 0x87, 0xdc, 0x1c, 0x7b, 0xfa, 0x94, 0xa3, 0xed, 0x54, 0x12,
+	0x99, 0xa5, 0xb9, 0xd5, 0x19, 0x51, 0x49, 0x06, 0x44, 0x12, 0x6b, 0x3b, 0x3f, 0xa6, 0xa0, 0xf3,
+	0xde, 0xd6, 0xdd, 0xb3, 0x75, 0x11, 0x82, 0xe9, 0x98, 0x8c, 0x68, 0xb7, 0xb6, 0x56, 0x5b, 0x6f,
+	0x62, 0x73, 0x46, 0x5d, 0x98, 0x1d, 0xf0, 0x11, 0x61, 0x71, 0xda, 0x9d, 0x5a, 0xab, 0x2b, 0xb8,
+	0x30, 0xd1, 0x6d, 0x68, 0x08, 0x9e, 0x49, 0x9a, 0x76, 0xeb, 0xca, 0xd1, 0xda, 0x42, 0x6e, 0xa9,
+	0x61, 0x17, 0x6b, 0x17, 0xce, 0x19, 0xe8, 0x3e, 0x40, 0x9a, 0x46, 0x9e, 0xcf, 0xe3, 0x80, 0x85,
+	0xdd, 0x69, 0x95, 0xbf, 0xb5, 0x75, 0xb1, 0xc2, 0xdf, 0xdb, 0x7b, 0xf9, 0xc4, 0x78, 0x71, 0x53,
+	0x31, 0xed, 0x11, 0xf5, 0xa1, 0x61, 0xbf, 0xa1, 0x3b, 0x63, 0x42, 0x16, 0xab, 0x21, 0xc6, 0xd5,
+	0x5f, 0xfe, 0xf3, 0x6b, 0xf5, 0x82, 0x2a, 0x22, 0x07, 0x2c, 0x08, 0xb6, 0x1d, 0x16, 0xc6, 0x5c,
+	0x50, 0x07, 0xe7, 0x91, 0x68, 0x09, 0x66, 0x04, 0x8f, 0x54, 0x97, 0xb3, 0xa6, 0x7d, 0x6b, 0xa0,
+	0x4d, 0x98, 0x2b, 0xf4, 0xe8, 0x36, 0x4c, 0xee, 0xe5, 0x4a, 0xee, 0x57, 0xb9, 0x13, 0x9f, 0xd0,
+	0x9c, 0x2f, 0x75, 0x98, 0x31, 0x5f, 0x85, 0x9e, 0xc1, 0x79, 0x41, 0x3f, 0x65, 0xaa, 0xa4, 0x37,
+	0x22, 0xd2, 0x1f, 0x52, 0x61, 0x24, 0x6b, 0x6d, 0x5d, 0xa9, 0x4a, 0x60, 0x39, 0xaf, 0x2c, 0xe5,
+	0xc5, 0x39, 0xdc, 0x11, 0x15, 0x04, 0x3d, 0x86, 0x79, 0x3a, 0xa6, 0xf1, 0x24, 0xcb, 0x94, 0xc9,
+	0x72, 0xb9, 0x92, 0x65, 0x47, 0x33, 0x26, 0x39, 0xda, 0xb4, 0x64, 0xa3, 0x7d, 0x58, 0x1e, 0x65,
+	0x91, 0x64, 0x49, 0x44, 0xbd, 0x81, 0xca, 0xcc, 0x62, 0x22, 0x19, 0x8f, 0x8b, 0x91, 0xac, 0x55,
+	0x32, 0x7d, 0xa0, 0x2c, 0x1c, 0x4a, 0x3a, 0x78, 0x3a, 0x21, 0xe2, 0xa5, 0x22, 0xbc, 0x04, 0xa6,
+	0xe8, 0x39, 0xa0, 0x94, 0xc5, 0x61, 0x35, 0x69, 0x3e, 0xb6, 0x6e, 0x25, 0x67, 0x39, 0xd7, 0x05,
+	0x1b, 0x53, 0x82, 0xd0, 0x2d, 0xe8, 0x24, 0x82, 0x06, 0xec, 0xc8, 0x13, 0xf4, 0xb3, 0x60, 0x92,
+	0x9a, 0x41, 0x36, 0xf1, 0xbc, 0x45, 0xb1, 0x05, 0xd1, 0x03, 0x00, 0x7a, 0x24, 0x69, 0x9c, 0x9a,
+	0xde, 0xed, 0x3c, 0x2e, 0xb9, 0x76, 0xe5, 0xdd, 0x62, 0xe5, 0xd5, 0xbc, 0xf5, 0xca, 0xe3, 0x12,
+	0xb5, 0xdf, 0x84, 0xd9, 0x5c, 0x3b, 0xe7, 0x5b, 0x1d, 0x3a, 0x55, 0xc5, 0xd1, 0x0d, 0x68, 0x25,
+	0x44, 0x0e, 0x3d, 0x5b, 0xcc, 0xae, 0xb5, 0x92, 0x10, 0x34, 0xb8, 0x6b, 0x30, 0xb4, 0x0a, 0xc6,
+	0x52, 0xed, 0x85, 0xf4, 0xc8, 0xe8, 0xaf, 0x19, 0x4d, 0x8d, 0x61, 0x0d, 0x9d, 0x10, 0xe8, 0x11,
+	0xf1, 0xa5, 0x92, 0xb5, 0x44, 0xd8, 0xd1, 0x90, 0xda, 0xd1, 0xd9, 0x21, 0x25, 0x03, 0x2a, 0x52,
+	0x25, 0x90, 0x16, 0x7d, 0xfd, 0x8c, 0x25, 0x70, 0x5f, 0x58, 0xea, 0x4e, 0x2c, 0xc5, 0x31, 0x2e,
+	0x02, 0xd1, 0x1b, 0x68, 0x2b, 0x96, 0x38, 0xf6, 0x12, 0x22, 0xc8, 0x48, 0x6f, 0xbb, 0x4e, 0x74,
+	0xe7, 0xac, 0x44, 0x6f, 0x35, 0x7f, 0xd7, 0xd0, 0x6d, 0xb2, 0xd6, 0xa7, 0x09, 0xa2, 0x97, 0x7e,
+	0x4c, 0xc5, 0x81, 0xd6, 0xd2, 0x2c, 0xbd, 0x31, 0x56, 0xb6, 0xa1, 0x5d, 0xae, 0x8f, 0x16, 0xa0,
+	0x7e, 0x48, 0x8f, 0xf3, 0xeb, 0xae, 0x8f, 0x26, 0x8e, 0x44, 0x19, 0xb5, 0x4a, 0x60, 0x6b, 0x6c,
+	0x4f, 0x3d, 0xac, 0xad, 0x3c, 0x82, 0x85, 0x7f, 0x4b, 0xfe, 0x4f, 0x7c, 0xbf, 0x01, 0xd3, 0x5a,
+	0x33, 0xe7, 0x2e, 0xb4, 0xcb, 0x1b, 0x8d, 0xae, 0xa9, 0xd1, 0x9b, 0x3b, 0x20, 0x8f, 0x93, 0xe2,
+	0xe5, 0x69, 0x1a, 0xe4, 0x9d, 0x02, 0x1c, 0x0e, 0x8b, 0xa7, 0xac, 0xad, 0xba, 0x39, 0xad, 0xf2,
+	0x66, 0xd6, 0xce, 0xde, 0xcc, 0xfe, 0xf4, 0xcf, 0x5f, 0xab, 0x35, 0x5c, 0x0e, 0x41, 0x17, 0xa1,
+	0xf1, 0xd9, 0x24, 0x36, 0xad, 0xce, 0xe3, 0xdc, 0x72, 0xbe, 0xd6, 0xa0, 0x55, 0xae, 0xf4, 0x08,
+	0xe6, 0x82, 0x2c, 0xf6, 0x4b, 0x65, 0xaa, 0x97, 0xea, 0x59, 0xee, 0x2c, 0xc5, 0xa8, 0xfd, 0x38,
+	0x89, 0xd1, 0xf1, 0x59, 0xa2, 0x1e, 0x6b, 0x4a, 0x46, 0xf9, 0xf5, 0xae, 0xc6, 0xef, 0xe7, 0xce,
+	0x7f, 0xe2, 0x8b, 0x98, 0x3e, 0x82, 0x85, 0x52, 0xdb, 0x46, 0x25, 0xc7, 0x83, 0xc5, 0x53, 0xca,
+	0xa2, 0x9b, 0x30, 0x5f, 0x84, 0x79, 0xa5, 0x77, 0xbc, 0x5d, 0x80, 0xaf, 0xf5, 0x7b, 0xae, 0x48,
+	0x45, 0x6f, 0x96, 0x64, 0x27, 0xd5, 0x2e, 0x40, 0x4d, 0x72, 0x36, 0x60, 0xf1, 0x94, 0xbe, 0x4e,
+	0xfb, 0x3f, 0x38, 0xfb, 0xd0, 0x3c, 0x79, 0xba, 0xf5, 0x30, 0x53, 0xea, 0x0b, 0x2a, 0xd5, 0x7d,
+	0x0a, 0x8a, 0x61, 0x5a, 0x04, 0xd3, 0x00, 0x6d, 0xc0, 0x02, 0xc9, 0x24, 0xf7, 0x7c, 0x2a, 0x24,
+	0x0b, 0x98, 0x4f, 0xa4, 0x2d, 0x3f, 0x87, 0xcf, 0x6b, 0xfc, 0xc9, 0x04, 0xee, 0xbb, 0xdf, 0x7f,
+	0x5f, 0xaf, 0x7d, 0x5c, 0x0f, 0x99, 0x1c, 0x66, 0x07, 0xae, 0xcf, 0x47, 0xbd, 0x94, 0x47, 0xfc,
+	0x2e, 0x53, 0x3f, 0x38, 0x25, 0x5e, 0x2f, 0x39, 0x0c, 0x7b, 0x4a, 0xc0, 0x9e, 0x96, 0x23, 0xed,
+	0x8d, 0x37, 0x0f, 0x1a, 0xe6, 0x95, 0xb8, 0xf7, 0x17, 0x39, 0xad, 0x52, 0xc7, 0x4b, 0x07, 0x00,
+	0x00,
 }
@@ -15,12 +15,27 @@ const (
 	backoffCap = 15 * time.Minute
 )
 
+// Options control how often and for how long WithBackoffOptions retries
+type Options struct {
+	// InitialInterval is the wait before the first retry. it doubles after each retry
+	InitialInterval time.Duration
+	// MaxElapsedTime is the total time to wait between retries before returning the last error
+	MaxElapsedTime time.Duration
+}
+
 func WithBackoff(fn func() error, stop chan struct{}) error {
+	return WithBackoffOptions(fn, stop, Options{
+		InitialInterval: defaultInitialInterval,
+		MaxElapsedTime:  defaultMaxElapsedTime,
+	})
+}
+
+func WithBackoffOptions(fn func() error, stop chan struct{}, opts Options) error {
 	// first try
 	if err := fn(); err == nil {
 		return nil
 	}
-	tilNextRetry := defaultInitialInterval
+	tilNextRetry := opts.InitialInterval
 	var elapsed time.Duration
 	for {
 		select {
@@ -34,7 +49,7 @@ func WithBackoff(fn func() error, stop chan struct{}) error {
 			if err == nil {
 				return nil
 			}
-			if elapsed >= defaultMaxElapsedTime {
+			if elapsed >= opts.MaxElapsedTime {
 				return err
 			}
 		}
@@ -1,16 +1,22 @@
 package artifactstorage
 
 import (
+	"github.com/coreos/etcd/clientv3"
 	"github.com/pkg/errors"
 
 	"github.com/solo-io/gloo/pkg/bootstrap"
 	"github.com/solo-io/gloo/pkg/storage/dependencies"
 	consulfiles "github.com/solo-io/gloo/pkg/storage/dependencies/consul"
+	etcdfiles "github.com/solo-io/gloo/pkg/storage/dependencies/etcd"
 	filestorage "github.com/solo-io/gloo/pkg/storage/dependencies/file"
 	"github.com/solo-io/gloo/pkg/storage/dependencies/kube"
+	"github.com/solo-io/gloo/pkg/storage/dependencies/memory"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
+// every component in the process that uses memory storage shares the same files
+var memoryStorage = memory.NewFileStorage()
+
 func Bootstrap(opts bootstrap.Options) (dependencies.FileStorage, error) {
 	switch opts.FileStorageOptions.Type {
 	case bootstrap.WatcherTypeFile:
@@ -40,6 +46,18 @@ func Bootstrap(opts bootstrap.Options) (dependencies.FileStorage, error) {
 			return nil, errors.Wrapf(err, "failed to start consul KV-based file storage client with config %#v", opts.ConsulOptions)
 		}
 		return store, nil
+	case bootstrap.WatcherTypeEtcd:
+		cfg, err := opts.EtcdOptions.ToEtcdConfig()
+		if err != nil {
+			return nil, err
+		}
+		client, err := clientv3.New(cfg)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create etcd client for endpoints %v", opts.EtcdOptions.Endpoints)
+		}
+		return etcdfiles.NewFileStorage(client, opts.EtcdOptions.RootPath), nil
+	case bootstrap.WatcherTypeMemory:
+		return memoryStorage, nil
 	}
 	return nil, errors.Errorf("unknown or unspecified file storage client type: %v", opts.FileStorageOptions.Type)
 }
@@ -1,15 +1,21 @@
 package configstorage
 
 import (
+	"github.com/coreos/etcd/clientv3"
 	"github.com/pkg/errors"
 	"github.com/solo-io/gloo/pkg/bootstrap"
 	"github.com/solo-io/gloo/pkg/storage"
 	"github.com/solo-io/gloo/pkg/storage/consul"
 	"github.com/solo-io/gloo/pkg/storage/crd"
+	"github.com/solo-io/gloo/pkg/storage/etcd"
 	"github.com/solo-io/gloo/pkg/storage/file"
+	"github.com/solo-io/gloo/pkg/storage/memory"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
+// every component in the process that uses memory storage shares the same objects
+var memoryStorage = memory.NewStorage()
+
 func Bootstrap(opts bootstrap.Options) (storage.Interface, error) {
 	switch opts.ConfigStorageOptions.Type {
 	case bootstrap.WatcherTypeFile:
@@ -27,7 +33,7 @@ func Bootstrap(opts bootstrap.Options) (storage.Interface, error) {
 		if err != nil {
 			return nil, errors.Wrap(err, "building kube restclient")
 		}
-		cfgWatcher, err := crd.NewStorage(cfg, opts.KubeOptions.Namespace, opts.ConfigStorageOptions.SyncFrequency)
+		cfgWatcher, err := crd.NewStorageForNamespaces(cfg, opts.KubeOptions.Namespace, opts.KubeOptions.WatchNamespaces, opts.ConfigStorageOptions.SyncFrequency)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to start kube config watcher with config %#v", opts.KubeOptions)
 		}
@@ -39,6 +45,18 @@ func Bootstrap(opts bootstrap.Options) (storage.Interface, error) {
 			return nil, errors.Wrapf(err, "failed to start consul config watcher with config %#v", opts.ConsulOptions)
 		}
 		return cfgWatcher, nil
+	case bootstrap.WatcherTypeEtcd:
+		cfg, err := opts.EtcdOptions.ToEtcdConfig()
+		if err != nil {
+			return nil, err
+		}
+		client, err := clientv3.New(cfg)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create etcd client for endpoints %v", opts.EtcdOptions.Endpoints)
+		}
+		return etcd.NewStorage(client, opts.EtcdOptions.RootPath), nil
+	case bootstrap.WatcherTypeMemory:
+		return memoryStorage, nil
 	}
 	return nil, errors.Errorf("unknown or unspecified config watcher type: %v", opts.ConfigStorageOptions.Type)
 }
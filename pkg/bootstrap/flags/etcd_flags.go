@@ -0,0 +1,19 @@
+package flags
+
+import (
+	"time"
+
+	"github.com/solo-io/gloo/pkg/bootstrap"
+	"github.com/spf13/cobra"
+)
+
+func AddEtcdFlags(cmd *cobra.Command, opts *bootstrap.Options) {
+	cmd.PersistentFlags().StringVar(&opts.EtcdOptions.RootPath, "etcd.root", "gloo", "prefix for all keys stored in etcd by gloo, when using etcd for storage")
+	cmd.PersistentFlags().StringSliceVar(&opts.EtcdOptions.Endpoints, "etcd.endpoints", []string{"http://127.0.0.1:2379"}, "comma-separated client urls of the etcd members to connect to when using etcd for storage")
+	cmd.PersistentFlags().DurationVar(&opts.EtcdOptions.DialTimeout, "etcd.dialtimeout", 5*time.Second, "how long to wait for a connection to etcd")
+	cmd.PersistentFlags().StringVar(&opts.EtcdOptions.Username, "etcd.username", "", "username for authenticating to etcd, if auth is enabled")
+	cmd.PersistentFlags().StringVar(&opts.EtcdOptions.Password, "etcd.password", "", "password for authenticating to etcd, if auth is enabled")
+	cmd.PersistentFlags().StringVar(&opts.EtcdOptions.CertFile, "etcd.cert", "", "client certificate for connecting to etcd over tls")
+	cmd.PersistentFlags().StringVar(&opts.EtcdOptions.KeyFile, "etcd.key", "", "private key of the client certificate for connecting to etcd over tls")
+	cmd.PersistentFlags().StringVar(&opts.EtcdOptions.CAFile, "etcd.cacert", "", "ca bundle to verify the etcd members with when connecting over tls")
+}
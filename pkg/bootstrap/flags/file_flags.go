@@ -9,4 +9,5 @@ func AddFileFlags(cmd *cobra.Command, opts *bootstrap.Options) {
 	cmd.PersistentFlags().StringVar(&opts.FileOptions.ConfigDir, "file.config.dir", "_gloo_config", "root directory to use for storing gloo config files")
 	cmd.PersistentFlags().StringVar(&opts.FileOptions.SecretDir, "file.secret.dir", "_gloo_config/secrets", "root directory to use for storing gloo secret files")
 	cmd.PersistentFlags().StringVar(&opts.FileOptions.FilesDir, "file.files.dir", "_gloo_config/files", "root directory to use for storing gloo input files")
+	cmd.PersistentFlags().StringVar(&opts.FileOptions.SecretKeyFile, "file.secret.keyfile", "", "file containing the keys to encrypt secret files with. defaults to the GLOO_SECRET_KEY environment variable. secrets are not encrypted if neither is set")
 }
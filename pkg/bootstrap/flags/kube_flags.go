@@ -13,4 +13,5 @@ func AddKubernetesFlags(cmd *cobra.Command, opts *bootstrap.Options) {
 	cmd.PersistentFlags().StringVar(&opts.KubeOptions.MasterURL, "master", "", "url of the kubernetes apiserver. not needed if running in-cluster")
 	cmd.PersistentFlags().StringVar(&opts.KubeOptions.KubeConfig, "kubeconfig", "", "path to kubeconfig file. not needed if running in-cluster")
 	cmd.PersistentFlags().StringVar(&opts.KubeOptions.Namespace, "kube.namespace", crd.GlooDefaultNamespace, "namespace to read/write gloo storage objects")
+	cmd.PersistentFlags().StringSliceVar(&opts.KubeOptions.WatchNamespaces, "kube.watch-namespaces", nil, "namespaces to read gloo config objects from, or * for every namespace. defaults to kube.namespace. objects outside kube.namespace are named <namespace>/<name>")
 }
@@ -0,0 +1,12 @@
+package flags
+
+import (
+	"github.com/solo-io/gloo/pkg/bootstrap"
+	"github.com/spf13/cobra"
+)
+
+func AddLogFlags(cmd *cobra.Command, opts *bootstrap.Options) {
+	cmd.PersistentFlags().StringVar(&opts.LogOptions.Level, "log.level", "", "log level: debug, info, warn or error. defaults to info, or debug if DEBUG=1 is set. "+
+		"the control plane's level can also be changed at runtime through the admin api")
+	cmd.PersistentFlags().StringVar(&opts.LogOptions.Format, "log.format", "", "log output format: console or json. defaults to console, or json if LOG_FORMAT=json is set")
+}
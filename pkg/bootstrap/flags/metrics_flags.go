@@ -0,0 +1,11 @@
+package flags
+
+import (
+	"github.com/solo-io/gloo/pkg/bootstrap"
+	"github.com/spf13/cobra"
+)
+
+func AddMetricsFlags(cmd *cobra.Command, opts *bootstrap.Options) {
+	cmd.PersistentFlags().StringVar(&opts.MetricsOptions.BindAddress, "metrics.bind-address", "", "bind address for the prometheus /metrics endpoint. defaults to all interfaces")
+	cmd.PersistentFlags().IntVar(&opts.MetricsOptions.Port, "metrics.port", 9090, "port to serve prometheus metrics on at /metrics. set to 0 to disable metrics")
+}
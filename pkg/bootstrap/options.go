@@ -3,7 +3,10 @@ package bootstrap
 import (
 	"time"
 
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/pkg/transport"
 	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
 )
 
 const (
@@ -11,6 +14,9 @@ const (
 	WatcherTypeConsul = "consul"
 	WatcherTypeFile   = "file"
 	WatcherTypeVault  = "vault"
+	WatcherTypeEtcd   = "etcd"
+	// objects are kept in memory, shared by every component in the process
+	WatcherTypeMemory = "memory"
 )
 
 var (
@@ -18,16 +24,22 @@ var (
 		WatcherTypeFile,
 		WatcherTypeKube,
 		WatcherTypeConsul,
+		WatcherTypeEtcd,
+		WatcherTypeMemory,
 	}
 	SupportedFwTypes = []string{
 		WatcherTypeConsul,
 		WatcherTypeFile,
 		WatcherTypeKube,
+		WatcherTypeEtcd,
+		WatcherTypeMemory,
 	}
 	SupportedSwTypes = []string{
 		WatcherTypeVault,
 		WatcherTypeKube,
 		WatcherTypeFile,
+		WatcherTypeEtcd,
+		WatcherTypeMemory,
 	}
 )
 
@@ -39,9 +51,14 @@ type Options struct {
 	// options for platforms
 	KubeOptions    KubeOptions
 	ConsulOptions  ConsulOptions
+	EtcdOptions    EtcdOptions
 	CoPilotOptions CoPilotOptions
 	FileOptions    FileOptions
 	VaultOptions   VaultOptions
+	// options for serving prometheus metrics
+	MetricsOptions MetricsOptions
+	// options for logging
+	LogOptions LogOptions
 }
 
 type StorageOptions struct {
@@ -53,6 +70,8 @@ type KubeOptions struct {
 	KubeConfig string
 	MasterURL  string
 	Namespace  string // where to watch for storage
+	// namespaces to list and watch config objects in, or crd.AllNamespaces. defaults to Namespace
+	WatchNamespaces []string
 }
 
 type ConsulOptions struct {
@@ -118,6 +137,50 @@ func (o ConsulOptions) ToConsulConfig() *api.Config {
 	return cfg
 }
 
+type EtcdOptions struct {
+	// Endpoints are the client urls of the etcd members
+	Endpoints []string
+
+	// DialTimeout is how long to wait for a connection to etcd
+	DialTimeout time.Duration
+
+	// Username and Password to authenticate with, if etcd has auth enabled
+	Username string
+	Password string
+
+	// CertFile, KeyFile and CAFile configure TLS for the connection to etcd.
+	// a client certificate is optional, the CA is used to verify the etcd members
+	CertFile string
+	KeyFile  string
+	CAFile   string
+
+	// RootPath is used as the prefix for all keys stored
+	// in etcd by gloo
+	RootPath string
+}
+
+func (o EtcdOptions) ToEtcdConfig() (clientv3.Config, error) {
+	cfg := clientv3.Config{
+		Endpoints:   o.Endpoints,
+		DialTimeout: o.DialTimeout,
+		Username:    o.Username,
+		Password:    o.Password,
+	}
+	if o.CertFile != "" || o.KeyFile != "" || o.CAFile != "" {
+		tlsInfo := transport.TLSInfo{
+			CertFile:      o.CertFile,
+			KeyFile:       o.KeyFile,
+			TrustedCAFile: o.CAFile,
+		}
+		tlsConfig, err := tlsInfo.ClientConfig()
+		if err != nil {
+			return clientv3.Config{}, errors.Wrap(err, "loading etcd tls config")
+		}
+		cfg.TLS = tlsConfig
+	}
+	return cfg, nil
+}
+
 type VaultOptions struct {
 	VaultAddr      string
 	VaultToken     string
@@ -130,8 +193,24 @@ type FileOptions struct {
 	ConfigDir string
 	SecretDir string
 	FilesDir  string
+	// file of base64 keys to encrypt secret files with, one per line. the first key encrypts,
+	// the others only decrypt. read from GLOO_SECRET_KEY if empty
+	SecretKeyFile string
 }
 
 type XdsOptions struct {
 	Port int
 }
+
+type LogOptions struct {
+	// Level is one of debug, info, warn or error
+	Level string
+	// Format is console or json
+	Format string
+}
+
+type MetricsOptions struct {
+	BindAddress string
+	// Port to serve /metrics on. 0 disables metrics
+	Port int
+}
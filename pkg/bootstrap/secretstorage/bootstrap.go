@@ -5,20 +5,30 @@ import (
 	"os"
 	"strings"
 
+	"github.com/coreos/etcd/clientv3"
 	"github.com/hashicorp/vault/api"
 	"github.com/pkg/errors"
 	"github.com/solo-io/gloo/pkg/bootstrap"
 	"github.com/solo-io/gloo/pkg/storage/dependencies"
+	"github.com/solo-io/gloo/pkg/storage/dependencies/etcd"
 	"github.com/solo-io/gloo/pkg/storage/dependencies/file"
 	"github.com/solo-io/gloo/pkg/storage/dependencies/kube"
+	"github.com/solo-io/gloo/pkg/storage/dependencies/memory"
 	"github.com/solo-io/gloo/pkg/storage/dependencies/vault"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
+// every component in the process that uses memory storage shares the same secrets
+var memoryStorage = memory.NewSecretStorage()
+
 func Bootstrap(opts bootstrap.Options) (dependencies.SecretStorage, error) {
 	switch opts.SecretStorageOptions.Type {
 	case bootstrap.WatcherTypeFile:
-		return file.NewSecretStorage(opts.FileOptions.SecretDir, opts.SecretStorageOptions.SyncFrequency)
+		keys, err := file.LoadKeyring(opts.FileOptions.SecretKeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "loading secret keys")
+		}
+		return file.NewSecretStorage(opts.FileOptions.SecretDir, opts.SecretStorageOptions.SyncFrequency, keys)
 	case bootstrap.WatcherTypeKube:
 		cfg, err := clientcmd.BuildConfigFromFlags(opts.KubeOptions.MasterURL, opts.KubeOptions.KubeConfig)
 		if err != nil {
@@ -51,6 +61,18 @@ func Bootstrap(opts bootstrap.Options) (dependencies.SecretStorage, error) {
 		}
 		vaultClient.SetToken(token)
 		return vault.NewSecretStorage(vaultClient, opts.VaultOptions.RootPath, opts.SecretStorageOptions.SyncFrequency), nil
+	case bootstrap.WatcherTypeEtcd:
+		cfg, err := opts.EtcdOptions.ToEtcdConfig()
+		if err != nil {
+			return nil, err
+		}
+		client, err := clientv3.New(cfg)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create etcd client for endpoints %v", opts.EtcdOptions.Endpoints)
+		}
+		return etcd.NewSecretStorage(client, opts.EtcdOptions.RootPath), nil
+	case bootstrap.WatcherTypeMemory:
+		return memoryStorage, nil
 	}
 	return nil, errors.Errorf("unknown or unspecified secret watcher type: %v", opts.SecretStorageOptions.Type)
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/solo-io/gloo/pkg/api/types/v1"
 	"github.com/solo-io/gloo/pkg/log"
@@ -17,11 +18,21 @@ type UpstreamSyncer struct {
 }
 
 func (c *UpstreamSyncer) SyncDesiredState() error {
+	start := time.Now()
+	err := c.syncDesiredState()
+	syncDuration.WithLabelValues(c.Owner).Observe(time.Since(start).Seconds())
+	if err != nil {
+		syncFailures.WithLabelValues(c.Owner).Inc()
+	}
+	return err
+}
 
+func (c *UpstreamSyncer) syncDesiredState() error {
 	desiredUpstreams, err := c.DesiredUpstreams()
 	if err != nil {
 		return fmt.Errorf("failed to generate desired upstreams: %v", err)
 	}
+	discoveredUpstreams.WithLabelValues(c.Owner).Set(float64(len(desiredUpstreams)))
 
 	c.setOwnerAnnotation(desiredUpstreams)
 
@@ -96,6 +107,7 @@ func (c *UpstreamSyncer) syncUpstreams(desiredUpstreams, actualUpstreams []*v1.U
 			log.Debugf("creating upstream %v", us.Name)
 			return fmt.Errorf("failed to create upstream crd %s: %v", us.Name, err)
 		}
+		upstreamWrites.WithLabelValues(c.Owner, "create").Inc()
 	}
 	for _, us := range upstreamsToUpdate {
 		log.Debugf("updating upstream %v", us.Name)
@@ -113,6 +125,7 @@ func (c *UpstreamSyncer) syncUpstreams(desiredUpstreams, actualUpstreams []*v1.U
 		if _, err := c.GlooStorage.V1().Upstreams().Update(currentUpstream); err != nil {
 			return fmt.Errorf("failed to update upstream %s: %v", us.Name, err)
 		}
+		upstreamWrites.WithLabelValues(c.Owner, "update").Inc()
 	}
 	// only remaining are no longer desired, delete em!
 	for _, us := range actualUpstreams {
@@ -120,6 +133,7 @@ func (c *UpstreamSyncer) syncUpstreams(desiredUpstreams, actualUpstreams []*v1.U
 		if err := c.GlooStorage.V1().Upstreams().Delete(us.Name); err != nil {
 			return fmt.Errorf("failed to update upstream crd %s: %v", us.Name, err)
 		}
+		upstreamWrites.WithLabelValues(c.Owner, "delete").Inc()
 	}
 	return nil
 }
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/solo-io/gloo/pkg/metrics"
+)
+
+const subsystem = "upstream_discovery"
+
+var (
+	syncDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystem,
+		Name:      "sync_duration_seconds",
+		Help:      "time taken to sync discovered upstreams to storage, by discovery source",
+	}, []string{"owner"})
+	syncFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystem,
+		Name:      "sync_failures_total",
+		Help:      "number of failed syncs of discovered upstreams, by discovery source",
+	}, []string{"owner"})
+	discoveredUpstreams = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystem,
+		Name:      "upstreams",
+		Help:      "number of upstreams found by the most recent discovery, by discovery source",
+	}, []string{"owner"})
+	upstreamWrites = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystem,
+		Name:      "upstream_writes_total",
+		Help:      "number of discovered upstreams created, updated or deleted, by discovery source",
+	}, []string{"owner", "operation"})
+)
+
+func init() {
+	prometheus.MustRegister(syncDuration, syncFailures, discoveredUpstreams, upstreamWrites)
+}
@@ -3,6 +3,7 @@ package matcher
 import (
 	envoyroute "github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
 
+	"sort"
 	"strings"
 
 	"github.com/gogo/protobuf/types"
@@ -63,7 +64,9 @@ func createRequestMatcher(requestMatcher *v1.RequestMatcher, out *envoyroute.Rou
 			Path: path.PathExact,
 		}
 	}
-	for headerName, headerValue := range requestMatcher.Headers {
+	// iterate in sorted order so the generated route is identical across translations
+	for _, headerName := range sortedKeys(requestMatcher.Headers) {
+		headerValue := requestMatcher.Headers[headerName]
 		var regex bool
 		if headerValue == "" {
 			headerValue = ".*"
@@ -77,7 +80,8 @@ func createRequestMatcher(requestMatcher *v1.RequestMatcher, out *envoyroute.Rou
 			Regex: &types.BoolValue{Value: regex},
 		})
 	}
-	for paramName, paramValue := range requestMatcher.QueryParams {
+	for _, paramName := range sortedKeys(requestMatcher.QueryParams) {
+		paramValue := requestMatcher.QueryParams[paramName]
 		var regex bool
 		if paramValue == "" {
 			paramValue = ".*"
@@ -98,3 +102,12 @@ func createRequestMatcher(requestMatcher *v1.RequestMatcher, out *envoyroute.Rou
 	}
 	return nil
 }
+
+func sortedKeys(m map[string]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -5,6 +5,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/solo-io/gloo/pkg/api/types/v1"
 	. "github.com/solo-io/gloo/pkg/coreplugins/matcher"
 	. "github.com/solo-io/gloo/test/helpers"
 )
@@ -19,5 +20,28 @@ var _ = Describe("Plugin", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(out.Match.PathSpecifier).To(Equal(&envoyroute.RouteMatch_Prefix{Prefix: "/foo"}))
 		})
+		It("sorts header and query parameter matchers by name", func() {
+			plug := &Plugin{}
+			route := &v1.Route{
+				Matcher: &v1.Route_RequestMatcher{
+					RequestMatcher: &v1.RequestMatcher{
+						Path:        &v1.RequestMatcher_PathPrefix{PathPrefix: "/"},
+						Headers:     map[string]string{"c": "", "a": "", "b": ""},
+						QueryParams: map[string]string{"z": "", "x": "", "y": ""},
+					},
+				},
+			}
+			out := &envoyroute.Route{}
+			err := plug.ProcessRoute(nil, route, out)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out.Match.Headers).To(HaveLen(3))
+			Expect(out.Match.Headers[0].Name).To(Equal("a"))
+			Expect(out.Match.Headers[1].Name).To(Equal("b"))
+			Expect(out.Match.Headers[2].Name).To(Equal("c"))
+			Expect(out.Match.QueryParameters).To(HaveLen(3))
+			Expect(out.Match.QueryParameters[0].Name).To(Equal("x"))
+			Expect(out.Match.QueryParameters[1].Name).To(Equal("y"))
+			Expect(out.Match.QueryParameters[2].Name).To(Equal("z"))
+		})
 	})
 })
@@ -12,6 +12,7 @@ import (
 
 	"github.com/solo-io/gloo/pkg/api/types/v1"
 	"github.com/solo-io/gloo/pkg/plugins"
+	"github.com/solo-io/gloo/pkg/secretwatcher"
 )
 
 type Plugin struct {
@@ -29,11 +30,25 @@ const (
 	UpstreamTypeService = "service"
 )
 
-func (p *Plugin) GetDependencies(_ *v1.Config) *plugins.Dependencies {
-	return nil
+func (p *Plugin) GetDependencies(cfg *v1.Config) *plugins.Dependencies {
+	deps := new(plugins.Dependencies)
+	for _, upstream := range cfg.Upstreams {
+		if upstream.Type != UpstreamTypeService {
+			continue
+		}
+		spec, err := DecodeUpstreamSpec(upstream.Spec)
+		if err != nil {
+			// errors will be handled during validation
+			continue
+		}
+		if spec.TLSSecretRef != "" {
+			deps.SecretRefs = append(deps.SecretRefs, spec.TLSSecretRef)
+		}
+	}
+	return deps
 }
 
-func (p *Plugin) ProcessUpstream(_ *plugins.UpstreamPluginParams, in *v1.Upstream, out *envoyapi.Cluster) error {
+func (p *Plugin) ProcessUpstream(params *plugins.UpstreamPluginParams, in *v1.Upstream, out *envoyapi.Cluster) error {
 	if in.Type != UpstreamTypeService {
 		return nil
 	}
@@ -78,10 +93,9 @@ func (p *Plugin) ProcessUpstream(_ *plugins.UpstreamPluginParams, in *v1.Upstrea
 			out.DnsLookupFamily = envoyapi.Cluster_V4_ONLY
 		}
 		// if host port is 443 && spec.TLS == nil we will use TLS
-		// or if the user wants it
-		if (spec.TLS != nil && *spec.TLS) || (spec.TLS == nil && foundSslPort) {
+		// or if the user wants it, or gave a client certificate
+		if (spec.TLS != nil && *spec.TLS) || (spec.TLS == nil && (foundSslPort || spec.TLSSecretRef != "")) {
 			// tell envoy to use TLS to connect to this upstream
-			// TODO: support client certificates
 			out.TlsContext = &envoyauth.UpstreamTlsContext{
 				Sni: hostname,
 			}
@@ -95,6 +109,23 @@ func (p *Plugin) ProcessUpstream(_ *plugins.UpstreamPluginParams, in *v1.Upstrea
 		}
 
 	}
+	if spec.TLSSecretRef != "" && out.TlsContext != nil {
+		certChain, privateKey, err := secretwatcher.TlsCertificateForRef(params.Secrets, spec.TLSSecretRef)
+		if err != nil {
+			return errors.Wrap(err, "invalid client certificate")
+		}
+		// the translator serves the certificate over sds
+		out.TlsContext.CommonTlsContext = &envoyauth.CommonTlsContext{
+			TlsCertificates: []*envoyauth.TlsCertificate{{
+				CertificateChain: &envoycore.DataSource{
+					Specifier: &envoycore.DataSource_InlineString{InlineString: certChain},
+				},
+				PrivateKey: &envoycore.DataSource{
+					Specifier: &envoycore.DataSource_InlineString{InlineString: privateKey},
+				},
+			}},
+		}
+	}
 	return nil
 }
 
@@ -9,7 +9,10 @@ import (
 type UpstreamSpec struct {
 	Hosts      []Host `json:"hosts"`
 	EnableIPv6 bool   `json:"enable_ipv6"`
-	TLS        *bool   `json:"tls"`
+	TLS        *bool  `json:"tls"`
+	// TLSSecretRef is a tls secret with the client certificate presented to the upstream, which enables TLS.
+	// the secret may be a kubernetes tls secret, a gloo tls secret or a PEM bundle
+	TLSSecretRef string `json:"tls_secret_ref,omitempty"`
 }
 
 type Host struct {
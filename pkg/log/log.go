@@ -3,54 +3,144 @@ package log
 import (
 	"fmt"
 	"io"
+	"net/http"
 	"os"
-	"regexp"
-	"runtime"
-	"time"
+	"strings"
+	"sync"
 
-	"github.com/k0kubun/pp"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
-var debugMode = os.Getenv("DEBUG") == "1"
+const (
+	FormatConsole = "console"
+	FormatJSON    = "json"
+)
+
+// DefaultOut is where logs are written. it may be replaced at any time, e.g. by tests
 var DefaultOut io.Writer = os.Stdout
 
-func init() {
-	if os.Getenv("DISABLE_COLOR") == "1" {
-		pp.ColoringEnabled = false
+var (
+	// the level can be changed at runtime, without rebuilding the logger
+	level = zap.NewAtomicLevelAt(defaultLevel())
+
+	lock   sync.RWMutex
+	format = defaultFormat()
+	root   = newLogger(format)
+)
+
+// DEBUG=1 and LOG_FORMAT are still respected, so binaries log correctly before flags are parsed
+func defaultLevel() zapcore.Level {
+	if os.Getenv("DEBUG") == "1" {
+		return zapcore.DebugLevel
+	}
+	return zapcore.InfoLevel
+}
+
+func defaultFormat() string {
+	if os.Getenv("LOG_FORMAT") == FormatJSON {
+		return FormatJSON
+	}
+	return FormatConsole
+}
+
+// writes to whatever DefaultOut currently is
+type defaultOutWriter struct{}
+
+func (defaultOutWriter) Write(p []byte) (int, error) {
+	return DefaultOut.Write(p)
+}
+
+func newLogger(format string) *zap.Logger {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.TimeKey = "time"
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	var encoder zapcore.Encoder
+	switch format {
+	case FormatJSON:
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	default:
+		encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	}
+	core := zapcore.NewCore(encoder, zapcore.AddSync(defaultOutWriter{}), level)
+	// skip the wrappers in this package when reporting the caller
+	return zap.New(core, zap.AddCaller(), zap.AddCallerSkip(2))
+}
+
+// Configure sets the output format (console or json) and the level (debug, info, warn or error).
+// empty values leave the current setting unchanged
+func Configure(outputFormat, logLevel string) error {
+	if logLevel != "" {
+		if err := SetLevel(logLevel); err != nil {
+			return err
+		}
+	}
+	if outputFormat == "" {
+		return nil
+	}
+	if outputFormat != FormatConsole && outputFormat != FormatJSON {
+		return errors.Errorf("invalid log format %q, must be %v or %v", outputFormat, FormatConsole, FormatJSON)
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	format = outputFormat
+	root = newLogger(format)
+	return nil
+}
+
+// SetLevel changes the level of every logger
+func SetLevel(logLevel string) error {
+	var l zapcore.Level
+	if err := l.UnmarshalText([]byte(strings.ToLower(logLevel))); err != nil {
+		return errors.Wrapf(err, "invalid log level %q", logLevel)
 	}
+	level.SetLevel(l)
+	return nil
 }
 
-var rxp = regexp.MustCompile(".*/src/")
+// Level returns the current level
+func Level() string {
+	return level.Level().String()
+}
 
+// LevelHandler serves the current level on GET, and changes it on PUT with a body like {"level": "debug"}
+func LevelHandler() http.Handler {
+	return level
+}
+
+func current() *zap.Logger {
+	lock.RLock()
+	defer lock.RUnlock()
+	return root
+}
+
+// Sprintf formats without logging
 func Sprintf(format string, a ...interface{}) string {
-	return pp.Sprintf("%v\t"+format, append([]interface{}{line()}, a...)...)
+	return fmt.Sprintf(format, a...)
 }
 
 func GreyPrintf(format string, a ...interface{}) {
-	fmt.Fprintf(DefaultOut, "%v\t"+format+"\n", append([]interface{}{line()}, a...)...)
+	std.logf(zapcore.InfoLevel, format, a...)
 }
 
 func Printf(format string, a ...interface{}) {
-	fmt.Fprintln(DefaultOut, Sprintf(format, a...))
+	std.logf(zapcore.InfoLevel, format, a...)
 }
 
 func Warnf(format string, a ...interface{}) {
-	fmt.Fprintln(DefaultOut, Sprintf("WARNING: %v\t"+format+"\n", a...))
+	std.logf(zapcore.WarnLevel, format, a...)
 }
 
 func Debugf(format string, a ...interface{}) {
-	if debugMode {
-		fmt.Fprintln(DefaultOut, Sprintf(format, a...))
-	}
+	std.logf(zapcore.DebugLevel, format, a...)
 }
 
-func Fatalf(format string, a ...interface{}) {
-	fmt.Fprintln(DefaultOut, Sprintf(format, a...))
-	os.Exit(1)
+func Errorf(format string, a ...interface{}) {
+	std.logf(zapcore.ErrorLevel, format, a...)
 }
 
-func line() string {
-	_, file, line, _ := runtime.Caller(3)
-	file = rxp.ReplaceAllString(file, "")
-	return fmt.Sprintf("%v: %v:%v", time.Now().Format(time.RFC1123), file, line)
+func Fatalf(format string, a ...interface{}) {
+	std.logf(zapcore.FatalLevel, format, a...)
 }
@@ -0,0 +1,81 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/solo-io/gloo/pkg/log"
+)
+
+var _ = Describe("Log", func() {
+	var (
+		out         *bytes.Buffer
+		originalOut io.Writer
+	)
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+		originalOut = DefaultOut
+		DefaultOut = out
+		Expect(Configure(FormatJSON, "info")).NotTo(HaveOccurred())
+	})
+	AfterEach(func() {
+		DefaultOut = originalOut
+		Expect(Configure(FormatConsole, "info")).NotTo(HaveOccurred())
+	})
+	entry := func() map[string]interface{} {
+		var e map[string]interface{}
+		Expect(json.Unmarshal(out.Bytes(), &e)).NotTo(HaveOccurred())
+		return e
+	}
+	It("writes json with the message and level", func() {
+		Printf("hello %v", "world")
+		e := entry()
+		Expect(e["msg"]).To(Equal("hello world"))
+		Expect(e["level"]).To(Equal("info"))
+		Expect(e["caller"]).To(ContainSubstring("log_test.go"))
+	})
+	It("adds fields to every message of a logger", func() {
+		logger := With(Component("control-plane"), Role("ingress"))
+		logger.With(Resource("upstream/foo")).Warnf("bad upstream")
+		e := entry()
+		Expect(e["level"]).To(Equal("warn"))
+		Expect(e["component"]).To(Equal("control-plane"))
+		Expect(e["role"]).To(Equal("ingress"))
+		Expect(e["resource"]).To(Equal("upstream/foo"))
+	})
+	It("drops messages below the level", func() {
+		Debugf("hidden")
+		Expect(out.String()).To(BeEmpty())
+		Expect(SetLevel("debug")).NotTo(HaveOccurred())
+		Debugf("shown")
+		Expect(entry()["msg"]).To(Equal("shown"))
+	})
+	It("writes console output", func() {
+		Expect(Configure(FormatConsole, "")).NotTo(HaveOccurred())
+		Printf("hello")
+		Expect(out.String()).To(ContainSubstring("INFO"))
+		Expect(out.String()).To(ContainSubstring("hello"))
+	})
+	It("rejects invalid options", func() {
+		Expect(Configure("xml", "")).To(HaveOccurred())
+		Expect(SetLevel("loud")).To(HaveOccurred())
+	})
+	It("changes the level over http", func() {
+		server := httptest.NewServer(LevelHandler())
+		defer server.Close()
+		req, err := http.NewRequest(http.MethodPut, server.URL, strings.NewReader(`{"level": "error"}`))
+		Expect(err).NotTo(HaveOccurred())
+		res, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		res.Body.Close()
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+		Expect(Level()).To(Equal("error"))
+	})
+})
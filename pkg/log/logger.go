@@ -0,0 +1,82 @@
+package log
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Field is a key-value pair attached to every message of a Logger
+type Field = zapcore.Field
+
+// Component is the gloo component logging the message, e.g. control-plane
+func Component(name string) Field {
+	return zap.String("component", name)
+}
+
+// Role is the envoy role the message is about
+func Role(name string) Field {
+	return zap.String("role", name)
+}
+
+// Resource is the config object the message is about, as <type>/<name>, e.g. upstream/my-upstream
+func Resource(ref string) Field {
+	return zap.String("resource", ref)
+}
+
+// String is an arbitrary field
+func String(key, value string) Field {
+	return zap.String(key, value)
+}
+
+// Logger logs messages with a set of fields.
+// it follows changes to the format and level of the package-level logger
+type Logger struct {
+	fields []Field
+}
+
+var std = &Logger{}
+
+// With returns a logger which adds the given fields to every message
+func With(fields ...Field) *Logger {
+	return std.With(fields...)
+}
+
+// With returns a logger with the fields of this logger, and the given fields
+func (l *Logger) With(fields ...Field) *Logger {
+	merged := make([]Field, 0, len(l.fields)+len(fields))
+	merged = append(merged, l.fields...)
+	merged = append(merged, fields...)
+	return &Logger{fields: merged}
+}
+
+func (l *Logger) Printf(format string, a ...interface{}) {
+	l.logf(zapcore.InfoLevel, format, a...)
+}
+
+func (l *Logger) Warnf(format string, a ...interface{}) {
+	l.logf(zapcore.WarnLevel, format, a...)
+}
+
+func (l *Logger) Debugf(format string, a ...interface{}) {
+	l.logf(zapcore.DebugLevel, format, a...)
+}
+
+func (l *Logger) Errorf(format string, a ...interface{}) {
+	l.logf(zapcore.ErrorLevel, format, a...)
+}
+
+func (l *Logger) Fatalf(format string, a ...interface{}) {
+	l.logf(zapcore.FatalLevel, format, a...)
+}
+
+func (l *Logger) logf(lvl zapcore.Level, format string, a ...interface{}) {
+	// avoid formatting messages that won't be written
+	if !level.Enabled(lvl) {
+		return
+	}
+	if ce := current().Check(lvl, fmt.Sprintf(format, a...)); ce != nil {
+		ce.Write(l.fields...)
+	}
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/solo-io/gloo/pkg/bootstrap"
+	"github.com/solo-io/gloo/pkg/log"
+)
+
+// Namespace prefixes the name of every gloo metric
+const Namespace = "gloo"
+
+const path = "/metrics"
+
+// Serve serves every metric registered with the default prometheus registry on /metrics,
+// until stop is closed. components register their metrics when their packages are initialized,
+// so a binary running multiple components only needs to serve metrics once
+func Serve(opts bootstrap.MetricsOptions, stop <-chan struct{}) error {
+	addr := fmt.Sprintf("%v:%v", opts.BindAddress, opts.Port)
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen on %v", addr)
+	}
+	m := http.NewServeMux()
+	m.Handle(path, promhttp.Handler())
+	server := &http.Server{Handler: m}
+	go func() {
+		<-stop
+		server.Shutdown(context.Background())
+	}()
+	log.Printf("serving metrics on %v%v", addr, path)
+	if err := server.Serve(lis); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// Start serves metrics in the background, unless they are disabled
+func Start(opts bootstrap.MetricsOptions, stop <-chan struct{}) {
+	if opts.Port == 0 {
+		return
+	}
+	go func() {
+		if err := Serve(opts, stop); err != nil {
+			log.Warnf("failed to serve metrics: %v", err)
+		}
+	}()
+}
@@ -1,6 +1,8 @@
 package plugins
 
 import (
+	"fmt"
+
 	envoyapi "github.com/envoyproxy/go-control-plane/envoy/api/v2"
 	envoyroute "github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
 	envoyhttp "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/http_connection_manager/v2"
@@ -23,6 +25,22 @@ const (
 	OutAuth
 )
 
+func (s Stage) String() string {
+	switch s {
+	case PreInAuth:
+		return "PreInAuth"
+	case InAuth:
+		return "InAuth"
+	case PostInAuth:
+		return "PostInAuth"
+	case PreOutAuth:
+		return "PreOutAuth"
+	case OutAuth:
+		return "OutAuth"
+	}
+	return fmt.Sprintf("Stage(%d)", int(s))
+}
+
 type EnvoyNameForUpstream func(upstreamName string) string
 
 type Dependencies struct {
@@ -25,13 +25,17 @@ var _ = Describe("FileSecretWatcher", func() {
 		err   error
 		watch Interface
 		stop  chan struct{}
+		keys  *filesecrets.Keyring
 	)
 	BeforeEach(func() {
 		ref = "secrets.yml"
 		dir, err = ioutil.TempDir("", "filesecrettest")
 		Must(err)
 		file = filepath.Join(dir, ref)
-		secretClient, err := filesecrets.NewSecretStorage(dir, time.Millisecond)
+		keys = nil
+	})
+	JustBeforeEach(func() {
+		secretClient, err := filesecrets.NewSecretStorage(dir, time.Millisecond, keys)
 		Must(err)
 		watch, err = NewSecretWatcher(secretClient)
 		Must(err)
@@ -88,5 +92,34 @@ var _ = Describe("FileSecretWatcher", func() {
 				}
 			})
 		})
+		Context("the secret storage encrypts secrets", func() {
+			BeforeEach(func() {
+				key, err := filesecrets.GenerateKey()
+				Must(err)
+				keys, err = filesecrets.ParseKeyring(key)
+				Must(err)
+			})
+			It("sends the decrypted secrets on Secrets()", func() {
+				secret := &dependencies.Secret{
+					Ref:  ref,
+					Data: map[string]string{"username": "me@example.com", "password": "foobar"},
+				}
+				secretClient, err := filesecrets.NewSecretStorage(dir, time.Millisecond, keys)
+				Must(err)
+				_, err = secretClient.Create(secret)
+				Must(err)
+				go watch.TrackSecrets([]string{ref})
+				select {
+				case parsedSecrets := <-watch.Secrets():
+					Expect(parsedSecrets).To(Equal(SecretMap{
+						ref: secret,
+					}))
+				case err := <-watch.Error():
+					Expect(err).NotTo(HaveOccurred())
+				case <-time.After(time.Second * 5):
+					Fail("expected new secrets to be read in before 5s")
+				}
+			})
+		})
 	})
 })
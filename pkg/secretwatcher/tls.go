@@ -0,0 +1,180 @@
+package secretwatcher
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/pkg/storage/dependencies"
+)
+
+// the keys of the tls secret formats
+const (
+	// kubernetes.io/tls secrets, e.g. the secrets of kubernetes ingresses
+	KubeTlsCertKey = "tls.crt"
+	KubeTlsKeyKey  = "tls.key"
+
+	// gloo's own format
+	GlooTlsCertChainKey  = "ca_chain"
+	GlooTlsPrivateKeyKey = "private_key"
+)
+
+// TlsFormat is the format a tls certificate and private key were stored in
+type TlsFormat string
+
+const (
+	TlsFormatKube TlsFormat = "kubernetes tls"
+	TlsFormatGloo TlsFormat = "gloo"
+	// a single value containing the certificate chain and the private key in PEM
+	TlsFormatPemBundle TlsFormat = "pem bundle"
+)
+
+// TlsCertificate returns the certificate chain and private key of a tls secret.
+// secrets can hold them as tls.crt and tls.key, as ca_chain and private_key,
+// or as a PEM bundle of both in a single value. the format is detected from the keys of the secret
+func TlsCertificate(secret *dependencies.Secret) (certChain, privateKey string, format TlsFormat, err error) {
+	for _, keys := range []struct {
+		format           TlsFormat
+		certKey, privKey string
+	}{
+		{TlsFormatKube, KubeTlsCertKey, KubeTlsKeyKey},
+		{TlsFormatGloo, GlooTlsCertChainKey, GlooTlsPrivateKeyKey},
+	} {
+		cert, hasCert := secret.Data[keys.certKey]
+		key, hasKey := secret.Data[keys.privKey]
+		switch {
+		case hasCert && hasKey:
+			return cert, key, keys.format, nil
+		case hasCert:
+			return "", "", "", errors.Errorf("secret %v looks like a %v secret, but has no %v", secret.Ref, keys.format, keys.privKey)
+		case hasKey:
+			return "", "", "", errors.Errorf("secret %v looks like a %v secret, but has no %v", secret.Ref, keys.format, keys.certKey)
+		}
+	}
+
+	keys := secretKeys(secret)
+	for _, key := range keys {
+		if cert, privKey, ok := splitPemBundle(secret.Data[key]); ok {
+			return cert, privKey, TlsFormatPemBundle, nil
+		}
+	}
+	return "", "", "", errors.Errorf("secret %v is not a tls secret. expected the keys %v and %v, the keys %v and %v, "+
+		"or a PEM bundle of the certificate chain and private key, but found the keys [%v]",
+		secret.Ref, KubeTlsCertKey, KubeTlsKeyKey, GlooTlsCertChainKey, GlooTlsPrivateKeyKey, strings.Join(keys, ", "))
+}
+
+// TlsCertificateForRef returns the certificate chain and private key of the tls secret with this ref
+func TlsCertificateForRef(secrets SecretMap, ref string) (string, string, error) {
+	secret, ok := secrets[ref]
+	if !ok {
+		return "", "", errors.Errorf("ssl secret not found for ref %v", ref)
+	}
+	certChain, privateKey, _, err := TlsCertificate(secret)
+	return certChain, privateKey, err
+}
+
+// LeafCertificate parses the first certificate of a PEM certificate chain
+func LeafCertificate(certChain string) (*x509.Certificate, error) {
+	rest := []byte(certChain)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, errors.New("no PEM encoded certificate found")
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		return x509.ParseCertificate(block.Bytes)
+	}
+}
+
+// UncoveredDomains returns the domains that are not covered by the subject alternative names of the certificate.
+// catch-all domains can not be covered by a certificate, so they are not checked
+func UncoveredDomains(domains []string, cert *x509.Certificate) []string {
+	var uncovered []string
+	for _, domain := range domains {
+		if domain == "" || domain == "*" {
+			continue
+		}
+		if !certificateCovers(cert, domain) {
+			uncovered = append(uncovered, domain)
+		}
+	}
+	return uncovered
+}
+
+func certificateCovers(cert *x509.Certificate, domain string) bool {
+	domain = strings.ToLower(domain)
+	// the certificate is only matched against the host of domains with a port
+	if host, _, err := net.SplitHostPort(domain); err == nil {
+		domain = host
+	}
+	if ip := net.ParseIP(domain); ip != nil {
+		for _, certIP := range cert.IPAddresses {
+			if certIP.Equal(ip) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, name := range cert.DNSNames {
+		name = strings.ToLower(name)
+		if name == domain {
+			return true
+		}
+		// wildcard names cover a single label, e.g. *.example.com covers api.example.com, and the domain *.example.com
+		if strings.HasPrefix(name, "*.") {
+			if i := strings.Index(domain, "."); i > 0 && domain[i:] == name[1:] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CertificateNames returns the dns names and ip addresses the certificate is valid for
+func CertificateNames(cert *x509.Certificate) []string {
+	names := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+	return names
+}
+
+// splitPemBundle splits PEM data into its certificates and its private key.
+// ok is false unless the data holds at least one certificate and exactly one private key
+func splitPemBundle(data string) (certChain, privateKey string, ok bool) {
+	var certs, keys []string
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		switch {
+		case block.Type == "CERTIFICATE":
+			certs = append(certs, string(pem.EncodeToMemory(block)))
+		case strings.HasSuffix(block.Type, "PRIVATE KEY"):
+			keys = append(keys, string(pem.EncodeToMemory(block)))
+		}
+	}
+	if len(certs) == 0 || len(keys) != 1 {
+		return "", "", false
+	}
+	return strings.Join(certs, ""), keys[0], true
+}
+
+func secretKeys(secret *dependencies.Secret) []string {
+	var keys []string
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,92 @@
+package secretwatcher
+
+import (
+	"crypto/x509"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo/pkg/storage/dependencies"
+	"github.com/solo-io/gloo/test/helpers"
+)
+
+var _ = Describe("TlsCertificate", func() {
+	var (
+		ca   *helpers.TestCert
+		leaf *helpers.TestCert
+	)
+	BeforeEach(func() {
+		var err error
+		ca, err = helpers.NewTestCert("ca", nil, time.Now().Add(time.Hour), nil)
+		Expect(err).NotTo(HaveOccurred())
+		leaf, err = helpers.NewTestCert("example.com", []string{"example.com"}, time.Now().Add(time.Hour), ca)
+		Expect(err).NotTo(HaveOccurred())
+	})
+	secret := func(data map[string]string) *dependencies.Secret {
+		return &dependencies.Secret{Ref: "my-tls", Data: data}
+	}
+
+	It("reads kubernetes tls secrets", func() {
+		certChain, privateKey, format, err := TlsCertificate(secret(map[string]string{
+			"tls.crt": leaf.CertPEM,
+			"tls.key": leaf.KeyPEM,
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(format).To(Equal(TlsFormatKube))
+		Expect(certChain).To(Equal(leaf.CertPEM))
+		Expect(privateKey).To(Equal(leaf.KeyPEM))
+	})
+	It("reads gloo tls secrets", func() {
+		certChain, privateKey, format, err := TlsCertificate(secret(map[string]string{
+			"ca_chain":    leaf.CertPEM,
+			"private_key": leaf.KeyPEM,
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(format).To(Equal(TlsFormatGloo))
+		Expect(certChain).To(Equal(leaf.CertPEM))
+		Expect(privateKey).To(Equal(leaf.KeyPEM))
+	})
+	It("splits PEM bundles into the certificate chain and the private key", func() {
+		certChain, privateKey, format, err := TlsCertificate(secret(map[string]string{
+			"tls.pem": leaf.KeyPEM + leaf.CertPEM + ca.CertPEM,
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(format).To(Equal(TlsFormatPemBundle))
+		Expect(certChain).To(Equal(leaf.CertPEM + ca.CertPEM))
+		Expect(privateKey).To(Equal(leaf.KeyPEM))
+	})
+	It("names the missing key of an incomplete secret", func() {
+		_, _, _, err := TlsCertificate(secret(map[string]string{"tls.crt": leaf.CertPEM}))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("looks like a kubernetes tls secret, but has no tls.key"))
+	})
+	It("lists the supported formats and the keys found for other secrets", func() {
+		_, _, _, err := TlsCertificate(secret(map[string]string{"username": "admin", "password": "secret"}))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("secret my-tls is not a tls secret"))
+		Expect(err.Error()).To(ContainSubstring("found the keys [password, username]"))
+	})
+})
+
+var _ = Describe("UncoveredDomains", func() {
+	cert := &x509.Certificate{
+		DNSNames:    []string{"example.com", "*.Api.example.com"},
+		IPAddresses: []net.IP{net.ParseIP("10.0.0.1")},
+	}
+	It("matches names exactly, ignoring case and ports", func() {
+		Expect(UncoveredDomains([]string{"Example.com", "example.com:8443", "10.0.0.1"}, cert)).To(BeEmpty())
+	})
+	It("matches wildcard names against a single label", func() {
+		Expect(UncoveredDomains([]string{"v1.api.example.com", "*.api.example.com"}, cert)).To(BeEmpty())
+		Expect(UncoveredDomains([]string{"api.example.com", "a.v1.api.example.com"}, cert)).
+			To(Equal([]string{"api.example.com", "a.v1.api.example.com"}))
+	})
+	It("does not check catch-all domains", func() {
+		Expect(UncoveredDomains([]string{"*", ""}, cert)).To(BeEmpty())
+	})
+	It("returns the domains that are not covered", func() {
+		Expect(UncoveredDomains([]string{"example.com", "other.com", "10.0.0.2"}, cert)).To(Equal([]string{"other.com", "10.0.0.2"}))
+	})
+})
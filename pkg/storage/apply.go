@@ -0,0 +1,273 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+)
+
+// Change is a single write made by Apply
+type Change struct {
+	// EventAdded creates the object, EventUpdated updates it and EventDeleted deletes it
+	Type EventType
+	// the object to write, or the stored object for deletes.
+	// the resource version of updates and deletes is the version of the stored object
+	Object v1.ConfigObject
+	// the stored object, for updates and deletes
+	Previous v1.ConfigObject
+}
+
+func (c Change) String() string {
+	var verb string
+	switch c.Type {
+	case EventAdded:
+		verb = "create"
+	case EventUpdated:
+		verb = "update"
+	case EventDeleted:
+		verb = "delete"
+	}
+	var kind string
+	switch c.Object.(type) {
+	case *v1.Upstream:
+		kind = "upstream"
+	case *v1.VirtualService:
+		kind = "virtual service"
+	case *v1.Role:
+		kind = "role"
+	}
+	return fmt.Sprintf("%v %v %v", verb, kind, c.Object.GetName())
+}
+
+// PlanApply returns the changes that make the upstreams, virtual services and roles of a storage match config.
+// objects in config that are not stored are created, objects that differ from the stored object are updated,
+// and stored objects that are not in config are deleted. the status of stored objects is kept,
+// as is their metadata if the object in config has none.
+//
+// the changes are ordered so objects are written before the objects that reference them: upstreams,
+// virtual services and roles are created and updated in that order, then roles, virtual services
+// and upstreams are deleted
+func PlanApply(v V1, config *v1.Config) ([]Change, error) {
+	upstreams, err := v.Upstreams().List()
+	if err != nil {
+		return nil, errors.Wrap(err, "listing upstreams")
+	}
+	virtualServices, err := v.VirtualServices().List()
+	if err != nil {
+		return nil, errors.Wrap(err, "listing virtual services")
+	}
+	roles, err := v.Roles().List()
+	if err != nil {
+		return nil, errors.Wrap(err, "listing roles")
+	}
+
+	var storedUpstreams, desiredUpstreams []v1.ConfigObject
+	for _, us := range upstreams {
+		storedUpstreams = append(storedUpstreams, us)
+	}
+	for _, us := range config.GetUpstreams() {
+		desiredUpstreams = append(desiredUpstreams, us)
+	}
+	var storedVirtualServices, desiredVirtualServices []v1.ConfigObject
+	for _, vs := range virtualServices {
+		storedVirtualServices = append(storedVirtualServices, vs)
+	}
+	for _, vs := range config.GetVirtualServices() {
+		desiredVirtualServices = append(desiredVirtualServices, vs)
+	}
+	var storedRoles, desiredRoles []v1.ConfigObject
+	for _, role := range roles {
+		storedRoles = append(storedRoles, role)
+	}
+	for _, role := range config.GetRoles() {
+		desiredRoles = append(desiredRoles, role)
+	}
+
+	upstreamWrites, upstreamDeletes, err := planKind("upstream", storedUpstreams, desiredUpstreams)
+	if err != nil {
+		return nil, err
+	}
+	virtualServiceWrites, virtualServiceDeletes, err := planKind("virtual service", storedVirtualServices, desiredVirtualServices)
+	if err != nil {
+		return nil, err
+	}
+	roleWrites, roleDeletes, err := planKind("role", storedRoles, desiredRoles)
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+	changes = append(changes, upstreamWrites...)
+	changes = append(changes, virtualServiceWrites...)
+	changes = append(changes, roleWrites...)
+	changes = append(changes, roleDeletes...)
+	changes = append(changes, virtualServiceDeletes...)
+	changes = append(changes, upstreamDeletes...)
+	return changes, nil
+}
+
+// planKind returns the creates and updates, and the deletes, of a single kind of object
+func planKind(kind string, stored, desired []v1.ConfigObject) ([]Change, []Change, error) {
+	storedByName := make(map[string]v1.ConfigObject)
+	for _, obj := range stored {
+		storedByName[obj.GetName()] = obj
+	}
+	var writes, deletes []Change
+	desiredNames := make(map[string]bool)
+	for _, obj := range desired {
+		name := obj.GetName()
+		if name == "" {
+			return nil, nil, errors.Errorf("%v without a name", kind)
+		}
+		if desiredNames[name] {
+			return nil, nil, errors.Errorf("%v %v is defined more than once", kind, name)
+		}
+		desiredNames[name] = true
+
+		obj = proto.Clone(obj).(v1.ConfigObject)
+		previous, ok := storedByName[name]
+		if !ok {
+			if obj.GetMetadata() != nil {
+				obj.GetMetadata().ResourceVersion = ""
+			}
+			writes = append(writes, Change{Type: EventAdded, Object: obj})
+			continue
+		}
+		obj.SetStatus(previous.GetStatus())
+		if obj.GetMetadata() == nil && previous.GetMetadata() != nil {
+			obj.SetMetadata(proto.Clone(previous.GetMetadata()).(*v1.Metadata))
+		}
+		setResourceVersion(obj, previous.GetMetadata().GetResourceVersion())
+		if obj.GetMetadata().Namespace == "" {
+			obj.GetMetadata().Namespace = previous.GetMetadata().GetNamespace()
+		}
+		if proto.Equal(obj, previous) {
+			continue
+		}
+		writes = append(writes, Change{Type: EventUpdated, Object: obj, Previous: previous})
+	}
+	for _, obj := range stored {
+		if !desiredNames[obj.GetName()] {
+			deletes = append(deletes, Change{Type: EventDeleted, Object: obj, Previous: obj})
+		}
+	}
+	return writes, deletes, nil
+}
+
+// ApplyInOrder applies config one change at a time, in the order of PlanApply, for backends that cannot
+// write several objects atomically. if a change fails, the changes made before it are rolled back.
+// a concurrent update of an object of config fails the apply with a conflict error
+func ApplyInOrder(v V1, config *v1.Config) error {
+	changes, err := PlanApply(v, config)
+	if err != nil {
+		return err
+	}
+	var applied []Change
+	for _, change := range changes {
+		written, err := write(v, change)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to %v", change)
+			if rollbackErr := rollback(v, applied); rollbackErr != nil {
+				return errors.Wrapf(err, "applying config failed, and rolling back the applied changes failed: %v", rollbackErr)
+			}
+			return errors.Wrap(err, "applying config failed, the applied changes were rolled back")
+		}
+		change.Object = written
+		applied = append(applied, change)
+	}
+	return nil
+}
+
+// rollback undoes the applied changes in reverse order. updated objects are only restored
+// if they were not changed since, and every change is undone even if some fail
+func rollback(v V1, applied []Change) error {
+	var result error
+	for i := len(applied) - 1; i >= 0; i-- {
+		change := applied[i]
+		var undo Change
+		switch change.Type {
+		case EventAdded:
+			undo = Change{Type: EventDeleted, Object: change.Object}
+		case EventUpdated:
+			previous := proto.Clone(change.Previous).(v1.ConfigObject)
+			setResourceVersion(previous, change.Object.GetMetadata().GetResourceVersion())
+			undo = Change{Type: EventUpdated, Object: previous}
+		case EventDeleted:
+			previous := proto.Clone(change.Previous).(v1.ConfigObject)
+			setResourceVersion(previous, "")
+			undo = Change{Type: EventAdded, Object: previous}
+		}
+		if _, err := write(v, undo); err != nil {
+			result = multierror.Append(result, errors.Wrapf(err, "failed to undo %v", change))
+		}
+	}
+	return result
+}
+
+// write makes a single change, and returns the written object
+func write(v V1, change Change) (v1.ConfigObject, error) {
+	switch obj := change.Object.(type) {
+	case *v1.Upstream:
+		client := v.Upstreams()
+		switch change.Type {
+		case EventAdded:
+			created, err := client.Create(obj)
+			if err != nil {
+				return nil, err
+			}
+			return created, nil
+		case EventUpdated:
+			updated, err := client.Update(obj)
+			if err != nil {
+				return nil, err
+			}
+			return updated, nil
+		}
+		return obj, client.Delete(obj.Name)
+	case *v1.VirtualService:
+		client := v.VirtualServices()
+		switch change.Type {
+		case EventAdded:
+			created, err := client.Create(obj)
+			if err != nil {
+				return nil, err
+			}
+			return created, nil
+		case EventUpdated:
+			updated, err := client.Update(obj)
+			if err != nil {
+				return nil, err
+			}
+			return updated, nil
+		}
+		return obj, client.Delete(obj.Name)
+	case *v1.Role:
+		client := v.Roles()
+		switch change.Type {
+		case EventAdded:
+			created, err := client.Create(obj)
+			if err != nil {
+				return nil, err
+			}
+			return created, nil
+		case EventUpdated:
+			updated, err := client.Update(obj)
+			if err != nil {
+				return nil, err
+			}
+			return updated, nil
+		}
+		return obj, client.Delete(obj.Name)
+	}
+	return nil, errors.Errorf("unknown config object type %T", change.Object)
+}
+
+func setResourceVersion(obj v1.ConfigObject, resourceVersion string) {
+	if obj.GetMetadata() == nil {
+		obj.SetMetadata(&v1.Metadata{})
+	}
+	obj.GetMetadata().ResourceVersion = resourceVersion
+}
@@ -30,20 +30,14 @@ func (c *ConsulStorageClient) Create(item *StorableItem) (*StorableItem, error)
 		return nil, errors.Wrapf(err, "converting %s to kv pair", item.GetName())
 	}
 
-	// error if the key already exists
-	existingP, _, err := c.consul.KV().Get(p.Key, &api.QueryOptions{RequireConsistent: true})
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to query consul")
-	}
-	if existingP != nil {
+	// create the item. a CAS with index 0 only succeeds if the key doesn't exist yet
+	p.ModifyIndex = 0
+	if success, _, err := c.consul.KV().CAS(p, nil); err != nil {
+		return nil, errors.Wrapf(err, "writing kv pair %s", p.Key)
+	} else if !success {
 		return nil, storage.NewAlreadyExistsErr(
 			errors.Errorf("key found for storageItem %s: %s", item.GetName(), p.Key))
 	}
-
-	// create the item
-	if _, err := c.consul.KV().Put(p, nil); err != nil {
-		return nil, errors.Wrapf(err, "writing kv pair %s", p.Key)
-	}
 	cfgObject, err := c.Get(item.GetName())
 	if err != nil {
 		return nil, errors.Wrapf(err, "getting newly created cfg object %s", p.Key)
@@ -52,6 +46,9 @@ func (c *ConsulStorageClient) Create(item *StorableItem) (*StorableItem, error)
 }
 
 func (c *ConsulStorageClient) Update(item *StorableItem) (*StorableItem, error) {
+	if item.GetResourceVersion() == "" {
+		return nil, errors.New("resource version must be set for update operations")
+	}
 	updatedP, err := toKVPair(c.rootPath, item)
 	if err != nil {
 		return nil, errors.Wrapf(err, "converting %s to kv pair", item.GetName())
@@ -66,11 +63,12 @@ func (c *ConsulStorageClient) Update(item *StorableItem) (*StorableItem, error)
 		return nil, errors.Errorf("key not found for storageItem %s: %s", item.GetName(), updatedP.Key)
 	}
 
-	// update the item
+	// update the item, only if the resource version is the latest
 	if success, _, err := c.consul.KV().CAS(updatedP, nil); err != nil {
 		return nil, errors.Wrapf(err, "writing kv pair %s", updatedP.Key)
 	} else if !success {
-		return nil, errors.Errorf("resource version was invalid for storageItem: %s", item.GetName())
+		return nil, storage.NewConflictErr(errors.Errorf("resource version %s was invalid for storageItem: %s, the current version is %v",
+			item.GetResourceVersion(), item.GetName(), existingP.ModifyIndex))
 	}
 
 	cfgObject, err := c.Get(item.GetName())
@@ -90,6 +88,40 @@ func (c *ConsulStorageClient) Delete(name string) error {
 	return nil
 }
 
+// CreateOp returns the transaction operation that creates item, to write several items in one transaction
+func (c *ConsulStorageClient) CreateOp(item *StorableItem) (*api.KVTxnOp, error) {
+	p, err := toKVPair(c.rootPath, item)
+	if err != nil {
+		return nil, errors.Wrapf(err, "converting %s to kv pair", item.GetName())
+	}
+	// a CAS with index 0 only succeeds if the key doesn't exist yet
+	return &api.KVTxnOp{Verb: api.KVCAS, Key: p.Key, Value: p.Value, Flags: p.Flags, Index: 0}, nil
+}
+
+// UpdateOp returns the transaction operation that updates item, if its resource version is the latest
+func (c *ConsulStorageClient) UpdateOp(item *StorableItem) (*api.KVTxnOp, error) {
+	if item.GetResourceVersion() == "" {
+		return nil, errors.New("resource version must be set for update operations")
+	}
+	p, err := toKVPair(c.rootPath, item)
+	if err != nil {
+		return nil, errors.Wrapf(err, "converting %s to kv pair", item.GetName())
+	}
+	return &api.KVTxnOp{Verb: api.KVCAS, Key: p.Key, Value: p.Value, Flags: p.Flags, Index: p.ModifyIndex}, nil
+}
+
+// DeleteOp returns the transaction operation that deletes item, if its resource version is the latest
+func (c *ConsulStorageClient) DeleteOp(item *StorableItem) (*api.KVTxnOp, error) {
+	if item.GetResourceVersion() == "" {
+		return nil, errors.New("resource version must be set for delete operations")
+	}
+	p, err := toKVPair(c.rootPath, item)
+	if err != nil {
+		return nil, errors.Wrapf(err, "converting %s to kv pair", item.GetName())
+	}
+	return &api.KVTxnOp{Verb: api.KVDeleteCAS, Key: p.Key, Index: p.ModifyIndex}, nil
+}
+
 func (c *ConsulStorageClient) Get(name string) (*StorableItem, error) {
 	key := key(c.rootPath, name)
 	p, _, err := c.consul.KV().Get(key, nil)
@@ -162,21 +194,16 @@ func (c *ConsulStorageClient) Watch(handlers ...StorableItemEventHandler) (*stor
 		}
 		// update index
 		lastIndex = meta.LastIndex
-		switch {
-		case len(upstreams) > 0:
-			for _, h := range handlers {
+		// handlers are called with empty lists too, so they see the last object being deleted
+		for _, h := range handlers {
+			switch {
+			case h.UpstreamEventHandler != nil:
 				h.UpstreamEventHandler.OnUpdate(upstreams, nil)
-			}
-		case len(virtualServices) > 0:
-			for _, h := range handlers {
+			case h.VirtualServiceEventHandler != nil:
 				h.VirtualServiceEventHandler.OnUpdate(virtualServices, nil)
-			}
-		case len(roles) > 0:
-			for _, h := range handlers {
+			case h.RoleEventHandler != nil:
 				h.RoleEventHandler.OnUpdate(roles, nil)
-			}
-		case len(files) > 0:
-			for _, h := range handlers {
+			case h.FileEventHandler != nil:
 				h.FileEventHandler.OnUpdate(files, nil)
 			}
 		}
@@ -0,0 +1,35 @@
+package consul_test
+
+import (
+	"time"
+
+	"github.com/hashicorp/consul/api"
+
+	"github.com/solo-io/gloo/pkg/storage"
+	. "github.com/solo-io/gloo/pkg/storage/consul"
+	"github.com/solo-io/gloo/pkg/storage/storagetest"
+)
+
+// each namespace is a root path in the consul agent started by the suite
+type consulBackend struct {
+	rootPaths []string
+}
+
+func (b *consulBackend) NewStorage(namespace string) (storage.Interface, error) {
+	b.rootPaths = append(b.rootPaths, namespace)
+	return NewStorage(api.DefaultConfig(), namespace, time.Second)
+}
+
+func (b *consulBackend) Cleanup() {
+	client, err := api.NewClient(api.DefaultConfig())
+	if err != nil {
+		return
+	}
+	for _, rootPath := range b.rootPaths {
+		client.KV().DeleteTree(rootPath, nil)
+	}
+}
+
+var _ = storagetest.Conformance("consul", func() storagetest.Backend {
+	return &consulBackend{}
+})
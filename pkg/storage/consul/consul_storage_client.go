@@ -1,14 +1,19 @@
 package consul
 
 import (
+	"strings"
 	"time"
 
 	"github.com/hashicorp/consul/api"
 	"github.com/pkg/errors"
+	"github.com/solo-io/gloo/pkg/api/types/v1"
 	"github.com/solo-io/gloo/pkg/storage"
 	"github.com/solo-io/gloo/pkg/storage/base"
 )
 
+// consul limits the operations of a transaction to 64
+const maxTxnOps = 64
+
 //go:generate go run ${GOPATH}/src/github.com/solo-io/gloo/pkg/storage/generate/generate_clients.go -f ${GOPATH}/src/github.com/solo-io/gloo/pkg/storage/consul/client_template.go.tmpl -o ${GOPATH}/src/github.com/solo-io/gloo/pkg/storage/consul/
 type Client struct {
 	v1 *v1client
@@ -26,6 +31,7 @@ func NewStorage(cfg *api.Config, rootPath string, syncFrequency time.Duration) (
 
 	return &Client{
 		v1: &v1client{
+			consul: client,
 			upstreams: &upstreamsClient{
 				base: base.NewConsulStorageClient(rootPath+"/upstreams", client),
 			},
@@ -44,9 +50,10 @@ func (c *Client) V1() storage.V1 {
 }
 
 type v1client struct {
+	consul          *api.Client
 	upstreams       *upstreamsClient
 	virtualServices *virtualServicesClient
-	roles *rolesClient
+	roles           *rolesClient
 }
 
 func (c *v1client) Register() error {
@@ -64,3 +71,66 @@ func (c *v1client) VirtualServices() storage.VirtualServices {
 func (c *v1client) Roles() storage.Roles {
 	return c.roles
 }
+
+// Apply writes every change in a single transaction, so watches see them at the same index.
+// if an object changed since the changes were planned, nothing is written and a conflict error is returned
+// configs with more than maxTxnOps changes are not written, and a too many changes error is returned
+func (c *v1client) Apply(config *v1.Config) error {
+	changes, err := storage.PlanApply(c, config)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	if len(changes) > maxTxnOps {
+		return storage.NewTooManyChangesErr(len(changes), maxTxnOps)
+	}
+	var ops api.KVTxnOps
+	for _, change := range changes {
+		op, err := c.txnOp(change)
+		if err != nil {
+			return errors.Wrapf(err, "failed to %v", change)
+		}
+		ops = append(ops, op)
+	}
+	ok, resp, _, err := c.consul.KV().Txn(ops, nil)
+	if err != nil {
+		return errors.Wrap(err, "applying config")
+	}
+	if !ok {
+		var reasons []string
+		if resp != nil {
+			for _, txnErr := range resp.Errors {
+				reasons = append(reasons, txnErr.What)
+			}
+		}
+		return storage.NewConflictErr(errors.Errorf("config objects changed while applying the config, no change was applied: %v",
+			strings.Join(reasons, ", ")))
+	}
+	return nil
+}
+
+func (c *v1client) txnOp(change storage.Change) (*api.KVTxnOp, error) {
+	var (
+		client *base.ConsulStorageClient
+		item   *base.StorableItem
+	)
+	switch obj := change.Object.(type) {
+	case *v1.Upstream:
+		client, item = c.upstreams.base, &base.StorableItem{Upstream: obj}
+	case *v1.VirtualService:
+		client, item = c.virtualServices.base, &base.StorableItem{VirtualService: obj}
+	case *v1.Role:
+		client, item = c.roles.base, &base.StorableItem{Role: obj}
+	default:
+		return nil, errors.Errorf("unknown config object type %T", change.Object)
+	}
+	switch change.Type {
+	case storage.EventAdded:
+		return client.CreateOp(item)
+	case storage.EventUpdated:
+		return client.UpdateOp(item)
+	}
+	return client.DeleteOp(item)
+}
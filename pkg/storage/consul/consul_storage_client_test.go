@@ -111,6 +111,22 @@ var _ = Describe("ConsulStorageClient", func() {
 					Expect(err).NotTo(HaveOccurred())
 					Expect(out.Type).To(Equal(changed.Type))
 				})
+				It("returns a conflict error if the upstream changed since it was read", func() {
+					client, err := NewStorage(api.DefaultConfig(), rootPath, time.Second)
+					Expect(err).NotTo(HaveOccurred())
+					input := &v1.Upstream{
+						Name:              "myupstream",
+						Type:              "foo",
+						ConnectionTimeout: time.Second,
+					}
+					us, err := client.V1().Upstreams().Create(input)
+					Expect(err).NotTo(HaveOccurred())
+					_, err = client.V1().Upstreams().Update(us)
+					Expect(err).NotTo(HaveOccurred())
+					_, err = client.V1().Upstreams().Update(us)
+					Expect(err).To(HaveOccurred())
+					Expect(storage.IsConflict(err)).To(BeTrue())
+				})
 				Describe("get", func() {
 					It("fails if the upstream doesn't exist", func() {
 						client, err := NewStorage(api.DefaultConfig(), rootPath, time.Second)
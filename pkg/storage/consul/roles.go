@@ -58,6 +58,14 @@ func (c *rolesClient) List() ([]*v1.Role, error) {
 	return roles, nil
 }
 
+func (c *rolesClient) ListWithOptions(opts storage.ListOptions) ([]*v1.Role, error) {
+	list, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	return storage.FilterRoles(list, opts), nil
+}
+
 func (c *rolesClient) Watch(handlers ...storage.RoleEventHandler) (*storage.Watcher, error) {
 	var baseHandlers []base.StorableItemEventHandler
 	for _, h := range handlers {
@@ -65,3 +73,17 @@ func (c *rolesClient) Watch(handlers ...storage.RoleEventHandler) (*storage.Watc
 	}
 	return c.base.Watch(baseHandlers...)
 }
+
+func (c *rolesClient) WatchWithOptions(opts storage.ListOptions, handlers ...storage.RoleEventHandler) (*storage.Watcher, error) {
+	var filtered []storage.RoleEventHandler
+	for _, h := range handlers {
+		filtered = append(filtered, storage.FilterRoleEventHandler(opts, h))
+	}
+	return c.Watch(filtered...)
+}
+
+// the watches of this backend only deliver lists, so they cannot resume from a resource version.
+// the index of a blocking query does not tell which keys were deleted since, so it can not be resumed from either
+func (c *rolesClient) WatchEvents(opts storage.WatchOptions, onEvent func(event storage.RoleEvent)) (*storage.Watcher, error) {
+	return storage.RoleEventsFromLists(opts, c.Watch, onEvent)
+}
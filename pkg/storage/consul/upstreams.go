@@ -58,6 +58,14 @@ func (c *upstreamsClient) List() ([]*v1.Upstream, error) {
 	return upstreams, nil
 }
 
+func (c *upstreamsClient) ListWithOptions(opts storage.ListOptions) ([]*v1.Upstream, error) {
+	list, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	return storage.FilterUpstreams(list, opts), nil
+}
+
 func (c *upstreamsClient) Watch(handlers ...storage.UpstreamEventHandler) (*storage.Watcher, error) {
 	var baseHandlers []base.StorableItemEventHandler
 	for _, h := range handlers {
@@ -65,3 +73,17 @@ func (c *upstreamsClient) Watch(handlers ...storage.UpstreamEventHandler) (*stor
 	}
 	return c.base.Watch(baseHandlers...)
 }
+
+func (c *upstreamsClient) WatchWithOptions(opts storage.ListOptions, handlers ...storage.UpstreamEventHandler) (*storage.Watcher, error) {
+	var filtered []storage.UpstreamEventHandler
+	for _, h := range handlers {
+		filtered = append(filtered, storage.FilterUpstreamEventHandler(opts, h))
+	}
+	return c.Watch(filtered...)
+}
+
+// the watches of this backend only deliver lists, so they cannot resume from a resource version.
+// the index of a blocking query does not tell which keys were deleted since, so it can not be resumed from either
+func (c *upstreamsClient) WatchEvents(opts storage.WatchOptions, onEvent func(event storage.UpstreamEvent)) (*storage.Watcher, error) {
+	return storage.UpstreamEventsFromLists(opts, c.Watch, onEvent)
+}
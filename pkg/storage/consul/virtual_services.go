@@ -58,6 +58,14 @@ func (c *virtualServicesClient) List() ([]*v1.VirtualService, error) {
 	return virtualServices, nil
 }
 
+func (c *virtualServicesClient) ListWithOptions(opts storage.ListOptions) ([]*v1.VirtualService, error) {
+	list, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	return storage.FilterVirtualServices(list, opts), nil
+}
+
 func (c *virtualServicesClient) Watch(handlers ...storage.VirtualServiceEventHandler) (*storage.Watcher, error) {
 	var baseHandlers []base.StorableItemEventHandler
 	for _, h := range handlers {
@@ -65,3 +73,17 @@ func (c *virtualServicesClient) Watch(handlers ...storage.VirtualServiceEventHan
 	}
 	return c.base.Watch(baseHandlers...)
 }
+
+func (c *virtualServicesClient) WatchWithOptions(opts storage.ListOptions, handlers ...storage.VirtualServiceEventHandler) (*storage.Watcher, error) {
+	var filtered []storage.VirtualServiceEventHandler
+	for _, h := range handlers {
+		filtered = append(filtered, storage.FilterVirtualServiceEventHandler(opts, h))
+	}
+	return c.Watch(filtered...)
+}
+
+// the watches of this backend only deliver lists, so they cannot resume from a resource version.
+// the index of a blocking query does not tell which keys were deleted since, so it can not be resumed from either
+func (c *virtualServicesClient) WatchEvents(opts storage.WatchOptions, onEvent func(event storage.VirtualServiceEvent)) (*storage.Watcher, error) {
+	return storage.VirtualServiceEventsFromLists(opts, c.Watch, onEvent)
+}
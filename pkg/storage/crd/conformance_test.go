@@ -0,0 +1,96 @@
+package crd_test
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	apiextsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	kuberrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	kubetesting "k8s.io/client-go/testing"
+
+	"github.com/solo-io/gloo/pkg/storage"
+	. "github.com/solo-io/gloo/pkg/storage/crd"
+	crdfake "github.com/solo-io/gloo/pkg/storage/crd/client/clientset/versioned/fake"
+	"github.com/solo-io/gloo/pkg/storage/storagetest"
+)
+
+// every namespace is stored in the same fake clientsets
+type crdBackend struct {
+	crds    *crdfake.Clientset
+	apiexts *apiextsfake.Clientset
+	kube    *kubefake.Clientset
+}
+
+func (b *crdBackend) NewStorage(namespace string) (storage.Interface, error) {
+	return NewStorageWithClients(b.crds, b.apiexts, b.kube, namespace, nil, time.Minute), nil
+}
+
+func (b *crdBackend) Cleanup() {}
+
+// fake clientsets only deliver the changes made after a watch starts
+func (b *crdBackend) NoWatchResume() {}
+
+var _ = storagetest.Conformance("crd", func() storagetest.Backend {
+	crds := crdfake.NewSimpleClientset()
+	withResourceVersions(&crds.Fake)
+	return &crdBackend{
+		crds:    crds,
+		apiexts: apiextsfake.NewSimpleClientset(),
+		kube:    kubefake.NewSimpleClientset(),
+	}
+})
+
+// the object tracker of fake clientsets ignores resource versions.
+// this reactor assigns them the way the api server does, and rejects updates
+// of outdated versions with a conflict
+func withResourceVersions(fake *kubetesting.Fake) {
+	var (
+		latest   uint64
+		versions = make(map[string]uint64)
+	)
+	key := func(action kubetesting.Action, name string) string {
+		return action.GetResource().Resource + "/" + action.GetNamespace() + "/" + name
+	}
+	fake.PrependReactor("*", "*", func(action kubetesting.Action) (bool, runtime.Object, error) {
+		switch action := action.(type) {
+		case kubetesting.CreateAction:
+			obj, err := meta.Accessor(action.GetObject())
+			if err != nil {
+				return true, nil, err
+			}
+			k := key(action, obj.GetName())
+			// the tracker returns the already exists error
+			if _, ok := versions[k]; ok {
+				return false, nil, nil
+			}
+			latest++
+			versions[k] = latest
+			obj.SetResourceVersion(strconv.FormatUint(latest, 10))
+		case kubetesting.UpdateAction:
+			obj, err := meta.Accessor(action.GetObject())
+			if err != nil {
+				return true, nil, err
+			}
+			k := key(action, obj.GetName())
+			// the tracker returns the not found error
+			current, ok := versions[k]
+			if !ok {
+				return false, nil, nil
+			}
+			if obj.GetResourceVersion() != strconv.FormatUint(current, 10) {
+				return true, nil, kuberrs.NewConflict(action.GetResource().GroupResource(), obj.GetName(),
+					errors.Errorf("resource version %v is outdated", obj.GetResourceVersion()))
+			}
+			latest++
+			versions[k] = latest
+			obj.SetResourceVersion(strconv.FormatUint(latest, 10))
+		case kubetesting.DeleteAction:
+			delete(versions, key(action, action.GetName()))
+		}
+		return false, nil, nil
+	})
+}
@@ -1,6 +1,8 @@
 package crd
 
 import (
+	"strings"
+
 	"github.com/gogo/protobuf/proto"
 	"github.com/pkg/errors"
 	"github.com/solo-io/gloo/pkg/api/types/v1"
@@ -9,8 +11,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// ConfigObjectToCrd converts a config object to a crd in its namespace, or the given namespace if it has none.
+// the name can be qualified with the namespace as <namespace>/<name>
 func ConfigObjectToCrd(namespace string, item v1.ConfigObject) (metav1.Object, error) {
-	name := item.GetName()
+	namespace, name := splitName(namespace, item.GetName())
+	qualified := strings.Contains(item.GetName(), "/")
 	var (
 		status *v1.Status
 		ok     bool
@@ -24,13 +29,18 @@ func ConfigObjectToCrd(namespace string, item v1.ConfigObject) (metav1.Object, e
 	var (
 		resourceVersion string
 		annotations     map[string]string
+		labels          map[string]string
 	)
 	if item.GetMetadata() != nil {
 		resourceVersion = item.GetMetadata().ResourceVersion
 		if item.GetMetadata().Namespace != "" {
+			if qualified && item.GetMetadata().Namespace != namespace {
+				return nil, errors.Errorf("name %v is qualified with a different namespace than its metadata namespace %v", item.GetName(), item.GetMetadata().Namespace)
+			}
 			namespace = item.GetMetadata().Namespace
 		}
 		annotations = item.GetMetadata().Annotations
+		labels = item.GetMetadata().Labels
 	}
 
 	// clone and remove fields
@@ -71,6 +81,7 @@ func ConfigObjectToCrd(namespace string, item v1.ConfigObject) (metav1.Object, e
 		Namespace:       namespace,
 		ResourceVersion: resourceVersion,
 		Annotations:     annotations,
+		Labels:          labels,
 	}
 
 	var crdObject metav1.Object
@@ -117,6 +128,7 @@ func ConfigObjectFromCrd(objectMeta metav1.ObjectMeta,
 		ResourceVersion: objectMeta.ResourceVersion,
 		Namespace:       objectMeta.Namespace,
 		Annotations:     objectMeta.Annotations,
+		Labels:          objectMeta.Labels,
 	})
 	item.SetStatus(status)
 	return nil
@@ -12,6 +12,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
+	"github.com/solo-io/gloo/pkg/api/types/v1"
 	"github.com/solo-io/gloo/pkg/log"
 	"github.com/solo-io/gloo/pkg/storage"
 	crdclientset "github.com/solo-io/gloo/pkg/storage/crd/client/clientset/versioned"
@@ -24,9 +25,13 @@ type Client struct {
 }
 
 func NewStorage(cfg *rest.Config, namespace string, syncFrequency time.Duration) (storage.Interface, error) {
-	if namespace == "" {
-		namespace = GlooDefaultNamespace
-	}
+	return NewStorageForNamespaces(cfg, namespace, nil, syncFrequency)
+}
+
+// NewStorageForNamespaces creates a storage that lists and watches the objects in each of namespaces,
+// or in every namespace if they contain AllNamespaces. namespace is the default namespace,
+// objects in other namespaces are named <namespace>/<name>. namespaces default to namespace
+func NewStorageForNamespaces(cfg *rest.Config, namespace string, namespaces []string, syncFrequency time.Duration) (storage.Interface, error) {
 	crdClient, err := crdclientset.NewForConfig(cfg)
 	if err != nil {
 		return nil, err
@@ -39,28 +44,43 @@ func NewStorage(cfg *rest.Config, namespace string, syncFrequency time.Duration)
 	if err != nil {
 		return nil, err
 	}
+	return NewStorageWithClients(crdClient, apiextClient, kubeClient, namespace, namespaces, syncFrequency), nil
+}
+
+// NewStorageWithClients creates the storage from existing clients, e.g. fake clientsets in tests
+func NewStorageWithClients(crdClient crdclientset.Interface, apiextClient apiexts.Interface, kubeClient kubernetes.Interface,
+	namespace string, namespaces []string, syncFrequency time.Duration) storage.Interface {
+	if namespace == "" {
+		namespace = GlooDefaultNamespace
+	}
+	if len(namespaces) == 0 {
+		namespaces = []string{namespace}
+	}
 	return &Client{
 		v1: &v1client{
 			upstreams: &upstreamsClient{
 				crds:          crdClient,
 				namespace:     namespace,
+				namespaces:    namespaces,
 				syncFrequency: syncFrequency,
 			},
 			virtualServices: &virtualServicesClient{
 				crds:          crdClient,
 				namespace:     namespace,
+				namespaces:    namespaces,
 				syncFrequency: syncFrequency,
 			},
 			roles: &rolesClient{
 				crds:          crdClient,
 				namespace:     namespace,
+				namespaces:    namespaces,
 				syncFrequency: syncFrequency,
 			},
 			apiexts:    apiextClient,
 			kubeclient: kubeClient,
 			namespace:  namespace,
 		},
-	}, nil
+	}
 }
 
 func (c *Client) V1() storage.V1 {
@@ -119,3 +139,8 @@ func (c *v1client) VirtualServices() storage.VirtualServices {
 func (c *v1client) Roles() storage.Roles {
 	return c.roles
 }
+
+// kubernetes has no transactions across objects, so the changes are applied in order
+func (c *v1client) Apply(config *v1.Config) error {
+	return storage.ApplyInOrder(c, config)
+}
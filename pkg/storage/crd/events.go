@@ -0,0 +1,114 @@
+package crd
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	kuberrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/solo-io/gloo/pkg/log"
+	"github.com/solo-io/gloo/pkg/storage"
+)
+
+// how long to wait before resuming a failed watch
+const watchRetryInterval = time.Second
+
+// watchEvents delivers the changes to the objects of lw after resourceVersion, or every object
+// followed by the changes if resourceVersion is empty. kubernetes closes watches regularly,
+// they are resumed after the last delivered change until stop is closed.
+// if the api server no longer has the changes since, an expired error is sent to errs
+func watchEvents(kind, resourceVersion string, lw *cache.ListWatch, deliver func(eventType storage.EventType, obj runtime.Object, resourceVersion string), stop <-chan struct{}, errs chan error) {
+	for {
+		var err error
+		resourceVersion, err = watchEventsOnce(resourceVersion, lw, deliver, stop)
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		if storage.IsResourceVersionExpired(err) {
+			select {
+			case errs <- err:
+			case <-stop:
+			}
+			return
+		}
+		if err != nil {
+			log.Warnf("watching %v failed, resuming the watch: %v", kind, err)
+			select {
+			case <-time.After(watchRetryInterval):
+			case <-stop:
+				return
+			}
+		}
+	}
+}
+
+// watchEventsOnce runs a single kubernetes watch, and returns the resource version of the last delivered change
+func watchEventsOnce(resourceVersion string, lw *cache.ListWatch, deliver func(eventType storage.EventType, obj runtime.Object, resourceVersion string), stop <-chan struct{}) (string, error) {
+	if resourceVersion == "" {
+		list, err := lw.List(metav1.ListOptions{})
+		if err != nil {
+			return "", errors.Wrap(err, "failed performing list api request")
+		}
+		listMeta, err := meta.ListAccessor(list)
+		if err != nil {
+			return "", err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return "", err
+		}
+		// every object is delivered at the version of the list, the watch continues from there
+		for _, item := range items {
+			deliver(storage.EventAdded, item, listMeta.GetResourceVersion())
+		}
+		resourceVersion = listMeta.GetResourceVersion()
+	}
+
+	w, err := lw.Watch(metav1.ListOptions{ResourceVersion: resourceVersion})
+	if err != nil {
+		return resourceVersion, errors.Wrap(err, "failed performing watch api request")
+	}
+	defer w.Stop()
+	for {
+		select {
+		case <-stop:
+			return resourceVersion, nil
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return resourceVersion, nil
+			}
+			var eventType storage.EventType
+			switch event.Type {
+			case watch.Added:
+				eventType = storage.EventAdded
+			case watch.Modified:
+				eventType = storage.EventUpdated
+			case watch.Deleted:
+				eventType = storage.EventDeleted
+			case watch.Error:
+				err := kuberrs.FromObject(event.Object)
+				// the api server answers with 410 gone once the resource version was compacted
+				if status, ok := err.(kuberrs.APIStatus); ok && status.Status().Code == http.StatusGone {
+					return resourceVersion, storage.NewResourceVersionExpiredErr(err)
+				}
+				return resourceVersion, err
+			default:
+				continue
+			}
+			obj, err := meta.Accessor(event.Object)
+			if err != nil {
+				return resourceVersion, err
+			}
+			deliver(eventType, event.Object, obj.GetResourceVersion())
+			resourceVersion = obj.GetResourceVersion()
+		}
+	}
+}
@@ -1,3 +1,53 @@
 package crd
 
+import (
+	"strings"
+
+	"github.com/solo-io/gloo/pkg/storage"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
 const GlooDefaultNamespace = "gloo-system"
+
+// AllNamespaces can be passed as one of the namespaces of a storage to list and watch every namespace
+const AllNamespaces = "*"
+
+// objects outside the default namespace of a storage are named <namespace>/<name>,
+// so their names are unique across every namespace the storage lists
+func qualifiedName(defaultNamespace string, meta metav1.ObjectMeta) string {
+	if meta.Namespace == "" || meta.Namespace == defaultNamespace {
+		return meta.Name
+	}
+	return meta.Namespace + "/" + meta.Name
+}
+
+// splitName returns the namespace and kubernetes name of an object name, qualified or not
+func splitName(defaultNamespace, name string) (string, string) {
+	if i := strings.Index(name, "/"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return defaultNamespace, name
+}
+
+// selectedNamespaces returns the namespaces to list for opts, out of the namespaces of a storage
+func selectedNamespaces(namespaces []string, opts storage.ListOptions) []string {
+	for _, namespace := range namespaces {
+		if namespace == AllNamespaces {
+			// metav1.NamespaceAll lists every namespace
+			return []string{opts.Namespace}
+		}
+		if opts.Namespace == namespace {
+			return []string{namespace}
+		}
+	}
+	if opts.Namespace != "" {
+		// the storage does not list the namespace
+		return nil
+	}
+	return namespaces
+}
+
+func labelSelector(opts storage.ListOptions) string {
+	return labels.SelectorFromSet(labels.Set(opts.Selector)).String()
+}
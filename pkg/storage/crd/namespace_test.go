@@ -0,0 +1,100 @@
+package crd_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apiextsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/storage"
+	. "github.com/solo-io/gloo/pkg/storage/crd"
+	crdfake "github.com/solo-io/gloo/pkg/storage/crd/client/clientset/versioned/fake"
+)
+
+var _ = Describe("multiple namespaces", func() {
+	var (
+		crds *crdfake.Clientset
+		// a storage of every namespace, used to create the objects of the tests
+		all storage.Interface
+	)
+	newStorage := func(namespaces ...string) storage.Interface {
+		return NewStorageWithClients(crds, apiextsfake.NewSimpleClientset(), kubefake.NewSimpleClientset(), "default", namespaces, time.Minute)
+	}
+	create := func(namespace, name string, labels map[string]string) {
+		_, err := all.V1().Upstreams().Create(&v1.Upstream{
+			Name:     name,
+			Type:     "test",
+			Metadata: &v1.Metadata{Namespace: namespace, Labels: labels},
+		})
+		Expect(err).NotTo(HaveOccurred())
+	}
+	BeforeEach(func() {
+		crds = crdfake.NewSimpleClientset()
+		withResourceVersions(&crds.Fake)
+		all = newStorage(AllNamespaces)
+		create("default", "a", nil)
+		create("team-a", "b", map[string]string{"tier": "edge"})
+		create("team-b", "c", map[string]string{"tier": "edge"})
+	})
+
+	It("qualifies the names of objects outside the default namespace", func() {
+		list, err := newStorage("default", "team-a").V1().Upstreams().List()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(upstreamNames(list)).To(ConsistOf("a", "team-a/b"))
+	})
+	It("lists every namespace", func() {
+		list, err := all.V1().Upstreams().List()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(upstreamNames(list)).To(ConsistOf("a", "team-a/b", "team-b/c"))
+
+		list, err = all.V1().Upstreams().ListWithOptions(storage.ListOptions{Namespace: "team-b"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(upstreamNames(list)).To(ConsistOf("team-b/c"))
+	})
+	It("lists the objects matching a selector across namespaces", func() {
+		list, err := all.V1().Upstreams().ListWithOptions(storage.ListOptions{Selector: map[string]string{"tier": "edge"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(upstreamNames(list)).To(ConsistOf("team-a/b", "team-b/c"))
+	})
+	It("does not list namespaces the storage does not watch", func() {
+		list, err := newStorage("default").V1().Upstreams().ListWithOptions(storage.ListOptions{Namespace: "team-a"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list).To(BeEmpty())
+	})
+	It("gets, updates and deletes objects by their qualified names", func() {
+		client := all.V1().Upstreams()
+		us, err := client.Get("team-a/b")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(us.Metadata.Namespace).To(Equal("team-a"))
+
+		us.Type = "modified"
+		updated, err := client.Update(us)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated.Name).To(Equal("team-a/b"))
+		Expect(updated.Type).To(Equal("modified"))
+
+		Expect(client.Delete("team-a/b")).To(Succeed())
+		_, err = client.Get("team-a/b")
+		Expect(err).To(HaveOccurred())
+		_, err = client.Get("team-b/c")
+		Expect(err).NotTo(HaveOccurred())
+	})
+	It("rejects names qualified with a different namespace than the metadata", func() {
+		_, err := all.V1().Upstreams().Create(&v1.Upstream{
+			Name:     "team-a/d",
+			Metadata: &v1.Metadata{Namespace: "team-b"},
+		})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+func upstreamNames(list []*v1.Upstream) []string {
+	var names []string
+	for _, us := range list {
+		names = append(names, us.Name)
+	}
+	return names
+}
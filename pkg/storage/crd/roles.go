@@ -1,6 +1,7 @@
 package crd
 
 import (
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -10,7 +11,8 @@ import (
 	crdv1 "github.com/solo-io/gloo/pkg/storage/crd/solo.io/v1"
 	apiexts "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
 
 	"github.com/solo-io/gloo/pkg/storage/crud"
 	kuberrs "k8s.io/apimachinery/pkg/api/errors"
@@ -22,7 +24,9 @@ type rolesClient struct {
 	crds    crdclientset.Interface
 	apiexts apiexts.Interface
 	// write and read objects to this namespace if not specified on the GlooObjects
-	namespace     string
+	namespace string
+	// list and watch objects in these namespaces
+	namespaces    []string
 	syncFrequency time.Duration
 }
 
@@ -35,56 +39,115 @@ func (c *rolesClient) Update(item *v1.Role) (*v1.Role, error) {
 }
 
 func (c *rolesClient) Delete(name string) error {
-	return c.crds.GlooV1().Roles(c.namespace).Delete(name, nil)
+	namespace, name := splitName(c.namespace, name)
+	return c.crds.GlooV1().Roles(namespace).Delete(name, nil)
 }
 
 func (c *rolesClient) Get(name string) (*v1.Role, error) {
-	crdRole, err := c.crds.GlooV1().Roles(c.namespace).Get(name, metav1.GetOptions{})
+	namespace, name := splitName(c.namespace, name)
+	crdRole, err := c.crds.GlooV1().Roles(namespace).Get(name, metav1.GetOptions{})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed performing get api request")
 	}
-	var returnedRole v1.Role
-	if err := ConfigObjectFromCrd(
-		crdRole.ObjectMeta,
-		crdRole.Spec,
-		crdRole.Status,
-		&returnedRole); err != nil {
-		return nil, errors.Wrap(err, "converting returned crd to role")
-	}
-	return &returnedRole, nil
+	return roleFromCrd(c.namespace, crdRole)
 }
 
 func (c *rolesClient) List() ([]*v1.Role, error) {
-	crdList, err := c.crds.GlooV1().Roles(c.namespace).List(metav1.ListOptions{})
-	if err != nil {
-		return nil, errors.Wrap(err, "failed performing list api request")
-	}
+	return c.ListWithOptions(storage.ListOptions{})
+}
+
+func (c *rolesClient) ListWithOptions(opts storage.ListOptions) ([]*v1.Role, error) {
 	var returnedRoles []*v1.Role
-	for _, crdRole := range crdList.Items {
-		var returnedRole v1.Role
-		if err := ConfigObjectFromCrd(
-			crdRole.ObjectMeta,
-			crdRole.Spec,
-			crdRole.Status,
-			&returnedRole); err != nil {
-			return nil, errors.Wrap(err, "converting returned crd to role")
+	for _, namespace := range selectedNamespaces(c.namespaces, opts) {
+		crdList, err := c.crds.GlooV1().Roles(namespace).List(metav1.ListOptions{LabelSelector: labelSelector(opts)})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed performing list api request")
+		}
+		for i := range crdList.Items {
+			returnedRole, err := roleFromCrd(c.namespace, &crdList.Items[i])
+			if err != nil {
+				return nil, err
+			}
+			returnedRoles = append(returnedRoles, returnedRole)
 		}
-		returnedRoles = append(returnedRoles, &returnedRole)
 	}
 	return returnedRoles, nil
 }
 
-func (u *rolesClient) Watch(handlers ...storage.RoleEventHandler) (*storage.Watcher, error) {
-	lw := cache.NewListWatchFromClient(u.crds.GlooV1().RESTClient(), crdv1.RoleCRD.Plural, u.namespace, fields.Everything())
-	sw := cache.NewSharedInformer(lw, new(crdv1.Role), u.syncFrequency)
+func (c *rolesClient) Watch(handlers ...storage.RoleEventHandler) (*storage.Watcher, error) {
+	return c.WatchWithOptions(storage.ListOptions{}, handlers...)
+}
+
+func (c *rolesClient) WatchWithOptions(opts storage.ListOptions, handlers ...storage.RoleEventHandler) (*storage.Watcher, error) {
+	var (
+		informers []cache.SharedInformer
+		stores    []cache.Store
+	)
+	for _, namespace := range selectedNamespaces(c.namespaces, opts) {
+		informer := cache.NewSharedInformer(c.listWatch(namespace, opts), new(crdv1.Role), c.syncFrequency)
+		informers = append(informers, informer)
+		stores = append(stores, informer.GetStore())
+	}
 	for _, h := range handlers {
-		sw.AddEventHandler(&roleEventHandler{handler: h, store: sw.GetStore()})
+		// the filter is applied to the watched objects too, in case the api server ignores the selector
+		eh := &roleEventHandler{
+			handler:   storage.FilterRoleEventHandler(opts, h),
+			stores:    stores,
+			namespace: c.namespace,
+		}
+		for _, informer := range informers {
+			informer.AddEventHandler(eh)
+		}
 	}
 	return storage.NewWatcher(func(stop <-chan struct{}, _ chan error) {
-		sw.Run(stop)
+		for _, informer := range informers {
+			go informer.Run(stop)
+		}
+		<-stop
+	}), nil
+}
+
+func (c *rolesClient) WatchEvents(opts storage.WatchOptions, onEvent func(event storage.RoleEvent)) (*storage.Watcher, error) {
+	filtered := storage.FilterRoleEvents(opts, onEvent)
+	// the watches of each namespace deliver their events concurrently
+	var lock sync.Mutex
+	deliver := func(eventType storage.EventType, obj runtime.Object, resourceVersion string) {
+		roleCrd, ok := obj.(*crdv1.Role)
+		if !ok {
+			log.Warnf("watch event: unexpected object %v", obj)
+			return
+		}
+		role, err := roleFromCrd(c.namespace, roleCrd)
+		if err != nil {
+			log.Warnf("watch event: %v", err)
+			return
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		filtered(storage.RoleEvent{Type: eventType, Role: role, ResourceVersion: resourceVersion})
+	}
+	return storage.NewWatcher(func(stop <-chan struct{}, errs chan error) {
+		for _, namespace := range selectedNamespaces(c.namespaces, opts.ListOptions) {
+			go watchEvents("roles", opts.ResourceVersion, c.listWatch(namespace, opts.ListOptions), deliver, stop, errs)
+		}
+		<-stop
 	}), nil
 }
 
+// the typed client is used rather than its rest client, so fake clientsets can be watched too
+func (c *rolesClient) listWatch(namespace string, opts storage.ListOptions) *cache.ListWatch {
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = labelSelector(opts)
+			return c.crds.GlooV1().Roles(namespace).List(options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = labelSelector(opts)
+			return c.crds.GlooV1().Roles(namespace).Watch(options)
+		},
+	}
+}
+
 func (c *rolesClient) createOrUpdateRoleCrd(role *v1.Role, op crud.Operation) (*v1.Role, error) {
 	roleCrd, err := ConfigObjectToCrd(c.namespace, role)
 	if err != nil {
@@ -102,92 +165,99 @@ func (c *rolesClient) createOrUpdateRoleCrd(role *v1.Role, op crud.Operation) (*
 			return nil, errors.Wrap(err, "kubernetes create api request")
 		}
 	case crud.OperationUpdate:
-		// need to make sure we preserve labels
-		currentCrd, err := roles.Get(roleCrd.GetName(), metav1.GetOptions{ResourceVersion: roleCrd.GetResourceVersion()})
-		if err != nil {
-			return nil, errors.Wrap(err, "kubernetes get api request")
+		// kubernetes allows custom resources to be updated without a resource version,
+		// which would overwrite concurrent changes
+		if roleCrd.GetResourceVersion() == "" {
+			return nil, errors.New("resource version must be set for update operations")
 		}
-		// copy labels
-		roleCrd.SetLabels(currentCrd.Labels)
 		returnedCrd, err = roles.Update(roleCrd.(*crdv1.Role))
 		if err != nil {
+			if kuberrs.IsConflict(err) {
+				return nil, storage.NewConflictErr(err)
+			}
 			return nil, errors.Wrap(err, "kubernetes update api request")
 		}
 	}
+	return roleFromCrd(c.namespace, returnedCrd)
+}
+
+// roleFromCrd converts a crd to an role of a storage with the given default namespace
+func roleFromCrd(namespace string, roleCrd *crdv1.Role) (*v1.Role, error) {
 	var returnedRole v1.Role
 	if err := ConfigObjectFromCrd(
-		returnedCrd.ObjectMeta,
-		returnedCrd.Spec,
-		returnedCrd.Status,
+		roleCrd.ObjectMeta,
+		roleCrd.Spec,
+		roleCrd.Status,
 		&returnedRole); err != nil {
 		return nil, errors.Wrap(err, "converting returned crd to role")
 	}
+	returnedRole.Name = qualifiedName(namespace, roleCrd.ObjectMeta)
 	return &returnedRole, nil
 }
 
 // implements the kubernetes ResourceEventHandler interface
 type roleEventHandler struct {
 	handler storage.RoleEventHandler
-	store   cache.Store
+	// the stores of the informers of every watched namespace
+	stores []cache.Store
+	// the default namespace of the storage
+	namespace string
+	// the informers of each namespace call the handler concurrently
+	lock sync.Mutex
 }
 
 func (eh *roleEventHandler) getUpdatedList() []*v1.Role {
-	updatedList := eh.store.List()
 	var updatedRoleList []*v1.Role
-	for _, updated := range updatedList {
-		roleCrd, ok := updated.(*crdv1.Role)
-		if !ok {
-			continue
-		}
-		var returnedRole v1.Role
-		if err := ConfigObjectFromCrd(
-			roleCrd.ObjectMeta,
-			roleCrd.Spec,
-			roleCrd.Status,
-			&returnedRole); err != nil {
-			log.Warnf("watch event: %v", errors.Wrap(err, "converting returned crd to role"))
+	for _, store := range eh.stores {
+		for _, updated := range store.List() {
+			role, ok := eh.convert(updated)
+			if !ok {
+				continue
+			}
+			updatedRoleList = append(updatedRoleList, role)
 		}
-		updatedRoleList = append(updatedRoleList, &returnedRole)
 	}
 	return updatedRoleList
 }
 
-func convertRole(obj interface{}) (*v1.Role, bool) {
+func (eh *roleEventHandler) convert(obj interface{}) (*v1.Role, bool) {
 	roleCrd, ok := obj.(*crdv1.Role)
 	if !ok {
 		return nil, ok
 	}
-	var returnedRole v1.Role
-	if err := ConfigObjectFromCrd(
-		roleCrd.ObjectMeta,
-		roleCrd.Spec,
-		roleCrd.Status,
-		&returnedRole); err != nil {
-		log.Warnf("watch event: %v", errors.Wrap(err, "converting returned crd to role"))
+	role, err := roleFromCrd(eh.namespace, roleCrd)
+	if err != nil {
+		log.Warnf("watch event: %v", err)
 		return nil, false
 	}
-	return &returnedRole, true
+	return role, true
 }
 
 func (eh *roleEventHandler) OnAdd(obj interface{}) {
-	role, ok := convertRole(obj)
+	role, ok := eh.convert(obj)
 	if !ok {
 		return
 	}
+	eh.lock.Lock()
+	defer eh.lock.Unlock()
 	eh.handler.OnAdd(eh.getUpdatedList(), role)
 }
 func (eh *roleEventHandler) OnUpdate(_, newObj interface{}) {
-	newRole, ok := convertRole(newObj)
+	newRole, ok := eh.convert(newObj)
 	if !ok {
 		return
 	}
+	eh.lock.Lock()
+	defer eh.lock.Unlock()
 	eh.handler.OnUpdate(eh.getUpdatedList(), newRole)
 }
 
 func (eh *roleEventHandler) OnDelete(obj interface{}) {
-	role, ok := convertRole(obj)
+	role, ok := eh.convert(obj)
 	if !ok {
 		return
 	}
+	eh.lock.Lock()
+	defer eh.lock.Unlock()
 	eh.handler.OnDelete(eh.getUpdatedList(), role)
 }
@@ -36,7 +36,7 @@ func (in *Role) DeepCopyInto(out *Role) {
 			*out = nil
 		} else {
 			*out = new(types_v1.Status)
-			**out = **in
+			(*in).DeepCopyInto(*out)
 		}
 	}
 	if in.Spec != nil {
@@ -124,7 +124,7 @@ func (in *Upstream) DeepCopyInto(out *Upstream) {
 			*out = nil
 		} else {
 			*out = new(types_v1.Status)
-			**out = **in
+			(*in).DeepCopyInto(*out)
 		}
 	}
 	if in.Spec != nil {
@@ -201,7 +201,7 @@ func (in *VirtualService) DeepCopyInto(out *VirtualService) {
 			*out = nil
 		} else {
 			*out = new(types_v1.Status)
-			**out = **in
+			(*in).DeepCopyInto(*out)
 		}
 	}
 	if in.Spec != nil {
@@ -1,6 +1,7 @@
 package crd
 
 import (
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -10,7 +11,8 @@ import (
 	crdv1 "github.com/solo-io/gloo/pkg/storage/crd/solo.io/v1"
 	apiexts "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
 
 	"github.com/solo-io/gloo/pkg/storage/crud"
 	kuberrs "k8s.io/apimachinery/pkg/api/errors"
@@ -22,7 +24,9 @@ type upstreamsClient struct {
 	crds    crdclientset.Interface
 	apiexts apiexts.Interface
 	// write and read objects to this namespace if not specified on the GlooObjects
-	namespace     string
+	namespace string
+	// list and watch objects in these namespaces
+	namespaces    []string
 	syncFrequency time.Duration
 }
 
@@ -35,56 +39,115 @@ func (c *upstreamsClient) Update(item *v1.Upstream) (*v1.Upstream, error) {
 }
 
 func (c *upstreamsClient) Delete(name string) error {
-	return c.crds.GlooV1().Upstreams(c.namespace).Delete(name, nil)
+	namespace, name := splitName(c.namespace, name)
+	return c.crds.GlooV1().Upstreams(namespace).Delete(name, nil)
 }
 
 func (c *upstreamsClient) Get(name string) (*v1.Upstream, error) {
-	crdUpstream, err := c.crds.GlooV1().Upstreams(c.namespace).Get(name, metav1.GetOptions{})
+	namespace, name := splitName(c.namespace, name)
+	crdUpstream, err := c.crds.GlooV1().Upstreams(namespace).Get(name, metav1.GetOptions{})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed performing get api request")
 	}
-	var returnedUpstream v1.Upstream
-	if err := ConfigObjectFromCrd(
-		crdUpstream.ObjectMeta,
-		crdUpstream.Spec,
-		crdUpstream.Status,
-		&returnedUpstream); err != nil {
-		return nil, errors.Wrap(err, "converting returned crd to upstream")
-	}
-	return &returnedUpstream, nil
+	return upstreamFromCrd(c.namespace, crdUpstream)
 }
 
 func (c *upstreamsClient) List() ([]*v1.Upstream, error) {
-	crdList, err := c.crds.GlooV1().Upstreams(c.namespace).List(metav1.ListOptions{})
-	if err != nil {
-		return nil, errors.Wrap(err, "failed performing list api request")
-	}
+	return c.ListWithOptions(storage.ListOptions{})
+}
+
+func (c *upstreamsClient) ListWithOptions(opts storage.ListOptions) ([]*v1.Upstream, error) {
 	var returnedUpstreams []*v1.Upstream
-	for _, crdUpstream := range crdList.Items {
-		var returnedUpstream v1.Upstream
-		if err := ConfigObjectFromCrd(
-			crdUpstream.ObjectMeta,
-			crdUpstream.Spec,
-			crdUpstream.Status,
-			&returnedUpstream); err != nil {
-			return nil, errors.Wrap(err, "converting returned crd to upstream")
+	for _, namespace := range selectedNamespaces(c.namespaces, opts) {
+		crdList, err := c.crds.GlooV1().Upstreams(namespace).List(metav1.ListOptions{LabelSelector: labelSelector(opts)})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed performing list api request")
+		}
+		for i := range crdList.Items {
+			returnedUpstream, err := upstreamFromCrd(c.namespace, &crdList.Items[i])
+			if err != nil {
+				return nil, err
+			}
+			returnedUpstreams = append(returnedUpstreams, returnedUpstream)
 		}
-		returnedUpstreams = append(returnedUpstreams, &returnedUpstream)
 	}
 	return returnedUpstreams, nil
 }
 
-func (u *upstreamsClient) Watch(handlers ...storage.UpstreamEventHandler) (*storage.Watcher, error) {
-	lw := cache.NewListWatchFromClient(u.crds.GlooV1().RESTClient(), crdv1.UpstreamCRD.Plural, u.namespace, fields.Everything())
-	sw := cache.NewSharedInformer(lw, new(crdv1.Upstream), u.syncFrequency)
+func (c *upstreamsClient) Watch(handlers ...storage.UpstreamEventHandler) (*storage.Watcher, error) {
+	return c.WatchWithOptions(storage.ListOptions{}, handlers...)
+}
+
+func (c *upstreamsClient) WatchWithOptions(opts storage.ListOptions, handlers ...storage.UpstreamEventHandler) (*storage.Watcher, error) {
+	var (
+		informers []cache.SharedInformer
+		stores    []cache.Store
+	)
+	for _, namespace := range selectedNamespaces(c.namespaces, opts) {
+		informer := cache.NewSharedInformer(c.listWatch(namespace, opts), new(crdv1.Upstream), c.syncFrequency)
+		informers = append(informers, informer)
+		stores = append(stores, informer.GetStore())
+	}
 	for _, h := range handlers {
-		sw.AddEventHandler(&upstreamEventHandler{handler: h, store: sw.GetStore()})
+		// the filter is applied to the watched objects too, in case the api server ignores the selector
+		eh := &upstreamEventHandler{
+			handler:   storage.FilterUpstreamEventHandler(opts, h),
+			stores:    stores,
+			namespace: c.namespace,
+		}
+		for _, informer := range informers {
+			informer.AddEventHandler(eh)
+		}
 	}
 	return storage.NewWatcher(func(stop <-chan struct{}, _ chan error) {
-		sw.Run(stop)
+		for _, informer := range informers {
+			go informer.Run(stop)
+		}
+		<-stop
+	}), nil
+}
+
+func (c *upstreamsClient) WatchEvents(opts storage.WatchOptions, onEvent func(event storage.UpstreamEvent)) (*storage.Watcher, error) {
+	filtered := storage.FilterUpstreamEvents(opts, onEvent)
+	// the watches of each namespace deliver their events concurrently
+	var lock sync.Mutex
+	deliver := func(eventType storage.EventType, obj runtime.Object, resourceVersion string) {
+		upstreamCrd, ok := obj.(*crdv1.Upstream)
+		if !ok {
+			log.Warnf("watch event: unexpected object %v", obj)
+			return
+		}
+		upstream, err := upstreamFromCrd(c.namespace, upstreamCrd)
+		if err != nil {
+			log.Warnf("watch event: %v", err)
+			return
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		filtered(storage.UpstreamEvent{Type: eventType, Upstream: upstream, ResourceVersion: resourceVersion})
+	}
+	return storage.NewWatcher(func(stop <-chan struct{}, errs chan error) {
+		for _, namespace := range selectedNamespaces(c.namespaces, opts.ListOptions) {
+			go watchEvents("upstreams", opts.ResourceVersion, c.listWatch(namespace, opts.ListOptions), deliver, stop, errs)
+		}
+		<-stop
 	}), nil
 }
 
+// the typed client is used rather than its rest client, so fake clientsets can be watched too
+func (c *upstreamsClient) listWatch(namespace string, opts storage.ListOptions) *cache.ListWatch {
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = labelSelector(opts)
+			return c.crds.GlooV1().Upstreams(namespace).List(options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = labelSelector(opts)
+			return c.crds.GlooV1().Upstreams(namespace).Watch(options)
+		},
+	}
+}
+
 func (c *upstreamsClient) createOrUpdateUpstreamCrd(upstream *v1.Upstream, op crud.Operation) (*v1.Upstream, error) {
 	upstreamCrd, err := ConfigObjectToCrd(c.namespace, upstream)
 	if err != nil {
@@ -102,92 +165,99 @@ func (c *upstreamsClient) createOrUpdateUpstreamCrd(upstream *v1.Upstream, op cr
 			return nil, errors.Wrap(err, "kubernetes create api request")
 		}
 	case crud.OperationUpdate:
-		// need to make sure we preserve labels
-		currentCrd, err := upstreams.Get(upstreamCrd.GetName(), metav1.GetOptions{ResourceVersion: upstreamCrd.GetResourceVersion()})
-		if err != nil {
-			return nil, errors.Wrap(err, "kubernetes get api request")
+		// kubernetes allows custom resources to be updated without a resource version,
+		// which would overwrite concurrent changes
+		if upstreamCrd.GetResourceVersion() == "" {
+			return nil, errors.New("resource version must be set for update operations")
 		}
-		// copy labels
-		upstreamCrd.SetLabels(currentCrd.Labels)
 		returnedCrd, err = upstreams.Update(upstreamCrd.(*crdv1.Upstream))
 		if err != nil {
+			if kuberrs.IsConflict(err) {
+				return nil, storage.NewConflictErr(err)
+			}
 			return nil, errors.Wrap(err, "kubernetes update api request")
 		}
 	}
+	return upstreamFromCrd(c.namespace, returnedCrd)
+}
+
+// upstreamFromCrd converts a crd to an upstream of a storage with the given default namespace
+func upstreamFromCrd(namespace string, upstreamCrd *crdv1.Upstream) (*v1.Upstream, error) {
 	var returnedUpstream v1.Upstream
 	if err := ConfigObjectFromCrd(
-		returnedCrd.ObjectMeta,
-		returnedCrd.Spec,
-		returnedCrd.Status,
+		upstreamCrd.ObjectMeta,
+		upstreamCrd.Spec,
+		upstreamCrd.Status,
 		&returnedUpstream); err != nil {
 		return nil, errors.Wrap(err, "converting returned crd to upstream")
 	}
+	returnedUpstream.Name = qualifiedName(namespace, upstreamCrd.ObjectMeta)
 	return &returnedUpstream, nil
 }
 
 // implements the kubernetes ResourceEventHandler interface
 type upstreamEventHandler struct {
 	handler storage.UpstreamEventHandler
-	store   cache.Store
+	// the stores of the informers of every watched namespace
+	stores []cache.Store
+	// the default namespace of the storage
+	namespace string
+	// the informers of each namespace call the handler concurrently
+	lock sync.Mutex
 }
 
 func (eh *upstreamEventHandler) getUpdatedList() []*v1.Upstream {
-	updatedList := eh.store.List()
 	var updatedUpstreamList []*v1.Upstream
-	for _, updated := range updatedList {
-		upstreamCrd, ok := updated.(*crdv1.Upstream)
-		if !ok {
-			continue
-		}
-		var returnedUpstream v1.Upstream
-		if err := ConfigObjectFromCrd(
-			upstreamCrd.ObjectMeta,
-			upstreamCrd.Spec,
-			upstreamCrd.Status,
-			&returnedUpstream); err != nil {
-			log.Warnf("watch event: %v", errors.Wrap(err, "converting returned crd to upstream"))
+	for _, store := range eh.stores {
+		for _, updated := range store.List() {
+			upstream, ok := eh.convert(updated)
+			if !ok {
+				continue
+			}
+			updatedUpstreamList = append(updatedUpstreamList, upstream)
 		}
-		updatedUpstreamList = append(updatedUpstreamList, &returnedUpstream)
 	}
 	return updatedUpstreamList
 }
 
-func convertUpstream(obj interface{}) (*v1.Upstream, bool) {
+func (eh *upstreamEventHandler) convert(obj interface{}) (*v1.Upstream, bool) {
 	upstreamCrd, ok := obj.(*crdv1.Upstream)
 	if !ok {
 		return nil, ok
 	}
-	var returnedUpstream v1.Upstream
-	if err := ConfigObjectFromCrd(
-		upstreamCrd.ObjectMeta,
-		upstreamCrd.Spec,
-		upstreamCrd.Status,
-		&returnedUpstream); err != nil {
-		log.Warnf("watch event: %v", errors.Wrap(err, "converting returned crd to upstream"))
+	upstream, err := upstreamFromCrd(eh.namespace, upstreamCrd)
+	if err != nil {
+		log.Warnf("watch event: %v", err)
 		return nil, false
 	}
-	return &returnedUpstream, true
+	return upstream, true
 }
 
 func (eh *upstreamEventHandler) OnAdd(obj interface{}) {
-	upstream, ok := convertUpstream(obj)
+	upstream, ok := eh.convert(obj)
 	if !ok {
 		return
 	}
+	eh.lock.Lock()
+	defer eh.lock.Unlock()
 	eh.handler.OnAdd(eh.getUpdatedList(), upstream)
 }
 func (eh *upstreamEventHandler) OnUpdate(_, newObj interface{}) {
-	newUpstream, ok := convertUpstream(newObj)
+	newUpstream, ok := eh.convert(newObj)
 	if !ok {
 		return
 	}
+	eh.lock.Lock()
+	defer eh.lock.Unlock()
 	eh.handler.OnUpdate(eh.getUpdatedList(), newUpstream)
 }
 
 func (eh *upstreamEventHandler) OnDelete(obj interface{}) {
-	upstream, ok := convertUpstream(obj)
+	upstream, ok := eh.convert(obj)
 	if !ok {
 		return
 	}
+	eh.lock.Lock()
+	defer eh.lock.Unlock()
 	eh.handler.OnDelete(eh.getUpdatedList(), upstream)
 }
@@ -1,6 +1,7 @@
 package crd
 
 import (
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -10,7 +11,8 @@ import (
 	crdv1 "github.com/solo-io/gloo/pkg/storage/crd/solo.io/v1"
 	apiexts "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
 
 	"github.com/solo-io/gloo/pkg/storage/crud"
 	kuberrs "k8s.io/apimachinery/pkg/api/errors"
@@ -22,7 +24,9 @@ type virtualServicesClient struct {
 	crds    crdclientset.Interface
 	apiexts apiexts.Interface
 	// write and read objects to this namespace if not specified on the GlooObjects
-	namespace     string
+	namespace string
+	// list and watch objects in these namespaces
+	namespaces    []string
 	syncFrequency time.Duration
 }
 
@@ -35,56 +39,115 @@ func (c *virtualServicesClient) Update(item *v1.VirtualService) (*v1.VirtualServ
 }
 
 func (c *virtualServicesClient) Delete(name string) error {
-	return c.crds.GlooV1().VirtualServices(c.namespace).Delete(name, nil)
+	namespace, name := splitName(c.namespace, name)
+	return c.crds.GlooV1().VirtualServices(namespace).Delete(name, nil)
 }
 
 func (c *virtualServicesClient) Get(name string) (*v1.VirtualService, error) {
-	crdVirtualService, err := c.crds.GlooV1().VirtualServices(c.namespace).Get(name, metav1.GetOptions{})
+	namespace, name := splitName(c.namespace, name)
+	crdVirtualService, err := c.crds.GlooV1().VirtualServices(namespace).Get(name, metav1.GetOptions{})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed performing get api request")
 	}
-	var returnedVirtualService v1.VirtualService
-	if err := ConfigObjectFromCrd(
-		crdVirtualService.ObjectMeta,
-		crdVirtualService.Spec,
-		crdVirtualService.Status,
-		&returnedVirtualService); err != nil {
-		return nil, errors.Wrap(err, "converting returned crd to virtualService")
-	}
-	return &returnedVirtualService, nil
+	return virtualServiceFromCrd(c.namespace, crdVirtualService)
 }
 
 func (c *virtualServicesClient) List() ([]*v1.VirtualService, error) {
-	crdList, err := c.crds.GlooV1().VirtualServices(c.namespace).List(metav1.ListOptions{})
-	if err != nil {
-		return nil, errors.Wrap(err, "failed performing list api request")
-	}
+	return c.ListWithOptions(storage.ListOptions{})
+}
+
+func (c *virtualServicesClient) ListWithOptions(opts storage.ListOptions) ([]*v1.VirtualService, error) {
 	var returnedVirtualServices []*v1.VirtualService
-	for _, crdVirtualService := range crdList.Items {
-		var returnedVirtualService v1.VirtualService
-		if err := ConfigObjectFromCrd(
-			crdVirtualService.ObjectMeta,
-			crdVirtualService.Spec,
-			crdVirtualService.Status,
-			&returnedVirtualService); err != nil {
-			return nil, errors.Wrap(err, "converting returned crd to virtualService")
+	for _, namespace := range selectedNamespaces(c.namespaces, opts) {
+		crdList, err := c.crds.GlooV1().VirtualServices(namespace).List(metav1.ListOptions{LabelSelector: labelSelector(opts)})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed performing list api request")
+		}
+		for i := range crdList.Items {
+			returnedVirtualService, err := virtualServiceFromCrd(c.namespace, &crdList.Items[i])
+			if err != nil {
+				return nil, err
+			}
+			returnedVirtualServices = append(returnedVirtualServices, returnedVirtualService)
 		}
-		returnedVirtualServices = append(returnedVirtualServices, &returnedVirtualService)
 	}
 	return returnedVirtualServices, nil
 }
 
-func (u *virtualServicesClient) Watch(handlers ...storage.VirtualServiceEventHandler) (*storage.Watcher, error) {
-	lw := cache.NewListWatchFromClient(u.crds.GlooV1().RESTClient(), crdv1.VirtualServiceCRD.Plural, u.namespace, fields.Everything())
-	sw := cache.NewSharedInformer(lw, new(crdv1.VirtualService), u.syncFrequency)
+func (c *virtualServicesClient) Watch(handlers ...storage.VirtualServiceEventHandler) (*storage.Watcher, error) {
+	return c.WatchWithOptions(storage.ListOptions{}, handlers...)
+}
+
+func (c *virtualServicesClient) WatchWithOptions(opts storage.ListOptions, handlers ...storage.VirtualServiceEventHandler) (*storage.Watcher, error) {
+	var (
+		informers []cache.SharedInformer
+		stores    []cache.Store
+	)
+	for _, namespace := range selectedNamespaces(c.namespaces, opts) {
+		informer := cache.NewSharedInformer(c.listWatch(namespace, opts), new(crdv1.VirtualService), c.syncFrequency)
+		informers = append(informers, informer)
+		stores = append(stores, informer.GetStore())
+	}
 	for _, h := range handlers {
-		sw.AddEventHandler(&virtualServiceEventHandler{handler: h, store: sw.GetStore()})
+		// the filter is applied to the watched objects too, in case the api server ignores the selector
+		eh := &virtualServiceEventHandler{
+			handler:   storage.FilterVirtualServiceEventHandler(opts, h),
+			stores:    stores,
+			namespace: c.namespace,
+		}
+		for _, informer := range informers {
+			informer.AddEventHandler(eh)
+		}
 	}
 	return storage.NewWatcher(func(stop <-chan struct{}, _ chan error) {
-		sw.Run(stop)
+		for _, informer := range informers {
+			go informer.Run(stop)
+		}
+		<-stop
+	}), nil
+}
+
+func (c *virtualServicesClient) WatchEvents(opts storage.WatchOptions, onEvent func(event storage.VirtualServiceEvent)) (*storage.Watcher, error) {
+	filtered := storage.FilterVirtualServiceEvents(opts, onEvent)
+	// the watches of each namespace deliver their events concurrently
+	var lock sync.Mutex
+	deliver := func(eventType storage.EventType, obj runtime.Object, resourceVersion string) {
+		virtualServiceCrd, ok := obj.(*crdv1.VirtualService)
+		if !ok {
+			log.Warnf("watch event: unexpected object %v", obj)
+			return
+		}
+		virtualService, err := virtualServiceFromCrd(c.namespace, virtualServiceCrd)
+		if err != nil {
+			log.Warnf("watch event: %v", err)
+			return
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		filtered(storage.VirtualServiceEvent{Type: eventType, VirtualService: virtualService, ResourceVersion: resourceVersion})
+	}
+	return storage.NewWatcher(func(stop <-chan struct{}, errs chan error) {
+		for _, namespace := range selectedNamespaces(c.namespaces, opts.ListOptions) {
+			go watchEvents("virtualServices", opts.ResourceVersion, c.listWatch(namespace, opts.ListOptions), deliver, stop, errs)
+		}
+		<-stop
 	}), nil
 }
 
+// the typed client is used rather than its rest client, so fake clientsets can be watched too
+func (c *virtualServicesClient) listWatch(namespace string, opts storage.ListOptions) *cache.ListWatch {
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = labelSelector(opts)
+			return c.crds.GlooV1().VirtualServices(namespace).List(options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = labelSelector(opts)
+			return c.crds.GlooV1().VirtualServices(namespace).Watch(options)
+		},
+	}
+}
+
 func (c *virtualServicesClient) createOrUpdateVirtualServiceCrd(virtualService *v1.VirtualService, op crud.Operation) (*v1.VirtualService, error) {
 	virtualServiceCrd, err := ConfigObjectToCrd(c.namespace, virtualService)
 	if err != nil {
@@ -102,92 +165,99 @@ func (c *virtualServicesClient) createOrUpdateVirtualServiceCrd(virtualService *
 			return nil, errors.Wrap(err, "kubernetes create api request")
 		}
 	case crud.OperationUpdate:
-		// need to make sure we preserve labels
-		currentCrd, err := virtualServices.Get(virtualServiceCrd.GetName(), metav1.GetOptions{ResourceVersion: virtualServiceCrd.GetResourceVersion()})
-		if err != nil {
-			return nil, errors.Wrap(err, "kubernetes get api request")
+		// kubernetes allows custom resources to be updated without a resource version,
+		// which would overwrite concurrent changes
+		if virtualServiceCrd.GetResourceVersion() == "" {
+			return nil, errors.New("resource version must be set for update operations")
 		}
-		// copy labels
-		virtualServiceCrd.SetLabels(currentCrd.Labels)
 		returnedCrd, err = virtualServices.Update(virtualServiceCrd.(*crdv1.VirtualService))
 		if err != nil {
+			if kuberrs.IsConflict(err) {
+				return nil, storage.NewConflictErr(err)
+			}
 			return nil, errors.Wrap(err, "kubernetes update api request")
 		}
 	}
+	return virtualServiceFromCrd(c.namespace, returnedCrd)
+}
+
+// virtualServiceFromCrd converts a crd to an virtualService of a storage with the given default namespace
+func virtualServiceFromCrd(namespace string, virtualServiceCrd *crdv1.VirtualService) (*v1.VirtualService, error) {
 	var returnedVirtualService v1.VirtualService
 	if err := ConfigObjectFromCrd(
-		returnedCrd.ObjectMeta,
-		returnedCrd.Spec,
-		returnedCrd.Status,
+		virtualServiceCrd.ObjectMeta,
+		virtualServiceCrd.Spec,
+		virtualServiceCrd.Status,
 		&returnedVirtualService); err != nil {
 		return nil, errors.Wrap(err, "converting returned crd to virtualService")
 	}
+	returnedVirtualService.Name = qualifiedName(namespace, virtualServiceCrd.ObjectMeta)
 	return &returnedVirtualService, nil
 }
 
 // implements the kubernetes ResourceEventHandler interface
 type virtualServiceEventHandler struct {
 	handler storage.VirtualServiceEventHandler
-	store   cache.Store
+	// the stores of the informers of every watched namespace
+	stores []cache.Store
+	// the default namespace of the storage
+	namespace string
+	// the informers of each namespace call the handler concurrently
+	lock sync.Mutex
 }
 
 func (eh *virtualServiceEventHandler) getUpdatedList() []*v1.VirtualService {
-	updatedList := eh.store.List()
 	var updatedVirtualServiceList []*v1.VirtualService
-	for _, updated := range updatedList {
-		virtualServiceCrd, ok := updated.(*crdv1.VirtualService)
-		if !ok {
-			continue
-		}
-		var returnedVirtualService v1.VirtualService
-		if err := ConfigObjectFromCrd(
-			virtualServiceCrd.ObjectMeta,
-			virtualServiceCrd.Spec,
-			virtualServiceCrd.Status,
-			&returnedVirtualService); err != nil {
-			log.Warnf("watch event: %v", errors.Wrap(err, "converting returned crd to virtualService"))
+	for _, store := range eh.stores {
+		for _, updated := range store.List() {
+			virtualService, ok := eh.convert(updated)
+			if !ok {
+				continue
+			}
+			updatedVirtualServiceList = append(updatedVirtualServiceList, virtualService)
 		}
-		updatedVirtualServiceList = append(updatedVirtualServiceList, &returnedVirtualService)
 	}
 	return updatedVirtualServiceList
 }
 
-func convertVirtualService(obj interface{}) (*v1.VirtualService, bool) {
+func (eh *virtualServiceEventHandler) convert(obj interface{}) (*v1.VirtualService, bool) {
 	virtualServiceCrd, ok := obj.(*crdv1.VirtualService)
 	if !ok {
 		return nil, ok
 	}
-	var returnedVirtualService v1.VirtualService
-	if err := ConfigObjectFromCrd(
-		virtualServiceCrd.ObjectMeta,
-		virtualServiceCrd.Spec,
-		virtualServiceCrd.Status,
-		&returnedVirtualService); err != nil {
-		log.Warnf("watch event: %v", errors.Wrap(err, "converting returned crd to virtualService"))
+	virtualService, err := virtualServiceFromCrd(eh.namespace, virtualServiceCrd)
+	if err != nil {
+		log.Warnf("watch event: %v", err)
 		return nil, false
 	}
-	return &returnedVirtualService, true
+	return virtualService, true
 }
 
 func (eh *virtualServiceEventHandler) OnAdd(obj interface{}) {
-	virtualService, ok := convertVirtualService(obj)
+	virtualService, ok := eh.convert(obj)
 	if !ok {
 		return
 	}
+	eh.lock.Lock()
+	defer eh.lock.Unlock()
 	eh.handler.OnAdd(eh.getUpdatedList(), virtualService)
 }
 func (eh *virtualServiceEventHandler) OnUpdate(_, newObj interface{}) {
-	newVirtualService, ok := convertVirtualService(newObj)
+	newVirtualService, ok := eh.convert(newObj)
 	if !ok {
 		return
 	}
+	eh.lock.Lock()
+	defer eh.lock.Unlock()
 	eh.handler.OnUpdate(eh.getUpdatedList(), newVirtualService)
 }
 
 func (eh *virtualServiceEventHandler) OnDelete(obj interface{}) {
-	virtualService, ok := convertVirtualService(obj)
+	virtualService, ok := eh.convert(obj)
 	if !ok {
 		return
 	}
+	eh.lock.Lock()
+	defer eh.lock.Unlock()
 	eh.handler.OnDelete(eh.getUpdatedList(), virtualService)
 }
@@ -0,0 +1,122 @@
+package etcd_test
+
+import (
+	"context"
+	"sync"
+
+	"github.com/coreos/etcd/clientv3"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo/pkg/storage"
+	"github.com/solo-io/gloo/pkg/storage/dependencies"
+	. "github.com/solo-io/gloo/pkg/storage/dependencies/etcd"
+	"github.com/solo-io/gloo/test/helpers"
+)
+
+var _ = Describe("SecretStorage", func() {
+	var (
+		rootPath string
+		secrets  dependencies.SecretStorage
+	)
+	BeforeEach(func() {
+		rootPath = helpers.RandString(8)
+		secrets = NewSecretStorage(client, rootPath)
+	})
+	AfterEach(func() {
+		client.Delete(context.Background(), rootPath+"/", clientv3.WithPrefix())
+	})
+	It("creates, updates and deletes secrets", func() {
+		created, err := secrets.Create(&dependencies.Secret{Ref: "creds", Data: map[string]string{"key": "value"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created.ResourceVersion).NotTo(BeEmpty())
+		_, err = secrets.Create(&dependencies.Secret{Ref: "creds"})
+		Expect(storage.IsAlreadyExists(err)).To(BeTrue())
+
+		created.Data["key"] = "changed"
+		updated, err := secrets.Update(created)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated.Data).To(Equal(map[string]string{"key": "changed"}))
+		stored, err := secrets.Get("creds")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored).To(Equal(updated))
+		_, err = secrets.Update(created)
+		Expect(storage.IsConflict(err)).To(BeTrue())
+
+		Expect(secrets.Delete("creds")).To(Succeed())
+		Expect(secrets.List()).To(BeEmpty())
+	})
+	It("delivers changes to watches", func() {
+		var (
+			lock  sync.Mutex
+			lists [][]*dependencies.Secret
+		)
+		record := func(list []*dependencies.Secret, _ *dependencies.Secret) {
+			lock.Lock()
+			defer lock.Unlock()
+			lists = append(lists, list)
+		}
+		latest := func() []*dependencies.Secret {
+			lock.Lock()
+			defer lock.Unlock()
+			if len(lists) == 0 {
+				return nil
+			}
+			return lists[len(lists)-1]
+		}
+		_, err := secrets.Create(&dependencies.Secret{Ref: "existing", Data: map[string]string{"key": "value"}})
+		Expect(err).NotTo(HaveOccurred())
+		w, err := secrets.Watch(&dependencies.SecretEventHandlerFuncs{
+			AddFunc:    record,
+			UpdateFunc: record,
+			DeleteFunc: record,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		stop := make(chan struct{})
+		defer close(stop)
+		go w.Run(stop, make(chan error))
+
+		Eventually(latest).Should(HaveLen(1))
+		_, err = secrets.Create(&dependencies.Secret{Ref: "new", Data: map[string]string{"key": "value"}})
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(latest).Should(HaveLen(2))
+		Expect(secrets.Delete("existing")).To(Succeed())
+		Eventually(func() string {
+			list := latest()
+			if len(list) != 1 {
+				return ""
+			}
+			return list[0].Ref
+		}).Should(Equal("new"))
+	})
+})
+
+var _ = Describe("FileStorage", func() {
+	var rootPath string
+	BeforeEach(func() {
+		rootPath = helpers.RandString(8)
+	})
+	AfterEach(func() {
+		client.Delete(context.Background(), rootPath+"/", clientv3.WithPrefix())
+	})
+	It("creates, updates and deletes files", func() {
+		files := NewFileStorage(client, rootPath)
+		created, err := files.Create(&dependencies.File{Ref: "some/file", Contents: []byte("contents")})
+		Expect(err).NotTo(HaveOccurred())
+		created.Contents = []byte("changed")
+		updated, err := files.Update(created)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated.ResourceVersion).NotTo(Equal(created.ResourceVersion))
+		stored, err := files.Get("some/file")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.Contents).To(Equal([]byte("changed")))
+		list, err := files.List()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list).To(Equal([]*dependencies.File{stored}))
+		_, err = files.Update(created)
+		Expect(storage.IsConflict(err)).To(BeTrue())
+		Expect(files.Delete("some/file")).To(Succeed())
+		_, err = files.Get("some/file")
+		Expect(err).To(HaveOccurred())
+	})
+})
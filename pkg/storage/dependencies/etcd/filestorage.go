@@ -0,0 +1,101 @@
+package etcd
+
+import (
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/pkg/storage"
+	"github.com/solo-io/gloo/pkg/storage/dependencies"
+	"github.com/solo-io/gloo/pkg/storage/etcd"
+)
+
+type fileStorage struct {
+	store *etcd.Store
+}
+
+// NewFileStorage creates a file storage that keeps files in etcd, under keys starting with rootPath.
+// the ref of a file is the rest of its key
+func NewFileStorage(client *clientv3.Client, rootPath string) dependencies.FileStorage {
+	return &fileStorage{store: etcd.NewStore(client, rootPath+"/files", "file")}
+}
+
+func (s *fileStorage) Create(file *dependencies.File) (*dependencies.File, error) {
+	if file.Ref == "" {
+		return nil, errors.Errorf("ref required")
+	}
+	entry, err := s.store.Create(file.Ref, file.Contents)
+	if err != nil {
+		return nil, err
+	}
+	return fileFromEntry(entry), nil
+}
+
+func (s *fileStorage) Update(file *dependencies.File) (*dependencies.File, error) {
+	if file.Ref == "" {
+		return nil, errors.Errorf("ref required")
+	}
+	if file.ResourceVersion == "" {
+		return nil, errors.New("resource version must be set for update operations")
+	}
+	entry, err := s.store.Update(file.Ref, file.ResourceVersion, file.Contents)
+	if err != nil {
+		return nil, err
+	}
+	return fileFromEntry(entry), nil
+}
+
+func (s *fileStorage) Delete(name string) error {
+	return s.store.Delete(name)
+}
+
+func (s *fileStorage) Get(name string) (*dependencies.File, error) {
+	entry, err := s.store.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return fileFromEntry(entry), nil
+}
+
+func (s *fileStorage) List() ([]*dependencies.File, error) {
+	entries, err := s.store.List()
+	if err != nil {
+		return nil, err
+	}
+	return filesFromEntries(entries), nil
+}
+
+func (s *fileStorage) Watch(handlers ...dependencies.FileEventHandler) (*storage.Watcher, error) {
+	return s.store.Watch(func(event etcd.Event) {
+		list := filesFromEntries(event.List)
+		var file *dependencies.File
+		if event.Entry != nil {
+			file = fileFromEntry(*event.Entry)
+		}
+		for _, h := range handlers {
+			switch event.Type {
+			case etcd.EventAdded:
+				h.OnAdd(list, file)
+			case etcd.EventUpdated:
+				h.OnUpdate(list, file)
+			case etcd.EventDeleted:
+				h.OnDelete(list, file)
+			}
+		}
+	}), nil
+}
+
+func fileFromEntry(entry etcd.Entry) *dependencies.File {
+	return &dependencies.File{
+		Ref:             entry.Name,
+		Contents:        entry.Data,
+		ResourceVersion: entry.ResourceVersion,
+	}
+}
+
+func filesFromEntries(entries []etcd.Entry) []*dependencies.File {
+	var files []*dependencies.File
+	for _, entry := range entries {
+		files = append(files, fileFromEntry(entry))
+	}
+	return files
+}
@@ -0,0 +1,128 @@
+package etcd
+
+import (
+	"encoding/json"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/pkg/log"
+	"github.com/solo-io/gloo/pkg/storage"
+	"github.com/solo-io/gloo/pkg/storage/dependencies"
+	"github.com/solo-io/gloo/pkg/storage/etcd"
+)
+
+type secretStorage struct {
+	store *etcd.Store
+}
+
+// NewSecretStorage creates a secret storage that keeps secrets in etcd, under keys starting with rootPath.
+// the data of each secret is stored as a json object
+func NewSecretStorage(client *clientv3.Client, rootPath string) dependencies.SecretStorage {
+	return &secretStorage{store: etcd.NewStore(client, rootPath+"/secrets", "secret")}
+}
+
+func (s *secretStorage) Create(secret *dependencies.Secret) (*dependencies.Secret, error) {
+	if secret.Ref == "" {
+		return nil, errors.Errorf("ref required")
+	}
+	data, err := json.Marshal(secret.Data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "marshalling secret %v", secret.Ref)
+	}
+	entry, err := s.store.Create(secret.Ref, data)
+	if err != nil {
+		return nil, err
+	}
+	return secretFromEntry(entry)
+}
+
+func (s *secretStorage) Update(secret *dependencies.Secret) (*dependencies.Secret, error) {
+	if secret.Ref == "" {
+		return nil, errors.Errorf("ref required")
+	}
+	if secret.ResourceVersion == "" {
+		return nil, errors.New("resource version must be set for update operations")
+	}
+	data, err := json.Marshal(secret.Data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "marshalling secret %v", secret.Ref)
+	}
+	entry, err := s.store.Update(secret.Ref, secret.ResourceVersion, data)
+	if err != nil {
+		return nil, err
+	}
+	return secretFromEntry(entry)
+}
+
+func (s *secretStorage) Delete(name string) error {
+	return s.store.Delete(name)
+}
+
+func (s *secretStorage) Get(name string) (*dependencies.Secret, error) {
+	entry, err := s.store.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return secretFromEntry(entry)
+}
+
+func (s *secretStorage) List() ([]*dependencies.Secret, error) {
+	entries, err := s.store.List()
+	if err != nil {
+		return nil, err
+	}
+	return secretsFromEntries(entries)
+}
+
+func (s *secretStorage) Watch(handlers ...dependencies.SecretEventHandler) (*storage.Watcher, error) {
+	return s.store.Watch(func(event etcd.Event) {
+		list, err := secretsFromEntries(event.List)
+		if err != nil {
+			log.Warnf("error in secret watch: %v", err)
+			return
+		}
+		var secret *dependencies.Secret
+		if event.Entry != nil {
+			secret, err = secretFromEntry(*event.Entry)
+			if err != nil {
+				log.Warnf("error in secret watch: %v", err)
+				return
+			}
+		}
+		for _, h := range handlers {
+			switch event.Type {
+			case etcd.EventAdded:
+				h.OnAdd(list, secret)
+			case etcd.EventUpdated:
+				h.OnUpdate(list, secret)
+			case etcd.EventDeleted:
+				h.OnDelete(list, secret)
+			}
+		}
+	}), nil
+}
+
+func secretFromEntry(entry etcd.Entry) (*dependencies.Secret, error) {
+	var data map[string]string
+	if err := json.Unmarshal(entry.Data, &data); err != nil {
+		return nil, errors.Wrapf(err, "unmarshalling secret %v", entry.Name)
+	}
+	return &dependencies.Secret{
+		Ref:             entry.Name,
+		Data:            data,
+		ResourceVersion: entry.ResourceVersion,
+	}, nil
+}
+
+func secretsFromEntries(entries []etcd.Entry) ([]*dependencies.Secret, error) {
+	var secrets []*dependencies.Secret
+	for _, entry := range entries {
+		secret, err := secretFromEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, secret)
+	}
+	return secrets, nil
+}
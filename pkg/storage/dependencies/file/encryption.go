@@ -0,0 +1,213 @@
+package file
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// SecretKeyEnv holds the secret keys when no key file is given
+const SecretKeyEnv = "GLOO_SECRET_KEY"
+
+// encrypted secret files have this field set to the algorithm they are encrypted with
+const (
+	encryptionField     = "gloo_encryption"
+	encryptionAlgorithm = "aes-256-gcm"
+)
+
+// secret keys are 256 bit AES keys
+const secretKeySize = 32
+
+// encryptedSecret is the content of an encrypted secret file.
+// the data of the secret is encrypted with a random data key, which is encrypted with the secret key
+type encryptedSecret struct {
+	Encryption   string `json:"gloo_encryption"`
+	KeyID        string `json:"key_id"`
+	EncryptedKey string `json:"encrypted_key"`
+	Data         string `json:"data"`
+}
+
+type secretKey struct {
+	id   string
+	aead cipher.AEAD
+}
+
+// Keyring holds the keys that secret files are encrypted with.
+// the first key encrypts secrets, the other keys only decrypt secrets that were encrypted before the key was rotated
+type Keyring struct {
+	keys []secretKey
+}
+
+// NewKeyring creates a keyring from 32 byte keys, the first of which encrypts secrets
+func NewKeyring(keys ...[]byte) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one secret key is required")
+	}
+	keyring := &Keyring{}
+	for i, key := range keys {
+		if len(key) != secretKeySize {
+			return nil, errors.Errorf("secret key %v is %v bytes long, must be %v", i, len(key), secretKeySize)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "secret key %v", i)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, errors.Wrapf(err, "secret key %v", i)
+		}
+		sum := sha256.Sum256(key)
+		keyring.keys = append(keyring.keys, secretKey{id: hex.EncodeToString(sum[:4]), aead: aead})
+	}
+	return keyring, nil
+}
+
+// ParseKeyring creates a keyring from base64 encoded keys separated by whitespace
+func ParseKeyring(keys string) (*Keyring, error) {
+	var decoded [][]byte
+	for i, key := range strings.Fields(keys) {
+		b, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "decoding secret key %v", i)
+		}
+		decoded = append(decoded, b)
+	}
+	return NewKeyring(decoded...)
+}
+
+// LoadKeyring reads the secret keys from keyFile, or the GLOO_SECRET_KEY environment variable if keyFile is empty.
+// returns nil if neither is set, in which case secrets are not encrypted
+func LoadKeyring(keyFile string) (*Keyring, error) {
+	keys := os.Getenv(SecretKeyEnv)
+	if keyFile != "" {
+		b, err := ioutil.ReadFile(keyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read secret key file")
+		}
+		keys = string(b)
+	}
+	if strings.TrimSpace(keys) == "" {
+		if keyFile != "" {
+			return nil, errors.Errorf("secret key file %v is empty", keyFile)
+		}
+		return nil, nil
+	}
+	return ParseKeyring(keys)
+}
+
+// GenerateKey returns a new random key, base64 encoded
+func GenerateKey() (string, error) {
+	key := make([]byte, secretKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", errors.Wrap(err, "generating secret key")
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+func (k *Keyring) primary() secretKey {
+	return k.keys[0]
+}
+
+func (k *Keyring) key(id string) (secretKey, bool) {
+	for _, key := range k.keys {
+		if key.id == id {
+			return key, true
+		}
+	}
+	return secretKey{}, false
+}
+
+// encrypt encrypts the yaml of a secret with the primary key.
+// the ref is authenticated with the data, so a secret file can not be passed off as another secret
+func (k *Keyring) encrypt(ref string, plaintext []byte) ([]byte, error) {
+	dataKey := make([]byte, secretKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, errors.Wrap(err, "generating data key")
+	}
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	dataAead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	data, err := seal(dataAead, plaintext, []byte(ref))
+	if err != nil {
+		return nil, err
+	}
+	key := k.primary()
+	encryptedKey, err := seal(key.aead, dataKey, []byte(key.id))
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(encryptedSecret{
+		Encryption:   encryptionAlgorithm,
+		KeyID:        key.id,
+		EncryptedKey: base64.StdEncoding.EncodeToString(encryptedKey),
+		Data:         base64.StdEncoding.EncodeToString(data),
+	})
+}
+
+// decrypt returns the yaml of an encrypted secret
+func (k *Keyring) decrypt(ref string, secret encryptedSecret) ([]byte, error) {
+	if secret.Encryption != encryptionAlgorithm {
+		return nil, errors.Errorf("unsupported encryption %v", secret.Encryption)
+	}
+	key, ok := k.key(secret.KeyID)
+	if !ok {
+		return nil, errors.Errorf("encrypted with secret key %v, which is not configured", secret.KeyID)
+	}
+	encryptedKey, err := base64.StdEncoding.DecodeString(secret.EncryptedKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding data key")
+	}
+	dataKey, err := open(key.aead, encryptedKey, []byte(key.id))
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting data key")
+	}
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid data key")
+	}
+	dataAead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(secret.Data)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding data")
+	}
+	plaintext, err := open(dataAead, data, []byte(ref))
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting data")
+	}
+	return plaintext, nil
+}
+
+// seal encrypts plaintext, prepending the random nonce to the ciphertext
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Wrap(err, "generating nonce")
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(aead cipher.AEAD, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
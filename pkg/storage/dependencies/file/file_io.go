@@ -32,31 +32,100 @@ func readFile(dir, filename string) (*dependencies.File, error) {
 	}, nil
 }
 
-func writeSecret(dir string, secret *dependencies.Secret) error {
+// writeSecret writes the secret as yaml, encrypted with the primary key if keys is not nil.
+// only the owner can read the file
+func writeSecret(dir string, secret *dependencies.Secret, keys *Keyring) error {
 	if strings.Contains(secret.Ref, "/") {
 		return errors.Errorf("secret ref cannot contain '/': %v", secret.Ref)
 	}
+	if isTempFile(secret.Ref) {
+		return errors.Errorf("secret ref cannot start with '.': %v", secret.Ref)
+	}
 	yml, err := yaml.Marshal(secret.Data)
 	if err != nil {
 		return errors.Wrap(err, "marshalling secret data to yaml")
 	}
-	return ioutil.WriteFile(filepath.Join(dir, secret.Ref), yml, 0644)
+	if keys != nil {
+		yml, err = keys.encrypt(secret.Ref, yml)
+		if err != nil {
+			return errors.Wrap(err, "encrypting secret")
+		}
+	}
+	return replaceFile(dir, secret.Ref, yml, 0600)
 }
 
-func readSecret(dir, filename string) (*dependencies.Secret, error) {
+// replaceFile writes data to a temp file in dir, and renames it to filename. readers see either the old or the
+// new contents of the file, never a partial write, and perm applies whether or not the file existed
+func replaceFile(dir, filename string, data []byte, perm os.FileMode) error {
+	tmp, err := ioutil.TempFile(dir, "."+filename+".")
+	if err != nil {
+		return errors.Wrap(err, "creating temp file")
+	}
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), perm)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(dir, filename))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return errors.Wrapf(err, "writing %v", filename)
+	}
+	return nil
+}
+
+// isTempFile is true for the hidden files replaceFile writes before renaming them
+func isTempFile(filename string) bool {
+	return strings.HasPrefix(filename, ".")
+}
+
+func readSecret(dir, filename string, keys *Keyring) (*dependencies.Secret, error) {
+	secret, _, err := readSecretFile(dir, filename, keys)
+	return secret, err
+}
+
+// readSecretFile reads a plain or encrypted secret, and returns the id of the key it is encrypted with.
+// the key id is empty if the secret is not encrypted
+func readSecretFile(dir, filename string, keys *Keyring) (*dependencies.Secret, string, error) {
 	yml, err := ioutil.ReadFile(filepath.Join(dir, filename))
 	if err != nil {
-		return nil, errors.Errorf("error reading file: %v", err)
+		return nil, "", errors.Errorf("error reading file: %v", err)
 	}
 	var data map[string]string
 	err = yaml.Unmarshal(yml, &data)
 	if err != nil {
-		return nil, errors.Wrap(err, "unmarshalling yaml")
+		return nil, "", errors.Wrap(err, "unmarshalling yaml")
+	}
+	var keyID string
+	if _, encrypted := data[encryptionField]; encrypted {
+		if keys == nil {
+			return nil, "", errors.Errorf("secret %v is encrypted, but no secret key is configured", filename)
+		}
+		var encrypted encryptedSecret
+		if err := yaml.Unmarshal(yml, &encrypted); err != nil {
+			return nil, "", errors.Wrap(err, "unmarshalling encrypted secret")
+		}
+		yml, err = keys.decrypt(filename, encrypted)
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "decrypting secret %v", filename)
+		}
+		data = nil
+		if err := yaml.Unmarshal(yml, &data); err != nil {
+			return nil, "", errors.Wrap(err, "unmarshalling decrypted yaml")
+		}
+		keyID = encrypted.KeyID
 	}
 	return &dependencies.Secret{
 		Ref:  filename,
 		Data: data,
-	}, nil
+	}, keyID, nil
 }
 
 func deleteFile(dir, filename string) error {
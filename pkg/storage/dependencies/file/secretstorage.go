@@ -17,12 +17,17 @@ import (
 type secretStorage struct {
 	dir           string
 	syncFrequency time.Duration
+	keys          *Keyring
 }
 
-func NewSecretStorage(dir string, syncFrequency time.Duration) (dependencies.SecretStorage, error) {
+// NewSecretStorage creates a secret storage that keeps each secret in a yaml file in dir.
+// if keys is not nil, secrets are encrypted with its primary key when written, and decrypted when read.
+// secrets that are not encrypted can still be read
+func NewSecretStorage(dir string, syncFrequency time.Duration, keys *Keyring) (dependencies.SecretStorage, error) {
 	return &secretStorage{
 		dir:           dir,
 		syncFrequency: syncFrequency,
+		keys:          keys,
 	}, nil
 }
 
@@ -30,14 +35,14 @@ func (s *secretStorage) Create(secret *dependencies.Secret) (*dependencies.Secre
 	if _, err := s.Get(secret.Ref); err == nil {
 		return nil, storage.NewAlreadyExistsErr(errors.Errorf("secret %v", secret.Ref))
 	}
-	if err := writeSecret(s.dir, secret); err != nil {
+	if err := writeSecret(s.dir, secret, s.keys); err != nil {
 		return nil, errors.Wrap(err, "writing secret")
 	}
 	return s.Get(secret.Ref)
 }
 
 func (s *secretStorage) Update(secret *dependencies.Secret) (*dependencies.Secret, error) {
-	if err := writeSecret(s.dir, secret); err != nil {
+	if err := writeSecret(s.dir, secret, s.keys); err != nil {
 		return nil, errors.Wrap(err, "writing secret")
 	}
 	return s.Get(secret.Ref)
@@ -52,7 +57,7 @@ func (s *secretStorage) Delete(ref string) error {
 }
 
 func (s *secretStorage) Get(ref string) (*dependencies.Secret, error) {
-	secret, err := readSecret(s.dir, ref)
+	secret, err := readSecret(s.dir, ref, s.keys)
 	if err != nil {
 		return nil, errors.Wrap(err, "reading secret")
 	}
@@ -66,6 +71,9 @@ func (s *secretStorage) List() ([]*dependencies.Secret, error) {
 	}
 	var secrets []*dependencies.Secret
 	for _, f := range osSecrets {
+		if isTempFile(f.Name()) {
+			continue
+		}
 		secret, err := s.Get(f.Name())
 		if err != nil {
 			return nil, errors.Wrapf(err, "getting secret %v", f.Name())
@@ -78,7 +86,8 @@ func (s *secretStorage) List() ([]*dependencies.Secret, error) {
 func (s *secretStorage) Watch(handlers ...dependencies.SecretEventHandler) (*storage.Watcher, error) {
 	w := watcher.New()
 	w.SetMaxEvents(0)
-	w.FilterOps(watcher.Create, watcher.Write, watcher.Remove)
+	// secrets are written to temp files that are renamed over the secret
+	w.FilterOps(watcher.Create, watcher.Write, watcher.Remove, watcher.Rename, watcher.Move)
 	if err := w.AddRecursive(s.dir); err != nil {
 		return nil, errors.Wrapf(err, "failed to add directory %v", s.dir)
 	}
@@ -120,6 +129,11 @@ func (s *secretStorage) Watch(handlers ...dependencies.SecretEventHandler) (*sto
 
 func (s *secretStorage) onEvent(event watcher.Event, handlers ...dependencies.SecretEventHandler) error {
 	log.Debugf("secret event: %v [%v]", event.Path, event.Op)
+	// the path of renames ends with the new path
+	filename := filepath.Base(event.Path)
+	if isTempFile(filename) {
+		return nil
+	}
 	current, err := s.List()
 	if err != nil {
 		return err
@@ -128,9 +142,9 @@ func (s *secretStorage) onEvent(event watcher.Event, handlers ...dependencies.Se
 		return nil
 	}
 	switch event.Op {
-	case watcher.Create:
+	case watcher.Create, watcher.Rename, watcher.Move:
 		for _, h := range handlers {
-			created, err := readSecret(s.dir, filepath.Base(event.Path))
+			created, err := readSecret(s.dir, filename, s.keys)
 			if err != nil {
 				return err
 			}
@@ -138,7 +152,7 @@ func (s *secretStorage) onEvent(event watcher.Event, handlers ...dependencies.Se
 		}
 	case watcher.Write:
 		for _, h := range handlers {
-			updated, err := readSecret(s.dir, filepath.Base(event.Path))
+			updated, err := readSecret(s.dir, filename, s.keys)
 			if err != nil {
 				return err
 			}
@@ -153,3 +167,33 @@ func (s *secretStorage) onEvent(event watcher.Event, handlers ...dependencies.Se
 	}
 	return nil
 }
+
+// RotateSecretKey re-encrypts the secrets in dir with the primary key of keys, so the other keys can be removed.
+// secrets that are not encrypted are encrypted. returns the refs of the secrets that were written
+func RotateSecretKey(dir string, keys *Keyring) ([]string, error) {
+	if keys == nil {
+		return nil, errors.New("no secret key is configured")
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read dir")
+	}
+	var rotated []string
+	for _, f := range files {
+		if f.IsDir() || isTempFile(f.Name()) {
+			continue
+		}
+		secret, keyID, err := readSecretFile(dir, f.Name(), keys)
+		if err != nil {
+			return rotated, errors.Wrapf(err, "reading secret %v", f.Name())
+		}
+		if keyID == keys.primary().id {
+			continue
+		}
+		if err := writeSecret(dir, secret, keys); err != nil {
+			return rotated, errors.Wrapf(err, "writing secret %v", f.Name())
+		}
+		rotated = append(rotated, secret.Ref)
+	}
+	return rotated, nil
+}
@@ -24,7 +24,7 @@ var _ = Describe("Client", func() {
 	BeforeEach(func() {
 		dir, err = ioutil.TempDir("", "")
 		Must(err)
-		client, err = NewSecretStorage(dir, time.Millisecond)
+		client, err = NewSecretStorage(dir, time.Millisecond, nil)
 		Must(err)
 	})
 	AfterEach(func() { os.RemoveAll(dir) })
@@ -159,4 +159,156 @@ var _ = Describe("Client", func() {
 			Expect(list3).To(ContainElement(s3))
 		})
 	})
+
+	Describe("writes", func() {
+		It("replaces secret files, readable only by their owner", func() {
+			path := filepath.Join(dir, "tls")
+			Expect(ioutil.WriteFile(path, []byte("private_key: old"), 0644)).To(Succeed())
+			_, err := client.Update(&dependencies.Secret{Ref: "tls", Data: map[string]string{"private_key": "new"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			info, err := os.Stat(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+			files, err := ioutil.ReadDir(dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(HaveLen(1))
+		})
+		It("ignores the temp files of writes in progress", func() {
+			Expect(ioutil.WriteFile(filepath.Join(dir, ".tls.123"), []byte("private_key: partial"), 0600)).To(Succeed())
+			list, err := client.List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(list).To(BeEmpty())
+			_, err = client.Create(&dependencies.Secret{Ref: ".tls"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("encryption", func() {
+		var keys *Keyring
+		newKey := func() string {
+			key, err := GenerateKey()
+			Must(err)
+			return key
+		}
+		BeforeEach(func() {
+			keys, err = ParseKeyring(newKey())
+			Must(err)
+			client, err = NewSecretStorage(dir, time.Millisecond, keys)
+			Must(err)
+		})
+		secret := &dependencies.Secret{
+			Ref:  "tls",
+			Data: map[string]string{"private_key": "super secret key"},
+		}
+		It("encrypts secrets on disk and decrypts them when read", func() {
+			s, err := client.Create(secret)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(Equal(secret))
+			b, err := ioutil.ReadFile(filepath.Join(dir, secret.Ref))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(b)).NotTo(ContainSubstring("super secret key"))
+
+			list, err := client.List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(list).To(Equal([]*dependencies.Secret{secret}))
+
+			plain, err := NewSecretStorage(dir, time.Millisecond, nil)
+			Must(err)
+			_, err = plain.Get(secret.Ref)
+			Expect(err).To(MatchError(ContainSubstring("secret tls is encrypted, but no secret key is configured")))
+			other, err := ParseKeyring(newKey())
+			Must(err)
+			wrongKey, err := NewSecretStorage(dir, time.Millisecond, other)
+			Must(err)
+			_, err = wrongKey.Get(secret.Ref)
+			Expect(err).To(MatchError(ContainSubstring("which is not configured")))
+		})
+		It("does not decrypt a secret renamed to another ref", func() {
+			_, err := client.Create(secret)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.Rename(filepath.Join(dir, secret.Ref), filepath.Join(dir, "other"))).To(Succeed())
+			_, err = client.Get("other")
+			Expect(err).To(MatchError(ContainSubstring("decrypting data")))
+		})
+		It("reads secrets that are not encrypted", func() {
+			b, err := yaml.Marshal(secret.Data)
+			Must(err)
+			Expect(ioutil.WriteFile(filepath.Join(dir, secret.Ref), b, 0644)).To(Succeed())
+			s, err := client.Get(secret.Ref)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(Equal(secret))
+		})
+		It("rotates secrets to the first key", func() {
+			oldKey := newKey()
+			oldKeys, err := ParseKeyring(oldKey)
+			Must(err)
+			old, err := NewSecretStorage(dir, time.Millisecond, oldKeys)
+			Must(err)
+			_, err = old.Create(secret)
+			Expect(err).NotTo(HaveOccurred())
+			plain, err := NewSecretStorage(dir, time.Millisecond, nil)
+			Must(err)
+			_, err = plain.Create(&dependencies.Secret{Ref: "plain", Data: map[string]string{"password": "hunter2"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			primaryKey := newKey()
+			newKeys, err := ParseKeyring(primaryKey + "\n" + oldKey + "\n")
+			Must(err)
+			rotated, err := RotateSecretKey(dir, newKeys)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rotated).To(Equal([]string{"plain", "tls"}))
+			// secrets already encrypted with the first key are left alone
+			rotated, err = RotateSecretKey(dir, newKeys)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rotated).To(BeEmpty())
+
+			// the old key is no longer needed
+			_, err = old.Get(secret.Ref)
+			Expect(err).To(HaveOccurred())
+			primaryKeys, err := ParseKeyring(primaryKey)
+			Must(err)
+			current, err := NewSecretStorage(dir, time.Millisecond, primaryKeys)
+			Must(err)
+			list, err := current.List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(list).To(HaveLen(2))
+			b, err := ioutil.ReadFile(filepath.Join(dir, "plain"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(b)).NotTo(ContainSubstring("hunter2"))
+		})
+		It("loads keys from the key file, or the environment", func() {
+			key := newKey()
+			keyFile, err := ioutil.TempFile("", "secret-key")
+			Must(err)
+			defer os.Remove(keyFile.Name())
+			_, err = keyFile.WriteString(key + "\n")
+			Must(err)
+			Must(keyFile.Close())
+			fromFile, err := LoadKeyring(keyFile.Name())
+			Expect(err).NotTo(HaveOccurred())
+
+			defer os.Unsetenv(SecretKeyEnv)
+			os.Unsetenv(SecretKeyEnv)
+			none, err := LoadKeyring("")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(none).To(BeNil())
+			os.Setenv(SecretKeyEnv, key)
+			fromEnv, err := LoadKeyring("")
+			Expect(err).NotTo(HaveOccurred())
+
+			writer, err := NewSecretStorage(dir, time.Millisecond, fromFile)
+			Must(err)
+			_, err = writer.Create(secret)
+			Expect(err).NotTo(HaveOccurred())
+			reader, err := NewSecretStorage(dir, time.Millisecond, fromEnv)
+			Must(err)
+			s, err := reader.Get(secret.Ref)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(Equal(secret))
+
+			_, err = ParseKeyring("c2hvcnQ=")
+			Expect(err).To(MatchError("secret key 0 is 5 bytes long, must be 32"))
+		})
+	})
 })
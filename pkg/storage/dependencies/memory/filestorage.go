@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/pkg/storage"
+	"github.com/solo-io/gloo/pkg/storage/dependencies"
+	"github.com/solo-io/gloo/pkg/storage/memory"
+)
+
+type fileStorage struct {
+	store *memory.Store
+}
+
+// NewFileStorage creates an empty file storage that keeps files in memory
+func NewFileStorage() dependencies.FileStorage {
+	return &fileStorage{store: memory.NewStore("file")}
+}
+
+func (s *fileStorage) Create(file *dependencies.File) (*dependencies.File, error) {
+	if file.Ref == "" {
+		return nil, errors.Errorf("ref required")
+	}
+	entry, err := s.store.Create(file.Ref, copyFile(file))
+	if err != nil {
+		return nil, err
+	}
+	return fileFromEntry(entry), nil
+}
+
+func (s *fileStorage) Update(file *dependencies.File) (*dependencies.File, error) {
+	if file.Ref == "" {
+		return nil, errors.Errorf("ref required")
+	}
+	if file.ResourceVersion == "" {
+		return nil, errors.New("resource version must be set for update operations")
+	}
+	entry, err := s.store.Update(file.Ref, file.ResourceVersion, copyFile(file))
+	if err != nil {
+		return nil, err
+	}
+	return fileFromEntry(entry), nil
+}
+
+func (s *fileStorage) Delete(name string) error {
+	return s.store.Delete(name)
+}
+
+func (s *fileStorage) Get(name string) (*dependencies.File, error) {
+	entry, err := s.store.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return fileFromEntry(entry), nil
+}
+
+func (s *fileStorage) List() ([]*dependencies.File, error) {
+	return filesFromEntries(s.store.List()), nil
+}
+
+func (s *fileStorage) Watch(handlers ...dependencies.FileEventHandler) (*storage.Watcher, error) {
+	return s.store.Watch(func(event memory.Event) {
+		list := filesFromEntries(event.List)
+		var file *dependencies.File
+		if event.Entry != nil {
+			file = fileFromEntry(*event.Entry)
+		}
+		for _, h := range handlers {
+			switch event.Type {
+			case memory.EventAdded:
+				h.OnAdd(list, file)
+			case memory.EventUpdated:
+				h.OnUpdate(list, file)
+			case memory.EventDeleted:
+				h.OnDelete(list, file)
+			}
+		}
+	}), nil
+}
+
+func copyFile(file *dependencies.File) *dependencies.File {
+	contents := make([]byte, len(file.Contents))
+	copy(contents, file.Contents)
+	return &dependencies.File{
+		Ref:             file.Ref,
+		Contents:        contents,
+		ResourceVersion: file.ResourceVersion,
+	}
+}
+
+func fileFromEntry(entry memory.Entry) *dependencies.File {
+	file := copyFile(entry.Object.(*dependencies.File))
+	file.ResourceVersion = entry.ResourceVersion
+	return file
+}
+
+func filesFromEntries(entries []memory.Entry) []*dependencies.File {
+	var files []*dependencies.File
+	for _, entry := range entries {
+		files = append(files, fileFromEntry(entry))
+	}
+	return files
+}
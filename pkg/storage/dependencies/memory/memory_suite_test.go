@@ -0,0 +1,15 @@
+package memory_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/solo-io/gloo/pkg/log"
+)
+
+func TestMemoryDependencies(t *testing.T) {
+	RegisterFailHandler(Fail)
+	log.DefaultOut = GinkgoWriter
+	RunSpecs(t, "Memory Dependencies Suite")
+}
@@ -0,0 +1,104 @@
+package memory_test
+
+import (
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo/pkg/storage"
+	"github.com/solo-io/gloo/pkg/storage/dependencies"
+	. "github.com/solo-io/gloo/pkg/storage/dependencies/memory"
+)
+
+var _ = Describe("SecretStorage", func() {
+	var secrets dependencies.SecretStorage
+	BeforeEach(func() {
+		secrets = NewSecretStorage()
+	})
+	It("creates, updates and deletes secrets", func() {
+		created, err := secrets.Create(&dependencies.Secret{Ref: "creds", Data: map[string]string{"key": "value"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created.ResourceVersion).NotTo(BeEmpty())
+		_, err = secrets.Create(&dependencies.Secret{Ref: "creds"})
+		Expect(storage.IsAlreadyExists(err)).To(BeTrue())
+
+		// the stored secret is not shared with callers
+		created.Data["key"] = "changed"
+		stored, err := secrets.Get("creds")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.Data).To(Equal(map[string]string{"key": "value"}))
+
+		updated, err := secrets.Update(created)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated.Data).To(Equal(map[string]string{"key": "changed"}))
+		_, err = secrets.Update(created)
+		Expect(storage.IsConflict(err)).To(BeTrue())
+
+		Expect(secrets.Delete("creds")).To(Succeed())
+		Expect(secrets.List()).To(BeEmpty())
+	})
+	It("delivers changes to watches", func() {
+		var (
+			lock  sync.Mutex
+			lists [][]*dependencies.Secret
+		)
+		record := func(list []*dependencies.Secret, _ *dependencies.Secret) {
+			lock.Lock()
+			defer lock.Unlock()
+			lists = append(lists, list)
+		}
+		latest := func() []*dependencies.Secret {
+			lock.Lock()
+			defer lock.Unlock()
+			if len(lists) == 0 {
+				return nil
+			}
+			return lists[len(lists)-1]
+		}
+		_, err := secrets.Create(&dependencies.Secret{Ref: "existing"})
+		Expect(err).NotTo(HaveOccurred())
+		w, err := secrets.Watch(&dependencies.SecretEventHandlerFuncs{
+			AddFunc:    record,
+			UpdateFunc: record,
+			DeleteFunc: record,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		stop := make(chan struct{})
+		defer close(stop)
+		go w.Run(stop, make(chan error))
+
+		Eventually(latest).Should(HaveLen(1))
+		_, err = secrets.Create(&dependencies.Secret{Ref: "new"})
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(latest).Should(HaveLen(2))
+		Expect(secrets.Delete("existing")).To(Succeed())
+		Eventually(func() string {
+			list := latest()
+			if len(list) != 1 {
+				return ""
+			}
+			return list[0].Ref
+		}).Should(Equal("new"))
+	})
+})
+
+var _ = Describe("FileStorage", func() {
+	It("creates, updates and deletes files", func() {
+		files := NewFileStorage()
+		created, err := files.Create(&dependencies.File{Ref: "some/file", Contents: []byte("contents")})
+		Expect(err).NotTo(HaveOccurred())
+		created.Contents = []byte("changed")
+		updated, err := files.Update(created)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated.ResourceVersion).NotTo(Equal(created.ResourceVersion))
+		stored, err := files.Get("some/file")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.Contents).To(Equal([]byte("changed")))
+		_, err = files.Update(created)
+		Expect(storage.IsConflict(err)).To(BeTrue())
+		Expect(files.Delete("some/file")).To(Succeed())
+		_, err = files.Get("some/file")
+		Expect(err).To(HaveOccurred())
+	})
+})
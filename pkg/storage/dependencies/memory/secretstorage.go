@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/pkg/storage"
+	"github.com/solo-io/gloo/pkg/storage/dependencies"
+	"github.com/solo-io/gloo/pkg/storage/memory"
+)
+
+type secretStorage struct {
+	store *memory.Store
+}
+
+// NewSecretStorage creates an empty secret storage that keeps secrets in memory
+func NewSecretStorage() dependencies.SecretStorage {
+	return &secretStorage{store: memory.NewStore("secret")}
+}
+
+func (s *secretStorage) Create(secret *dependencies.Secret) (*dependencies.Secret, error) {
+	if secret.Ref == "" {
+		return nil, errors.Errorf("ref required")
+	}
+	entry, err := s.store.Create(secret.Ref, copySecret(secret))
+	if err != nil {
+		return nil, err
+	}
+	return secretFromEntry(entry), nil
+}
+
+func (s *secretStorage) Update(secret *dependencies.Secret) (*dependencies.Secret, error) {
+	if secret.Ref == "" {
+		return nil, errors.Errorf("ref required")
+	}
+	if secret.ResourceVersion == "" {
+		return nil, errors.New("resource version must be set for update operations")
+	}
+	entry, err := s.store.Update(secret.Ref, secret.ResourceVersion, copySecret(secret))
+	if err != nil {
+		return nil, err
+	}
+	return secretFromEntry(entry), nil
+}
+
+func (s *secretStorage) Delete(name string) error {
+	return s.store.Delete(name)
+}
+
+func (s *secretStorage) Get(name string) (*dependencies.Secret, error) {
+	entry, err := s.store.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return secretFromEntry(entry), nil
+}
+
+func (s *secretStorage) List() ([]*dependencies.Secret, error) {
+	return secretsFromEntries(s.store.List()), nil
+}
+
+func (s *secretStorage) Watch(handlers ...dependencies.SecretEventHandler) (*storage.Watcher, error) {
+	return s.store.Watch(func(event memory.Event) {
+		list := secretsFromEntries(event.List)
+		var secret *dependencies.Secret
+		if event.Entry != nil {
+			secret = secretFromEntry(*event.Entry)
+		}
+		for _, h := range handlers {
+			switch event.Type {
+			case memory.EventAdded:
+				h.OnAdd(list, secret)
+			case memory.EventUpdated:
+				h.OnUpdate(list, secret)
+			case memory.EventDeleted:
+				h.OnDelete(list, secret)
+			}
+		}
+	}), nil
+}
+
+func copySecret(secret *dependencies.Secret) *dependencies.Secret {
+	data := make(map[string]string)
+	for k, v := range secret.Data {
+		data[k] = v
+	}
+	return &dependencies.Secret{
+		Ref:             secret.Ref,
+		Data:            data,
+		ResourceVersion: secret.ResourceVersion,
+	}
+}
+
+func secretFromEntry(entry memory.Entry) *dependencies.Secret {
+	secret := copySecret(entry.Object.(*dependencies.Secret))
+	secret.ResourceVersion = entry.ResourceVersion
+	return secret
+}
+
+func secretsFromEntries(entries []memory.Entry) []*dependencies.Secret {
+	var secrets []*dependencies.Secret
+	for _, entry := range entries {
+		secrets = append(secrets, secretFromEntry(entry))
+	}
+	return secrets
+}
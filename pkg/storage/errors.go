@@ -1,6 +1,10 @@
 package storage
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
 
 // a special kind of error returned by "create" funcs
 // can be used by callers to tell if they can ignore create errors
@@ -23,3 +27,75 @@ func IsAlreadyExists(err error) bool {
 	}
 	return false
 }
+
+// a special kind of error returned by "update" funcs when the resource version
+// of the object is not the latest. callers can get the latest version and retry
+type conflictErr struct {
+	err error
+}
+
+func (err *conflictErr) Error() string {
+	return fmt.Sprintf("conflict: %v", err.err.Error())
+}
+
+func NewConflictErr(err error) *conflictErr {
+	return &conflictErr{err: err}
+}
+
+// IsConflict also detects conflicts wrapped with errors.Wrap
+func IsConflict(err error) bool {
+	switch errors.Cause(err).(type) {
+	case *conflictErr:
+		return true
+	}
+	return false
+}
+
+// a special kind of error delivered by event watches that cannot resume from the requested
+// resource version, because the backend no longer keeps the changes since. callers must restart
+// the watch without a resource version, which delivers every object again
+type resourceVersionExpiredErr struct {
+	err error
+}
+
+func (err *resourceVersionExpiredErr) Error() string {
+	return fmt.Sprintf("resource version expired: %v", err.err.Error())
+}
+
+func NewResourceVersionExpiredErr(err error) *resourceVersionExpiredErr {
+	return &resourceVersionExpiredErr{err: err}
+}
+
+// IsResourceVersionExpired also detects errors wrapped with errors.Wrap
+func IsResourceVersionExpired(err error) bool {
+	switch errors.Cause(err).(type) {
+	case *resourceVersionExpiredErr:
+		return true
+	}
+	return false
+}
+
+// a special kind of error returned by Apply when a config has more changes than the backend can
+// write atomically. nothing is written. callers that do not need the changes to be applied atomically
+// can apply the config with ApplyInOrder
+type tooManyChangesErr struct {
+	changes int
+	max     int
+}
+
+func (err *tooManyChangesErr) Error() string {
+	return fmt.Sprintf("too many changes: the config has %v changes, but at most %v can be applied at once", err.changes, err.max)
+}
+
+func NewTooManyChangesErr(changes, max int) *tooManyChangesErr {
+	return &tooManyChangesErr{changes: changes, max: max}
+}
+
+// IsTooManyChanges also detects errors wrapped with errors.Wrap
+func IsTooManyChanges(err error) bool {
+	switch errors.Cause(err).(type) {
+	case *tooManyChangesErr:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package etcd_test
+
+import (
+	"context"
+
+	"github.com/coreos/etcd/clientv3"
+
+	"github.com/solo-io/gloo/pkg/storage"
+	. "github.com/solo-io/gloo/pkg/storage/etcd"
+	"github.com/solo-io/gloo/pkg/storage/storagetest"
+)
+
+// each namespace is a root path in the etcd started by the suite
+type etcdBackend struct {
+	rootPaths []string
+}
+
+func (b *etcdBackend) NewStorage(namespace string) (storage.Interface, error) {
+	b.rootPaths = append(b.rootPaths, namespace)
+	return NewStorage(client, namespace), nil
+}
+
+func (b *etcdBackend) Cleanup() {
+	for _, rootPath := range b.rootPaths {
+		client.Delete(context.Background(), rootPath+"/", clientv3.WithPrefix())
+	}
+}
+
+var _ = storagetest.Conformance("etcd", func() storagetest.Backend {
+	return &etcdBackend{}
+})
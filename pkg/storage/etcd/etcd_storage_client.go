@@ -0,0 +1,132 @@
+package etcd
+
+import (
+	"context"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/storage"
+)
+
+// etcd servers limit the operations of a transaction, to 128 by default
+const maxTxnOps = 128
+
+//go:generate go run ${GOPATH}/src/github.com/solo-io/gloo/pkg/storage/generate/generate_clients.go -f ${GOPATH}/src/github.com/solo-io/gloo/pkg/storage/etcd/client_template.go.tmpl -o ${GOPATH}/src/github.com/solo-io/gloo/pkg/storage/etcd/
+type Client struct {
+	v1 *v1client
+}
+
+// NewStorage creates a storage that keeps config objects in etcd, under keys starting with rootPath.
+// resource versions are the etcd revisions the objects were last modified at
+func NewStorage(client *clientv3.Client, rootPath string) storage.Interface {
+	return &Client{
+		v1: &v1client{
+			client: client,
+			upstreams: &upstreamsClient{
+				store: NewStore(client, rootPath+"/upstreams", "upstream"),
+			},
+			virtualServices: &virtualServicesClient{
+				store: NewStore(client, rootPath+"/virtualservices", "virtual service"),
+			},
+			roles: &rolesClient{
+				store: NewStore(client, rootPath+"/roles", "role"),
+			},
+		},
+	}
+}
+
+func (c *Client) V1() storage.V1 {
+	return c.v1
+}
+
+type v1client struct {
+	client          *clientv3.Client
+	upstreams       *upstreamsClient
+	virtualServices *virtualServicesClient
+	roles           *rolesClient
+}
+
+func (c *v1client) Register() error {
+	return nil
+}
+
+func (c *v1client) Upstreams() storage.Upstreams {
+	return c.upstreams
+}
+
+func (c *v1client) VirtualServices() storage.VirtualServices {
+	return c.virtualServices
+}
+
+func (c *v1client) Roles() storage.Roles {
+	return c.roles
+}
+
+// Apply writes every change in a single transaction, so watches see them at the same revision.
+// if an object changed since the changes were planned, nothing is written and a conflict error is returned
+// configs with more than maxTxnOps changes are not written, and a too many changes error is returned
+func (c *v1client) Apply(config *v1.Config) error {
+	changes, err := storage.PlanApply(c, config)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	if len(changes) > maxTxnOps {
+		return storage.NewTooManyChangesErr(len(changes), maxTxnOps)
+	}
+	var (
+		conditions []clientv3.Cmp
+		ops        []clientv3.Op
+	)
+	for _, change := range changes {
+		condition, op, err := c.txnOp(change)
+		if err != nil {
+			return errors.Wrapf(err, "failed to %v", change)
+		}
+		conditions = append(conditions, condition)
+		ops = append(ops, op)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	resp, err := c.client.Txn(ctx).If(conditions...).Then(ops...).Commit()
+	if err != nil {
+		return errors.Wrap(err, "applying config")
+	}
+	if !resp.Succeeded {
+		return storage.NewConflictErr(errors.New("config objects changed while applying the config, no change was applied"))
+	}
+	return nil
+}
+
+func (c *v1client) txnOp(change storage.Change) (clientv3.Cmp, clientv3.Op, error) {
+	var store *Store
+	switch change.Object.(type) {
+	case *v1.Upstream:
+		store = c.upstreams.store
+	case *v1.VirtualService:
+		store = c.virtualServices.store
+	case *v1.Role:
+		store = c.roles.store
+	default:
+		return clientv3.Cmp{}, clientv3.Op{}, errors.Errorf("unknown config object type %T", change.Object)
+	}
+	name := change.Object.GetName()
+	resourceVersion := change.Object.GetMetadata().GetResourceVersion()
+	if change.Type == storage.EventDeleted {
+		return store.DeleteOp(name, resourceVersion)
+	}
+	data, err := proto.Marshal(change.Object)
+	if err != nil {
+		return clientv3.Cmp{}, clientv3.Op{}, errors.Wrapf(err, "marshalling %v", name)
+	}
+	if change.Type == storage.EventAdded {
+		condition, op := store.CreateOp(name, data)
+		return condition, op, nil
+	}
+	return store.UpdateOp(name, resourceVersion, data)
+}
@@ -0,0 +1,53 @@
+package etcd_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/solo-io/gloo/pkg/log"
+	"github.com/solo-io/gloo/test/helpers"
+	"github.com/solo-io/gloo/test/helpers/local"
+)
+
+func TestEtcd(t *testing.T) {
+	if os.Getenv("RUN_ETCD_TESTS") != "1" {
+		log.Printf("This test downloads and runs etcd and is disabled by default. To enable, set RUN_ETCD_TESTS=1 in your env.")
+		return
+	}
+	RegisterFailHandler(Fail)
+	log.DefaultOut = GinkgoWriter
+	RunSpecs(t, "Etcd Suite")
+}
+
+var (
+	etcdFactory  *localhelpers.EtcdFactory
+	etcdInstance *localhelpers.EtcdInstance
+	client       *clientv3.Client
+	err          error
+)
+
+var _ = BeforeSuite(func() {
+	etcdFactory, err = localhelpers.NewEtcdFactory()
+	helpers.Must(err)
+	etcdInstance, err = etcdFactory.NewEtcdInstance()
+	helpers.Must(err)
+	err = etcdInstance.Run()
+	helpers.Must(err)
+	client, err = clientv3.New(clientv3.Config{
+		Endpoints:   []string{localhelpers.EtcdEndpoint},
+		DialTimeout: 5 * time.Second,
+	})
+	helpers.Must(err)
+})
+
+var _ = AfterSuite(func() {
+	if client != nil {
+		client.Close()
+	}
+	etcdInstance.Clean()
+	etcdFactory.Clean()
+})
@@ -0,0 +1,155 @@
+package etcd
+
+import (
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/log"
+	"github.com/solo-io/gloo/pkg/storage"
+)
+
+type rolesClient struct {
+	store *Store
+}
+
+func (c *rolesClient) Create(item *v1.Role) (*v1.Role, error) {
+	if item.Name == "" {
+		return nil, errors.Errorf("name required")
+	}
+	data, err := proto.Marshal(item)
+	if err != nil {
+		return nil, errors.Wrapf(err, "marshalling role %v", item.Name)
+	}
+	entry, err := c.store.Create(item.Name, data)
+	if err != nil {
+		return nil, err
+	}
+	return roleFromEntry(entry)
+}
+
+func (c *rolesClient) Update(item *v1.Role) (*v1.Role, error) {
+	if item.Name == "" {
+		return nil, errors.Errorf("name required")
+	}
+	if item.Metadata == nil || item.Metadata.ResourceVersion == "" {
+		return nil, errors.New("resource version must be set for update operations")
+	}
+	data, err := proto.Marshal(item)
+	if err != nil {
+		return nil, errors.Wrapf(err, "marshalling role %v", item.Name)
+	}
+	entry, err := c.store.Update(item.Name, item.Metadata.ResourceVersion, data)
+	if err != nil {
+		return nil, err
+	}
+	return roleFromEntry(entry)
+}
+
+func (c *rolesClient) Delete(name string) error {
+	return c.store.Delete(name)
+}
+
+func (c *rolesClient) Get(name string) (*v1.Role, error) {
+	entry, err := c.store.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return roleFromEntry(entry)
+}
+
+func (c *rolesClient) List() ([]*v1.Role, error) {
+	entries, err := c.store.List()
+	if err != nil {
+		return nil, err
+	}
+	return rolesFromEntries(entries)
+}
+
+func (c *rolesClient) ListWithOptions(opts storage.ListOptions) ([]*v1.Role, error) {
+	list, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	return storage.FilterRoles(list, opts), nil
+}
+
+func (c *rolesClient) Watch(handlers ...storage.RoleEventHandler) (*storage.Watcher, error) {
+	return c.store.Watch(func(event Event) {
+		list, err := rolesFromEntries(event.List)
+		if err != nil {
+			log.Warnf("error in role watch: %v", err)
+			return
+		}
+		var role *v1.Role
+		if event.Entry != nil {
+			role, err = roleFromEntry(*event.Entry)
+			if err != nil {
+				log.Warnf("error in role watch: %v", err)
+				return
+			}
+		}
+		for _, h := range handlers {
+			switch event.Type {
+			case EventAdded:
+				h.OnAdd(list, role)
+			case EventUpdated:
+				h.OnUpdate(list, role)
+			case EventDeleted:
+				h.OnDelete(list, role)
+			}
+		}
+	}), nil
+}
+
+func (c *rolesClient) WatchWithOptions(opts storage.ListOptions, handlers ...storage.RoleEventHandler) (*storage.Watcher, error) {
+	var filtered []storage.RoleEventHandler
+	for _, h := range handlers {
+		filtered = append(filtered, storage.FilterRoleEventHandler(opts, h))
+	}
+	return c.Watch(filtered...)
+}
+
+func (c *rolesClient) WatchEvents(opts storage.WatchOptions, onEvent func(event storage.RoleEvent)) (*storage.Watcher, error) {
+	filtered := storage.FilterRoleEvents(opts, onEvent)
+	return c.store.WatchEvents(opts.ResourceVersion, func(event Event) {
+		role, err := roleFromEntry(*event.Entry)
+		if err != nil {
+			log.Warnf("error in role watch: %v", err)
+			return
+		}
+		// deletes of entries etcd no longer has the value of only carry the name
+		if role.Name == "" {
+			role.Name = event.Entry.Name
+		}
+		filtered(storage.RoleEvent{
+			Type:            event.Type,
+			Role:            role,
+			ResourceVersion: event.ResourceVersion,
+		})
+	}), nil
+}
+
+func roleFromEntry(entry Entry) (*v1.Role, error) {
+	var role v1.Role
+	if err := proto.Unmarshal(entry.Data, &role); err != nil {
+		return nil, errors.Wrapf(err, "unmarshalling role %v", entry.Name)
+	}
+	if role.Metadata == nil {
+		role.Metadata = &v1.Metadata{}
+	}
+	role.Metadata.ResourceVersion = entry.ResourceVersion
+	return &role, nil
+}
+
+func rolesFromEntries(entries []Entry) ([]*v1.Role, error) {
+	var roles []*v1.Role
+	for _, entry := range entries {
+		role, err := roleFromEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
@@ -0,0 +1,383 @@
+package etcd
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/pkg/log"
+	"github.com/solo-io/gloo/pkg/storage"
+)
+
+const (
+	// timeout for a single request to etcd
+	requestTimeout = 10 * time.Second
+	// how long to wait before restarting a failed watch
+	watchRetryInterval = time.Second
+)
+
+// the kinds of change delivered to watches
+const (
+	EventAdded   = storage.EventAdded
+	EventUpdated = storage.EventUpdated
+	EventDeleted = storage.EventDeleted
+)
+
+// Entry is the serialized object stored under a key, along with its name and resource version.
+// the resource version is the etcd revision the key was last modified at
+type Entry struct {
+	Name            string
+	ResourceVersion string
+	Data            []byte
+}
+
+// Event is a change to a Store. List contains every entry in the store after the change,
+// and is only set for watches of lists
+type Event struct {
+	Type storage.EventType
+	// the changed entry. nil for the initial event of a watch of lists
+	Entry *Entry
+	List  []Entry
+	// the revision of the store after the change, which event watches can resume from
+	ResourceVersion string
+}
+
+// Store keeps serialized objects of a single kind under a key prefix in etcd.
+// updates are compare-and-swap transactions on the revision the key was last modified at.
+// it is the base of the typed etcd storage clients
+type Store struct {
+	client *clientv3.Client
+	// every key of the store starts with the prefix, the rest of the key is the name
+	prefix string
+	// the kind of object, for error messages
+	kind string
+}
+
+func NewStore(client *clientv3.Client, rootPath, kind string) *Store {
+	return &Store{
+		client: client,
+		prefix: strings.TrimSuffix(rootPath, "/") + "/",
+		kind:   kind,
+	}
+}
+
+func (s *Store) Create(name string, data []byte) (Entry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	key := s.key(name)
+	// a key that was never created, or was deleted since, has a create revision of 0
+	resp, err := s.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, string(data))).
+		Commit()
+	if err != nil {
+		return Entry{}, errors.Wrapf(err, "creating %v %v", s.kind, name)
+	}
+	if !resp.Succeeded {
+		return Entry{}, storage.NewAlreadyExistsErr(errors.Errorf("%v %v already exists", s.kind, name))
+	}
+	return s.entry(name, resp.Header.Revision, data), nil
+}
+
+// Update replaces the object only if resourceVersion is the version currently stored
+func (s *Store) Update(name, resourceVersion string, data []byte) (Entry, error) {
+	revision, err := strconv.ParseInt(resourceVersion, 10, 64)
+	if err != nil {
+		return Entry{}, errors.Wrapf(err, "invalid resource version %v for %v %v", resourceVersion, s.kind, name)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	key := s.key(name)
+	resp, err := s.client.Txn(ctx).
+		If(
+			clientv3.Compare(clientv3.Version(key), ">", 0),
+			clientv3.Compare(clientv3.ModRevision(key), "=", revision),
+		).
+		Then(clientv3.OpPut(key, string(data))).
+		Else(clientv3.OpGet(key)).
+		Commit()
+	if err != nil {
+		return Entry{}, errors.Wrapf(err, "updating %v %v", s.kind, name)
+	}
+	if !resp.Succeeded {
+		current := resp.Responses[0].GetResponseRange().GetKvs()
+		if len(current) == 0 {
+			return Entry{}, errors.Errorf("%v %v not found", s.kind, name)
+		}
+		return Entry{}, storage.NewConflictErr(errors.Errorf("resource version %v of %v %v is outdated, the current version is %v",
+			resourceVersion, s.kind, name, current[0].ModRevision))
+	}
+	return s.entry(name, resp.Header.Revision, data), nil
+}
+
+func (s *Store) Delete(name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	resp, err := s.client.Delete(ctx, s.key(name))
+	if err != nil {
+		return errors.Wrapf(err, "deleting %v %v", s.kind, name)
+	}
+	if resp.Deleted == 0 {
+		return errors.Errorf("%v %v not found", s.kind, name)
+	}
+	return nil
+}
+
+// CreateOp returns the condition and operation of a create, to combine writes to several stores in one transaction
+func (s *Store) CreateOp(name string, data []byte) (clientv3.Cmp, clientv3.Op) {
+	key := s.key(name)
+	return clientv3.Compare(clientv3.CreateRevision(key), "=", 0), clientv3.OpPut(key, string(data))
+}
+
+// UpdateOp returns the condition and operation of an update of the entry at resourceVersion
+func (s *Store) UpdateOp(name, resourceVersion string, data []byte) (clientv3.Cmp, clientv3.Op, error) {
+	revision, err := strconv.ParseInt(resourceVersion, 10, 64)
+	if err != nil {
+		return clientv3.Cmp{}, clientv3.Op{}, errors.Wrapf(err, "invalid resource version %v for %v %v", resourceVersion, s.kind, name)
+	}
+	// missing keys have a mod revision of 0, which is never a resource version
+	key := s.key(name)
+	return clientv3.Compare(clientv3.ModRevision(key), "=", revision), clientv3.OpPut(key, string(data)), nil
+}
+
+// DeleteOp returns the condition and operation of a delete of the entry at resourceVersion
+func (s *Store) DeleteOp(name, resourceVersion string) (clientv3.Cmp, clientv3.Op, error) {
+	revision, err := strconv.ParseInt(resourceVersion, 10, 64)
+	if err != nil {
+		return clientv3.Cmp{}, clientv3.Op{}, errors.Wrapf(err, "invalid resource version %v for %v %v", resourceVersion, s.kind, name)
+	}
+	key := s.key(name)
+	return clientv3.Compare(clientv3.ModRevision(key), "=", revision), clientv3.OpDelete(key), nil
+}
+
+func (s *Store) Get(name string) (Entry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	resp, err := s.client.Get(ctx, s.key(name))
+	if err != nil {
+		return Entry{}, errors.Wrapf(err, "getting %v %v", s.kind, name)
+	}
+	if len(resp.Kvs) == 0 {
+		return Entry{}, errors.Errorf("%v %v not found", s.kind, name)
+	}
+	return s.entryFromKv(resp.Kvs[0]), nil
+}
+
+// List returns the entries sorted by name
+func (s *Store) List() ([]Entry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	entries, _, err := s.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sortedEntries(entries), nil
+}
+
+// Watch calls onEvent for every change, in the order of the changes.
+// the first event contains the entries in the store when the watch starts running.
+// if the watch fails, e.g. because the revision it watches from was compacted,
+// it is restarted with another initial event
+func (s *Store) Watch(onEvent func(event Event)) *storage.Watcher {
+	return storage.NewWatcher(func(stop <-chan struct{}, _ chan error) {
+		ctx, cancel := stopContext(stop)
+		defer cancel()
+		for {
+			err := s.watch(ctx, onEvent)
+			if ctx.Err() != nil {
+				return
+			}
+			log.Warnf("watching %v in etcd failed, restarting the watch: %v", s.kind, err)
+			select {
+			case <-time.After(watchRetryInterval):
+			case <-stop:
+				return
+			}
+		}
+	})
+}
+
+// WatchEvents calls onEvent for every change after resourceVersion, in the order of the changes.
+// an empty resourceVersion starts the watch with an added event for every entry in the store.
+// failed watches are restarted after the last delivered change. if etcd compacted
+// the changes since, the watch fails with an expired error
+func (s *Store) WatchEvents(resourceVersion string, onEvent func(event Event)) *storage.Watcher {
+	return storage.NewWatcher(func(stop <-chan struct{}, errs chan error) {
+		var revision int64
+		if resourceVersion != "" {
+			var err error
+			revision, err = strconv.ParseInt(resourceVersion, 10, 64)
+			if err != nil {
+				storage.SendExpiredErr(s.kind, resourceVersion, stop, errs)
+				return
+			}
+		}
+		ctx, cancel := stopContext(stop)
+		defer cancel()
+		for {
+			var err error
+			revision, err = s.watchEvents(ctx, revision, onEvent)
+			if ctx.Err() != nil {
+				return
+			}
+			if storage.IsResourceVersionExpired(err) {
+				select {
+				case errs <- err:
+				case <-stop:
+				}
+				return
+			}
+			log.Warnf("watching %v in etcd failed, resuming the watch: %v", s.kind, err)
+			select {
+			case <-time.After(watchRetryInterval):
+			case <-stop:
+				return
+			}
+		}
+	})
+}
+
+// watchEvents delivers the changes after revision, or every entry followed by the changes
+// if revision is 0. it returns the revision of the last delivered change
+func (s *Store) watchEvents(ctx context.Context, revision int64, onEvent func(event Event)) (int64, error) {
+	if revision == 0 {
+		entries, listRevision, err := s.list(ctx)
+		if err != nil {
+			return 0, err
+		}
+		version := strconv.FormatInt(listRevision, 10)
+		for _, entry := range sortedEntries(entries) {
+			entry := entry
+			onEvent(Event{Type: EventAdded, Entry: &entry, ResourceVersion: version})
+		}
+		revision = listRevision
+	}
+
+	// deletes only carry the key, the previous value is the deleted entry
+	changes := s.client.Watch(clientv3.WithRequireLeader(ctx), s.prefix, clientv3.WithPrefix(), clientv3.WithRev(revision+1), clientv3.WithPrevKV())
+	for resp := range changes {
+		if resp.CompactRevision != 0 {
+			return revision, storage.NewResourceVersionExpiredErr(errors.Errorf("the changes to %v after revision %v were compacted up to revision %v",
+				s.prefix, revision, resp.CompactRevision))
+		}
+		if err := resp.Err(); err != nil {
+			return revision, errors.Wrapf(err, "watching %v", s.prefix)
+		}
+		for _, ev := range resp.Events {
+			entry := s.entryFromKv(ev.Kv)
+			event := Event{Entry: &entry, ResourceVersion: strconv.FormatInt(ev.Kv.ModRevision, 10)}
+			switch {
+			case ev.Type == mvccpb.DELETE:
+				if ev.PrevKv != nil {
+					entry = s.entryFromKv(ev.PrevKv)
+				}
+				event.Type = EventDeleted
+			case ev.IsCreate():
+				event.Type = EventAdded
+			default:
+				event.Type = EventUpdated
+			}
+			onEvent(event)
+			revision = ev.Kv.ModRevision
+		}
+	}
+	return revision, errors.Errorf("watch of %v was closed", s.prefix)
+}
+
+func (s *Store) watch(ctx context.Context, onEvent func(event Event)) error {
+	entries, revision, err := s.list(ctx)
+	if err != nil {
+		return err
+	}
+	onEvent(Event{Type: EventAdded, List: sortedEntries(entries)})
+
+	// watching from the revision after the list guarantees no change is missed or delivered twice
+	changes := s.client.Watch(clientv3.WithRequireLeader(ctx), s.prefix, clientv3.WithPrefix(), clientv3.WithRev(revision+1))
+	for resp := range changes {
+		if err := resp.Err(); err != nil {
+			return errors.Wrapf(err, "watching %v", s.prefix)
+		}
+		for _, ev := range resp.Events {
+			entry := s.entryFromKv(ev.Kv)
+			var eventType storage.EventType
+			switch {
+			case ev.Type == mvccpb.DELETE:
+				previous, ok := entries[entry.Name]
+				if !ok {
+					continue
+				}
+				delete(entries, entry.Name)
+				entry = previous
+				eventType = EventDeleted
+			case ev.IsCreate():
+				entries[entry.Name] = entry
+				eventType = EventAdded
+			default:
+				entries[entry.Name] = entry
+				eventType = EventUpdated
+			}
+			onEvent(Event{Type: eventType, Entry: &entry, List: sortedEntries(entries)})
+		}
+	}
+	return errors.Errorf("watch of %v was closed", s.prefix)
+}
+
+// stopContext returns a context that is cancelled when stop is closed
+func stopContext(stop <-chan struct{}) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-stop:
+		case <-ctx.Done():
+		}
+		cancel()
+	}()
+	return ctx, cancel
+}
+
+// list returns the entries by name, and the revision of the store they were read at
+func (s *Store) list(ctx context.Context) (map[string]Entry, int64, error) {
+	resp, err := s.client.Get(ctx, s.prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "listing %v", s.prefix)
+	}
+	entries := make(map[string]Entry)
+	for _, kv := range resp.Kvs {
+		entry := s.entryFromKv(kv)
+		entries[entry.Name] = entry
+	}
+	return entries, resp.Header.Revision, nil
+}
+
+func (s *Store) key(name string) string {
+	return s.prefix + name
+}
+
+func (s *Store) entry(name string, revision int64, data []byte) Entry {
+	return Entry{
+		Name:            name,
+		ResourceVersion: strconv.FormatInt(revision, 10),
+		Data:            data,
+	}
+}
+
+func (s *Store) entryFromKv(kv *mvccpb.KeyValue) Entry {
+	return s.entry(strings.TrimPrefix(string(kv.Key), s.prefix), kv.ModRevision, kv.Value)
+}
+
+func sortedEntries(entries map[string]Entry) []Entry {
+	list := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		list = append(list, entry)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
@@ -0,0 +1,157 @@
+package etcd_test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/coreos/etcd/clientv3"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo/pkg/storage"
+	. "github.com/solo-io/gloo/pkg/storage/etcd"
+	"github.com/solo-io/gloo/test/helpers"
+)
+
+var _ = Describe("Store", func() {
+	var (
+		rootPath string
+		store    *Store
+	)
+	BeforeEach(func() {
+		rootPath = helpers.RandString(8)
+		store = NewStore(client, rootPath+"/things", "thing")
+	})
+	AfterEach(func() {
+		client.Delete(context.Background(), rootPath+"/", clientv3.WithPrefix())
+	})
+
+	It("uses the revision the key was modified at as the resource version", func() {
+		created, err := store.Create("foo", []byte("a"))
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := client.Get(context.Background(), rootPath+"/things/foo")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Kvs).To(HaveLen(1))
+		Expect(created.ResourceVersion).To(Equal(revision(resp.Kvs[0].ModRevision)))
+
+		_, err = store.Update("foo", "not-a-revision", []byte("b"))
+		Expect(err).To(HaveOccurred())
+		Expect(storage.IsConflict(err)).To(BeFalse())
+	})
+
+	It("does not see keys of other stores sharing its prefix", func() {
+		other := NewStore(client, rootPath+"/things-other", "thing")
+		_, err := other.Create("foo", []byte("a"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(store.List()).To(BeEmpty())
+		_, err = store.Get("foo")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("delivers typed events to watches", func() {
+		var (
+			lock   sync.Mutex
+			events []Event
+		)
+		_, err := store.Create("existing", []byte("a"))
+		Expect(err).NotTo(HaveOccurred())
+		w := store.Watch(func(event Event) {
+			lock.Lock()
+			defer lock.Unlock()
+			events = append(events, event)
+		})
+		stop := make(chan struct{})
+		defer close(stop)
+		go w.Run(stop, make(chan error))
+		received := func() []Event {
+			lock.Lock()
+			defer lock.Unlock()
+			return append([]Event{}, events...)
+		}
+		Eventually(received).Should(HaveLen(1))
+
+		created, err := store.Create("foo", []byte("a"))
+		Expect(err).NotTo(HaveOccurred())
+		updated, err := store.Update("foo", created.ResourceVersion, []byte("b"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(store.Delete("existing")).To(Succeed())
+		Eventually(received).Should(HaveLen(4))
+
+		all := received()
+		Expect(all[0].Type).To(Equal(EventAdded))
+		Expect(all[0].Entry).To(BeNil())
+		Expect(all[0].List).To(HaveLen(1))
+
+		Expect(all[1].Type).To(Equal(EventAdded))
+		Expect(*all[1].Entry).To(Equal(created))
+		Expect(all[1].List).To(HaveLen(2))
+
+		Expect(all[2].Type).To(Equal(EventUpdated))
+		Expect(*all[2].Entry).To(Equal(updated))
+
+		Expect(all[3].Type).To(Equal(EventDeleted))
+		Expect(all[3].Entry.Name).To(Equal("existing"))
+		Expect(all[3].List).To(Equal([]Entry{updated}))
+	})
+
+	It("resumes event watches after a resource version", func() {
+		created, err := store.Create("foo", []byte("a"))
+		Expect(err).NotTo(HaveOccurred())
+		updated, err := store.Update("foo", created.ResourceVersion, []byte("b"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(store.Delete("foo")).To(Succeed())
+
+		var (
+			lock   sync.Mutex
+			events []Event
+		)
+		w := store.WatchEvents(created.ResourceVersion, func(event Event) {
+			lock.Lock()
+			defer lock.Unlock()
+			events = append(events, event)
+		})
+		stop := make(chan struct{})
+		defer close(stop)
+		go w.Run(stop, make(chan error))
+		received := func() []Event {
+			lock.Lock()
+			defer lock.Unlock()
+			return append([]Event{}, events...)
+		}
+		Eventually(received).Should(HaveLen(2))
+		all := received()
+		Expect(all[0].Type).To(Equal(EventUpdated))
+		Expect(*all[0].Entry).To(Equal(updated))
+		Expect(all[0].ResourceVersion).To(Equal(updated.ResourceVersion))
+		// deletes carry the deleted entry, at the revision of the delete
+		Expect(all[1].Type).To(Equal(EventDeleted))
+		Expect(*all[1].Entry).To(Equal(updated))
+		Expect(all[1].ResourceVersion).NotTo(Equal(updated.ResourceVersion))
+	})
+
+	It("fails event watches resumed from a compacted revision", func() {
+		created, err := store.Create("foo", []byte("a"))
+		Expect(err).NotTo(HaveOccurred())
+		updated, err := store.Update("foo", created.ResourceVersion, []byte("b"))
+		Expect(err).NotTo(HaveOccurred())
+		latest, err := strconv.ParseInt(updated.ResourceVersion, 10, 64)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.Compact(context.Background(), latest)
+		Expect(err).NotTo(HaveOccurred())
+
+		w := store.WatchEvents(created.ResourceVersion, func(event Event) {})
+		stop := make(chan struct{})
+		defer close(stop)
+		errs := make(chan error, 1)
+		go w.Run(stop, errs)
+		var watchErr error
+		Eventually(errs).Should(Receive(&watchErr))
+		Expect(storage.IsResourceVersionExpired(watchErr)).To(BeTrue())
+	})
+})
+
+func revision(rev int64) string {
+	return fmt.Sprintf("%v", rev)
+}
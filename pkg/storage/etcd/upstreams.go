@@ -0,0 +1,155 @@
+package etcd
+
+import (
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/log"
+	"github.com/solo-io/gloo/pkg/storage"
+)
+
+type upstreamsClient struct {
+	store *Store
+}
+
+func (c *upstreamsClient) Create(item *v1.Upstream) (*v1.Upstream, error) {
+	if item.Name == "" {
+		return nil, errors.Errorf("name required")
+	}
+	data, err := proto.Marshal(item)
+	if err != nil {
+		return nil, errors.Wrapf(err, "marshalling upstream %v", item.Name)
+	}
+	entry, err := c.store.Create(item.Name, data)
+	if err != nil {
+		return nil, err
+	}
+	return upstreamFromEntry(entry)
+}
+
+func (c *upstreamsClient) Update(item *v1.Upstream) (*v1.Upstream, error) {
+	if item.Name == "" {
+		return nil, errors.Errorf("name required")
+	}
+	if item.Metadata == nil || item.Metadata.ResourceVersion == "" {
+		return nil, errors.New("resource version must be set for update operations")
+	}
+	data, err := proto.Marshal(item)
+	if err != nil {
+		return nil, errors.Wrapf(err, "marshalling upstream %v", item.Name)
+	}
+	entry, err := c.store.Update(item.Name, item.Metadata.ResourceVersion, data)
+	if err != nil {
+		return nil, err
+	}
+	return upstreamFromEntry(entry)
+}
+
+func (c *upstreamsClient) Delete(name string) error {
+	return c.store.Delete(name)
+}
+
+func (c *upstreamsClient) Get(name string) (*v1.Upstream, error) {
+	entry, err := c.store.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return upstreamFromEntry(entry)
+}
+
+func (c *upstreamsClient) List() ([]*v1.Upstream, error) {
+	entries, err := c.store.List()
+	if err != nil {
+		return nil, err
+	}
+	return upstreamsFromEntries(entries)
+}
+
+func (c *upstreamsClient) ListWithOptions(opts storage.ListOptions) ([]*v1.Upstream, error) {
+	list, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	return storage.FilterUpstreams(list, opts), nil
+}
+
+func (c *upstreamsClient) Watch(handlers ...storage.UpstreamEventHandler) (*storage.Watcher, error) {
+	return c.store.Watch(func(event Event) {
+		list, err := upstreamsFromEntries(event.List)
+		if err != nil {
+			log.Warnf("error in upstream watch: %v", err)
+			return
+		}
+		var upstream *v1.Upstream
+		if event.Entry != nil {
+			upstream, err = upstreamFromEntry(*event.Entry)
+			if err != nil {
+				log.Warnf("error in upstream watch: %v", err)
+				return
+			}
+		}
+		for _, h := range handlers {
+			switch event.Type {
+			case EventAdded:
+				h.OnAdd(list, upstream)
+			case EventUpdated:
+				h.OnUpdate(list, upstream)
+			case EventDeleted:
+				h.OnDelete(list, upstream)
+			}
+		}
+	}), nil
+}
+
+func (c *upstreamsClient) WatchWithOptions(opts storage.ListOptions, handlers ...storage.UpstreamEventHandler) (*storage.Watcher, error) {
+	var filtered []storage.UpstreamEventHandler
+	for _, h := range handlers {
+		filtered = append(filtered, storage.FilterUpstreamEventHandler(opts, h))
+	}
+	return c.Watch(filtered...)
+}
+
+func (c *upstreamsClient) WatchEvents(opts storage.WatchOptions, onEvent func(event storage.UpstreamEvent)) (*storage.Watcher, error) {
+	filtered := storage.FilterUpstreamEvents(opts, onEvent)
+	return c.store.WatchEvents(opts.ResourceVersion, func(event Event) {
+		upstream, err := upstreamFromEntry(*event.Entry)
+		if err != nil {
+			log.Warnf("error in upstream watch: %v", err)
+			return
+		}
+		// deletes of entries etcd no longer has the value of only carry the name
+		if upstream.Name == "" {
+			upstream.Name = event.Entry.Name
+		}
+		filtered(storage.UpstreamEvent{
+			Type:            event.Type,
+			Upstream:        upstream,
+			ResourceVersion: event.ResourceVersion,
+		})
+	}), nil
+}
+
+func upstreamFromEntry(entry Entry) (*v1.Upstream, error) {
+	var upstream v1.Upstream
+	if err := proto.Unmarshal(entry.Data, &upstream); err != nil {
+		return nil, errors.Wrapf(err, "unmarshalling upstream %v", entry.Name)
+	}
+	if upstream.Metadata == nil {
+		upstream.Metadata = &v1.Metadata{}
+	}
+	upstream.Metadata.ResourceVersion = entry.ResourceVersion
+	return &upstream, nil
+}
+
+func upstreamsFromEntries(entries []Entry) ([]*v1.Upstream, error) {
+	var upstreams []*v1.Upstream
+	for _, entry := range entries {
+		upstream, err := upstreamFromEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		upstreams = append(upstreams, upstream)
+	}
+	return upstreams, nil
+}
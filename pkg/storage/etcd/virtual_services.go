@@ -0,0 +1,155 @@
+package etcd
+
+import (
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/log"
+	"github.com/solo-io/gloo/pkg/storage"
+)
+
+type virtualServicesClient struct {
+	store *Store
+}
+
+func (c *virtualServicesClient) Create(item *v1.VirtualService) (*v1.VirtualService, error) {
+	if item.Name == "" {
+		return nil, errors.Errorf("name required")
+	}
+	data, err := proto.Marshal(item)
+	if err != nil {
+		return nil, errors.Wrapf(err, "marshalling virtualService %v", item.Name)
+	}
+	entry, err := c.store.Create(item.Name, data)
+	if err != nil {
+		return nil, err
+	}
+	return virtualServiceFromEntry(entry)
+}
+
+func (c *virtualServicesClient) Update(item *v1.VirtualService) (*v1.VirtualService, error) {
+	if item.Name == "" {
+		return nil, errors.Errorf("name required")
+	}
+	if item.Metadata == nil || item.Metadata.ResourceVersion == "" {
+		return nil, errors.New("resource version must be set for update operations")
+	}
+	data, err := proto.Marshal(item)
+	if err != nil {
+		return nil, errors.Wrapf(err, "marshalling virtualService %v", item.Name)
+	}
+	entry, err := c.store.Update(item.Name, item.Metadata.ResourceVersion, data)
+	if err != nil {
+		return nil, err
+	}
+	return virtualServiceFromEntry(entry)
+}
+
+func (c *virtualServicesClient) Delete(name string) error {
+	return c.store.Delete(name)
+}
+
+func (c *virtualServicesClient) Get(name string) (*v1.VirtualService, error) {
+	entry, err := c.store.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return virtualServiceFromEntry(entry)
+}
+
+func (c *virtualServicesClient) List() ([]*v1.VirtualService, error) {
+	entries, err := c.store.List()
+	if err != nil {
+		return nil, err
+	}
+	return virtualServicesFromEntries(entries)
+}
+
+func (c *virtualServicesClient) ListWithOptions(opts storage.ListOptions) ([]*v1.VirtualService, error) {
+	list, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	return storage.FilterVirtualServices(list, opts), nil
+}
+
+func (c *virtualServicesClient) Watch(handlers ...storage.VirtualServiceEventHandler) (*storage.Watcher, error) {
+	return c.store.Watch(func(event Event) {
+		list, err := virtualServicesFromEntries(event.List)
+		if err != nil {
+			log.Warnf("error in virtualService watch: %v", err)
+			return
+		}
+		var virtualService *v1.VirtualService
+		if event.Entry != nil {
+			virtualService, err = virtualServiceFromEntry(*event.Entry)
+			if err != nil {
+				log.Warnf("error in virtualService watch: %v", err)
+				return
+			}
+		}
+		for _, h := range handlers {
+			switch event.Type {
+			case EventAdded:
+				h.OnAdd(list, virtualService)
+			case EventUpdated:
+				h.OnUpdate(list, virtualService)
+			case EventDeleted:
+				h.OnDelete(list, virtualService)
+			}
+		}
+	}), nil
+}
+
+func (c *virtualServicesClient) WatchWithOptions(opts storage.ListOptions, handlers ...storage.VirtualServiceEventHandler) (*storage.Watcher, error) {
+	var filtered []storage.VirtualServiceEventHandler
+	for _, h := range handlers {
+		filtered = append(filtered, storage.FilterVirtualServiceEventHandler(opts, h))
+	}
+	return c.Watch(filtered...)
+}
+
+func (c *virtualServicesClient) WatchEvents(opts storage.WatchOptions, onEvent func(event storage.VirtualServiceEvent)) (*storage.Watcher, error) {
+	filtered := storage.FilterVirtualServiceEvents(opts, onEvent)
+	return c.store.WatchEvents(opts.ResourceVersion, func(event Event) {
+		virtualService, err := virtualServiceFromEntry(*event.Entry)
+		if err != nil {
+			log.Warnf("error in virtualService watch: %v", err)
+			return
+		}
+		// deletes of entries etcd no longer has the value of only carry the name
+		if virtualService.Name == "" {
+			virtualService.Name = event.Entry.Name
+		}
+		filtered(storage.VirtualServiceEvent{
+			Type:            event.Type,
+			VirtualService:  virtualService,
+			ResourceVersion: event.ResourceVersion,
+		})
+	}), nil
+}
+
+func virtualServiceFromEntry(entry Entry) (*v1.VirtualService, error) {
+	var virtualService v1.VirtualService
+	if err := proto.Unmarshal(entry.Data, &virtualService); err != nil {
+		return nil, errors.Wrapf(err, "unmarshalling virtualService %v", entry.Name)
+	}
+	if virtualService.Metadata == nil {
+		virtualService.Metadata = &v1.Metadata{}
+	}
+	virtualService.Metadata.ResourceVersion = entry.ResourceVersion
+	return &virtualService, nil
+}
+
+func virtualServicesFromEntries(entries []Entry) ([]*v1.VirtualService, error) {
+	var virtualServices []*v1.VirtualService
+	for _, entry := range entries {
+		virtualService, err := virtualServiceFromEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		virtualServices = append(virtualServices, virtualService)
+	}
+	return virtualServices, nil
+}
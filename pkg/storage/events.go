@@ -0,0 +1,248 @@
+package storage
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+)
+
+// EventType is the kind of change delivered by event watches
+type EventType int
+
+const (
+	EventAdded EventType = iota
+	EventUpdated
+	EventDeleted
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventAdded:
+		return "added"
+	case EventUpdated:
+		return "updated"
+	case EventDeleted:
+		return "deleted"
+	}
+	return "unknown"
+}
+
+// WatchOptions select the objects delivered by event watches, and the change they start after
+type WatchOptions struct {
+	ListOptions
+	// ResourceVersion resumes a watch after the change at this version, usually the ResourceVersion of the
+	// last event the caller handled. empty starts the watch with an added event for every existing object.
+	// if the backend does not keep the changes since the version, the watch fails with an error
+	// for which IsResourceVersionExpired is true
+	ResourceVersion string
+}
+
+// SendExpiredErr fails a watch that cannot resume from resourceVersion
+func SendExpiredErr(kind, resourceVersion string, stop <-chan struct{}, errs chan error) {
+	err := NewResourceVersionExpiredErr(errors.Errorf("cannot resume watching %v from resource version %v", kind, resourceVersion))
+	select {
+	case errs <- err:
+	case <-stop:
+	}
+}
+
+type change struct {
+	eventType EventType
+	obj       v1.ConfigObject
+}
+
+// listDiff finds the changes between the lists delivered by a watch of lists
+type listDiff struct {
+	lock    sync.Mutex
+	objects map[string]v1.ConfigObject
+}
+
+func newListDiff() *listDiff {
+	return &listDiff{objects: make(map[string]v1.ConfigObject)}
+}
+
+// apply returns the changes since the previous list, sorted by namespace and name
+func (d *listDiff) apply(list []v1.ConfigObject) []change {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	current := make(map[string]v1.ConfigObject)
+	var changes []change
+	for _, obj := range list {
+		key := selectionKey(obj)
+		current[key] = obj
+		previous, ok := d.objects[key]
+		switch {
+		case !ok:
+			changes = append(changes, change{eventType: EventAdded, obj: obj})
+		case !proto.Equal(previous, obj):
+			changes = append(changes, change{eventType: EventUpdated, obj: obj})
+		}
+	}
+	for key, obj := range d.objects {
+		if _, ok := current[key]; !ok {
+			changes = append(changes, change{eventType: EventDeleted, obj: obj})
+		}
+	}
+	d.objects = current
+	sort.SliceStable(changes, func(i, j int) bool {
+		return selectionKey(changes[i].obj) < selectionKey(changes[j].obj)
+	})
+	return changes
+}
+
+// UpstreamEvent is a change to an upstream
+type UpstreamEvent struct {
+	Type EventType
+	// the upstream after the change, or the last version of a deleted upstream
+	Upstream *v1.Upstream
+	// the version to resume the watch from after handling the event.
+	// empty if the backend cannot resume watches
+	ResourceVersion string
+}
+
+// FilterUpstreamEvents wraps onEvent so it only receives the upstreams selected by opts.
+// changes that move an upstream out of the selection are delivered as deletes, and into it as adds
+func FilterUpstreamEvents(opts WatchOptions, onEvent func(event UpstreamEvent)) func(event UpstreamEvent) {
+	selection := newSelection(opts.ListOptions)
+	selection.resumed = opts.ResourceVersion != ""
+	return func(event UpstreamEvent) {
+		eventType, ok := selection.filter(event.Type, event.Upstream)
+		if !ok {
+			return
+		}
+		event.Type = eventType
+		onEvent(event)
+	}
+}
+
+// UpstreamEventsFromLists turns a watch of upstream lists into a watch of events,
+// for backends whose watches only deliver lists. these watches cannot resume
+func UpstreamEventsFromLists(opts WatchOptions, watch func(handlers ...UpstreamEventHandler) (*Watcher, error), onEvent func(event UpstreamEvent)) (*Watcher, error) {
+	if opts.ResourceVersion != "" {
+		return NewWatcher(func(stop <-chan struct{}, errs chan error) {
+			SendExpiredErr("upstreams", opts.ResourceVersion, stop, errs)
+		}), nil
+	}
+	diff := newListDiff()
+	filtered := FilterUpstreamEvents(opts, onEvent)
+	changed := func(list []*v1.Upstream, _ *v1.Upstream) {
+		var objects []v1.ConfigObject
+		for _, us := range list {
+			objects = append(objects, us)
+		}
+		for _, change := range diff.apply(objects) {
+			filtered(UpstreamEvent{Type: change.eventType, Upstream: change.obj.(*v1.Upstream)})
+		}
+	}
+	return watch(&UpstreamEventHandlerFuncs{
+		AddFunc:    changed,
+		UpdateFunc: changed,
+		DeleteFunc: changed,
+	})
+}
+
+// VirtualServiceEvent is a change to a virtual service
+type VirtualServiceEvent struct {
+	Type EventType
+	// the virtual service after the change, or the last version of a deleted virtual service
+	VirtualService *v1.VirtualService
+	// the version to resume the watch from after handling the event.
+	// empty if the backend cannot resume watches
+	ResourceVersion string
+}
+
+// FilterVirtualServiceEvents wraps onEvent so it only receives the virtual services selected by opts.
+// changes that move a virtual service out of the selection are delivered as deletes, and into it as adds
+func FilterVirtualServiceEvents(opts WatchOptions, onEvent func(event VirtualServiceEvent)) func(event VirtualServiceEvent) {
+	selection := newSelection(opts.ListOptions)
+	selection.resumed = opts.ResourceVersion != ""
+	return func(event VirtualServiceEvent) {
+		eventType, ok := selection.filter(event.Type, event.VirtualService)
+		if !ok {
+			return
+		}
+		event.Type = eventType
+		onEvent(event)
+	}
+}
+
+// VirtualServiceEventsFromLists turns a watch of virtual service lists into a watch of events,
+// for backends whose watches only deliver lists. these watches cannot resume
+func VirtualServiceEventsFromLists(opts WatchOptions, watch func(handlers ...VirtualServiceEventHandler) (*Watcher, error), onEvent func(event VirtualServiceEvent)) (*Watcher, error) {
+	if opts.ResourceVersion != "" {
+		return NewWatcher(func(stop <-chan struct{}, errs chan error) {
+			SendExpiredErr("virtual services", opts.ResourceVersion, stop, errs)
+		}), nil
+	}
+	diff := newListDiff()
+	filtered := FilterVirtualServiceEvents(opts, onEvent)
+	changed := func(list []*v1.VirtualService, _ *v1.VirtualService) {
+		var objects []v1.ConfigObject
+		for _, vs := range list {
+			objects = append(objects, vs)
+		}
+		for _, change := range diff.apply(objects) {
+			filtered(VirtualServiceEvent{Type: change.eventType, VirtualService: change.obj.(*v1.VirtualService)})
+		}
+	}
+	return watch(&VirtualServiceEventHandlerFuncs{
+		AddFunc:    changed,
+		UpdateFunc: changed,
+		DeleteFunc: changed,
+	})
+}
+
+// RoleEvent is a change to a role
+type RoleEvent struct {
+	Type EventType
+	// the role after the change, or the last version of a deleted role
+	Role *v1.Role
+	// the version to resume the watch from after handling the event.
+	// empty if the backend cannot resume watches
+	ResourceVersion string
+}
+
+// FilterRoleEvents wraps onEvent so it only receives the roles selected by opts.
+// changes that move a role out of the selection are delivered as deletes, and into it as adds
+func FilterRoleEvents(opts WatchOptions, onEvent func(event RoleEvent)) func(event RoleEvent) {
+	selection := newSelection(opts.ListOptions)
+	selection.resumed = opts.ResourceVersion != ""
+	return func(event RoleEvent) {
+		eventType, ok := selection.filter(event.Type, event.Role)
+		if !ok {
+			return
+		}
+		event.Type = eventType
+		onEvent(event)
+	}
+}
+
+// RoleEventsFromLists turns a watch of role lists into a watch of events,
+// for backends whose watches only deliver lists. these watches cannot resume
+func RoleEventsFromLists(opts WatchOptions, watch func(handlers ...RoleEventHandler) (*Watcher, error), onEvent func(event RoleEvent)) (*Watcher, error) {
+	if opts.ResourceVersion != "" {
+		return NewWatcher(func(stop <-chan struct{}, errs chan error) {
+			SendExpiredErr("roles", opts.ResourceVersion, stop, errs)
+		}), nil
+	}
+	diff := newListDiff()
+	filtered := FilterRoleEvents(opts, onEvent)
+	changed := func(list []*v1.Role, _ *v1.Role) {
+		var objects []v1.ConfigObject
+		for _, role := range list {
+			objects = append(objects, role)
+		}
+		for _, change := range diff.apply(objects) {
+			filtered(RoleEvent{Type: change.eventType, Role: change.obj.(*v1.Role)})
+		}
+	}
+	return watch(&RoleEventHandlerFuncs{
+		AddFunc:    changed,
+		UpdateFunc: changed,
+		DeleteFunc: changed,
+	})
+}
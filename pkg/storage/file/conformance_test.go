@@ -0,0 +1,33 @@
+package file_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo/pkg/storage"
+	. "github.com/solo-io/gloo/pkg/storage/file"
+	"github.com/solo-io/gloo/pkg/storage/storagetest"
+)
+
+// each namespace is a subdirectory of a temp dir
+type fileBackend struct {
+	dir string
+}
+
+func (b *fileBackend) NewStorage(namespace string) (storage.Interface, error) {
+	return NewStorage(filepath.Join(b.dir, namespace), 100*time.Millisecond)
+}
+
+func (b *fileBackend) Cleanup() {
+	os.RemoveAll(b.dir)
+}
+
+var _ = storagetest.Conformance("file", func() storagetest.Backend {
+	dir, err := ioutil.TempDir("", "file-conformance")
+	Expect(err).NotTo(HaveOccurred())
+	return &fileBackend{dir: dir}
+})
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/solo-io/gloo/pkg/api/types/v1"
 	"github.com/solo-io/gloo/pkg/storage"
 )
 
@@ -77,3 +78,8 @@ func (c *v1client) VirtualServices() storage.VirtualServices {
 func (c *v1client) Roles() storage.Roles {
 	return c.roles
 }
+
+// files can only be written one at a time, so the changes are applied in order
+func (c *v1client) Apply(config *v1.Config) error {
+	return storage.ApplyInOrder(c, config)
+}
@@ -6,9 +6,12 @@ import (
 
 	"time"
 
+	"github.com/gogo/protobuf/proto"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/solo-io/gloo/pkg/api/types/v1"
 	"github.com/solo-io/gloo/pkg/log"
+	"github.com/solo-io/gloo/pkg/storage"
 	. "github.com/solo-io/gloo/pkg/storage/file"
 	. "github.com/solo-io/gloo/test/helpers"
 )
@@ -159,6 +162,69 @@ var _ = Describe("CrdStorageClient", func() {
 			upstream.Metadata = updated.GetMetadata()
 			Expect(updated).To(Equal(upstream))
 		})
+		It("increments the resource version on every update", func() {
+			client, err := NewStorage(dir, resync)
+			Expect(err).NotTo(HaveOccurred())
+			err = client.V1().Register()
+			Expect(err).NotTo(HaveOccurred())
+			upstream, err := client.V1().Upstreams().Create(NewTestUpstream1())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(upstream.Metadata.ResourceVersion).To(Equal("1"))
+			for _, expected := range []string{"2", "3", "4", "5", "6", "7", "8", "9", "10", "11"} {
+				upstream, err = client.V1().Upstreams().Update(upstream)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(upstream.Metadata.ResourceVersion).To(Equal(expected))
+			}
+			stored, err := client.V1().Upstreams().Get(upstream.Name)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored.Metadata.ResourceVersion).To(Equal("11"))
+		})
+		It("returns a conflict error for an outdated resource version", func() {
+			client, err := NewStorage(dir, resync)
+			Expect(err).NotTo(HaveOccurred())
+			err = client.V1().Register()
+			Expect(err).NotTo(HaveOccurred())
+			created, err := client.V1().Upstreams().Create(NewTestUpstream1())
+			Expect(err).NotTo(HaveOccurred())
+			_, err = client.V1().Upstreams().Update(created)
+			Expect(err).NotTo(HaveOccurred())
+			created.Type = "something-else"
+			_, err = client.V1().Upstreams().Update(created)
+			Expect(err).To(HaveOccurred())
+			Expect(storage.IsConflict(err)).To(BeTrue())
+			stored, err := client.V1().Upstreams().Get(created.Name)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored.Type).NotTo(Equal("something-else"))
+		})
+		It("lets exactly one of several concurrent writers of the same version succeed", func() {
+			client, err := NewStorage(dir, resync)
+			Expect(err).NotTo(HaveOccurred())
+			err = client.V1().Register()
+			Expect(err).NotTo(HaveOccurred())
+			created, err := client.V1().Upstreams().Create(NewTestUpstream1())
+			Expect(err).NotTo(HaveOccurred())
+			const writers = 10
+			results := make(chan error, writers)
+			for i := 0; i < writers; i++ {
+				go func() {
+					defer GinkgoRecover()
+					_, err := client.V1().Upstreams().Update(proto.Clone(created).(*v1.Upstream))
+					results <- err
+				}()
+			}
+			var succeeded, conflicts int
+			for i := 0; i < writers; i++ {
+				err := <-results
+				switch {
+				case err == nil:
+					succeeded++
+				case storage.IsConflict(err):
+					conflicts++
+				}
+			}
+			Expect(succeeded).To(Equal(1))
+			Expect(conflicts).To(Equal(writers - 1))
+		})
 	})
 	Describe("Delete", func() {
 		It("deletes a file from the name", func() {
@@ -1,18 +1,86 @@
 package file
 
 import (
-	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// resource versions in the file backend come from one counter per directory, which is
+// only advanced while the directory is locked. an object that is deleted and created
+// again never gets a version it had before, so updates based on the old object fail.
+// objects written by hand without a resource version are at the initial version
+const (
+	initialResourceVer = "1"
+	resourceVerFile    = ".resource_version"
 )
 
-func newOrIncrementResourceVer(resourceVersion string) string {
-	curr, err := strconv.Atoi(resourceVersion)
+// nextResourceVer advances the counter of dir, and returns a version that is newer than
+// both the counter and current, the version of the object being updated.
+// the directory must be locked
+func nextResourceVer(dir, current string) (string, error) {
+	var counter uint64
+	data, err := ioutil.ReadFile(filepath.Join(dir, resourceVerFile))
+	switch {
+	case os.IsNotExist(err):
+		// objects written before the counter was kept may be at any version
+	case err != nil:
+		return "", errors.Wrapf(err, "failed to read the resource version of %v", dir)
+	default:
+		counter, err = strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+		if err != nil {
+			return "", errors.Wrapf(err, "invalid resource version in %v", filepath.Join(dir, resourceVerFile))
+		}
+	}
+	if curr, err := strconv.ParseUint(current, 10, 64); err == nil && curr > counter {
+		counter = curr
+	}
+	next := strconv.FormatUint(counter+1, 10)
+	if err := writeResourceVer(dir, next); err != nil {
+		return "", err
+	}
+	return next, nil
+}
+
+// the counter is replaced with a rename, so it is never left half written
+func writeResourceVer(dir, resourceVersion string) error {
+	tmp, err := ioutil.TempFile(dir, resourceVerFile+".")
 	if err != nil {
-		curr = 1
+		return errors.Wrapf(err, "failed to write the resource version of %v", dir)
 	}
-	return fmt.Sprintf("%v", curr)
+	_, err = tmp.WriteString(resourceVersion + "\n")
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(dir, resourceVerFile))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return errors.Wrapf(err, "failed to write the resource version of %v", dir)
+	}
+	return nil
 }
 
-func lessThan(rv1, rv2 string) bool {
-	return newOrIncrementResourceVer(rv1) < newOrIncrementResourceVer(rv2)
+// lockDir takes an exclusive lock on the directory of a resource type, so that
+// checking the resource version and writing an object is atomic, including across
+// processes sharing the directory. the returned func releases the lock
+func lockDir(dir string) (func(), error) {
+	f, err := os.Open(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open %v", dir)
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, errors.Wrapf(err, "failed to lock %v", dir)
+	}
+	return func() {
+		syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
 }
@@ -28,6 +28,11 @@ func (c *rolesClient) Create(item *v1.Role) (*v1.Role, error) {
 	if item.Name == "" {
 		return nil, errors.Errorf("name required")
 	}
+	unlock, err := lockDir(c.dir)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 	// set resourceversion on clone
 	roleClone, ok := proto.Clone(item).(*v1.Role)
 	if !ok {
@@ -36,7 +41,6 @@ func (c *rolesClient) Create(item *v1.Role) (*v1.Role, error) {
 	if roleClone.Metadata == nil {
 		roleClone.Metadata = &v1.Metadata{}
 	}
-	roleClone.Metadata.ResourceVersion = newOrIncrementResourceVer(roleClone.Metadata.ResourceVersion)
 	roleFiles, err := c.pathsToRoles()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to read role dir")
@@ -47,6 +51,11 @@ func (c *rolesClient) Create(item *v1.Role) (*v1.Role, error) {
 			return nil, storage.NewAlreadyExistsErr(errors.Errorf("role %v already defined in %s", item.Name, file))
 		}
 	}
+	resourceVersion, err := nextResourceVer(c.dir, "")
+	if err != nil {
+		return nil, err
+	}
+	roleClone.Metadata.ResourceVersion = resourceVersion
 	filename := filepath.Join(c.dir, item.Name+".yml")
 	err = WriteToFile(filename, roleClone)
 	if err != nil {
@@ -62,6 +71,11 @@ func (c *rolesClient) Update(item *v1.Role) (*v1.Role, error) {
 	if item.Metadata == nil || item.Metadata.ResourceVersion == "" {
 		return nil, errors.New("resource version must be set for update operations")
 	}
+	unlock, err := lockDir(c.dir)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 	roleFiles, err := c.pathsToRoles()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to read role dir")
@@ -71,14 +85,20 @@ func (c *rolesClient) Update(item *v1.Role) (*v1.Role, error) {
 		if existingUps.Name != item.Name {
 			continue
 		}
-		if existingUps.Metadata != nil && lessThan(item.Metadata.ResourceVersion, existingUps.Metadata.ResourceVersion) {
-			return nil, errors.Errorf("resource version outdated for %v", item.Name)
+		// only the latest version of the object may be updated
+		if item.Metadata.ResourceVersion != existingUps.Metadata.ResourceVersion {
+			return nil, storage.NewConflictErr(errors.Errorf("resource version %v of %v is outdated, the current version is %v",
+				item.Metadata.ResourceVersion, item.Name, existingUps.Metadata.ResourceVersion))
 		}
 		roleClone, ok := proto.Clone(item).(*v1.Role)
 		if !ok {
 			return nil, errors.New("internal error: output of proto.Clone was not expected type")
 		}
-		roleClone.Metadata.ResourceVersion = newOrIncrementResourceVer(roleClone.Metadata.ResourceVersion)
+		resourceVersion, err := nextResourceVer(c.dir, existingUps.Metadata.ResourceVersion)
+		if err != nil {
+			return nil, err
+		}
+		roleClone.Metadata.ResourceVersion = resourceVersion
 
 		err = WriteToFile(file, roleClone)
 		if err != nil {
@@ -91,6 +111,11 @@ func (c *rolesClient) Update(item *v1.Role) (*v1.Role, error) {
 }
 
 func (c *rolesClient) Delete(name string) error {
+	unlock, err := lockDir(c.dir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	roleFiles, err := c.pathsToRoles()
 	if err != nil {
 		return errors.Wrap(err, "failed to read role dir")
@@ -130,6 +155,14 @@ func (c *rolesClient) List() ([]*v1.Role, error) {
 	return roles, nil
 }
 
+func (c *rolesClient) ListWithOptions(opts storage.ListOptions) ([]*v1.Role, error) {
+	list, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	return storage.FilterRoles(list, opts), nil
+}
+
 func (c *rolesClient) pathsToRoles() (map[string]*v1.Role, error) {
 	files, err := ioutil.ReadDir(c.dir)
 	if err != nil {
@@ -162,7 +195,7 @@ func pathToRole(path string) (*v1.Role, error) {
 		role.Metadata = &v1.Metadata{}
 	}
 	if role.Metadata.ResourceVersion == "" {
-		role.Metadata.ResourceVersion = "1"
+		role.Metadata.ResourceVersion = initialResourceVer
 	}
 	return &role, nil
 }
@@ -210,8 +243,25 @@ func (u *rolesClient) Watch(handlers ...storage.RoleEventHandler) (*storage.Watc
 	}), nil
 }
 
+func (c *rolesClient) WatchWithOptions(opts storage.ListOptions, handlers ...storage.RoleEventHandler) (*storage.Watcher, error) {
+	var filtered []storage.RoleEventHandler
+	for _, h := range handlers {
+		filtered = append(filtered, storage.FilterRoleEventHandler(opts, h))
+	}
+	return c.Watch(filtered...)
+}
+
+// the watches of this backend only deliver lists, so they cannot resume from a resource version
+func (c *rolesClient) WatchEvents(opts storage.WatchOptions, onEvent func(event storage.RoleEvent)) (*storage.Watcher, error) {
+	return storage.RoleEventsFromLists(opts, c.Watch, onEvent)
+}
+
 func (u *rolesClient) onEvent(event watcher.Event, handlers ...storage.RoleEventHandler) error {
 	log.Debugf("file event: %v [%v]", event.Path, event.Op)
+	// hidden files hold the resource version counter, not objects
+	if strings.HasPrefix(filepath.Base(event.Path), ".") {
+		return nil
+	}
 	current, err := u.List()
 	if err != nil {
 		return err
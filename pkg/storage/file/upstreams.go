@@ -28,6 +28,11 @@ func (c *upstreamsClient) Create(item *v1.Upstream) (*v1.Upstream, error) {
 	if item.Name == "" {
 		return nil, errors.Errorf("name required")
 	}
+	unlock, err := lockDir(c.dir)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 	// set resourceversion on clone
 	upstreamClone, ok := proto.Clone(item).(*v1.Upstream)
 	if !ok {
@@ -36,7 +41,6 @@ func (c *upstreamsClient) Create(item *v1.Upstream) (*v1.Upstream, error) {
 	if upstreamClone.Metadata == nil {
 		upstreamClone.Metadata = &v1.Metadata{}
 	}
-	upstreamClone.Metadata.ResourceVersion = newOrIncrementResourceVer(upstreamClone.Metadata.ResourceVersion)
 	upstreamFiles, err := c.pathsToUpstreams()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to read upstream dir")
@@ -47,6 +51,11 @@ func (c *upstreamsClient) Create(item *v1.Upstream) (*v1.Upstream, error) {
 			return nil, storage.NewAlreadyExistsErr(errors.Errorf("upstream %v already defined in %s", item.Name, file))
 		}
 	}
+	resourceVersion, err := nextResourceVer(c.dir, "")
+	if err != nil {
+		return nil, err
+	}
+	upstreamClone.Metadata.ResourceVersion = resourceVersion
 	filename := filepath.Join(c.dir, item.Name+".yml")
 	err = WriteToFile(filename, upstreamClone)
 	if err != nil {
@@ -62,6 +71,11 @@ func (c *upstreamsClient) Update(item *v1.Upstream) (*v1.Upstream, error) {
 	if item.Metadata == nil || item.Metadata.ResourceVersion == "" {
 		return nil, errors.New("resource version must be set for update operations")
 	}
+	unlock, err := lockDir(c.dir)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 	upstreamFiles, err := c.pathsToUpstreams()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to read upstream dir")
@@ -71,14 +85,20 @@ func (c *upstreamsClient) Update(item *v1.Upstream) (*v1.Upstream, error) {
 		if existingUps.Name != item.Name {
 			continue
 		}
-		if existingUps.Metadata != nil && lessThan(item.Metadata.ResourceVersion, existingUps.Metadata.ResourceVersion) {
-			return nil, errors.Errorf("resource version outdated for %v", item.Name)
+		// only the latest version of the object may be updated
+		if item.Metadata.ResourceVersion != existingUps.Metadata.ResourceVersion {
+			return nil, storage.NewConflictErr(errors.Errorf("resource version %v of %v is outdated, the current version is %v",
+				item.Metadata.ResourceVersion, item.Name, existingUps.Metadata.ResourceVersion))
 		}
 		upstreamClone, ok := proto.Clone(item).(*v1.Upstream)
 		if !ok {
 			return nil, errors.New("internal error: output of proto.Clone was not expected type")
 		}
-		upstreamClone.Metadata.ResourceVersion = newOrIncrementResourceVer(upstreamClone.Metadata.ResourceVersion)
+		resourceVersion, err := nextResourceVer(c.dir, existingUps.Metadata.ResourceVersion)
+		if err != nil {
+			return nil, err
+		}
+		upstreamClone.Metadata.ResourceVersion = resourceVersion
 
 		err = WriteToFile(file, upstreamClone)
 		if err != nil {
@@ -91,6 +111,11 @@ func (c *upstreamsClient) Update(item *v1.Upstream) (*v1.Upstream, error) {
 }
 
 func (c *upstreamsClient) Delete(name string) error {
+	unlock, err := lockDir(c.dir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	upstreamFiles, err := c.pathsToUpstreams()
 	if err != nil {
 		return errors.Wrap(err, "failed to read upstream dir")
@@ -130,6 +155,14 @@ func (c *upstreamsClient) List() ([]*v1.Upstream, error) {
 	return upstreams, nil
 }
 
+func (c *upstreamsClient) ListWithOptions(opts storage.ListOptions) ([]*v1.Upstream, error) {
+	list, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	return storage.FilterUpstreams(list, opts), nil
+}
+
 func (c *upstreamsClient) pathsToUpstreams() (map[string]*v1.Upstream, error) {
 	files, err := ioutil.ReadDir(c.dir)
 	if err != nil {
@@ -162,7 +195,7 @@ func pathToUpstream(path string) (*v1.Upstream, error) {
 		upstream.Metadata = &v1.Metadata{}
 	}
 	if upstream.Metadata.ResourceVersion == "" {
-		upstream.Metadata.ResourceVersion = "1"
+		upstream.Metadata.ResourceVersion = initialResourceVer
 	}
 	return &upstream, nil
 }
@@ -210,8 +243,25 @@ func (u *upstreamsClient) Watch(handlers ...storage.UpstreamEventHandler) (*stor
 	}), nil
 }
 
+func (c *upstreamsClient) WatchWithOptions(opts storage.ListOptions, handlers ...storage.UpstreamEventHandler) (*storage.Watcher, error) {
+	var filtered []storage.UpstreamEventHandler
+	for _, h := range handlers {
+		filtered = append(filtered, storage.FilterUpstreamEventHandler(opts, h))
+	}
+	return c.Watch(filtered...)
+}
+
+// the watches of this backend only deliver lists, so they cannot resume from a resource version
+func (c *upstreamsClient) WatchEvents(opts storage.WatchOptions, onEvent func(event storage.UpstreamEvent)) (*storage.Watcher, error) {
+	return storage.UpstreamEventsFromLists(opts, c.Watch, onEvent)
+}
+
 func (u *upstreamsClient) onEvent(event watcher.Event, handlers ...storage.UpstreamEventHandler) error {
 	log.Debugf("file event: %v [%v]", event.Path, event.Op)
+	// hidden files hold the resource version counter, not objects
+	if strings.HasPrefix(filepath.Base(event.Path), ".") {
+		return nil
+	}
 	current, err := u.List()
 	if err != nil {
 		return err
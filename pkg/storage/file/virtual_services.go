@@ -28,6 +28,11 @@ func (c *virtualServicesClient) Create(item *v1.VirtualService) (*v1.VirtualServ
 	if item.Name == "" {
 		return nil, errors.Errorf("name required")
 	}
+	unlock, err := lockDir(c.dir)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 	// set resourceversion on clone
 	virtualServiceClone, ok := proto.Clone(item).(*v1.VirtualService)
 	if !ok {
@@ -36,7 +41,6 @@ func (c *virtualServicesClient) Create(item *v1.VirtualService) (*v1.VirtualServ
 	if virtualServiceClone.Metadata == nil {
 		virtualServiceClone.Metadata = &v1.Metadata{}
 	}
-	virtualServiceClone.Metadata.ResourceVersion = newOrIncrementResourceVer(virtualServiceClone.Metadata.ResourceVersion)
 	virtualServiceFiles, err := c.pathsToVirtualServices()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to read virtualService dir")
@@ -47,6 +51,11 @@ func (c *virtualServicesClient) Create(item *v1.VirtualService) (*v1.VirtualServ
 			return nil, storage.NewAlreadyExistsErr(errors.Errorf("virtualService %v already defined in %s", item.Name, file))
 		}
 	}
+	resourceVersion, err := nextResourceVer(c.dir, "")
+	if err != nil {
+		return nil, err
+	}
+	virtualServiceClone.Metadata.ResourceVersion = resourceVersion
 	filename := filepath.Join(c.dir, item.Name+".yml")
 	err = WriteToFile(filename, virtualServiceClone)
 	if err != nil {
@@ -62,6 +71,11 @@ func (c *virtualServicesClient) Update(item *v1.VirtualService) (*v1.VirtualServ
 	if item.Metadata == nil || item.Metadata.ResourceVersion == "" {
 		return nil, errors.New("resource version must be set for update operations")
 	}
+	unlock, err := lockDir(c.dir)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 	virtualServiceFiles, err := c.pathsToVirtualServices()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to read virtualService dir")
@@ -71,14 +85,20 @@ func (c *virtualServicesClient) Update(item *v1.VirtualService) (*v1.VirtualServ
 		if existingUps.Name != item.Name {
 			continue
 		}
-		if existingUps.Metadata != nil && lessThan(item.Metadata.ResourceVersion, existingUps.Metadata.ResourceVersion) {
-			return nil, errors.Errorf("resource version outdated for %v", item.Name)
+		// only the latest version of the object may be updated
+		if item.Metadata.ResourceVersion != existingUps.Metadata.ResourceVersion {
+			return nil, storage.NewConflictErr(errors.Errorf("resource version %v of %v is outdated, the current version is %v",
+				item.Metadata.ResourceVersion, item.Name, existingUps.Metadata.ResourceVersion))
 		}
 		virtualServiceClone, ok := proto.Clone(item).(*v1.VirtualService)
 		if !ok {
 			return nil, errors.New("internal error: output of proto.Clone was not expected type")
 		}
-		virtualServiceClone.Metadata.ResourceVersion = newOrIncrementResourceVer(virtualServiceClone.Metadata.ResourceVersion)
+		resourceVersion, err := nextResourceVer(c.dir, existingUps.Metadata.ResourceVersion)
+		if err != nil {
+			return nil, err
+		}
+		virtualServiceClone.Metadata.ResourceVersion = resourceVersion
 
 		err = WriteToFile(file, virtualServiceClone)
 		if err != nil {
@@ -91,6 +111,11 @@ func (c *virtualServicesClient) Update(item *v1.VirtualService) (*v1.VirtualServ
 }
 
 func (c *virtualServicesClient) Delete(name string) error {
+	unlock, err := lockDir(c.dir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	virtualServiceFiles, err := c.pathsToVirtualServices()
 	if err != nil {
 		return errors.Wrap(err, "failed to read virtualService dir")
@@ -130,6 +155,14 @@ func (c *virtualServicesClient) List() ([]*v1.VirtualService, error) {
 	return virtualServices, nil
 }
 
+func (c *virtualServicesClient) ListWithOptions(opts storage.ListOptions) ([]*v1.VirtualService, error) {
+	list, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	return storage.FilterVirtualServices(list, opts), nil
+}
+
 func (c *virtualServicesClient) pathsToVirtualServices() (map[string]*v1.VirtualService, error) {
 	files, err := ioutil.ReadDir(c.dir)
 	if err != nil {
@@ -162,7 +195,7 @@ func pathToVirtualService(path string) (*v1.VirtualService, error) {
 		virtualService.Metadata = &v1.Metadata{}
 	}
 	if virtualService.Metadata.ResourceVersion == "" {
-		virtualService.Metadata.ResourceVersion = "1"
+		virtualService.Metadata.ResourceVersion = initialResourceVer
 	}
 	return &virtualService, nil
 }
@@ -210,8 +243,25 @@ func (u *virtualServicesClient) Watch(handlers ...storage.VirtualServiceEventHan
 	}), nil
 }
 
+func (c *virtualServicesClient) WatchWithOptions(opts storage.ListOptions, handlers ...storage.VirtualServiceEventHandler) (*storage.Watcher, error) {
+	var filtered []storage.VirtualServiceEventHandler
+	for _, h := range handlers {
+		filtered = append(filtered, storage.FilterVirtualServiceEventHandler(opts, h))
+	}
+	return c.Watch(filtered...)
+}
+
+// the watches of this backend only deliver lists, so they cannot resume from a resource version
+func (c *virtualServicesClient) WatchEvents(opts storage.WatchOptions, onEvent func(event storage.VirtualServiceEvent)) (*storage.Watcher, error) {
+	return storage.VirtualServiceEventsFromLists(opts, c.Watch, onEvent)
+}
+
 func (u *virtualServicesClient) onEvent(event watcher.Event, handlers ...storage.VirtualServiceEventHandler) error {
 	log.Debugf("file event: %v [%v]", event.Path, event.Op)
+	// hidden files hold the resource version counter, not objects
+	if strings.HasPrefix(filepath.Base(event.Path), ".") {
+		return nil
+	}
 	current, err := u.List()
 	if err != nil {
 		return err
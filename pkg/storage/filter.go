@@ -0,0 +1,235 @@
+package storage
+
+import (
+	"sync"
+
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+)
+
+// selection tracks the objects a filtered handler has delivered, so changes that move an object
+// out of the selection are delivered as deletes, and changes that move it in as adds
+type selection struct {
+	opts     ListOptions
+	lock     sync.Mutex
+	selected map[string]bool
+	// set for watches resumed from a resource version. the caller may have selected objects before
+	// the watch resumed, so changes that move an object it has not seen out of the selection are delivered as deletes
+	resumed bool
+	seen    map[string]bool
+}
+
+func newSelection(opts ListOptions) *selection {
+	return &selection{opts: opts, selected: make(map[string]bool), seen: make(map[string]bool)}
+}
+
+// filter returns the event to deliver for a change to obj, or false if the change is not delivered
+func (s *selection) filter(event EventType, obj v1.ConfigObject) (EventType, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	key := selectionKey(obj)
+	was := s.selected[key]
+	if s.resumed && !s.seen[key] {
+		was = event != EventAdded
+		s.seen[key] = true
+	}
+	is := event != EventDeleted && s.opts.Matches(obj.GetMetadata())
+	if is {
+		s.selected[key] = true
+	} else {
+		delete(s.selected, key)
+	}
+	switch {
+	case was && is:
+		return event, true
+	case is:
+		return EventAdded, true
+	case was:
+		return EventDeleted, true
+	}
+	return event, false
+}
+
+// reset replaces the selected objects, for events that only carry the updated list
+func (s *selection) reset(list []v1.ConfigObject) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.selected = make(map[string]bool)
+	for _, obj := range list {
+		s.selected[selectionKey(obj)] = true
+	}
+}
+
+func selectionKey(obj v1.ConfigObject) string {
+	return obj.GetMetadata().GetNamespace() + "/" + obj.GetName()
+}
+
+// FilterUpstreams returns the upstreams selected by opts
+func FilterUpstreams(list []*v1.Upstream, opts ListOptions) []*v1.Upstream {
+	var selected []*v1.Upstream
+	for _, obj := range list {
+		if opts.Matches(obj.GetMetadata()) {
+			selected = append(selected, obj)
+		}
+	}
+	return selected
+}
+
+// FilterUpstreamEventHandler wraps handler so it only receives the upstreams selected by opts
+func FilterUpstreamEventHandler(opts ListOptions, handler UpstreamEventHandler) UpstreamEventHandler {
+	return &upstreamFilter{selection: newSelection(opts), handler: handler}
+}
+
+type upstreamFilter struct {
+	selection *selection
+	handler   UpstreamEventHandler
+}
+
+func (f *upstreamFilter) OnAdd(updatedList []*v1.Upstream, obj *v1.Upstream) {
+	f.deliver(EventAdded, updatedList, obj)
+}
+
+func (f *upstreamFilter) OnUpdate(updatedList []*v1.Upstream, newObj *v1.Upstream) {
+	f.deliver(EventUpdated, updatedList, newObj)
+}
+
+func (f *upstreamFilter) OnDelete(updatedList []*v1.Upstream, obj *v1.Upstream) {
+	f.deliver(EventDeleted, updatedList, obj)
+}
+
+func (f *upstreamFilter) deliver(event EventType, updatedList []*v1.Upstream, obj *v1.Upstream) {
+	selected := FilterUpstreams(updatedList, f.selection.opts)
+	if obj == nil {
+		var objects []v1.ConfigObject
+		for _, us := range selected {
+			objects = append(objects, us)
+		}
+		f.selection.reset(objects)
+	} else {
+		var ok bool
+		if event, ok = f.selection.filter(event, obj); !ok {
+			return
+		}
+	}
+	switch event {
+	case EventAdded:
+		f.handler.OnAdd(selected, obj)
+	case EventUpdated:
+		f.handler.OnUpdate(selected, obj)
+	case EventDeleted:
+		f.handler.OnDelete(selected, obj)
+	}
+}
+
+// FilterVirtualServices returns the virtual services selected by opts
+func FilterVirtualServices(list []*v1.VirtualService, opts ListOptions) []*v1.VirtualService {
+	var selected []*v1.VirtualService
+	for _, obj := range list {
+		if opts.Matches(obj.GetMetadata()) {
+			selected = append(selected, obj)
+		}
+	}
+	return selected
+}
+
+// FilterVirtualServiceEventHandler wraps handler so it only receives the virtual services selected by opts
+func FilterVirtualServiceEventHandler(opts ListOptions, handler VirtualServiceEventHandler) VirtualServiceEventHandler {
+	return &virtualServiceFilter{selection: newSelection(opts), handler: handler}
+}
+
+type virtualServiceFilter struct {
+	selection *selection
+	handler   VirtualServiceEventHandler
+}
+
+func (f *virtualServiceFilter) OnAdd(updatedList []*v1.VirtualService, obj *v1.VirtualService) {
+	f.deliver(EventAdded, updatedList, obj)
+}
+
+func (f *virtualServiceFilter) OnUpdate(updatedList []*v1.VirtualService, newObj *v1.VirtualService) {
+	f.deliver(EventUpdated, updatedList, newObj)
+}
+
+func (f *virtualServiceFilter) OnDelete(updatedList []*v1.VirtualService, obj *v1.VirtualService) {
+	f.deliver(EventDeleted, updatedList, obj)
+}
+
+func (f *virtualServiceFilter) deliver(event EventType, updatedList []*v1.VirtualService, obj *v1.VirtualService) {
+	selected := FilterVirtualServices(updatedList, f.selection.opts)
+	if obj == nil {
+		var objects []v1.ConfigObject
+		for _, vs := range selected {
+			objects = append(objects, vs)
+		}
+		f.selection.reset(objects)
+	} else {
+		var ok bool
+		if event, ok = f.selection.filter(event, obj); !ok {
+			return
+		}
+	}
+	switch event {
+	case EventAdded:
+		f.handler.OnAdd(selected, obj)
+	case EventUpdated:
+		f.handler.OnUpdate(selected, obj)
+	case EventDeleted:
+		f.handler.OnDelete(selected, obj)
+	}
+}
+
+// FilterRoles returns the roles selected by opts
+func FilterRoles(list []*v1.Role, opts ListOptions) []*v1.Role {
+	var selected []*v1.Role
+	for _, obj := range list {
+		if opts.Matches(obj.GetMetadata()) {
+			selected = append(selected, obj)
+		}
+	}
+	return selected
+}
+
+// FilterRoleEventHandler wraps handler so it only receives the roles selected by opts
+func FilterRoleEventHandler(opts ListOptions, handler RoleEventHandler) RoleEventHandler {
+	return &roleFilter{selection: newSelection(opts), handler: handler}
+}
+
+type roleFilter struct {
+	selection *selection
+	handler   RoleEventHandler
+}
+
+func (f *roleFilter) OnAdd(updatedList []*v1.Role, obj *v1.Role) {
+	f.deliver(EventAdded, updatedList, obj)
+}
+
+func (f *roleFilter) OnUpdate(updatedList []*v1.Role, newObj *v1.Role) {
+	f.deliver(EventUpdated, updatedList, newObj)
+}
+
+func (f *roleFilter) OnDelete(updatedList []*v1.Role, obj *v1.Role) {
+	f.deliver(EventDeleted, updatedList, obj)
+}
+
+func (f *roleFilter) deliver(event EventType, updatedList []*v1.Role, obj *v1.Role) {
+	selected := FilterRoles(updatedList, f.selection.opts)
+	if obj == nil {
+		var objects []v1.ConfigObject
+		for _, role := range selected {
+			objects = append(objects, role)
+		}
+		f.selection.reset(objects)
+	} else {
+		var ok bool
+		if event, ok = f.selection.filter(event, obj); !ok {
+			return
+		}
+	}
+	switch event {
+	case EventAdded:
+		f.handler.OnAdd(selected, obj)
+	case EventUpdated:
+		f.handler.OnUpdate(selected, obj)
+	case EventDeleted:
+		f.handler.OnDelete(selected, obj)
+	}
+}
@@ -12,6 +12,10 @@ type V1 interface {
 	Upstreams() Upstreams
 	VirtualServices() VirtualServices
 	Roles() Roles
+	// Apply makes the stored upstreams, virtual services and roles match config, see PlanApply.
+	// backends with transactions apply every change atomically, the others apply them in order and roll back on failure
+	// backends with transactions fail with an error for which IsTooManyChanges is true, if the changes do not fit in one
+	Apply(config *v1.Config) error
 }
 
 type Upstreams interface {
@@ -20,7 +24,11 @@ type Upstreams interface {
 	Delete(name string) error
 	Get(name string) (*v1.Upstream, error)
 	List() ([]*v1.Upstream, error)
+	ListWithOptions(opts ListOptions) ([]*v1.Upstream, error)
 	Watch(handlers ...UpstreamEventHandler) (*Watcher, error)
+	WatchWithOptions(opts ListOptions, handlers ...UpstreamEventHandler) (*Watcher, error)
+	// WatchEvents delivers a typed event for every change, and can resume from a resource version
+	WatchEvents(opts WatchOptions, onEvent func(event UpstreamEvent)) (*Watcher, error)
 }
 
 type VirtualServices interface {
@@ -29,7 +37,11 @@ type VirtualServices interface {
 	Delete(name string) error
 	Get(name string) (*v1.VirtualService, error)
 	List() ([]*v1.VirtualService, error)
+	ListWithOptions(opts ListOptions) ([]*v1.VirtualService, error)
 	Watch(...VirtualServiceEventHandler) (*Watcher, error)
+	WatchWithOptions(opts ListOptions, handlers ...VirtualServiceEventHandler) (*Watcher, error)
+	// WatchEvents delivers a typed event for every change, and can resume from a resource version
+	WatchEvents(opts WatchOptions, onEvent func(event VirtualServiceEvent)) (*Watcher, error)
 }
 
 type Roles interface {
@@ -38,5 +50,9 @@ type Roles interface {
 	Delete(name string) error
 	Get(name string) (*v1.Role, error)
 	List() ([]*v1.Role, error)
+	ListWithOptions(opts ListOptions) ([]*v1.Role, error)
 	Watch(...RoleEventHandler) (*Watcher, error)
+	WatchWithOptions(opts ListOptions, handlers ...RoleEventHandler) (*Watcher, error)
+	// WatchEvents delivers a typed event for every change, and can resume from a resource version
+	WatchEvents(opts WatchOptions, onEvent func(event RoleEvent)) (*Watcher, error)
 }
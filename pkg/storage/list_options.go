@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+)
+
+// ListOptions select the objects returned by ListWithOptions, and the objects delivered by WatchWithOptions
+type ListOptions struct {
+	// Namespace selects the objects in a single namespace. empty selects every namespace
+	Namespace string
+	// Selector selects the objects that have all of these labels. empty selects every object
+	Selector map[string]string
+}
+
+// Matches returns true if an object with this metadata is selected
+func (o ListOptions) Matches(meta *v1.Metadata) bool {
+	if o.Namespace != "" && meta.GetNamespace() != o.Namespace {
+		return false
+	}
+	labels := meta.GetLabels()
+	for k, v := range o.Selector {
+		if value, ok := labels[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseSelector parses a label selector in the format key=value,key=value
+func ParseSelector(selector string) (map[string]string, error) {
+	if strings.TrimSpace(selector) == "" {
+		return nil, nil
+	}
+	labels := make(map[string]string)
+	for _, pair := range strings.Split(selector, ",") {
+		kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, errors.Errorf("invalid selector %q, must be in the format key=value,key=value", selector)
+		}
+		labels[kv[0]] = kv[1]
+	}
+	return labels, nil
+}
@@ -0,0 +1,56 @@
+package memory_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/storage"
+	. "github.com/solo-io/gloo/pkg/storage/memory"
+)
+
+// failingRoles fails every create, to interrupt an apply after the upstreams and virtual services were written
+type failingRoles struct {
+	storage.Roles
+}
+
+func (r *failingRoles) Create(*v1.Role) (*v1.Role, error) {
+	return nil, errors.New("create failed")
+}
+
+type failingV1 struct {
+	storage.V1
+}
+
+func (v *failingV1) Roles() storage.Roles {
+	return &failingRoles{Roles: v.V1.Roles()}
+}
+
+var _ = Describe("ApplyInOrder", func() {
+	It("rolls back the applied changes when a change fails", func() {
+		v := NewStorage().V1()
+		_, err := v.Upstreams().Create(&v1.Upstream{Name: "old", Type: "test"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = v.VirtualServices().Create(&v1.VirtualService{Name: "vs", Domains: []string{"test.com"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = storage.ApplyInOrder(&failingV1{V1: v}, &v1.Config{
+			Upstreams:       []*v1.Upstream{{Name: "new", Type: "test"}},
+			VirtualServices: []*v1.VirtualService{{Name: "vs", Domains: []string{"modified.com"}}},
+			Roles:           []*v1.Role{{Name: "role"}},
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("create role role"))
+		Expect(err.Error()).To(ContainSubstring("rolled back"))
+
+		upstreams, err := v.Upstreams().List()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(upstreams).To(HaveLen(1))
+		Expect(upstreams[0].Name).To(Equal("old"))
+		vs, err := v.VirtualServices().Get("vs")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(vs.Domains).To(Equal([]string{"test.com"}))
+	})
+})
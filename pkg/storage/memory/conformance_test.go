@@ -0,0 +1,25 @@
+package memory_test
+
+import (
+	"github.com/solo-io/gloo/pkg/storage"
+	. "github.com/solo-io/gloo/pkg/storage/memory"
+	"github.com/solo-io/gloo/pkg/storage/storagetest"
+)
+
+// each namespace is a separate storage
+type memoryBackend struct {
+	namespaces map[string]storage.Interface
+}
+
+func (b *memoryBackend) NewStorage(namespace string) (storage.Interface, error) {
+	if _, ok := b.namespaces[namespace]; !ok {
+		b.namespaces[namespace] = NewStorage()
+	}
+	return b.namespaces[namespace], nil
+}
+
+func (b *memoryBackend) Cleanup() {}
+
+var _ = storagetest.Conformance("memory", func() storagetest.Backend {
+	return &memoryBackend{namespaces: make(map[string]storage.Interface)}
+})
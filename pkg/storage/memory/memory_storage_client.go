@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/storage"
+)
+
+//go:generate go run ${GOPATH}/src/github.com/solo-io/gloo/pkg/storage/generate/generate_clients.go -f ${GOPATH}/src/github.com/solo-io/gloo/pkg/storage/memory/client_template.go.tmpl -o ${GOPATH}/src/github.com/solo-io/gloo/pkg/storage/memory/
+type Client struct {
+	v1 *v1client
+}
+
+// NewStorage creates an empty storage that keeps config objects in memory.
+// objects are shared by every user of the returned client, and lost when the process exits
+func NewStorage() storage.Interface {
+	return &Client{
+		v1: &v1client{
+			upstreams: &upstreamsClient{
+				store: NewStore("upstream"),
+			},
+			virtualServices: &virtualServicesClient{
+				store: NewStore("virtual service"),
+			},
+			roles: &rolesClient{
+				store: NewStore("role"),
+			},
+		},
+	}
+}
+
+func (c *Client) V1() storage.V1 {
+	return c.v1
+}
+
+type v1client struct {
+	upstreams       *upstreamsClient
+	virtualServices *virtualServicesClient
+	roles           *rolesClient
+}
+
+func (c *v1client) Register() error {
+	return nil
+}
+
+func (c *v1client) Upstreams() storage.Upstreams {
+	return c.upstreams
+}
+
+func (c *v1client) VirtualServices() storage.VirtualServices {
+	return c.virtualServices
+}
+
+func (c *v1client) Roles() storage.Roles {
+	return c.roles
+}
+
+// each kind of object is a separate store, so the changes are applied in order
+func (c *v1client) Apply(config *v1.Config) error {
+	return storage.ApplyInOrder(c, config)
+}
@@ -0,0 +1,119 @@
+package memory
+
+import (
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/storage"
+)
+
+type rolesClient struct {
+	store *Store
+}
+
+func (c *rolesClient) Create(item *v1.Role) (*v1.Role, error) {
+	if item.Name == "" {
+		return nil, errors.Errorf("name required")
+	}
+	entry, err := c.store.Create(item.Name, proto.Clone(item))
+	if err != nil {
+		return nil, err
+	}
+	return roleFromEntry(entry), nil
+}
+
+func (c *rolesClient) Update(item *v1.Role) (*v1.Role, error) {
+	if item.Name == "" {
+		return nil, errors.Errorf("name required")
+	}
+	if item.Metadata == nil || item.Metadata.ResourceVersion == "" {
+		return nil, errors.New("resource version must be set for update operations")
+	}
+	entry, err := c.store.Update(item.Name, item.Metadata.ResourceVersion, proto.Clone(item))
+	if err != nil {
+		return nil, err
+	}
+	return roleFromEntry(entry), nil
+}
+
+func (c *rolesClient) Delete(name string) error {
+	return c.store.Delete(name)
+}
+
+func (c *rolesClient) Get(name string) (*v1.Role, error) {
+	entry, err := c.store.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return roleFromEntry(entry), nil
+}
+
+func (c *rolesClient) List() ([]*v1.Role, error) {
+	return rolesFromEntries(c.store.List()), nil
+}
+
+func (c *rolesClient) ListWithOptions(opts storage.ListOptions) ([]*v1.Role, error) {
+	list, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	return storage.FilterRoles(list, opts), nil
+}
+
+func (c *rolesClient) Watch(handlers ...storage.RoleEventHandler) (*storage.Watcher, error) {
+	return c.store.Watch(func(event Event) {
+		list := rolesFromEntries(event.List)
+		var role *v1.Role
+		if event.Entry != nil {
+			role = roleFromEntry(*event.Entry)
+		}
+		for _, h := range handlers {
+			switch event.Type {
+			case EventAdded:
+				h.OnAdd(list, role)
+			case EventUpdated:
+				h.OnUpdate(list, role)
+			case EventDeleted:
+				h.OnDelete(list, role)
+			}
+		}
+	}), nil
+}
+
+func (c *rolesClient) WatchWithOptions(opts storage.ListOptions, handlers ...storage.RoleEventHandler) (*storage.Watcher, error) {
+	var filtered []storage.RoleEventHandler
+	for _, h := range handlers {
+		filtered = append(filtered, storage.FilterRoleEventHandler(opts, h))
+	}
+	return c.Watch(filtered...)
+}
+
+func (c *rolesClient) WatchEvents(opts storage.WatchOptions, onEvent func(event storage.RoleEvent)) (*storage.Watcher, error) {
+	filtered := storage.FilterRoleEvents(opts, onEvent)
+	return c.store.WatchEvents(opts.ResourceVersion, func(event Event) {
+		filtered(storage.RoleEvent{
+			Type:            event.Type,
+			Role:            roleFromEntry(*event.Entry),
+			ResourceVersion: event.ResourceVersion,
+		})
+	}), nil
+}
+
+// the stored objects are shared, so every caller gets its own copy
+func roleFromEntry(entry Entry) *v1.Role {
+	role := proto.Clone(entry.Object.(*v1.Role)).(*v1.Role)
+	if role.Metadata == nil {
+		role.Metadata = &v1.Metadata{}
+	}
+	role.Metadata.ResourceVersion = entry.ResourceVersion
+	return role
+}
+
+func rolesFromEntries(entries []Entry) []*v1.Role {
+	var roles []*v1.Role
+	for _, entry := range entries {
+		roles = append(roles, roleFromEntry(entry))
+	}
+	return roles
+}
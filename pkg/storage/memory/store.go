@@ -0,0 +1,256 @@
+package memory
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/pkg/storage"
+)
+
+// how many changes the store keeps to resume event watches from
+const maxHistory = 1000
+
+// the kinds of change delivered to watches
+const (
+	EventAdded   = storage.EventAdded
+	EventUpdated = storage.EventUpdated
+	EventDeleted = storage.EventDeleted
+)
+
+// Entry is an object in a Store, along with its name and resource version
+type Entry struct {
+	Name            string
+	ResourceVersion string
+	Object          interface{}
+}
+
+// Event is a change to a Store. List contains every object in the store after the change,
+// and is only set for watches of lists
+type Event struct {
+	Type storage.EventType
+	// the changed object. nil for the initial event of a watch of lists
+	Entry *Entry
+	List  []Entry
+	// the version of the store after the change, which event watches can resume from
+	ResourceVersion string
+}
+
+// Store keeps objects of a single kind in memory, with compare-and-swap updates and watches.
+// objects are never modified by the store, callers must pass it objects they no longer
+// modify, and copy the objects they get from it before modifying them.
+// it is the base of the typed in-memory storage clients
+type Store struct {
+	// the kind of object, for error messages
+	kind string
+
+	lock    sync.Mutex
+	latest  uint64
+	objects map[string]Entry
+	watches map[*watch]bool
+	// the last changes, oldest first. the changes up to and including compacted were dropped
+	history   []Event
+	compacted uint64
+}
+
+func NewStore(kind string) *Store {
+	return &Store{
+		kind:    kind,
+		objects: make(map[string]Entry),
+		watches: make(map[*watch]bool),
+	}
+}
+
+func (s *Store) Create(name string, obj interface{}) (Entry, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.objects[name]; ok {
+		return Entry{}, storage.NewAlreadyExistsErr(errors.Errorf("%v %v already exists", s.kind, name))
+	}
+	entry := s.set(name, obj)
+	s.notify(EventAdded, entry)
+	return entry, nil
+}
+
+// Update replaces the object only if resourceVersion is the version currently stored
+func (s *Store) Update(name, resourceVersion string, obj interface{}) (Entry, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	current, ok := s.objects[name]
+	if !ok {
+		return Entry{}, errors.Errorf("%v %v not found", s.kind, name)
+	}
+	if current.ResourceVersion != resourceVersion {
+		return Entry{}, storage.NewConflictErr(errors.Errorf("resource version %v of %v %v is outdated, the current version is %v",
+			resourceVersion, s.kind, name, current.ResourceVersion))
+	}
+	entry := s.set(name, obj)
+	s.notify(EventUpdated, entry)
+	return entry, nil
+}
+
+func (s *Store) Delete(name string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	entry, ok := s.objects[name]
+	if !ok {
+		return errors.Errorf("%v %v not found", s.kind, name)
+	}
+	delete(s.objects, name)
+	// deletes are changes too, so event watches can resume after them
+	s.latest++
+	s.notify(EventDeleted, entry)
+	return nil
+}
+
+func (s *Store) Get(name string) (Entry, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	entry, ok := s.objects[name]
+	if !ok {
+		return Entry{}, errors.Errorf("%v %v not found", s.kind, name)
+	}
+	return entry, nil
+}
+
+// List returns the objects sorted by name
+func (s *Store) List() []Entry {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.list()
+}
+
+// Watch calls onEvent for every change, in the order of the changes.
+// the first event contains the objects in the store when the watch starts running
+func (s *Store) Watch(onEvent func(event Event)) *storage.Watcher {
+	return storage.NewWatcher(func(stop <-chan struct{}, _ chan error) {
+		w := &watch{notify: make(chan struct{}, 1), lists: true}
+		s.lock.Lock()
+		w.pending = append(w.pending, Event{Type: EventAdded, List: s.list()})
+		s.run(w, stop, onEvent)
+	})
+}
+
+// WatchEvents calls onEvent for every change after resourceVersion, in the order of the changes.
+// an empty resourceVersion starts the watch with an added event for every object in the store.
+// if the store no longer keeps the changes since resourceVersion, the watch fails with an expired error
+func (s *Store) WatchEvents(resourceVersion string, onEvent func(event Event)) *storage.Watcher {
+	return storage.NewWatcher(func(stop <-chan struct{}, errs chan error) {
+		w := &watch{notify: make(chan struct{}, 1)}
+		s.lock.Lock()
+		if resourceVersion == "" {
+			version := strconv.FormatUint(s.latest, 10)
+			for _, entry := range s.list() {
+				entry := entry
+				w.pending = append(w.pending, Event{Type: EventAdded, Entry: &entry, ResourceVersion: version})
+			}
+		} else {
+			since, ok := s.since(resourceVersion)
+			if !ok {
+				s.lock.Unlock()
+				storage.SendExpiredErr(s.kind, resourceVersion, stop, errs)
+				return
+			}
+			w.pending = since
+		}
+		s.run(w, stop, onEvent)
+	})
+}
+
+// events are queued, so writes never wait for slow watches
+type watch struct {
+	pending []Event
+	notify  chan struct{}
+	// whether the watch receives the list of objects with each event
+	lists bool
+}
+
+// run registers the watch and delivers its events until stop is closed.
+// must be called with the lock held, which it releases
+func (s *Store) run(w *watch, stop <-chan struct{}, onEvent func(event Event)) {
+	w.notify <- struct{}{}
+	s.watches[w] = true
+	s.lock.Unlock()
+	defer func() {
+		s.lock.Lock()
+		delete(s.watches, w)
+		s.lock.Unlock()
+	}()
+
+	for {
+		select {
+		case <-w.notify:
+			s.lock.Lock()
+			events := w.pending
+			w.pending = nil
+			s.lock.Unlock()
+			for _, event := range events {
+				onEvent(event)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// since returns the changes after resourceVersion, or false if they are no longer kept.
+// must be called with the lock held
+func (s *Store) since(resourceVersion string) ([]Event, bool) {
+	revision, err := strconv.ParseUint(resourceVersion, 10, 64)
+	if err != nil || revision < s.compacted || revision > s.latest {
+		return nil, false
+	}
+	// the history holds the changes compacted+1 to latest, one per revision
+	return append([]Event{}, s.history[revision-s.compacted:]...), true
+}
+
+// must be called with the lock held
+func (s *Store) set(name string, obj interface{}) Entry {
+	s.latest++
+	entry := Entry{
+		Name:            name,
+		ResourceVersion: strconv.FormatUint(s.latest, 10),
+		Object:          obj,
+	}
+	s.objects[name] = entry
+	return entry
+}
+
+// must be called with the lock held
+func (s *Store) list() []Entry {
+	list := make([]Entry, 0, len(s.objects))
+	for _, entry := range s.objects {
+		list = append(list, entry)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// must be called with the lock held
+func (s *Store) notify(eventType storage.EventType, entry Entry) {
+	event := Event{Type: eventType, Entry: &entry, ResourceVersion: strconv.FormatUint(s.latest, 10)}
+	s.history = append(s.history, event)
+	if len(s.history) > maxHistory {
+		s.history = s.history[1:]
+		s.compacted++
+	}
+	var list []Entry
+	for w := range s.watches {
+		delivered := event
+		if w.lists {
+			if list == nil {
+				list = s.list()
+			}
+			delivered.List = list
+		}
+		w.pending = append(w.pending, delivered)
+		select {
+		case w.notify <- struct{}{}:
+		default:
+		}
+	}
+}
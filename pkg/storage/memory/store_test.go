@@ -0,0 +1,87 @@
+package memory_test
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo/pkg/storage"
+	. "github.com/solo-io/gloo/pkg/storage/memory"
+)
+
+var _ = Describe("Store", func() {
+	var store *Store
+	BeforeEach(func() {
+		store = NewStore("thing")
+	})
+
+	It("resumes event watches after a resource version", func() {
+		created, err := store.Create("foo", "a")
+		Expect(err).NotTo(HaveOccurred())
+		updated, err := store.Update("foo", created.ResourceVersion, "b")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(store.Delete("foo")).To(Succeed())
+
+		var (
+			lock   sync.Mutex
+			events []Event
+		)
+		w := store.WatchEvents(created.ResourceVersion, func(event Event) {
+			lock.Lock()
+			defer lock.Unlock()
+			events = append(events, event)
+		})
+		stop := make(chan struct{})
+		defer close(stop)
+		go w.Run(stop, make(chan error))
+		received := func() []Event {
+			lock.Lock()
+			defer lock.Unlock()
+			return append([]Event{}, events...)
+		}
+		Eventually(received).Should(HaveLen(2))
+		all := received()
+		Expect(all[0].Type).To(Equal(EventUpdated))
+		Expect(*all[0].Entry).To(Equal(updated))
+		Expect(all[0].ResourceVersion).To(Equal(updated.ResourceVersion))
+		// deletes carry the deleted entry, at the version of the delete
+		Expect(all[1].Type).To(Equal(EventDeleted))
+		Expect(*all[1].Entry).To(Equal(updated))
+		Expect(all[1].ResourceVersion).NotTo(Equal(updated.ResourceVersion))
+	})
+
+	It("fails event watches resumed from a version it no longer keeps the changes since", func() {
+		created, err := store.Create("foo", 0)
+		Expect(err).NotTo(HaveOccurred())
+		latest := created
+		// the store keeps the last 1000 changes
+		for i := 1; i <= 1001; i++ {
+			latest, err = store.Update("foo", latest.ResourceVersion, i)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		expired := func(resourceVersion string) bool {
+			w := store.WatchEvents(resourceVersion, func(event Event) {})
+			stop := make(chan struct{})
+			defer close(stop)
+			errs := make(chan error, 1)
+			go w.Run(stop, errs)
+			select {
+			case err := <-errs:
+				return storage.IsResourceVersionExpired(err)
+			case <-time.After(100 * time.Millisecond):
+				return false
+			}
+		}
+		Expect(expired(created.ResourceVersion)).To(BeTrue())
+		Expect(expired(latest.ResourceVersion)).To(BeFalse())
+
+		// versions the store never had can't be resumed from either
+		next, err := strconv.Atoi(latest.ResourceVersion)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(expired(strconv.Itoa(next + 1))).To(BeTrue())
+	})
+})
@@ -0,0 +1,119 @@
+package memory
+
+import (
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/storage"
+)
+
+type upstreamsClient struct {
+	store *Store
+}
+
+func (c *upstreamsClient) Create(item *v1.Upstream) (*v1.Upstream, error) {
+	if item.Name == "" {
+		return nil, errors.Errorf("name required")
+	}
+	entry, err := c.store.Create(item.Name, proto.Clone(item))
+	if err != nil {
+		return nil, err
+	}
+	return upstreamFromEntry(entry), nil
+}
+
+func (c *upstreamsClient) Update(item *v1.Upstream) (*v1.Upstream, error) {
+	if item.Name == "" {
+		return nil, errors.Errorf("name required")
+	}
+	if item.Metadata == nil || item.Metadata.ResourceVersion == "" {
+		return nil, errors.New("resource version must be set for update operations")
+	}
+	entry, err := c.store.Update(item.Name, item.Metadata.ResourceVersion, proto.Clone(item))
+	if err != nil {
+		return nil, err
+	}
+	return upstreamFromEntry(entry), nil
+}
+
+func (c *upstreamsClient) Delete(name string) error {
+	return c.store.Delete(name)
+}
+
+func (c *upstreamsClient) Get(name string) (*v1.Upstream, error) {
+	entry, err := c.store.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return upstreamFromEntry(entry), nil
+}
+
+func (c *upstreamsClient) List() ([]*v1.Upstream, error) {
+	return upstreamsFromEntries(c.store.List()), nil
+}
+
+func (c *upstreamsClient) ListWithOptions(opts storage.ListOptions) ([]*v1.Upstream, error) {
+	list, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	return storage.FilterUpstreams(list, opts), nil
+}
+
+func (c *upstreamsClient) Watch(handlers ...storage.UpstreamEventHandler) (*storage.Watcher, error) {
+	return c.store.Watch(func(event Event) {
+		list := upstreamsFromEntries(event.List)
+		var upstream *v1.Upstream
+		if event.Entry != nil {
+			upstream = upstreamFromEntry(*event.Entry)
+		}
+		for _, h := range handlers {
+			switch event.Type {
+			case EventAdded:
+				h.OnAdd(list, upstream)
+			case EventUpdated:
+				h.OnUpdate(list, upstream)
+			case EventDeleted:
+				h.OnDelete(list, upstream)
+			}
+		}
+	}), nil
+}
+
+func (c *upstreamsClient) WatchWithOptions(opts storage.ListOptions, handlers ...storage.UpstreamEventHandler) (*storage.Watcher, error) {
+	var filtered []storage.UpstreamEventHandler
+	for _, h := range handlers {
+		filtered = append(filtered, storage.FilterUpstreamEventHandler(opts, h))
+	}
+	return c.Watch(filtered...)
+}
+
+func (c *upstreamsClient) WatchEvents(opts storage.WatchOptions, onEvent func(event storage.UpstreamEvent)) (*storage.Watcher, error) {
+	filtered := storage.FilterUpstreamEvents(opts, onEvent)
+	return c.store.WatchEvents(opts.ResourceVersion, func(event Event) {
+		filtered(storage.UpstreamEvent{
+			Type:            event.Type,
+			Upstream:        upstreamFromEntry(*event.Entry),
+			ResourceVersion: event.ResourceVersion,
+		})
+	}), nil
+}
+
+// the stored objects are shared, so every caller gets its own copy
+func upstreamFromEntry(entry Entry) *v1.Upstream {
+	upstream := proto.Clone(entry.Object.(*v1.Upstream)).(*v1.Upstream)
+	if upstream.Metadata == nil {
+		upstream.Metadata = &v1.Metadata{}
+	}
+	upstream.Metadata.ResourceVersion = entry.ResourceVersion
+	return upstream
+}
+
+func upstreamsFromEntries(entries []Entry) []*v1.Upstream {
+	var upstreams []*v1.Upstream
+	for _, entry := range entries {
+		upstreams = append(upstreams, upstreamFromEntry(entry))
+	}
+	return upstreams
+}
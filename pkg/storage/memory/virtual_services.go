@@ -0,0 +1,119 @@
+package memory
+
+import (
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/storage"
+)
+
+type virtualServicesClient struct {
+	store *Store
+}
+
+func (c *virtualServicesClient) Create(item *v1.VirtualService) (*v1.VirtualService, error) {
+	if item.Name == "" {
+		return nil, errors.Errorf("name required")
+	}
+	entry, err := c.store.Create(item.Name, proto.Clone(item))
+	if err != nil {
+		return nil, err
+	}
+	return virtualServiceFromEntry(entry), nil
+}
+
+func (c *virtualServicesClient) Update(item *v1.VirtualService) (*v1.VirtualService, error) {
+	if item.Name == "" {
+		return nil, errors.Errorf("name required")
+	}
+	if item.Metadata == nil || item.Metadata.ResourceVersion == "" {
+		return nil, errors.New("resource version must be set for update operations")
+	}
+	entry, err := c.store.Update(item.Name, item.Metadata.ResourceVersion, proto.Clone(item))
+	if err != nil {
+		return nil, err
+	}
+	return virtualServiceFromEntry(entry), nil
+}
+
+func (c *virtualServicesClient) Delete(name string) error {
+	return c.store.Delete(name)
+}
+
+func (c *virtualServicesClient) Get(name string) (*v1.VirtualService, error) {
+	entry, err := c.store.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return virtualServiceFromEntry(entry), nil
+}
+
+func (c *virtualServicesClient) List() ([]*v1.VirtualService, error) {
+	return virtualServicesFromEntries(c.store.List()), nil
+}
+
+func (c *virtualServicesClient) ListWithOptions(opts storage.ListOptions) ([]*v1.VirtualService, error) {
+	list, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	return storage.FilterVirtualServices(list, opts), nil
+}
+
+func (c *virtualServicesClient) Watch(handlers ...storage.VirtualServiceEventHandler) (*storage.Watcher, error) {
+	return c.store.Watch(func(event Event) {
+		list := virtualServicesFromEntries(event.List)
+		var virtualService *v1.VirtualService
+		if event.Entry != nil {
+			virtualService = virtualServiceFromEntry(*event.Entry)
+		}
+		for _, h := range handlers {
+			switch event.Type {
+			case EventAdded:
+				h.OnAdd(list, virtualService)
+			case EventUpdated:
+				h.OnUpdate(list, virtualService)
+			case EventDeleted:
+				h.OnDelete(list, virtualService)
+			}
+		}
+	}), nil
+}
+
+func (c *virtualServicesClient) WatchWithOptions(opts storage.ListOptions, handlers ...storage.VirtualServiceEventHandler) (*storage.Watcher, error) {
+	var filtered []storage.VirtualServiceEventHandler
+	for _, h := range handlers {
+		filtered = append(filtered, storage.FilterVirtualServiceEventHandler(opts, h))
+	}
+	return c.Watch(filtered...)
+}
+
+func (c *virtualServicesClient) WatchEvents(opts storage.WatchOptions, onEvent func(event storage.VirtualServiceEvent)) (*storage.Watcher, error) {
+	filtered := storage.FilterVirtualServiceEvents(opts, onEvent)
+	return c.store.WatchEvents(opts.ResourceVersion, func(event Event) {
+		filtered(storage.VirtualServiceEvent{
+			Type:            event.Type,
+			VirtualService:  virtualServiceFromEntry(*event.Entry),
+			ResourceVersion: event.ResourceVersion,
+		})
+	}), nil
+}
+
+// the stored objects are shared, so every caller gets its own copy
+func virtualServiceFromEntry(entry Entry) *v1.VirtualService {
+	virtualService := proto.Clone(entry.Object.(*v1.VirtualService)).(*v1.VirtualService)
+	if virtualService.Metadata == nil {
+		virtualService.Metadata = &v1.Metadata{}
+	}
+	virtualService.Metadata.ResourceVersion = entry.ResourceVersion
+	return virtualService
+}
+
+func virtualServicesFromEntries(entries []Entry) []*v1.VirtualService {
+	var virtualServices []*v1.VirtualService
+	for _, entry := range entries {
+		virtualServices = append(virtualServices, virtualServiceFromEntry(entry))
+	}
+	return virtualServices
+}
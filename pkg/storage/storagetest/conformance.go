@@ -0,0 +1,607 @@
+// Package storagetest contains the conformance tests every implementation of storage.Interface must pass.
+// a backend runs them by calling Conformance from a ginkgo test file in its package:
+//
+//	var _ = storagetest.Conformance("file", newFileBackend)
+package storagetest
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/storage"
+	"github.com/solo-io/gloo/test/helpers"
+)
+
+// Backend creates storage clients for one test. clients for the same namespace
+// share their objects, clients for different namespaces must not see each other's objects.
+// backends without namespaces can scope objects by e.g. directory or key prefix
+type Backend interface {
+	NewStorage(namespace string) (storage.Interface, error)
+	// Cleanup removes everything created during the test
+	Cleanup()
+}
+
+// NoWatchResume is implemented by backends whose tests cannot resume event watches from a resource version,
+// e.g. because they run against fake clients that do not keep past changes
+type NoWatchResume interface {
+	NoWatchResume()
+}
+
+// how long to wait for watches to deliver a change
+const watchTimeout = 10 * time.Second
+
+// Conformance describes the behavior shared by every storage backend.
+// newBackend is called before each test
+func Conformance(name string, newBackend func() Backend) bool {
+	return Describe(name+" storage conformance", func() {
+		for _, k := range kinds {
+			k := k
+			Describe(k.name, func() {
+				var (
+					backend   Backend
+					namespace string
+					store     storage.Interface
+					client    objectClient
+				)
+				BeforeEach(func() {
+					backend = newBackend()
+					namespace = helpers.RandString(8)
+					var err error
+					store, err = backend.NewStorage(namespace)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(store.V1().Register()).To(Succeed())
+					client = k.client(store)
+				})
+				AfterEach(func() {
+					backend.Cleanup()
+				})
+
+				Describe("Create", func() {
+					It("stores the object with a resource version", func() {
+						created, err := client.Create(k.newObject("foo"))
+						Expect(err).NotTo(HaveOccurred())
+						Expect(created.GetName()).To(Equal("foo"))
+						Expect(created.GetMetadata().GetResourceVersion()).NotTo(BeEmpty())
+
+						stored, err := client.Get("foo")
+						Expect(err).NotTo(HaveOccurred())
+						Expect(withoutMetadata(stored)).To(Equal(withoutMetadata(k.newObject("foo"))))
+						Expect(stored.GetMetadata().GetResourceVersion()).To(Equal(created.GetMetadata().GetResourceVersion()))
+					})
+					It("returns an already exists error for an existing name", func() {
+						_, err := client.Create(k.newObject("foo"))
+						Expect(err).NotTo(HaveOccurred())
+						_, err = client.Create(k.newObject("foo"))
+						Expect(err).To(HaveOccurred())
+						Expect(storage.IsAlreadyExists(err)).To(BeTrue())
+					})
+				})
+
+				Describe("Get", func() {
+					It("returns an error for a missing object", func() {
+						_, err := client.Get("missing")
+						Expect(err).To(HaveOccurred())
+					})
+				})
+
+				Describe("List", func() {
+					It("lists every object", func() {
+						Expect(client.List()).To(BeEmpty())
+						for _, name := range []string{"a", "b", "c"} {
+							_, err := client.Create(k.newObject(name))
+							Expect(err).NotTo(HaveOccurred())
+						}
+						list, err := client.List()
+						Expect(err).NotTo(HaveOccurred())
+						Expect(names(list)).To(ConsistOf("a", "b", "c"))
+					})
+				})
+
+				Describe("Update", func() {
+					It("stores the change with a new resource version", func() {
+						created, err := client.Create(k.newObject("foo"))
+						Expect(err).NotTo(HaveOccurred())
+						k.modify(created)
+						updated, err := client.Update(created)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(updated.GetMetadata().GetResourceVersion()).NotTo(BeEmpty())
+						Expect(updated.GetMetadata().GetResourceVersion()).NotTo(Equal(created.GetMetadata().GetResourceVersion()))
+
+						stored, err := client.Get("foo")
+						Expect(err).NotTo(HaveOccurred())
+						Expect(withoutMetadata(stored)).To(Equal(withoutMetadata(created)))
+						Expect(stored.GetMetadata().GetResourceVersion()).To(Equal(updated.GetMetadata().GetResourceVersion()))
+					})
+					It("returns a conflict error for an outdated resource version", func() {
+						created, err := client.Create(k.newObject("foo"))
+						Expect(err).NotTo(HaveOccurred())
+						_, err = client.Update(created)
+						Expect(err).NotTo(HaveOccurred())
+						k.modify(created)
+						_, err = client.Update(created)
+						Expect(err).To(HaveOccurred())
+						Expect(storage.IsConflict(err)).To(BeTrue())
+
+						stored, err := client.Get("foo")
+						Expect(err).NotTo(HaveOccurred())
+						Expect(withoutMetadata(stored)).To(Equal(withoutMetadata(k.newObject("foo"))))
+					})
+					It("returns a conflict error for the resource version of a deleted object with the same name", func() {
+						stale, err := client.Create(k.newObject("foo"))
+						Expect(err).NotTo(HaveOccurred())
+						Expect(client.Delete("foo")).To(Succeed())
+						_, err = client.Create(k.newObject("foo"))
+						Expect(err).NotTo(HaveOccurred())
+						k.modify(stale)
+						_, err = client.Update(stale)
+						Expect(err).To(HaveOccurred())
+						Expect(storage.IsConflict(err)).To(BeTrue())
+
+						stored, err := client.Get("foo")
+						Expect(err).NotTo(HaveOccurred())
+						Expect(withoutMetadata(stored)).To(Equal(withoutMetadata(k.newObject("foo"))))
+					})
+					It("returns an error without a resource version", func() {
+						_, err := client.Create(k.newObject("foo"))
+						Expect(err).NotTo(HaveOccurred())
+						obj := k.newObject("foo")
+						k.modify(obj)
+						_, err = client.Update(obj)
+						Expect(err).To(HaveOccurred())
+					})
+					It("returns an error for a missing object", func() {
+						obj := k.newObject("missing")
+						obj.SetMetadata(&v1.Metadata{ResourceVersion: "1"})
+						_, err := client.Update(obj)
+						Expect(err).To(HaveOccurred())
+					})
+				})
+
+				Describe("Delete", func() {
+					It("removes the object", func() {
+						_, err := client.Create(k.newObject("foo"))
+						Expect(err).NotTo(HaveOccurred())
+						Expect(client.Delete("foo")).To(Succeed())
+						_, err = client.Get("foo")
+						Expect(err).To(HaveOccurred())
+						Expect(client.List()).To(BeEmpty())
+					})
+				})
+
+				Describe("Watch", func() {
+					It("delivers every change in order", func() {
+						var (
+							lock sync.Mutex
+							// the resource version of foo in each list delivered to the handler,
+							// or "" if the list didn't contain foo
+							observed []string
+						)
+						latest := func() []string {
+							lock.Lock()
+							defer lock.Unlock()
+							return append([]string{}, observed...)
+						}
+						w, err := client.Watch(func(list []v1.ConfigObject) {
+							version := ""
+							for _, obj := range list {
+								if obj.GetName() == "foo" {
+									version = obj.GetMetadata().GetResourceVersion()
+								}
+							}
+							lock.Lock()
+							defer lock.Unlock()
+							observed = append(observed, version)
+						})
+						Expect(err).NotTo(HaveOccurred())
+						stop := make(chan struct{})
+						defer close(stop)
+						go w.Run(stop, make(chan error, 1))
+
+						lastObserved := func() string {
+							all := latest()
+							if len(all) == 0 {
+								return "none"
+							}
+							return all[len(all)-1]
+						}
+						created, err := client.Create(k.newObject("foo"))
+						Expect(err).NotTo(HaveOccurred())
+						Eventually(lastObserved, watchTimeout).Should(Equal(created.GetMetadata().GetResourceVersion()))
+
+						k.modify(created)
+						updated, err := client.Update(created)
+						Expect(err).NotTo(HaveOccurred())
+						Eventually(lastObserved, watchTimeout).Should(Equal(updated.GetMetadata().GetResourceVersion()))
+
+						Expect(client.Delete("foo")).To(Succeed())
+						Eventually(lastObserved, watchTimeout).Should(Equal(""))
+
+						// once a version was delivered, no older state may be delivered after it
+						stages := map[string]int{
+							created.GetMetadata().GetResourceVersion(): 1,
+							updated.GetMetadata().GetResourceVersion(): 2,
+						}
+						stage := 0
+						for _, version := range latest() {
+							next, ok := stages[version]
+							if !ok {
+								// foo is missing before it is created and after it is deleted
+								next = 0
+								if stage > 0 {
+									next = 3
+								}
+							}
+							Expect(next).To(BeNumerically(">=", stage), fmt.Sprintf("resource versions delivered out of order: %v", latest()))
+							stage = next
+						}
+					})
+				})
+
+				Describe("WatchEvents", func() {
+					var stop chan struct{}
+					BeforeEach(func() {
+						stop = make(chan struct{})
+					})
+					AfterEach(func() {
+						close(stop)
+					})
+					// startWatch runs an event watch until the test ends
+					startWatch := func(opts storage.WatchOptions) (*eventLog, chan error) {
+						events := &eventLog{}
+						w, err := client.WatchEvents(opts, events.add)
+						Expect(err).NotTo(HaveOccurred())
+						errs := make(chan error, 1)
+						go w.Run(stop, errs)
+						return events, errs
+					}
+
+					It("delivers a typed event for every change", func() {
+						existing, err := client.Create(k.newObject("existing"))
+						Expect(err).NotTo(HaveOccurred())
+						events, _ := startWatch(storage.WatchOptions{})
+						Eventually(events.changes, watchTimeout).Should(ConsistOf(
+							"added existing " + existing.GetMetadata().GetResourceVersion(),
+						))
+
+						created, err := client.Create(k.newObject("foo"))
+						Expect(err).NotTo(HaveOccurred())
+						Eventually(events.changes, watchTimeout).Should(HaveLen(2))
+						k.modify(created)
+						updated, err := client.Update(created)
+						Expect(err).NotTo(HaveOccurred())
+						Eventually(events.changes, watchTimeout).Should(HaveLen(3))
+						Expect(client.Delete("foo")).To(Succeed())
+
+						// deletes carry the last version of the object
+						Eventually(events.changes, watchTimeout).Should(Equal([]string{
+							"added existing " + existing.GetMetadata().GetResourceVersion(),
+							"added foo " + created.GetMetadata().GetResourceVersion(),
+							"updated foo " + updated.GetMetadata().GetResourceVersion(),
+							"deleted foo " + updated.GetMetadata().GetResourceVersion(),
+						}))
+					})
+					It("resumes after the last handled event", func() {
+						if _, ok := backend.(NoWatchResume); ok {
+							Skip("the backend cannot resume watches in tests")
+						}
+						created, err := client.Create(k.newObject("foo"))
+						Expect(err).NotTo(HaveOccurred())
+						events, _ := startWatch(storage.WatchOptions{})
+						Eventually(events.changes, watchTimeout).Should(HaveLen(1))
+						resourceVersion := events.last().ResourceVersion
+
+						if resourceVersion == "" {
+							Skip("the backend only watches lists, and cannot resume watches")
+						}
+
+						k.modify(created)
+						updated, err := client.Update(created)
+						Expect(err).NotTo(HaveOccurred())
+						bar, err := client.Create(k.newObject("bar"))
+						Expect(err).NotTo(HaveOccurred())
+
+						// only the changes after the version are delivered
+						resumed, _ := startWatch(storage.WatchOptions{ResourceVersion: resourceVersion})
+						Eventually(resumed.changes, watchTimeout).Should(Equal([]string{
+							"updated foo " + updated.GetMetadata().GetResourceVersion(),
+							"added bar " + bar.GetMetadata().GetResourceVersion(),
+						}))
+						Consistently(resumed.changes, time.Second).Should(HaveLen(2))
+					})
+					It("fails resumed watches if it only watches lists, so callers start over", func() {
+						existing, err := client.Create(k.newObject("existing"))
+						Expect(err).NotTo(HaveOccurred())
+						events, _ := startWatch(storage.WatchOptions{})
+						Eventually(events.changes, watchTimeout).Should(HaveLen(1))
+						if events.last().ResourceVersion != "" {
+							Skip("the backend resumes watches")
+						}
+
+						_, errs := startWatch(storage.WatchOptions{ResourceVersion: existing.GetMetadata().GetResourceVersion()})
+						var watchErr error
+						Eventually(errs, watchTimeout).Should(Receive(&watchErr))
+						Expect(storage.IsResourceVersionExpired(watchErr)).To(BeTrue())
+
+						restarted, _ := startWatch(storage.WatchOptions{})
+						Eventually(restarted.changes, watchTimeout).Should(Equal([]string{
+							"added existing " + existing.GetMetadata().GetResourceVersion(),
+						}))
+					})
+					It("delivers objects that leave the selection as deletes", func() {
+						obj := k.newObject("foo")
+						obj.SetMetadata(&v1.Metadata{Labels: map[string]string{"tier": "edge"}})
+						created, err := client.Create(obj)
+						Expect(err).NotTo(HaveOccurred())
+						events, _ := startWatch(storage.WatchOptions{ListOptions: storage.ListOptions{Selector: map[string]string{"tier": "edge"}}})
+						Eventually(events.changes, watchTimeout).Should(HaveLen(1))
+
+						created.GetMetadata().Labels = map[string]string{"tier": "internal"}
+						_, err = client.Update(created)
+						Expect(err).NotTo(HaveOccurred())
+						Eventually(events.changes, watchTimeout).Should(HaveLen(2))
+						Expect(events.last().Type).To(Equal(storage.EventDeleted))
+						Expect(events.last().Object.GetName()).To(Equal("foo"))
+					})
+				})
+
+				Describe("namespaces", func() {
+					It("keeps the objects of each namespace separate", func() {
+						other, err := backend.NewStorage(namespace + "-other")
+						Expect(err).NotTo(HaveOccurred())
+						Expect(other.V1().Register()).To(Succeed())
+						otherClient := k.client(other)
+
+						_, err = client.Create(k.newObject("foo"))
+						Expect(err).NotTo(HaveOccurred())
+						Expect(otherClient.List()).To(BeEmpty())
+						_, err = otherClient.Get("foo")
+						Expect(err).To(HaveOccurred())
+
+						// the same name can be used in each namespace
+						otherObj := k.newObject("foo")
+						k.modify(otherObj)
+						_, err = otherClient.Create(otherObj)
+						Expect(err).NotTo(HaveOccurred())
+						stored, err := client.Get("foo")
+						Expect(err).NotTo(HaveOccurred())
+						Expect(withoutMetadata(stored)).To(Equal(withoutMetadata(k.newObject("foo"))))
+
+						Expect(otherClient.Delete("foo")).To(Succeed())
+						_, err = client.Get("foo")
+						Expect(err).NotTo(HaveOccurred())
+					})
+					It("lists the objects of a single namespace", func() {
+						_, err := client.Create(k.newObject("foo"))
+						Expect(err).NotTo(HaveOccurred())
+						stored, err := client.Get("foo")
+						Expect(err).NotTo(HaveOccurred())
+						// backends without namespaces leave it empty, which selects every namespace
+						objectNamespace := stored.GetMetadata().GetNamespace()
+
+						list, err := client.ListWithOptions(storage.ListOptions{Namespace: objectNamespace})
+						Expect(err).NotTo(HaveOccurred())
+						Expect(names(list)).To(ConsistOf("foo"))
+						list, err = client.ListWithOptions(storage.ListOptions{Namespace: objectNamespace + "-other"})
+						Expect(err).NotTo(HaveOccurred())
+						Expect(list).To(BeEmpty())
+					})
+				})
+
+				Describe("labels", func() {
+					create := func(name string, labels map[string]string) {
+						obj := k.newObject(name)
+						obj.SetMetadata(&v1.Metadata{Labels: labels})
+						_, err := client.Create(obj)
+						Expect(err).NotTo(HaveOccurred())
+					}
+					setLabels := func(name string, labels map[string]string) {
+						obj, err := client.Get(name)
+						Expect(err).NotTo(HaveOccurred())
+						obj.GetMetadata().Labels = labels
+						_, err = client.Update(obj)
+						Expect(err).NotTo(HaveOccurred())
+					}
+
+					It("stores the labels of the object", func() {
+						create("foo", map[string]string{"tier": "edge"})
+						stored, err := client.Get("foo")
+						Expect(err).NotTo(HaveOccurred())
+						Expect(stored.GetMetadata().GetLabels()).To(Equal(map[string]string{"tier": "edge"}))
+					})
+					It("lists the objects matching a selector", func() {
+						create("a", map[string]string{"tier": "edge", "zone": "west"})
+						create("b", map[string]string{"tier": "internal"})
+						create("c", nil)
+
+						list, err := client.ListWithOptions(storage.ListOptions{Selector: map[string]string{"tier": "edge"}})
+						Expect(err).NotTo(HaveOccurred())
+						Expect(names(list)).To(ConsistOf("a"))
+						list, err = client.ListWithOptions(storage.ListOptions{Selector: map[string]string{"tier": "edge", "zone": "east"}})
+						Expect(err).NotTo(HaveOccurred())
+						Expect(list).To(BeEmpty())
+						list, err = client.ListWithOptions(storage.ListOptions{})
+						Expect(err).NotTo(HaveOccurred())
+						Expect(names(list)).To(ConsistOf("a", "b", "c"))
+					})
+					It("watches the objects matching a selector", func() {
+						var (
+							lock sync.Mutex
+							// the names in the last list delivered to the handler
+							observed = []string{"none"}
+						)
+						lastObserved := func() []string {
+							lock.Lock()
+							defer lock.Unlock()
+							return observed
+						}
+						selector := storage.ListOptions{Selector: map[string]string{"tier": "edge"}}
+						w, err := client.WatchWithOptions(selector, func(list []v1.ConfigObject) {
+							lock.Lock()
+							defer lock.Unlock()
+							observed = append([]string{}, names(list)...)
+						})
+						Expect(err).NotTo(HaveOccurred())
+						stop := make(chan struct{})
+						defer close(stop)
+						go w.Run(stop, make(chan error, 1))
+
+						create("a", map[string]string{"tier": "edge"})
+						create("b", map[string]string{"tier": "internal"})
+						Eventually(lastObserved, watchTimeout).Should(ConsistOf("a"))
+
+						// objects enter and leave the selection when their labels change
+						setLabels("b", map[string]string{"tier": "edge"})
+						Eventually(lastObserved, watchTimeout).Should(ConsistOf("a", "b"))
+						setLabels("a", map[string]string{"tier": "internal"})
+						Eventually(lastObserved, watchTimeout).Should(ConsistOf("b"))
+
+						Expect(client.Delete("b")).To(Succeed())
+						Eventually(lastObserved, watchTimeout).Should(BeEmpty())
+					})
+				})
+			})
+		}
+
+		Describe("Apply", func() {
+			var (
+				backend Backend
+				v       storage.V1
+			)
+			BeforeEach(func() {
+				backend = newBackend()
+				store, err := backend.NewStorage(helpers.RandString(8))
+				Expect(err).NotTo(HaveOccurred())
+				v = store.V1()
+				Expect(v.Register()).To(Succeed())
+			})
+			AfterEach(func() {
+				backend.Cleanup()
+			})
+			stored := func() *v1.Config {
+				upstreams, err := v.Upstreams().List()
+				Expect(err).NotTo(HaveOccurred())
+				virtualServices, err := v.VirtualServices().List()
+				Expect(err).NotTo(HaveOccurred())
+				roles, err := v.Roles().List()
+				Expect(err).NotTo(HaveOccurred())
+				return &v1.Config{Upstreams: upstreams, VirtualServices: virtualServices, Roles: roles}
+			}
+
+			It("creates, updates and deletes objects of every kind", func() {
+				_, err := v.Upstreams().Create(&v1.Upstream{Name: "old", Type: "test"})
+				Expect(err).NotTo(HaveOccurred())
+				_, err = v.VirtualServices().Create(&v1.VirtualService{Name: "vs", Domains: []string{"test.com"}})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(v.Apply(&v1.Config{
+					Upstreams:       []*v1.Upstream{{Name: "new", Type: "test"}},
+					VirtualServices: []*v1.VirtualService{{Name: "vs", Domains: []string{"modified.com"}}},
+					Roles:           []*v1.Role{{Name: "role", VirtualServices: []string{"vs"}}},
+				})).To(Succeed())
+
+				config := stored()
+				Expect(config.Upstreams).To(HaveLen(1))
+				Expect(config.Upstreams[0].Name).To(Equal("new"))
+				Expect(config.VirtualServices).To(HaveLen(1))
+				Expect(config.VirtualServices[0].Domains).To(Equal([]string{"modified.com"}))
+				Expect(config.Roles).To(HaveLen(1))
+				Expect(config.Roles[0].VirtualServices).To(Equal([]string{"vs"}))
+			})
+			It("keeps the status of stored objects", func() {
+				created, err := v.Upstreams().Create(&v1.Upstream{Name: "foo", Type: "test"})
+				Expect(err).NotTo(HaveOccurred())
+				created.Status = &v1.Status{State: v1.Status_Accepted}
+				_, err = v.Upstreams().Update(created)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(v.Apply(&v1.Config{Upstreams: []*v1.Upstream{{Name: "foo", Type: "modified"}}})).To(Succeed())
+				us, err := v.Upstreams().Get("foo")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(us.Type).To(Equal("modified"))
+				Expect(us.Status.GetState()).To(Equal(v1.Status_Accepted))
+			})
+			It("does not write objects that did not change", func() {
+				config := &v1.Config{Upstreams: []*v1.Upstream{{Name: "foo", Type: "test"}}}
+				Expect(v.Apply(config)).To(Succeed())
+				before, err := v.Upstreams().Get("foo")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(v.Apply(config)).To(Succeed())
+				after, err := v.Upstreams().Get("foo")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(after.Metadata.ResourceVersion).To(Equal(before.Metadata.ResourceVersion))
+			})
+			It("applies configs with more changes than fit in a transaction, or writes nothing", func() {
+				var upstreams []*v1.Upstream
+				for i := 0; i < 200; i++ {
+					upstreams = append(upstreams, &v1.Upstream{Name: fmt.Sprintf("us-%v", i), Type: "test"})
+				}
+				err := v.Apply(&v1.Config{Upstreams: upstreams})
+				if storage.IsTooManyChanges(err) {
+					Expect(stored().Upstreams).To(BeEmpty())
+					return
+				}
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stored().Upstreams).To(HaveLen(200))
+			})
+			It("returns an error for names defined more than once, without writing anything", func() {
+				err := v.Apply(&v1.Config{
+					Upstreams: []*v1.Upstream{{Name: "foo", Type: "test"}},
+					Roles:     []*v1.Role{{Name: "role"}, {Name: "role"}},
+				})
+				Expect(err).To(HaveOccurred())
+				Expect(stored().Upstreams).To(BeEmpty())
+			})
+		})
+	})
+}
+
+func names(list []v1.ConfigObject) []string {
+	var out []string
+	for _, obj := range list {
+		out = append(out, obj.GetName())
+	}
+	return out
+}
+
+// backends may add their own metadata, e.g. the namespace, so only the spec is compared
+func withoutMetadata(obj v1.ConfigObject) v1.ConfigObject {
+	obj = proto.Clone(obj).(v1.ConfigObject)
+	obj.SetMetadata(nil)
+	return obj
+}
+
+// eventLog collects the events delivered to an event watch
+type eventLog struct {
+	lock   sync.Mutex
+	events []objectEvent
+}
+
+func (l *eventLog) add(event objectEvent) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.events = append(l.events, event)
+}
+
+func (l *eventLog) last() objectEvent {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.events[len(l.events)-1]
+}
+
+// changes describes each event as "<type> <name> <resource version of the object>"
+func (l *eventLog) changes() []string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	var out []string
+	for _, event := range l.events {
+		out = append(out, fmt.Sprintf("%v %v %v", event.Type, event.Object.GetName(), event.Object.GetMetadata().GetResourceVersion()))
+	}
+	return out
+}
@@ -0,0 +1,328 @@
+package storagetest
+
+import (
+	"github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/storage"
+)
+
+// objectClient lets the tests treat the clients of every kind of object the same way
+type objectClient interface {
+	Create(obj v1.ConfigObject) (v1.ConfigObject, error)
+	Update(obj v1.ConfigObject) (v1.ConfigObject, error)
+	Delete(name string) error
+	Get(name string) (v1.ConfigObject, error)
+	List() ([]v1.ConfigObject, error)
+	ListWithOptions(opts storage.ListOptions) ([]v1.ConfigObject, error)
+	// onChange is called with the updated list on every event
+	Watch(onChange func(list []v1.ConfigObject)) (*storage.Watcher, error)
+	WatchWithOptions(opts storage.ListOptions, onChange func(list []v1.ConfigObject)) (*storage.Watcher, error)
+	WatchEvents(opts storage.WatchOptions, onEvent func(event objectEvent)) (*storage.Watcher, error)
+}
+
+type objectEvent struct {
+	Type            storage.EventType
+	Object          v1.ConfigObject
+	ResourceVersion string
+}
+
+type kind struct {
+	name      string
+	newObject func(name string) v1.ConfigObject
+	// modify changes the spec of the object
+	modify func(obj v1.ConfigObject)
+	client func(store storage.Interface) objectClient
+}
+
+var kinds = []kind{
+	{
+		name: "upstreams",
+		newObject: func(name string) v1.ConfigObject {
+			return &v1.Upstream{Name: name, Type: "test"}
+		},
+		modify: func(obj v1.ConfigObject) {
+			obj.(*v1.Upstream).Type = "modified"
+		},
+		client: func(store storage.Interface) objectClient {
+			return &upstreams{client: store.V1().Upstreams()}
+		},
+	},
+	{
+		name: "virtual services",
+		newObject: func(name string) v1.ConfigObject {
+			return &v1.VirtualService{Name: name, Domains: []string{"test.com"}}
+		},
+		modify: func(obj v1.ConfigObject) {
+			obj.(*v1.VirtualService).Domains = []string{"modified.com"}
+		},
+		client: func(store storage.Interface) objectClient {
+			return &virtualServices{client: store.V1().VirtualServices()}
+		},
+	},
+	{
+		name: "roles",
+		newObject: func(name string) v1.ConfigObject {
+			return &v1.Role{Name: name, VirtualServices: []string{"test"}}
+		},
+		modify: func(obj v1.ConfigObject) {
+			obj.(*v1.Role).VirtualServices = []string{"modified"}
+		},
+		client: func(store storage.Interface) objectClient {
+			return &roles{client: store.V1().Roles()}
+		},
+	},
+}
+
+type upstreams struct {
+	client storage.Upstreams
+}
+
+func (c *upstreams) Create(obj v1.ConfigObject) (v1.ConfigObject, error) {
+	created, err := c.client.Create(obj.(*v1.Upstream))
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (c *upstreams) Update(obj v1.ConfigObject) (v1.ConfigObject, error) {
+	updated, err := c.client.Update(obj.(*v1.Upstream))
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (c *upstreams) Delete(name string) error {
+	return c.client.Delete(name)
+}
+
+func (c *upstreams) Get(name string) (v1.ConfigObject, error) {
+	obj, err := c.client.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (c *upstreams) List() ([]v1.ConfigObject, error) {
+	list, err := c.client.List()
+	if err != nil {
+		return nil, err
+	}
+	var out []v1.ConfigObject
+	for _, obj := range list {
+		out = append(out, obj)
+	}
+	return out, nil
+}
+
+func (c *upstreams) ListWithOptions(opts storage.ListOptions) ([]v1.ConfigObject, error) {
+	list, err := c.client.ListWithOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	var out []v1.ConfigObject
+	for _, obj := range list {
+		out = append(out, obj)
+	}
+	return out, nil
+}
+
+func (c *upstreams) Watch(onChange func(list []v1.ConfigObject)) (*storage.Watcher, error) {
+	return c.client.Watch(c.handler(onChange))
+}
+
+func (c *upstreams) WatchWithOptions(opts storage.ListOptions, onChange func(list []v1.ConfigObject)) (*storage.Watcher, error) {
+	return c.client.WatchWithOptions(opts, c.handler(onChange))
+}
+
+func (c *upstreams) WatchEvents(opts storage.WatchOptions, onEvent func(event objectEvent)) (*storage.Watcher, error) {
+	return c.client.WatchEvents(opts, func(event storage.UpstreamEvent) {
+		onEvent(objectEvent{Type: event.Type, Object: event.Upstream, ResourceVersion: event.ResourceVersion})
+	})
+}
+
+func (c *upstreams) handler(onChange func(list []v1.ConfigObject)) storage.UpstreamEventHandler {
+	changed := func(list []*v1.Upstream, _ *v1.Upstream) {
+		var out []v1.ConfigObject
+		for _, obj := range list {
+			out = append(out, obj)
+		}
+		onChange(out)
+	}
+	return &storage.UpstreamEventHandlerFuncs{
+		AddFunc:    changed,
+		UpdateFunc: changed,
+		DeleteFunc: changed,
+	}
+}
+
+type virtualServices struct {
+	client storage.VirtualServices
+}
+
+func (c *virtualServices) Create(obj v1.ConfigObject) (v1.ConfigObject, error) {
+	created, err := c.client.Create(obj.(*v1.VirtualService))
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (c *virtualServices) Update(obj v1.ConfigObject) (v1.ConfigObject, error) {
+	updated, err := c.client.Update(obj.(*v1.VirtualService))
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (c *virtualServices) Delete(name string) error {
+	return c.client.Delete(name)
+}
+
+func (c *virtualServices) Get(name string) (v1.ConfigObject, error) {
+	obj, err := c.client.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (c *virtualServices) List() ([]v1.ConfigObject, error) {
+	list, err := c.client.List()
+	if err != nil {
+		return nil, err
+	}
+	var out []v1.ConfigObject
+	for _, obj := range list {
+		out = append(out, obj)
+	}
+	return out, nil
+}
+
+func (c *virtualServices) ListWithOptions(opts storage.ListOptions) ([]v1.ConfigObject, error) {
+	list, err := c.client.ListWithOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	var out []v1.ConfigObject
+	for _, obj := range list {
+		out = append(out, obj)
+	}
+	return out, nil
+}
+
+func (c *virtualServices) Watch(onChange func(list []v1.ConfigObject)) (*storage.Watcher, error) {
+	return c.client.Watch(c.handler(onChange))
+}
+
+func (c *virtualServices) WatchWithOptions(opts storage.ListOptions, onChange func(list []v1.ConfigObject)) (*storage.Watcher, error) {
+	return c.client.WatchWithOptions(opts, c.handler(onChange))
+}
+
+func (c *virtualServices) WatchEvents(opts storage.WatchOptions, onEvent func(event objectEvent)) (*storage.Watcher, error) {
+	return c.client.WatchEvents(opts, func(event storage.VirtualServiceEvent) {
+		onEvent(objectEvent{Type: event.Type, Object: event.VirtualService, ResourceVersion: event.ResourceVersion})
+	})
+}
+
+func (c *virtualServices) handler(onChange func(list []v1.ConfigObject)) storage.VirtualServiceEventHandler {
+	changed := func(list []*v1.VirtualService, _ *v1.VirtualService) {
+		var out []v1.ConfigObject
+		for _, obj := range list {
+			out = append(out, obj)
+		}
+		onChange(out)
+	}
+	return &storage.VirtualServiceEventHandlerFuncs{
+		AddFunc:    changed,
+		UpdateFunc: changed,
+		DeleteFunc: changed,
+	}
+}
+
+type roles struct {
+	client storage.Roles
+}
+
+func (c *roles) Create(obj v1.ConfigObject) (v1.ConfigObject, error) {
+	created, err := c.client.Create(obj.(*v1.Role))
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (c *roles) Update(obj v1.ConfigObject) (v1.ConfigObject, error) {
+	updated, err := c.client.Update(obj.(*v1.Role))
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (c *roles) Delete(name string) error {
+	return c.client.Delete(name)
+}
+
+func (c *roles) Get(name string) (v1.ConfigObject, error) {
+	obj, err := c.client.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (c *roles) List() ([]v1.ConfigObject, error) {
+	list, err := c.client.List()
+	if err != nil {
+		return nil, err
+	}
+	var out []v1.ConfigObject
+	for _, obj := range list {
+		out = append(out, obj)
+	}
+	return out, nil
+}
+
+func (c *roles) ListWithOptions(opts storage.ListOptions) ([]v1.ConfigObject, error) {
+	list, err := c.client.ListWithOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	var out []v1.ConfigObject
+	for _, obj := range list {
+		out = append(out, obj)
+	}
+	return out, nil
+}
+
+func (c *roles) Watch(onChange func(list []v1.ConfigObject)) (*storage.Watcher, error) {
+	return c.client.Watch(c.handler(onChange))
+}
+
+func (c *roles) WatchWithOptions(opts storage.ListOptions, onChange func(list []v1.ConfigObject)) (*storage.Watcher, error) {
+	return c.client.WatchWithOptions(opts, c.handler(onChange))
+}
+
+func (c *roles) WatchEvents(opts storage.WatchOptions, onEvent func(event objectEvent)) (*storage.Watcher, error) {
+	return c.client.WatchEvents(opts, func(event storage.RoleEvent) {
+		onEvent(objectEvent{Type: event.Type, Object: event.Role, ResourceVersion: event.ResourceVersion})
+	})
+}
+
+func (c *roles) handler(onChange func(list []v1.ConfigObject)) storage.RoleEventHandler {
+	changed := func(list []*v1.Role, _ *v1.Role) {
+		var out []v1.ConfigObject
+		for _, obj := range list {
+			out = append(out, obj)
+		}
+		onChange(out)
+	}
+	return &storage.RoleEventHandlerFuncs{
+		AddFunc:    changed,
+		UpdateFunc: changed,
+		DeleteFunc: changed,
+	}
+}
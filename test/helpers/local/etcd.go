@@ -0,0 +1,133 @@
+package localhelpers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/onsi/ginkgo"
+)
+
+const defaultEtcdDockerImage = "quay.io/coreos/etcd:v3.3.10"
+
+// the client url of instances started by the factory
+const EtcdEndpoint = "http://127.0.0.1:2379"
+
+type EtcdFactory struct {
+	etcdpath string
+	tmpdir   string
+}
+
+func NewEtcdFactory() (*EtcdFactory, error) {
+	etcdpath := os.Getenv("ETCD_BINARY")
+
+	if etcdpath != "" {
+		return &EtcdFactory{
+			etcdpath: etcdpath,
+		}, nil
+	}
+
+	// try to grab one form docker...
+	tmpdir, err := ioutil.TempDir(os.Getenv("HELPER_TMP"), "etcd")
+	if err != nil {
+		return nil, err
+	}
+
+	bash := fmt.Sprintf(`
+set -ex
+CID=$(docker run -d  %s /bin/sh -c exit)
+
+# just print the image sha for repoducibility
+echo "Using Etcd Image:"
+docker inspect %s -f "{{.RepoDigests}}"
+
+docker cp $CID:/usr/local/bin/etcd .
+docker rm -f $CID
+    `, defaultEtcdDockerImage, defaultEtcdDockerImage)
+	scriptfile := filepath.Join(tmpdir, "getetcd.sh")
+
+	ioutil.WriteFile(scriptfile, []byte(bash), 0755)
+
+	cmd := exec.Command("bash", scriptfile)
+	cmd.Dir = tmpdir
+	cmd.Stdout = ginkgo.GinkgoWriter
+	cmd.Stderr = ginkgo.GinkgoWriter
+	if err := cmd.Run(); err != nil {
+		return nil, err
+	}
+
+	return &EtcdFactory{
+		etcdpath: filepath.Join(tmpdir, "etcd"),
+		tmpdir:   tmpdir,
+	}, nil
+}
+
+func (ef *EtcdFactory) Clean() error {
+	if ef == nil {
+		return nil
+	}
+	if ef.tmpdir != "" {
+		os.RemoveAll(ef.tmpdir)
+	}
+	return nil
+}
+
+type EtcdInstance struct {
+	etcdpath string
+	tmpdir   string
+	cmd      *exec.Cmd
+}
+
+func (ef *EtcdFactory) NewEtcdInstance() (*EtcdInstance, error) {
+	// the data dir of the instance
+	tmpdir, err := ioutil.TempDir(os.Getenv("HELPER_TMP"), "etcd")
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(ef.etcdpath,
+		"--data-dir", tmpdir,
+		"--listen-client-urls", EtcdEndpoint,
+		"--advertise-client-urls", EtcdEndpoint,
+	)
+	cmd.Dir = tmpdir
+	cmd.Stdout = ginkgo.GinkgoWriter
+	cmd.Stderr = ginkgo.GinkgoWriter
+	return &EtcdInstance{
+		etcdpath: ef.etcdpath,
+		tmpdir:   tmpdir,
+		cmd:      cmd,
+	}, nil
+}
+
+func (i *EtcdInstance) Silence() {
+	i.cmd.Stdout = nil
+	i.cmd.Stderr = nil
+}
+
+func (i *EtcdInstance) Run() error {
+	err := i.cmd.Start()
+	if err != nil {
+		return err
+	}
+	time.Sleep(time.Millisecond * 1500)
+	return nil
+}
+
+func (i *EtcdInstance) Binary() string {
+	return i.etcdpath
+}
+
+func (i *EtcdInstance) Clean() error {
+	if i.cmd != nil {
+		i.cmd.Process.Kill()
+		i.cmd.Wait()
+	}
+	if i.tmpdir != "" {
+		os.RemoveAll(i.tmpdir)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package localhelpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/onsi/ginkgo"
+
+	"github.com/solo-io/gloo/test/helpers"
+)
+
+// pebble is a small ACME server for testing, https://github.com/letsencrypt/pebble
+const defaultPebbleDockerImage = "ghcr.io/letsencrypt/pebble:latest"
+
+// the directory url of instances started by the factory
+const PebbleDirectoryURL = "https://localhost:14000/dir"
+
+type PebbleFactory struct {
+	pebblepath string
+	tmpdir     string
+}
+
+func NewPebbleFactory() (*PebbleFactory, error) {
+	pebblepath := os.Getenv("PEBBLE_BINARY")
+
+	if pebblepath != "" {
+		return &PebbleFactory{
+			pebblepath: pebblepath,
+		}, nil
+	}
+
+	// try to grab one form docker...
+	tmpdir, err := ioutil.TempDir(os.Getenv("HELPER_TMP"), "pebble")
+	if err != nil {
+		return nil, err
+	}
+
+	bash := fmt.Sprintf(`
+set -ex
+CID=$(docker create %s)
+
+# just print the image sha for repoducibility
+echo "Using Pebble Image:"
+docker inspect %s -f "{{.RepoDigests}}"
+
+docker cp $CID:/app ./pebble
+docker rm -f $CID
+    `, defaultPebbleDockerImage, defaultPebbleDockerImage)
+	scriptfile := filepath.Join(tmpdir, "getpebble.sh")
+
+	ioutil.WriteFile(scriptfile, []byte(bash), 0755)
+
+	cmd := exec.Command("bash", scriptfile)
+	cmd.Dir = tmpdir
+	cmd.Stdout = ginkgo.GinkgoWriter
+	cmd.Stderr = ginkgo.GinkgoWriter
+	if err := cmd.Run(); err != nil {
+		return nil, err
+	}
+
+	return &PebbleFactory{
+		pebblepath: filepath.Join(tmpdir, "pebble"),
+		tmpdir:     tmpdir,
+	}, nil
+}
+
+func (pf *PebbleFactory) Clean() error {
+	if pf == nil {
+		return nil
+	}
+	if pf.tmpdir != "" {
+		os.RemoveAll(pf.tmpdir)
+	}
+	return nil
+}
+
+type PebbleInstance struct {
+	pebblepath string
+	tmpdir     string
+	cmd        *exec.Cmd
+}
+
+// NewPebbleInstance creates a pebble server that validates HTTP-01 challenges on httpPort.
+// the certificate of its directory is signed by the CA in CAFile
+func (pf *PebbleFactory) NewPebbleInstance(httpPort int) (*PebbleInstance, error) {
+	tmpdir, err := ioutil.TempDir(os.Getenv("HELPER_TMP"), "pebble")
+	if err != nil {
+		return nil, err
+	}
+
+	ca, err := helpers.NewTestCert("pebble test ca", nil, time.Now().Add(24*time.Hour), nil)
+	if err != nil {
+		return nil, err
+	}
+	serverCert, err := helpers.NewTestCert("localhost", []string{"localhost"}, time.Now().Add(24*time.Hour), ca)
+	if err != nil {
+		return nil, err
+	}
+	config, err := json.Marshal(map[string]interface{}{
+		"pebble": map[string]interface{}{
+			"listenAddress":           "0.0.0.0:14000",
+			"managementListenAddress": "0.0.0.0:15000",
+			"certificate":             "cert.pem",
+			"privateKey":              "key.pem",
+			"httpPort":                httpPort,
+			"tlsPort":                 5001,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for name, content := range map[string]string{
+		"ca.pem":             ca.CertPEM,
+		"cert.pem":           serverCert.CertPEM,
+		"key.pem":            serverCert.KeyPEM,
+		"pebble-config.json": string(config),
+	} {
+		if err := ioutil.WriteFile(filepath.Join(tmpdir, name), []byte(content), 0644); err != nil {
+			return nil, err
+		}
+	}
+
+	cmd := exec.Command(pf.pebblepath, "-config", "pebble-config.json")
+	cmd.Dir = tmpdir
+	// validate challenges right away, and don't reject nonces at random
+	cmd.Env = append(os.Environ(), "PEBBLE_VA_NOSLEEP=1", "PEBBLE_WFE_NONCEREJECT=0")
+	cmd.Stdout = ginkgo.GinkgoWriter
+	cmd.Stderr = ginkgo.GinkgoWriter
+	return &PebbleInstance{
+		pebblepath: pf.pebblepath,
+		tmpdir:     tmpdir,
+		cmd:        cmd,
+	}, nil
+}
+
+func (i *PebbleInstance) Silence() {
+	i.cmd.Stdout = nil
+	i.cmd.Stderr = nil
+}
+
+func (i *PebbleInstance) Run() error {
+	err := i.cmd.Start()
+	if err != nil {
+		return err
+	}
+	time.Sleep(time.Millisecond * 1500)
+	return nil
+}
+
+// CAFile is the PEM file of the CA that signed the certificate of the directory
+func (i *PebbleInstance) CAFile() string {
+	return filepath.Join(i.tmpdir, "ca.pem")
+}
+
+func (i *PebbleInstance) Clean() error {
+	if i.cmd != nil {
+		i.cmd.Process.Kill()
+		i.cmd.Wait()
+	}
+	if i.tmpdir != "" {
+		os.RemoveAll(i.tmpdir)
+	}
+	return nil
+}